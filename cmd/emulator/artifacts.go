@@ -0,0 +1,92 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/ozgen/openapi-emulator/config"
+	"github.com/ozgen/openapi-emulator/internal/artifact"
+	"github.com/sirupsen/logrus"
+)
+
+// artifactSync keeps local copies of a SPEC_PATH and/or SAMPLES_DIR given as
+// s3:// or oci:// references.
+type artifactSync struct {
+	fetcher    *artifact.Fetcher
+	specRef    string
+	samplesRef string
+}
+
+// newArtifactSync returns nil when neither SPEC_PATH nor SAMPLES_DIR is remote.
+func newArtifactSync(cfg *config.Config) *artifactSync {
+	a := &artifactSync{}
+	if artifact.Remote(cfg.SpecPath) {
+		a.specRef = cfg.SpecPath
+	}
+	if artifact.Remote(cfg.SamplesDir) {
+		a.samplesRef = cfg.SamplesDir
+	}
+	if a.specRef == "" && a.samplesRef == "" {
+		return nil
+	}
+
+	ac := cfg.Artifacts
+	a.fetcher = artifact.NewFetcher(artifact.Config{
+		CacheDir:       ac.CacheDir,
+		S3Region:       ac.S3Region,
+		S3Endpoint:     ac.S3Endpoint,
+		S3AccessKey:    ac.S3AccessKey,
+		S3SecretKey:    ac.S3SecretKey,
+		S3SessionToken: ac.S3SessionToken,
+		OCIUsername:    ac.OCIUsername,
+		OCIPassword:    ac.OCIPassword,
+	}, nil)
+	return a
+}
+
+// fetch downloads the artifacts and points cfg at the local copies. Later
+// fetches of the same reference reuse the same paths. Downloads that do not
+// match SPEC_SHA256/SAMPLES_SHA256 are rejected.
+func (a *artifactSync) fetch(ctx context.Context, cfg *config.Config) error {
+	if a.specRef != "" {
+		p, err := a.fetcher.FetchFile(ctx, a.specRef, cfg.Artifacts.SpecSHA256)
+		if err != nil {
+			return err
+		}
+		cfg.SpecPath = p
+	}
+	if a.samplesRef != "" {
+		p, err := a.fetcher.FetchDir(ctx, a.samplesRef, cfg.Artifacts.SamplesSHA256)
+		if err != nil {
+			return err
+		}
+		cfg.SamplesDir = p
+	}
+	return nil
+}
+
+// refresh re-downloads the artifacts every interval. Samples are read per
+// request, so only a refreshed spec needs reloadSpec. A failed or rejected
+// refresh keeps serving the previous copy.
+func (a *artifactSync) refresh(cfg config.Config, every time.Duration, reloadSpec func() error, log *logrus.Logger) {
+	for range time.Tick(every) {
+		ctx, cancel := context.WithTimeout(context.Background(), every)
+		err := a.fetch(ctx, &cfg)
+		cancel()
+		if err != nil {
+			log.WithError(err).Warn("artifact refresh failed")
+			continue
+		}
+		if a.specRef != "" {
+			if err := reloadSpec(); err != nil {
+				log.WithError(err).Warn("spec reload after artifact refresh failed")
+				continue
+			}
+		}
+		log.Debug("artifacts refreshed")
+	}
+}
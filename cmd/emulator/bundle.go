@@ -0,0 +1,41 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ozgen/openapi-emulator/config"
+)
+
+// Layout of the embedded bundle, see `make build-bundle`.
+const (
+	bundleSpec    = "swagger.json"
+	bundleSamples = "sample"
+)
+
+// useBundle points cfg at the spec and samples compiled into the binary.
+// They are extracted to a temporary directory because the providers read
+// from disk. It returns false for binaries built without -tags embedbundle.
+func useBundle(cfg *config.Config) (bool, error) {
+	fsys, ok := embeddedBundle()
+	if !ok {
+		return false, nil
+	}
+
+	dir, err := os.MkdirTemp("", "openapi-emulator-bundle-")
+	if err != nil {
+		return false, fmt.Errorf("create bundle dir: %w", err)
+	}
+	if err := os.CopyFS(dir, fsys); err != nil {
+		return false, fmt.Errorf("extract bundle: %w", err)
+	}
+
+	cfg.SpecPath = filepath.Join(dir, bundleSpec)
+	cfg.SamplesDir = filepath.Join(dir, bundleSamples)
+	return true, nil
+}
@@ -0,0 +1,23 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+//go:build embedbundle
+
+package main
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed all:bundle
+var bundle embed.FS
+
+func embeddedBundle() (fs.FS, bool) {
+	sub, err := fs.Sub(bundle, "bundle")
+	if err != nil {
+		return nil, false
+	}
+	return sub, true
+}
@@ -0,0 +1,13 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+//go:build !embedbundle
+
+package main
+
+import "io/fs"
+
+func embeddedBundle() (fs.FS, bool) {
+	return nil, false
+}
@@ -0,0 +1,67 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/ozgen/openapi-emulator/config"
+	"github.com/ozgen/openapi-emulator/internal/server"
+)
+
+// runCoverage reports which spec operations recorded traffic exercised:
+// emulator coverage --recordings dir [--threshold 80]
+func runCoverage(args []string, cfg config.Config) error {
+	fs := flag.NewFlagSet("coverage", flag.ContinueOnError)
+	dir := fs.String("recordings", "", "directory of saved GET /__admin/requests responses")
+	threshold := fs.Float64("threshold", 0, "minimum percentage of operations that must be exercised")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *dir == "" {
+		return fmt.Errorf("--recordings is required")
+	}
+
+	recorded, err := server.LoadRecordings(*dir)
+	if err != nil {
+		return err
+	}
+	if _, err := useBundle(&cfg); err != nil {
+		return err
+	}
+	if artifacts := newArtifactSync(&cfg); artifacts != nil {
+		if err := artifacts.fetch(context.Background(), &cfg); err != nil {
+			return fmt.Errorf("fetch artifacts: %w", err)
+		}
+	}
+	srv, err := server.New(server.Config{
+		SpecPath:          cfg.SpecPath,
+		SamplesDir:        cfg.SamplesDir,
+		FallbackMode:      cfg.FallbackMode,
+		RoutePriorityPath: cfg.RoutePriorityPath,
+		Layout:            cfg.Layout,
+		RoutesDisable:     cfg.RoutesDisable,
+	})
+	if err != nil {
+		return err
+	}
+
+	report := srv.Coverage(recorded)
+	for _, op := range report.Operations {
+		if op.Hits > 0 {
+			fmt.Printf("ok   %s %s (%d)\n", op.Method, op.SwaggerPath, op.Hits)
+		} else {
+			fmt.Printf("miss %s %s\n", op.Method, op.SwaggerPath)
+		}
+	}
+	fmt.Printf("%d of %d operation(s) exercised (%.1f%%) by %d request(s), %d unmatched\n",
+		report.Covered(), len(report.Operations), report.Percent(), len(recorded), report.Unmatched)
+	if report.Percent() < *threshold {
+		return fmt.Errorf("%.1f%% of operations exercised, below the threshold of %.1f%%", report.Percent(), *threshold)
+	}
+	return nil
+}
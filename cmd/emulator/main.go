@@ -5,7 +5,16 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	// Switch windows name IANA time zones; the alpine image has no tzdata.
+	_ "time/tzdata"
+
 	"github.com/ozgen/openapi-emulator/config"
+	"github.com/ozgen/openapi-emulator/internal/samples"
 	"github.com/ozgen/openapi-emulator/internal/server"
 	"github.com/ozgen/openapi-emulator/logger"
 )
@@ -14,14 +23,76 @@ func main() {
 	cfg := config.Envs
 	log := logger.GetLogger()
 
-	srv, err := server.New(server.Config{
-		Port:           cfg.ServerPort,
-		SpecPath:       cfg.SpecPath,
-		SamplesDir:     cfg.SamplesDir,
-		FallbackMode:   cfg.FallbackMode,
-		ValidationMode: cfg.ValidationMode,
-		Layout:         cfg.Layout,
-	})
+	if len(os.Args) > 1 && os.Args[1] == "migrate-samples" {
+		if err := migrateSamples(os.Args[2:], cfg.SamplesDir); err != nil {
+			log.Fatalf("migrate-samples: %v", err)
+		}
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "coverage" {
+		if err := runCoverage(os.Args[2:], cfg); err != nil {
+			log.Fatalf("coverage: %v", err)
+		}
+		return
+	}
+
+	selfTest := flag.Bool("selftest", false, "produce every route's response once, report failures and exit")
+	flag.Parse()
+
+	if ok, err := useBundle(&cfg); err != nil {
+		log.Fatalf("failed to load embedded bundle: %v", err)
+	} else if ok {
+		log.Printf("serving embedded spec and samples from %s", cfg.SamplesDir)
+	}
+
+	artifacts := newArtifactSync(&cfg)
+	if artifacts != nil {
+		if err := artifacts.fetch(context.Background(), &cfg); err != nil {
+			log.Fatalf("failed to fetch artifacts: %v", err)
+		}
+		log.Printf("serving spec %s and samples %s", cfg.SpecPath, cfg.SamplesDir)
+	}
+
+	serverCfg := server.Config{
+		Port:                  cfg.ServerPort,
+		TLSCertFile:           cfg.TLSCertFile,
+		TLSKeyFile:            cfg.TLSKeyFile,
+		SpecPath:              cfg.SpecPath,
+		SamplesDir:            cfg.SamplesDir,
+		FallbackMode:          cfg.FallbackMode,
+		FallbackOverridesPath: cfg.FallbackOverridesPath,
+		RoutePriorityPath:     cfg.RoutePriorityPath,
+		ValidationMode:        cfg.ValidationMode,
+		ValidateHeaders:       cfg.ValidateHeaders,
+		ValidateCookies:       cfg.ValidateCookies,
+		ValidateContentType:   cfg.ValidateContentType,
+		StrictQueryParams:     cfg.StrictQueryParams,
+		SampleValidation:      cfg.SampleValidation,
+		SecurityMode:          cfg.SecurityMode,
+		SecurityTokens:        cfg.SecurityTokens,
+		OAuthSigningKey:       cfg.OAuthSigningKey,
+		StateIsolation:        cfg.StateIsolation,
+		StateIsolationHeader:  cfg.StateIsolationHeader,
+		Layout:                cfg.Layout,
+		TrailingSlash:         cfg.TrailingSlash,
+		BasePath:              cfg.BasePath,
+		ClockSkew:             cfg.ClockSkew,
+		RoutesDisable:         cfg.RoutesDisable,
+		RoutesDisableStatus:   cfg.RoutesDisableStatus,
+		RequestLogSize:        cfg.RequestLogSize,
+		RequestLogMaxAge:      cfg.RequestLogMaxAge,
+		RequestLogExclude:     cfg.RequestLogExclude,
+		ExpectContinueReject:  cfg.ExpectContinueReject,
+		UnmatchedStatus:       cfg.UnmatchedStatus,
+		UnmatchedBodyFile:     cfg.UnmatchedBodyFile,
+		UnmatchedUpstream:     cfg.UnmatchedUpstream,
+		PreserveHeaderCase:    cfg.PreserveHeaderCase,
+		SampleCacheSize:       cfg.SampleCacheSize,
+		TemplateBodyMaxBytes:  cfg.TemplateBodyMaxBytes,
+		StateDir:              cfg.StateDir,
+	}
+	srv, err := server.New(serverCfg)
 	if err != nil {
 		log.Fatalf("failed to init server: %v", err)
 	}
@@ -30,7 +101,167 @@ func main() {
 		log.Print("\n" + srv.DebugRoutes())
 	}
 
+	servers := []*server.Server{srv}
+	var vhosts *server.VirtualHosts
+	if cfg.VirtualHostsPath != "" {
+		vhosts, err = newVirtualHosts(srv, serverCfg, cfg.VirtualHostsPath)
+		if err != nil {
+			log.Fatalf("failed to init virtual hosts: %v", err)
+		}
+		servers = vhosts.Servers()
+	}
+
+	if *selfTest {
+		os.Exit(runSelfTest(srv, vhosts, cfg.SelfTestRoutes))
+	}
+
+	if cfg.StartupHooksPath != "" {
+		hooks, err := config.LoadStartupHooks(cfg.StartupHooksPath)
+		if err != nil {
+			log.Fatalf("failed to load startup hooks: %v", err)
+		}
+		if vhosts != nil {
+			err = vhosts.RunStartupHooks(hooks)
+		} else {
+			err = srv.RunStartupHooks(hooks)
+		}
+		if err != nil {
+			log.Fatalf("startup hooks failed: %v", err)
+		}
+	}
+
+	if artifacts != nil && cfg.Artifacts.Refresh > 0 {
+		go artifacts.refresh(cfg, cfg.Artifacts.Refresh, func() error { return reloadSpecs(servers) }, log)
+	}
+	for _, s := range servers {
+		if cfg.SamplesWatchInterval > 0 {
+			go s.WatchSamples(cfg.SamplesWatchInterval)
+		}
+		if cfg.Scenario.DirectivesDir != "" {
+			go s.WatchScenarioDirectives(cfg.Scenario.DirectivesDir, cfg.Scenario.DirectivesInterval)
+		}
+	}
+
+	if vhosts != nil {
+		if err := vhosts.ListenAndServe(); err != nil {
+			log.Fatalf("server stopped: %v", err)
+		}
+		return
+	}
+
 	if err := srv.ListenAndServe(); err != nil {
 		log.Fatalf("server stopped: %v", err)
 	}
 }
+
+// reloadSpecs re-reads the spec of every server, so virtual hosts pick up
+// refreshed artifacts as well.
+func reloadSpecs(servers []*server.Server) error {
+	var errs []error
+	for _, s := range servers {
+		errs = append(errs, s.ReloadSpec())
+	}
+	return errors.Join(errs...)
+}
+
+// newVirtualHosts builds one server per host in the VIRTUAL_HOSTS_PATH file,
+// sharing every setting of base except the spec and samples. def serves all
+// other hosts.
+func newVirtualHosts(def *server.Server, base server.Config, path string) (*server.VirtualHosts, error) {
+	hosts, err := config.LoadVirtualHosts(path)
+	if err != nil {
+		return nil, err
+	}
+	servers := make(map[string]*server.Server, len(hosts))
+	for host, vh := range hosts {
+		c := base
+		c.SpecPath = vh.SpecPath
+		c.SamplesDir = vh.SamplesDir
+		// Hosts without their own certificate get the default one by SNI.
+		c.TLSCertFile = vh.TLSCert
+		c.TLSKeyFile = vh.TLSKey
+		s, err := server.New(c)
+		if err != nil {
+			return nil, fmt.Errorf("virtual host %s: %w", host, err)
+		}
+		servers[host] = s
+	}
+	return server.NewVirtualHosts(def, servers), nil
+}
+
+// runSelfTest prints the outcome of SelfTest on the default server and, when
+// vhosts is set, on every virtual host, and returns the process exit code:
+// 0 when every response could be produced, 1 otherwise.
+func runSelfTest(def *server.Server, vhosts *server.VirtualHosts, routes []string) int {
+	if vhosts == nil {
+		return selfTestServer(def, routes)
+	}
+	code := 0
+	servers := vhosts.Servers()
+	for i, host := range append([]string{""}, vhosts.Hosts()...) {
+		if host == "" {
+			fmt.Println("== default host")
+		} else {
+			fmt.Printf("== virtual host %s\n", host)
+		}
+		code = max(code, selfTestServer(servers[i], routes))
+	}
+	return code
+}
+
+// selfTestServer prints one line per response of srv.SelfTest and returns 1
+// when any of them failed.
+func selfTestServer(srv *server.Server, routes []string) int {
+	results, err := srv.SelfTest(routes)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "selftest: %v\n", err)
+		return 1
+	}
+
+	failed := 0
+	for _, r := range results {
+		name := r.Method + " " + r.SwaggerPath
+		if r.State != "" {
+			name += " [" + r.State + "]"
+		}
+		if r.Err != nil {
+			failed++
+			fmt.Printf("FAIL %s: %v\n", name, r.Err)
+			continue
+		}
+		fmt.Printf("ok   %s (%s)\n", name, r.Source)
+	}
+	fmt.Printf("%d response(s) checked, %d failed\n", len(results), failed)
+	if failed > 0 {
+		return 1
+	}
+	return 0
+}
+
+// migrateSamples renames {param} sample folders to _param_:
+// emulator migrate-samples [-dry-run] [dir]
+func migrateSamples(args []string, defaultDir string) error {
+	fs := flag.NewFlagSet("migrate-samples", flag.ContinueOnError)
+	dryRun := fs.Bool("dry-run", false, "only print the planned renames")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	dir := defaultDir
+	if fs.NArg() > 0 {
+		dir = fs.Arg(0)
+	}
+
+	renames, err := samples.MigrateParamFolders(dir, *dryRun)
+	for _, r := range renames {
+		fmt.Printf("%s -> %s\n", r.From, r.To)
+	}
+	if err != nil {
+		return err
+	}
+	if *dryRun {
+		fmt.Printf("%d folder(s) would be renamed\n", len(renames))
+	} else {
+		fmt.Printf("%d folder(s) renamed\n", len(renames))
+	}
+	return nil
+}
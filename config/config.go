@@ -5,6 +5,10 @@
 package config
 
 import (
+	"os"
+	"path/filepath"
+	"time"
+
 	"github.com/joho/godotenv"
 	"github.com/ozgen/openapi-emulator/utils"
 )
@@ -22,6 +26,7 @@ type FallbackMode string
 const (
 	FallbackNone           FallbackMode = "none"
 	FallbackOpenAPIExample FallbackMode = "openapi_examples"
+	FallbackRandom         FallbackMode = "random"
 )
 
 type ValidationMode string
@@ -29,6 +34,44 @@ type ValidationMode string
 const (
 	ValidationNone     ValidationMode = "none"
 	ValidationRequired ValidationMode = "required"
+	ValidationSchema   ValidationMode = "schema"
+	// ValidationWarn runs the schema checks but only reports failures in
+	// the X-Emulator-Validation response header.
+	ValidationWarn ValidationMode = "warn"
+)
+
+type SecurityMode string
+
+const (
+	SecurityNone    SecurityMode = "none"
+	SecurityEnforce SecurityMode = "enforce"
+)
+
+type SampleValidationMode string
+
+const (
+	SampleValidationOff    SampleValidationMode = "off"
+	SampleValidationWarn   SampleValidationMode = "warn"
+	SampleValidationStrict SampleValidationMode = "strict"
+)
+
+// TrailingSlashMode controls requests whose trailing slash differs from the
+// spec path.
+type TrailingSlashMode string
+
+const (
+	TrailingSlashIgnore   TrailingSlashMode = "ignore"   // match either way
+	TrailingSlashStrict   TrailingSlashMode = "strict"   // 404
+	TrailingSlashRedirect TrailingSlashMode = "redirect" // 301 to the spec form
+)
+
+type IsolationMode string
+
+const (
+	IsolationNone   IsolationMode = "none"
+	IsolationIP     IsolationMode = "ip"
+	IsolationAPIKey IsolationMode = "apikey"
+	IsolationHeader IsolationMode = "header"
 )
 
 type LayoutMode string
@@ -37,25 +80,114 @@ const (
 	LayoutAuto    LayoutMode = "auto"    // folder-first, then flat
 	LayoutFolders LayoutMode = "folders" // only folders
 	LayoutFlat    LayoutMode = "flat"    // only flat
+	LayoutTags    LayoutMode = "tags"    // only <tag>/<operationId>.json
 )
 
 type ScenarioConfig struct {
 	Enabled  bool
 	Filename string
+	// Dir holds scenario files mirroring the path structure. Empty means
+	// scenarios live next to the samples in SAMPLES_DIR.
+	Dir string
+	// DirectivesDir is polled for files of scenario state directives, such
+	// as a mounted ConfigMap. Empty disables the watch.
+	DirectivesDir      string
+	DirectivesInterval time.Duration
+	// StateURL names a redis:// or rediss:// server, or a file:// snapshot on
+	// a shared volume, holding scenario state shared by replicas. Empty keeps
+	// state in process memory.
+	StateURL string
+}
+
+// ArtifactConfig covers SPEC_PATH and SAMPLES_DIR values that point at
+// s3:// or oci:// artifacts instead of local paths.
+type ArtifactConfig struct {
+	CacheDir string
+	// Refresh is the interval for re-downloading artifacts; 0 disables it.
+	Refresh time.Duration
+	// SpecSHA256 and SamplesSHA256 pin the expected digests; empty skips
+	// the check.
+	SpecSHA256    string
+	SamplesSHA256 string
+
+	S3Region       string
+	S3Endpoint     string
+	S3AccessKey    string
+	S3SecretKey    string
+	S3SessionToken string
+
+	OCIUsername string
+	OCIPassword string
 }
 
 type Config struct {
-	ServerPort     string
-	SpecPath       string
-	SamplesDir     string
-	LogLevel       string
-	RunningEnv     RunningEnv
-	FallbackMode   FallbackMode
-	DebugRoutes    bool
-	ValidationMode ValidationMode
-	Layout         LayoutMode
-
-	Scenario ScenarioConfig
+	ServerPort            string
+	TLSCertFile           string
+	TLSKeyFile            string
+	SpecPath              string
+	SamplesDir            string
+	LogLevel              string
+	RunningEnv            RunningEnv
+	FallbackMode          FallbackMode
+	FallbackOverridesPath string
+	RoutePriorityPath     string
+	VirtualHostsPath      string
+	StartupHooksPath      string
+	DebugRoutes           bool
+	ValidationMode        ValidationMode
+	ValidateHeaders       bool
+	ValidateCookies       bool
+	ValidateContentType   bool
+	StrictQueryParams     bool
+	SampleValidation      SampleValidationMode
+	SecurityMode          SecurityMode
+	SecurityTokens        []string
+	OAuthSigningKey       string
+	StateIsolation        IsolationMode
+	StateIsolationHeader  string
+	Layout                LayoutMode
+	TrailingSlash         TrailingSlashMode
+	BasePath              string
+	// RoutesDisable lists "METHOD /path" patterns of operations answered as
+	// if they were not deployed, with RoutesDisableStatus (404 or 503).
+	RoutesDisable       []string
+	RoutesDisableStatus int
+	// UnmatchedStatus, UnmatchedBodyFile and UnmatchedUpstream customize the
+	// answer to requests that match no spec operation.
+	UnmatchedStatus   int
+	UnmatchedBodyFile string
+	UnmatchedUpstream string
+	// RequestLogSize and RequestLogMaxAge bound the admin request log and
+	// scenario board; requests to routes matching RequestLogExclude are not logged.
+	RequestLogSize    int
+	RequestLogMaxAge  time.Duration
+	RequestLogExclude []string
+	// ExpectContinueReject lists "METHOD /path" patterns whose
+	// "Expect: 100-continue" requests are refused before the body is sent.
+	ExpectContinueReject []string
+	// SelfTestRoutes limits --selftest to operations matching these
+	// "METHOD /path" patterns; empty tests every operation.
+	SelfTestRoutes []string
+	// PreserveHeaderCase writes sample header names exactly as spelled.
+	PreserveHeaderCase bool
+	// ClockSkew shifts the emulator's Date headers and token timestamps.
+	ClockSkew time.Duration
+	// SampleCacheSize bounds the parsed samples kept in memory; 0 disables
+	// the cache.
+	SampleCacheSize int
+	// TemplateBodyMaxBytes caps the request body read for sample templates,
+	// echoFields and body matchers; a larger body is seen as no body.
+	TemplateBodyMaxBytes int
+	// SamplesWatchInterval polls SAMPLES_DIR for edited files; 0 disables it.
+	SamplesWatchInterval time.Duration
+	// StateDir is a writable directory for what the emulator keeps on disk:
+	// the artifact cache and relative file: scenario state. SAMPLES_DIR can
+	// then be mounted read-only. Empty uses the system temp directory for
+	// artifacts and allows only absolute file:// scenario state.
+	StateDir string
+
+	Scenario  ScenarioConfig
+	Artifacts ArtifactConfig
 }
 
 var Envs = initConfig()
@@ -63,20 +195,79 @@ var Envs = initConfig()
 func initConfig() Config {
 	_ = godotenv.Load()
 
+	stateDir := utils.GetEnv("STATE_DIR", "")
+	artifactCache := filepath.Join(os.TempDir(), "openapi-emulator-artifacts")
+	if stateDir != "" {
+		artifactCache = filepath.Join(stateDir, "artifacts")
+	}
+
 	return Config{
-		ServerPort:     utils.GetEnv("SERVER_PORT", "8086"),
-		SpecPath:       utils.GetEnv("SPEC_PATH", "/work/swagger.json"),
-		SamplesDir:     utils.GetEnv("SAMPLES_DIR", "/work/sample"),
-		LogLevel:       utils.GetEnv("LOG_LEVEL", "info"),
-		RunningEnv:     RunningEnv(utils.GetEnv("RUNNING_ENV", "docker")),
-		ValidationMode: ValidationMode(utils.GetEnv("VALIDATION_MODE", "required")),
-		FallbackMode:   FallbackMode(utils.GetEnv("FALLBACK_MODE", "openapi_examples")),
-		DebugRoutes:    utils.GetEnvAsBool("DEBUG_ROUTES", false),
-		Layout:         LayoutMode(utils.GetEnv("LAYOUT_MODE", "auto")),
+		ServerPort:            utils.GetEnv("SERVER_PORT", "8086"),
+		TLSCertFile:           utils.GetEnv("TLS_CERT_FILE", ""),
+		TLSKeyFile:            utils.GetEnv("TLS_KEY_FILE", ""),
+		SpecPath:              utils.GetEnv("SPEC_PATH", "/work/swagger.json"),
+		SamplesDir:            utils.GetEnv("SAMPLES_DIR", "/work/sample"),
+		LogLevel:              utils.GetEnv("LOG_LEVEL", "info"),
+		RunningEnv:            RunningEnv(utils.GetEnv("RUNNING_ENV", "docker")),
+		ValidationMode:        ValidationMode(utils.GetEnv("VALIDATION_MODE", "required")),
+		ValidateHeaders:       utils.GetEnvAsBool("VALIDATE_HEADERS", false),
+		ValidateCookies:       utils.GetEnvAsBool("VALIDATE_COOKIES", false),
+		ValidateContentType:   utils.GetEnvAsBool("VALIDATE_CONTENT_TYPE", false),
+		StrictQueryParams:     utils.GetEnvAsBool("STRICT_QUERY_PARAMS", false),
+		SampleValidation:      SampleValidationMode(utils.GetEnv("SAMPLE_VALIDATION", "off")),
+		SecurityMode:          SecurityMode(utils.GetEnv("SECURITY_MODE", "none")),
+		SecurityTokens:        utils.GetEnvAsList("SECURITY_TOKENS"),
+		OAuthSigningKey:       utils.GetEnv("OAUTH_SIGNING_KEY", "openapi-emulator"),
+		StateIsolation:        IsolationMode(utils.GetEnv("STATE_ISOLATION", "none")),
+		StateIsolationHeader:  utils.GetEnv("STATE_ISOLATION_HEADER", "X-Client-Id"),
+		FallbackMode:          FallbackMode(utils.GetEnv("FALLBACK_MODE", "openapi_examples")),
+		FallbackOverridesPath: utils.GetEnv("FALLBACK_OVERRIDES_PATH", ""),
+		RoutePriorityPath:     utils.GetEnv("ROUTE_PRIORITY_PATH", ""),
+		VirtualHostsPath:      utils.GetEnv("VIRTUAL_HOSTS_PATH", ""),
+		StartupHooksPath:      utils.GetEnv("STARTUP_HOOKS_PATH", ""),
+		DebugRoutes:           utils.GetEnvAsBool("DEBUG_ROUTES", false),
+		Layout:                LayoutMode(utils.GetEnv("LAYOUT_MODE", "auto")),
+		TrailingSlash:         TrailingSlashMode(utils.GetEnv("TRAILING_SLASH", "ignore")),
+		BasePath:              utils.GetEnv("SERVER_BASE_PATH", ""),
+		ClockSkew:             utils.GetEnvAsDuration("CLOCK_SKEW", 0),
+		RoutesDisable:         utils.GetEnvAsList("ROUTES_DISABLE"),
+		RoutesDisableStatus:   utils.GetEnvAsInt("ROUTES_DISABLE_STATUS", 404),
+		UnmatchedStatus:       utils.GetEnvAsInt("UNMATCHED_STATUS", 404),
+		UnmatchedBodyFile:     utils.GetEnv("UNMATCHED_BODY_FILE", ""),
+		UnmatchedUpstream:     utils.GetEnv("UNMATCHED_UPSTREAM", ""),
+		RequestLogSize:        utils.GetEnvAsInt("REQUEST_LOG_SIZE", 200),
+		RequestLogMaxAge:      utils.GetEnvAsDuration("REQUEST_LOG_MAX_AGE", 0),
+		RequestLogExclude:     utils.GetEnvAsList("REQUEST_LOG_EXCLUDE"),
+		ExpectContinueReject:  utils.GetEnvAsList("EXPECT_CONTINUE_REJECT"),
+		SelfTestRoutes:        utils.GetEnvAsList("SELFTEST_ROUTES"),
+		PreserveHeaderCase:    utils.GetEnvAsBool("PRESERVE_HEADER_CASE", false),
+		SamplesWatchInterval:  utils.GetEnvAsDuration("SAMPLES_WATCH_INTERVAL", 0),
+		SampleCacheSize:       utils.GetEnvAsInt("SAMPLE_CACHE_SIZE", 256),
+		TemplateBodyMaxBytes:  utils.GetEnvAsInt("TEMPLATE_BODY_MAX_BYTES", 1<<20),
+		StateDir:              stateDir,
 
 		Scenario: ScenarioConfig{
 			Enabled:  utils.GetEnvAsBool("SCENARIO_ENABLED", true),
 			Filename: utils.GetEnv("SCENARIO_FILENAME", "scenario.json"),
+			Dir:      utils.GetEnv("SCENARIOS_DIR", ""),
+
+			DirectivesDir:      utils.GetEnv("SCENARIO_DIRECTIVES_DIR", ""),
+			DirectivesInterval: utils.GetEnvAsDuration("SCENARIO_DIRECTIVES_INTERVAL", 2*time.Second),
+			StateURL:           utils.GetEnv("SCENARIO_STATE_URL", ""),
+		},
+
+		Artifacts: ArtifactConfig{
+			CacheDir:       utils.GetEnv("ARTIFACT_CACHE_DIR", artifactCache),
+			Refresh:        utils.GetEnvAsDuration("ARTIFACT_REFRESH", 0),
+			SpecSHA256:     utils.GetEnv("SPEC_SHA256", ""),
+			SamplesSHA256:  utils.GetEnv("SAMPLES_SHA256", ""),
+			S3Region:       utils.GetEnv("AWS_REGION", "us-east-1"),
+			S3Endpoint:     utils.GetEnv("AWS_ENDPOINT_URL", ""),
+			S3AccessKey:    utils.GetEnv("AWS_ACCESS_KEY_ID", ""),
+			S3SecretKey:    utils.GetEnv("AWS_SECRET_ACCESS_KEY", ""),
+			S3SessionToken: utils.GetEnv("AWS_SESSION_TOKEN", ""),
+			OCIUsername:    utils.GetEnv("OCI_USERNAME", ""),
+			OCIPassword:    utils.GetEnv("OCI_PASSWORD", ""),
 		},
 	}
 }
@@ -6,11 +6,15 @@ package config
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestInitConfig_Defaults_AllFields(t *testing.T) {
 	_ = os.Unsetenv("SERVER_PORT")
+	_ = os.Unsetenv("TLS_CERT_FILE")
+	_ = os.Unsetenv("TLS_KEY_FILE")
 	_ = os.Unsetenv("SPEC_PATH")
 	_ = os.Unsetenv("SAMPLES_DIR")
 	_ = os.Unsetenv("LOG_LEVEL")
@@ -19,14 +23,41 @@ func TestInitConfig_Defaults_AllFields(t *testing.T) {
 	_ = os.Unsetenv("FALLBACK_MODE")
 	_ = os.Unsetenv("DEBUG_ROUTES")
 	_ = os.Unsetenv("LAYOUT_MODE")
+	_ = os.Unsetenv("TRAILING_SLASH")
+	_ = os.Unsetenv("SERVER_BASE_PATH")
+	_ = os.Unsetenv("CLOCK_SKEW")
+	_ = os.Unsetenv("ROUTES_DISABLE")
+	_ = os.Unsetenv("ROUTE_PRIORITY_PATH")
+	_ = os.Unsetenv("VIRTUAL_HOSTS_PATH")
+	_ = os.Unsetenv("ROUTES_DISABLE_STATUS")
+	_ = os.Unsetenv("REQUEST_LOG_SIZE")
+	_ = os.Unsetenv("REQUEST_LOG_MAX_AGE")
+	_ = os.Unsetenv("REQUEST_LOG_EXCLUDE")
+	_ = os.Unsetenv("EXPECT_CONTINUE_REJECT")
+	_ = os.Unsetenv("SELFTEST_ROUTES")
+	_ = os.Unsetenv("PRESERVE_HEADER_CASE")
+	_ = os.Unsetenv("TEMPLATE_BODY_MAX_BYTES")
 	_ = os.Unsetenv("SCENARIO_ENABLED")
 	_ = os.Unsetenv("SCENARIO_FILENAME")
+	_ = os.Unsetenv("SCENARIOS_DIR")
+	_ = os.Unsetenv("SCENARIO_DIRECTIVES_DIR")
+	_ = os.Unsetenv("SCENARIO_DIRECTIVES_INTERVAL")
+	_ = os.Unsetenv("SCENARIO_STATE_URL")
+	_ = os.Unsetenv("ARTIFACT_CACHE_DIR")
+	_ = os.Unsetenv("ARTIFACT_REFRESH")
+	_ = os.Unsetenv("SPEC_SHA256")
+	_ = os.Unsetenv("SAMPLES_SHA256")
+	_ = os.Unsetenv("AWS_REGION")
+	_ = os.Unsetenv("AWS_ENDPOINT_URL")
 
 	cfg := initConfig()
 
 	if cfg.ServerPort != "8086" {
 		t.Fatalf("ServerPort: expected %q, got %q", "8086", cfg.ServerPort)
 	}
+	if cfg.TLSCertFile != "" || cfg.TLSKeyFile != "" {
+		t.Fatalf("TLS files: expected empty, got %q and %q", cfg.TLSCertFile, cfg.TLSKeyFile)
+	}
 	if cfg.SpecPath != "/work/swagger.json" {
 		t.Fatalf("SpecPath: expected %q, got %q", "/work/swagger.json", cfg.SpecPath)
 	}
@@ -51,6 +82,48 @@ func TestInitConfig_Defaults_AllFields(t *testing.T) {
 	if cfg.Layout != LayoutAuto {
 		t.Fatalf("Layout: expected %q, got %q", LayoutAuto, cfg.Layout)
 	}
+	if cfg.TrailingSlash != TrailingSlashIgnore {
+		t.Fatalf("TrailingSlash: expected %q, got %q", TrailingSlashIgnore, cfg.TrailingSlash)
+	}
+	if cfg.BasePath != "" {
+		t.Fatalf("BasePath: expected empty, got %q", cfg.BasePath)
+	}
+	if cfg.ClockSkew != 0 {
+		t.Fatalf("ClockSkew: expected 0, got %v", cfg.ClockSkew)
+	}
+	if cfg.RoutePriorityPath != "" {
+		t.Fatalf("RoutePriorityPath: expected empty, got %q", cfg.RoutePriorityPath)
+	}
+	if cfg.VirtualHostsPath != "" {
+		t.Fatalf("VirtualHostsPath: expected empty, got %q", cfg.VirtualHostsPath)
+	}
+	if cfg.RoutesDisable != nil {
+		t.Fatalf("RoutesDisable: expected nil, got %q", cfg.RoutesDisable)
+	}
+	if cfg.RoutesDisableStatus != 404 {
+		t.Fatalf("RoutesDisableStatus: expected %d, got %d", 404, cfg.RoutesDisableStatus)
+	}
+	if cfg.RequestLogSize != 200 {
+		t.Fatalf("RequestLogSize: expected %d, got %d", 200, cfg.RequestLogSize)
+	}
+	if cfg.RequestLogMaxAge != 0 {
+		t.Fatalf("RequestLogMaxAge: expected 0, got %v", cfg.RequestLogMaxAge)
+	}
+	if cfg.TemplateBodyMaxBytes != 1<<20 {
+		t.Fatalf("TemplateBodyMaxBytes: expected %d, got %d", 1<<20, cfg.TemplateBodyMaxBytes)
+	}
+	if cfg.RequestLogExclude != nil {
+		t.Fatalf("RequestLogExclude: expected nil, got %q", cfg.RequestLogExclude)
+	}
+	if cfg.ExpectContinueReject != nil {
+		t.Fatalf("ExpectContinueReject: expected nil, got %q", cfg.ExpectContinueReject)
+	}
+	if cfg.SelfTestRoutes != nil {
+		t.Fatalf("SelfTestRoutes: expected nil, got %q", cfg.SelfTestRoutes)
+	}
+	if cfg.PreserveHeaderCase {
+		t.Fatalf("PreserveHeaderCase: expected false")
+	}
 
 	if cfg.Scenario.Enabled != true {
 		t.Fatalf("Scenario.Enabled: expected %v, got %v", true, cfg.Scenario.Enabled)
@@ -58,10 +131,40 @@ func TestInitConfig_Defaults_AllFields(t *testing.T) {
 	if cfg.Scenario.Filename != "scenario.json" {
 		t.Fatalf("Scenario.Filename: expected %q, got %q", "scenario.json", cfg.Scenario.Filename)
 	}
+	if cfg.Scenario.Dir != "" {
+		t.Fatalf("Scenario.Dir: expected empty, got %q", cfg.Scenario.Dir)
+	}
+	if cfg.Scenario.DirectivesDir != "" {
+		t.Fatalf("Scenario.DirectivesDir: expected empty, got %q", cfg.Scenario.DirectivesDir)
+	}
+	if cfg.Scenario.DirectivesInterval != 2*time.Second {
+		t.Fatalf("Scenario.DirectivesInterval: expected %v, got %v", 2*time.Second, cfg.Scenario.DirectivesInterval)
+	}
+	if cfg.Scenario.StateURL != "" {
+		t.Fatalf("Scenario.StateURL: expected empty, got %q", cfg.Scenario.StateURL)
+	}
+
+	if want := filepath.Join(os.TempDir(), "openapi-emulator-artifacts"); cfg.Artifacts.CacheDir != want {
+		t.Fatalf("Artifacts.CacheDir: expected %q, got %q", want, cfg.Artifacts.CacheDir)
+	}
+	if cfg.Artifacts.Refresh != 0 {
+		t.Fatalf("Artifacts.Refresh: expected 0, got %v", cfg.Artifacts.Refresh)
+	}
+	if cfg.Artifacts.SpecSHA256 != "" || cfg.Artifacts.SamplesSHA256 != "" {
+		t.Fatalf("Artifacts pins: expected empty, got %q / %q", cfg.Artifacts.SpecSHA256, cfg.Artifacts.SamplesSHA256)
+	}
+	if cfg.Artifacts.S3Region != "us-east-1" {
+		t.Fatalf("Artifacts.S3Region: expected %q, got %q", "us-east-1", cfg.Artifacts.S3Region)
+	}
+	if cfg.Artifacts.S3Endpoint != "" {
+		t.Fatalf("Artifacts.S3Endpoint: expected empty, got %q", cfg.Artifacts.S3Endpoint)
+	}
 }
 
 func TestInitConfig_Overrides_AllFields(t *testing.T) {
 	t.Setenv("SERVER_PORT", "9999")
+	t.Setenv("TLS_CERT_FILE", "/certs/tls.crt")
+	t.Setenv("TLS_KEY_FILE", "/certs/tls.key")
 	t.Setenv("SPEC_PATH", "/tmp/spec.json")
 	t.Setenv("SAMPLES_DIR", "/tmp/samples")
 	t.Setenv("LOG_LEVEL", "debug")
@@ -70,15 +173,42 @@ func TestInitConfig_Overrides_AllFields(t *testing.T) {
 	t.Setenv("FALLBACK_MODE", "none")
 	t.Setenv("DEBUG_ROUTES", "1")
 	t.Setenv("LAYOUT_MODE", "folders")
+	t.Setenv("TRAILING_SLASH", "redirect")
+	t.Setenv("SERVER_BASE_PATH", "/gateway/v2")
+	t.Setenv("CLOCK_SKEW", "-90s")
+	t.Setenv("ROUTES_DISABLE", "DELETE /items/{id}, POST /admin/*")
+	t.Setenv("ROUTE_PRIORITY_PATH", "/etc/emulator/priorities.yaml")
+	t.Setenv("VIRTUAL_HOSTS_PATH", "/etc/emulator/hosts.yaml")
+	t.Setenv("ROUTES_DISABLE_STATUS", "503")
+	t.Setenv("REQUEST_LOG_SIZE", "50")
+	t.Setenv("REQUEST_LOG_MAX_AGE", "10m")
+	t.Setenv("REQUEST_LOG_EXCLUDE", "GET /health,* /metrics/*")
+	t.Setenv("EXPECT_CONTINUE_REJECT", "PUT /uploads/*")
+	t.Setenv("SELFTEST_ROUTES", "GET /scans/*, POST /scans")
+	t.Setenv("PRESERVE_HEADER_CASE", "true")
 
 	t.Setenv("SCENARIO_ENABLED", "false")
 	t.Setenv("SCENARIO_FILENAME", "my-scenario.json")
+	t.Setenv("SCENARIOS_DIR", "/tmp/scenarios")
+	t.Setenv("SCENARIO_DIRECTIVES_DIR", "/etc/emulator/directives")
+	t.Setenv("SCENARIO_DIRECTIVES_INTERVAL", "10s")
+	t.Setenv("SCENARIO_STATE_URL", "redis://redis:6379/1")
+
+	t.Setenv("ARTIFACT_CACHE_DIR", "/tmp/artifacts")
+	t.Setenv("ARTIFACT_REFRESH", "5m")
+	t.Setenv("SPEC_SHA256", "aaaa")
+	t.Setenv("SAMPLES_SHA256", "bbbb")
+	t.Setenv("AWS_REGION", "eu-central-1")
+	t.Setenv("AWS_ENDPOINT_URL", "http://minio:9000")
 
 	cfg := initConfig()
 
 	if cfg.ServerPort != "9999" {
 		t.Fatalf("ServerPort: expected %q, got %q", "9999", cfg.ServerPort)
 	}
+	if cfg.TLSCertFile != "/certs/tls.crt" || cfg.TLSKeyFile != "/certs/tls.key" {
+		t.Fatalf("TLS files: unexpected %q and %q", cfg.TLSCertFile, cfg.TLSKeyFile)
+	}
 	if cfg.SpecPath != "/tmp/spec.json" {
 		t.Fatalf("SpecPath: expected %q, got %q", "/tmp/spec.json", cfg.SpecPath)
 	}
@@ -103,6 +233,45 @@ func TestInitConfig_Overrides_AllFields(t *testing.T) {
 	if cfg.Layout != LayoutFolders {
 		t.Fatalf("Layout: expected %q, got %q", LayoutFolders, cfg.Layout)
 	}
+	if cfg.TrailingSlash != TrailingSlashRedirect {
+		t.Fatalf("TrailingSlash: expected %q, got %q", TrailingSlashRedirect, cfg.TrailingSlash)
+	}
+	if cfg.BasePath != "/gateway/v2" {
+		t.Fatalf("BasePath: expected %q, got %q", "/gateway/v2", cfg.BasePath)
+	}
+	if cfg.ClockSkew != -90*time.Second {
+		t.Fatalf("ClockSkew: expected %v, got %v", -90*time.Second, cfg.ClockSkew)
+	}
+	if cfg.RoutePriorityPath != "/etc/emulator/priorities.yaml" {
+		t.Fatalf("RoutePriorityPath: unexpected %q", cfg.RoutePriorityPath)
+	}
+	if cfg.VirtualHostsPath != "/etc/emulator/hosts.yaml" {
+		t.Fatalf("VirtualHostsPath: unexpected %q", cfg.VirtualHostsPath)
+	}
+	if len(cfg.RoutesDisable) != 2 || cfg.RoutesDisable[1] != "POST /admin/*" {
+		t.Fatalf("RoutesDisable: unexpected %q", cfg.RoutesDisable)
+	}
+	if cfg.RoutesDisableStatus != 503 {
+		t.Fatalf("RoutesDisableStatus: expected %d, got %d", 503, cfg.RoutesDisableStatus)
+	}
+	if cfg.RequestLogSize != 50 {
+		t.Fatalf("RequestLogSize: expected %d, got %d", 50, cfg.RequestLogSize)
+	}
+	if cfg.RequestLogMaxAge != 10*time.Minute {
+		t.Fatalf("RequestLogMaxAge: expected %v, got %v", 10*time.Minute, cfg.RequestLogMaxAge)
+	}
+	if len(cfg.RequestLogExclude) != 2 || cfg.RequestLogExclude[1] != "* /metrics/*" {
+		t.Fatalf("RequestLogExclude: unexpected %q", cfg.RequestLogExclude)
+	}
+	if len(cfg.ExpectContinueReject) != 1 || cfg.ExpectContinueReject[0] != "PUT /uploads/*" {
+		t.Fatalf("ExpectContinueReject: unexpected %q", cfg.ExpectContinueReject)
+	}
+	if len(cfg.SelfTestRoutes) != 2 || cfg.SelfTestRoutes[0] != "GET /scans/*" {
+		t.Fatalf("SelfTestRoutes: unexpected %q", cfg.SelfTestRoutes)
+	}
+	if !cfg.PreserveHeaderCase {
+		t.Fatalf("PreserveHeaderCase: expected true")
+	}
 
 	if cfg.Scenario.Enabled != false {
 		t.Fatalf("Scenario.Enabled: expected %v, got %v", false, cfg.Scenario.Enabled)
@@ -110,6 +279,37 @@ func TestInitConfig_Overrides_AllFields(t *testing.T) {
 	if cfg.Scenario.Filename != "my-scenario.json" {
 		t.Fatalf("Scenario.Filename: expected %q, got %q", "my-scenario.json", cfg.Scenario.Filename)
 	}
+	if cfg.Scenario.Dir != "/tmp/scenarios" {
+		t.Fatalf("Scenario.Dir: expected %q, got %q", "/tmp/scenarios", cfg.Scenario.Dir)
+	}
+	if cfg.Scenario.DirectivesDir != "/etc/emulator/directives" {
+		t.Fatalf("Scenario.DirectivesDir: unexpected %q", cfg.Scenario.DirectivesDir)
+	}
+	if cfg.Scenario.DirectivesInterval != 10*time.Second {
+		t.Fatalf("Scenario.DirectivesInterval: expected %v, got %v", 10*time.Second, cfg.Scenario.DirectivesInterval)
+	}
+	if cfg.Scenario.StateURL != "redis://redis:6379/1" {
+		t.Fatalf("Scenario.StateURL: expected %q, got %q", "redis://redis:6379/1", cfg.Scenario.StateURL)
+	}
+
+	if cfg.Artifacts.CacheDir != "/tmp/artifacts" {
+		t.Fatalf("Artifacts.CacheDir: expected %q, got %q", "/tmp/artifacts", cfg.Artifacts.CacheDir)
+	}
+	if cfg.Artifacts.Refresh != 5*time.Minute {
+		t.Fatalf("Artifacts.Refresh: expected %v, got %v", 5*time.Minute, cfg.Artifacts.Refresh)
+	}
+	if cfg.Artifacts.SpecSHA256 != "aaaa" {
+		t.Fatalf("Artifacts.SpecSHA256: expected %q, got %q", "aaaa", cfg.Artifacts.SpecSHA256)
+	}
+	if cfg.Artifacts.SamplesSHA256 != "bbbb" {
+		t.Fatalf("Artifacts.SamplesSHA256: expected %q, got %q", "bbbb", cfg.Artifacts.SamplesSHA256)
+	}
+	if cfg.Artifacts.S3Region != "eu-central-1" {
+		t.Fatalf("Artifacts.S3Region: expected %q, got %q", "eu-central-1", cfg.Artifacts.S3Region)
+	}
+	if cfg.Artifacts.S3Endpoint != "http://minio:9000" {
+		t.Fatalf("Artifacts.S3Endpoint: expected %q, got %q", "http://minio:9000", cfg.Artifacts.S3Endpoint)
+	}
 }
 
 func TestInitConfig_BoolParsing_DebugRoutesVariants(t *testing.T) {
@@ -0,0 +1,57 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FallbackOverride replaces the global FALLBACK_MODE for a single route.
+// Status is the fallback response status; for mode "none" it replaces 501.
+type FallbackOverride struct {
+	Mode   FallbackMode `yaml:"mode"`
+	Status int          `yaml:"status"`
+}
+
+// LoadFallbackOverrides reads a YAML file mapping "METHOD /swagger/path" to
+// a FallbackOverride. Keys are normalized with RouteKey.
+func LoadFallbackOverrides(path string) (map[string]FallbackOverride, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read fallback overrides: %w", err)
+	}
+
+	var raw map[string]FallbackOverride
+	if err := yaml.Unmarshal(b, &raw); err != nil {
+		return nil, fmt.Errorf("parse fallback overrides: %w", err)
+	}
+
+	out := make(map[string]FallbackOverride, len(raw))
+	for k, v := range raw {
+		method, p, ok := strings.Cut(strings.TrimSpace(k), " ")
+		if !ok || strings.TrimSpace(p) == "" {
+			return nil, fmt.Errorf("invalid fallback override key %q (want \"METHOD /path\")", k)
+		}
+		switch v.Mode {
+		case "", FallbackNone, FallbackOpenAPIExample, FallbackRandom:
+		default:
+			return nil, fmt.Errorf("invalid fallback mode %q for %q", v.Mode, k)
+		}
+		if v.Status != 0 && (v.Status < 100 || v.Status > 599) {
+			return nil, fmt.Errorf("invalid fallback status %d for %q", v.Status, k)
+		}
+		out[RouteKey(method, p)] = v
+	}
+	return out, nil
+}
+
+// RouteKey builds the "METHOD /path" key used by route-level config files.
+func RouteKey(method, swaggerPath string) string {
+	return strings.ToUpper(strings.TrimSpace(method)) + " " + strings.TrimSpace(swaggerPath)
+}
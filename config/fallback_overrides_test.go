@@ -0,0 +1,67 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeOverrides(t *testing.T, content string) string {
+	t.Helper()
+	p := filepath.Join(t.TempDir(), "fallback.yaml")
+	if err := os.WriteFile(p, []byte(content), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	return p
+}
+
+func TestLoadFallbackOverrides_OK(t *testing.T) {
+	p := writeOverrides(t, `
+"get /items/{id}":
+  mode: none
+  status: 404
+"POST  /items":
+  mode: random
+`)
+
+	got, err := LoadFallbackOverrides(p)
+	if err != nil {
+		t.Fatalf("LoadFallbackOverrides: %v", err)
+	}
+
+	if o := got["GET /items/{id}"]; o.Mode != FallbackNone || o.Status != 404 {
+		t.Fatalf("unexpected override: %#v", o)
+	}
+	if o := got["POST /items"]; o.Mode != FallbackRandom || o.Status != 0 {
+		t.Fatalf("unexpected override: %#v", o)
+	}
+}
+
+func TestLoadFallbackOverrides_Invalid(t *testing.T) {
+	cases := map[string]string{
+		"bad key":    "\"/items\":\n  mode: none\n",
+		"bad mode":   "\"GET /items\":\n  mode: sometimes\n",
+		"bad status": "\"GET /items\":\n  status: 42\n",
+		"bad yaml":   "::: not yaml",
+	}
+
+	for name, content := range cases {
+		if _, err := LoadFallbackOverrides(writeOverrides(t, content)); err == nil {
+			t.Fatalf("%s: expected error", name)
+		}
+	}
+
+	if _, err := LoadFallbackOverrides("/no/such/fallback.yaml"); err == nil {
+		t.Fatalf("expected error for missing file")
+	}
+}
+
+func TestRouteKey(t *testing.T) {
+	if got := RouteKey(" get ", " /items/{id} "); got != "GET /items/{id}" {
+		t.Fatalf("unexpected key: %q", got)
+	}
+}
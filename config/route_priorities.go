@@ -0,0 +1,38 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LoadRoutePriorities reads a YAML file mapping "METHOD /swagger/path" to a
+// route priority; "*" as method applies to every method of the path. Keys
+// are normalized with RouteKey.
+func LoadRoutePriorities(path string) (map[string]int, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read route priorities: %w", err)
+	}
+
+	var raw map[string]int
+	if err := yaml.Unmarshal(b, &raw); err != nil {
+		return nil, fmt.Errorf("parse route priorities: %w", err)
+	}
+
+	out := make(map[string]int, len(raw))
+	for k, v := range raw {
+		method, p, ok := strings.Cut(strings.TrimSpace(k), " ")
+		if !ok || !strings.HasPrefix(strings.TrimSpace(p), "/") {
+			return nil, fmt.Errorf("invalid route priority key %q (want \"METHOD /path\")", k)
+		}
+		out[RouteKey(method, p)] = v
+	}
+	return out, nil
+}
@@ -0,0 +1,49 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadRoutePriorities(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "priorities.yaml")
+	if err := os.WriteFile(path, []byte(`
+"get /items/latest": 10
+"* /items/{id}": -1
+`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := LoadRoutePriorities(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if got["GET /items/latest"] != 10 || got["* /items/{id}"] != -1 {
+		t.Fatalf("unexpected priorities: %v", got)
+	}
+}
+
+func TestLoadRoutePriorities_Invalid(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"nokey.yaml":  `"/items": 1`,
+		"value.yaml":  `"GET /items": high`,
+		"broken.yaml": `:`,
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadRoutePriorities(path); err == nil {
+			t.Fatalf("%s: expected an error", name)
+		}
+	}
+	if _, err := LoadRoutePriorities(filepath.Join(dir, "missing.yaml")); err == nil {
+		t.Fatalf("expected an error for a missing file")
+	}
+}
@@ -0,0 +1,55 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// StartupHook is one step of the STARTUP_HOOKS_PATH file: either a request
+// served by the emulator itself, sent Repeat times (default once), or a
+// scenario directive line such as "set /scans/{id} key 42 to running". Host
+// limits the step to the virtual host serving that name.
+type StartupHook struct {
+	Host      string            `yaml:"host"`
+	Method    string            `yaml:"method"`
+	Path      string            `yaml:"path"`
+	Headers   map[string]string `yaml:"headers"`
+	Body      any               `yaml:"body"`
+	Repeat    int               `yaml:"repeat"`
+	Directive string            `yaml:"directive"`
+}
+
+// LoadStartupHooks reads a YAML list of StartupHook steps, to be run in
+// order once the emulator is up.
+func LoadStartupHooks(path string) ([]StartupHook, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read startup hooks: %w", err)
+	}
+
+	var hooks []StartupHook
+	if err := yaml.Unmarshal(b, &hooks); err != nil {
+		return nil, fmt.Errorf("parse startup hooks: %w", err)
+	}
+
+	for i, h := range hooks {
+		isRequest := strings.TrimSpace(h.Method) != "" || h.Path != ""
+		isDirective := strings.TrimSpace(h.Directive) != ""
+		switch {
+		case isRequest == isDirective:
+			return nil, fmt.Errorf("startup hook %d needs either method and path or a directive", i+1)
+		case isRequest && (strings.TrimSpace(h.Method) == "" || !strings.HasPrefix(h.Path, "/")):
+			return nil, fmt.Errorf("startup hook %d needs a method and an absolute path", i+1)
+		case h.Repeat < 0 || (isDirective && h.Repeat > 0):
+			return nil, fmt.Errorf("startup hook %d: repeat must be a positive count on a request", i+1)
+		}
+	}
+	return hooks, nil
+}
@@ -0,0 +1,61 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadStartupHooks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hooks.yaml")
+	if err := os.WriteFile(path, []byte(`
+- method: POST
+  path: /scans
+  headers: {X-Tenant: demo}
+  body: {target: 10.0.0.1}
+  repeat: 3
+- directive: set /scans/{id} key 1 to running
+  host: payments.local
+`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := LoadStartupHooks(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if len(got) != 2 || got[0].Method != "POST" || got[0].Repeat != 3 || got[0].Headers["X-Tenant"] != "demo" {
+		t.Fatalf("unexpected hooks: %+v", got)
+	}
+	if body, ok := got[0].Body.(map[string]any); !ok || body["target"] != "10.0.0.1" {
+		t.Fatalf("unexpected body: %#v", got[0].Body)
+	}
+	if got[1].Directive != "set /scans/{id} key 1 to running" || got[1].Host != "payments.local" {
+		t.Fatalf("unexpected directive: %+v", got[1])
+	}
+}
+
+func TestLoadStartupHooks_Invalid(t *testing.T) {
+	for name, content := range map[string]string{
+		"empty step":            `- {}`,
+		"request and directive": "- {method: GET, path: /a, directive: reset /a key 1}",
+		"relative path":         `- {method: GET, path: a}`,
+		"missing method":        `- {path: /a}`,
+		"repeat on directive":   "- {directive: reset /a key 1, repeat: 2}",
+		"not a list":            `method: GET`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "hooks.yaml")
+			if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := LoadStartupHooks(path); err == nil {
+				t.Fatalf("expected an error")
+			}
+		})
+	}
+}
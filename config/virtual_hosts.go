@@ -0,0 +1,55 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// VirtualHost binds a spec and sample directory to a Host header. With TLS
+// enabled, TLSCert and TLSKey are the certificate served to clients asking
+// for the host by SNI.
+type VirtualHost struct {
+	SpecPath   string `yaml:"specPath"`
+	SamplesDir string `yaml:"samplesDir"`
+	TLSCert    string `yaml:"tlsCert"`
+	TLSKey     string `yaml:"tlsKey"`
+}
+
+// LoadVirtualHosts reads a YAML file mapping host names to a VirtualHost.
+// Host names are lowercased; a port in the name is not allowed, as the
+// request port is ignored when matching. A name may start with "*." to cover
+// every subdomain.
+func LoadVirtualHosts(path string) (map[string]VirtualHost, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read virtual hosts: %w", err)
+	}
+
+	var raw map[string]VirtualHost
+	if err := yaml.Unmarshal(b, &raw); err != nil {
+		return nil, fmt.Errorf("parse virtual hosts: %w", err)
+	}
+
+	out := make(map[string]VirtualHost, len(raw))
+	for k, v := range raw {
+		host := strings.ToLower(strings.TrimSpace(k))
+		if host == "" || strings.ContainsAny(host, ":/ ") || strings.Contains(strings.TrimPrefix(host, "*."), "*") {
+			return nil, fmt.Errorf("invalid virtual host %q", k)
+		}
+		if strings.TrimSpace(v.SpecPath) == "" || strings.TrimSpace(v.SamplesDir) == "" {
+			return nil, fmt.Errorf("virtual host %q needs specPath and samplesDir", k)
+		}
+		if (strings.TrimSpace(v.TLSCert) == "") != (strings.TrimSpace(v.TLSKey) == "") {
+			return nil, fmt.Errorf("virtual host %q needs both tlsCert and tlsKey", k)
+		}
+		out[host] = v
+	}
+	return out, nil
+}
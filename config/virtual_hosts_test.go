@@ -0,0 +1,57 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadVirtualHosts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hosts.yaml")
+	if err := os.WriteFile(path, []byte(`
+API.foo.local:
+  specPath: /specs/api.yaml
+  samplesDir: /samples/api
+payments.foo.local:
+  specPath: /specs/payments.yaml
+  samplesDir: /samples/payments
+  tlsCert: /certs/payments.crt
+  tlsKey: /certs/payments.key
+`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := LoadVirtualHosts(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if len(got) != 2 || got["api.foo.local"].SpecPath != "/specs/api.yaml" || got["payments.foo.local"].SamplesDir != "/samples/payments" {
+		t.Fatalf("unexpected virtual hosts: %v", got)
+	}
+	if got["payments.foo.local"].TLSCert != "/certs/payments.crt" || got["api.foo.local"].TLSCert != "" {
+		t.Fatalf("unexpected certificates: %v", got)
+	}
+}
+
+func TestLoadVirtualHosts_Invalid(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"port.yaml":    "api.local:8080: {specPath: a, samplesDir: b}",
+		"missing.yaml": "api.local: {specPath: a}",
+		"tls.yaml":     "api.local: {specPath: a, samplesDir: b, tlsCert: c}",
+		"glob.yaml":    "api.*.local: {specPath: a, samplesDir: b}",
+		"broken.yaml":  `:`,
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadVirtualHosts(path); err == nil {
+			t.Fatalf("%s: expected error", name)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+// Package artifact downloads specs and sample trees published as s3:// or
+// oci:// artifacts into a local cache directory.
+package artifact
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+type Config struct {
+	CacheDir string
+
+	S3Region       string
+	S3Endpoint     string // path-style endpoint, e.g. MinIO; empty uses AWS
+	S3AccessKey    string
+	S3SecretKey    string
+	S3SessionToken string
+
+	OCIUsername string
+	OCIPassword string
+}
+
+type Fetcher struct {
+	cfg    Config
+	client *http.Client
+}
+
+// NewFetcher returns a Fetcher; a nil client uses one with a 60s timeout.
+func NewFetcher(cfg Config, client *http.Client) *Fetcher {
+	if client == nil {
+		client = &http.Client{Timeout: 60 * time.Second}
+	}
+	return &Fetcher{cfg: cfg, client: client}
+}
+
+// Remote reports whether ref names an artifact rather than a local path.
+func Remote(ref string) bool {
+	return strings.HasPrefix(ref, "s3://") || strings.HasPrefix(ref, "oci://")
+}
+
+// FetchDir downloads a sample tree and returns its local directory:
+// every object below an s3:// prefix, or the files of an oci:// artifact.
+// A #path fragment selects a subdirectory of the artifact. A non-empty pin
+// is the expected DirDigest of that directory.
+func (f *Fetcher) FetchDir(ctx context.Context, ref, pin string) (string, error) {
+	return f.sync(ctx, ref, false, pin)
+}
+
+// FetchFile downloads a single file and returns its local path: an s3://
+// object, or the file named by the #path fragment of an oci:// artifact.
+// Without a fragment the artifact must contain exactly one file. A non-empty
+// pin is the expected SHA-256 of the file.
+func (f *Fetcher) FetchFile(ctx context.Context, ref, pin string) (string, error) {
+	return f.sync(ctx, ref, true, pin)
+}
+
+// sync downloads ref into a staging directory, verifies the pin and swaps
+// the download into the cache directory of ref, so paths handed out earlier
+// stay valid and a rejected download never replaces a good one. It returns
+// the local path the caller asked for.
+func (f *Fetcher) sync(ctx context.Context, ref string, file bool, pin string) (string, error) {
+	if err := os.MkdirAll(f.cfg.CacheDir, 0o755); err != nil {
+		return "", fmt.Errorf("create artifact cache: %w", err)
+	}
+	staging, err := os.MkdirTemp(f.cfg.CacheDir, ".staging-")
+	if err != nil {
+		return "", fmt.Errorf("create artifact cache: %w", err)
+	}
+	defer os.RemoveAll(staging)
+
+	base, sub, _ := strings.Cut(ref, "#")
+	switch {
+	case strings.HasPrefix(base, "s3://"):
+		var name string
+		name, err = f.fetchS3(ctx, strings.TrimPrefix(base, "s3://"), staging, file)
+		if name != "" {
+			sub = name
+		}
+	case strings.HasPrefix(base, "oci://"):
+		err = f.fetchOCI(ctx, strings.TrimPrefix(base, "oci://"), staging)
+	default:
+		err = fmt.Errorf("unsupported artifact reference")
+	}
+	if err == nil && file && sub == "" {
+		sub, err = singleFile(staging)
+	}
+	if err == nil && pin != "" {
+		err = verifyPin(filepath.Join(staging, filepath.FromSlash(sub)), file, pin)
+	}
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", ref, err)
+	}
+
+	sum := sha256.Sum256([]byte(ref))
+	dest := filepath.Join(f.cfg.CacheDir, hex.EncodeToString(sum[:8]))
+	old := dest + ".old"
+	_ = os.RemoveAll(old)
+	if err := os.Rename(dest, old); err != nil && !os.IsNotExist(err) {
+		return "", fmt.Errorf("replace %s: %w", dest, err)
+	}
+	if err := os.Rename(staging, dest); err != nil {
+		return "", fmt.Errorf("replace %s: %w", dest, err)
+	}
+	_ = os.RemoveAll(old)
+	return filepath.Join(dest, filepath.FromSlash(sub)), nil
+}
+
+// singleFile returns the only file below root, relative to root.
+func singleFile(root string) (string, error) {
+	var files []string
+	_ = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && d.Type().IsRegular() {
+			rel, _ := filepath.Rel(root, path)
+			files = append(files, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	if len(files) != 1 {
+		return "", fmt.Errorf("artifact has %d files; name one with #path", len(files))
+	}
+	return files[0], nil
+}
+
+// writeFile stores r at rel below dir, refusing paths that leave dir.
+func writeFile(dir, rel string, r io.Reader) error {
+	rel = filepath.Clean(filepath.FromSlash(strings.TrimLeft(rel, "/")))
+	if rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("invalid file name %q", rel)
+	}
+	full := filepath.Join(dir, rel)
+	if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+		return err
+	}
+	out, err := os.Create(full)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, r); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// checkStatus turns a non-2xx response into an error, closing its body.
+func checkStatus(resp *http.Response) error {
+	if resp.StatusCode/100 == 2 {
+		return nil
+	}
+	b, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	_ = resp.Body.Close()
+	return fmt.Errorf("GET %s: %s: %s", resp.Request.URL.Redacted(), resp.Status, strings.TrimSpace(string(b)))
+}
@@ -0,0 +1,88 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package artifact
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// FileDigest returns the hex SHA-256 of a file, as printed by sha256sum.
+func FileDigest(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// DirDigest returns the hex SHA-256 of the sha256sum listing of every file
+// below dir, sorted bytewise by relative path. It equals
+//
+//	cd dir && find . -type f | sed 's|^\./||' | LC_ALL=C sort | xargs sha256sum | sha256sum
+func DirDigest(dir string) (string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type().IsRegular() {
+			rel, err := filepath.Rel(dir, path)
+			if err != nil {
+				return err
+			}
+			files = append(files, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	sort.Strings(files)
+
+	h := sha256.New()
+	for _, rel := range files {
+		sum, err := FileDigest(filepath.Join(dir, filepath.FromSlash(rel)))
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(h, "%s  %s\n", sum, rel)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// verifyPin compares the digest of path with pin, which may carry a
+// "sha256:" prefix.
+func verifyPin(path string, file bool, pin string) error {
+	want := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(pin), "sha256:"))
+
+	var got string
+	var err error
+	if file {
+		got, err = FileDigest(path)
+	} else {
+		got, err = DirDigest(path)
+	}
+	if err != nil {
+		return err
+	}
+	if got != want {
+		return fmt.Errorf("sha256 mismatch: expected %s, got %s", want, got)
+	}
+	return nil
+}
@@ -0,0 +1,231 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package artifact
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	ociManifestAccept = "application/vnd.oci.image.manifest.v1+json, application/vnd.docker.distribution.manifest.v2+json"
+	// ociTitle names the file of a non-tar layer, as set by `oras push`.
+	ociTitle = "org.opencontainers.image.title"
+)
+
+type ociManifest struct {
+	Layers []struct {
+		MediaType   string            `json:"mediaType"`
+		Digest      string            `json:"digest"`
+		Annotations map[string]string `json:"annotations"`
+	} `json:"layers"`
+}
+
+// ociClient talks to one repository and keeps the bearer token it obtained.
+type ociClient struct {
+	f     *Fetcher
+	host  string
+	repo  string
+	token string
+}
+
+// fetchOCI downloads the layers of host/repo[:tag|@digest] into dir. Tar
+// layers are unpacked; other layers are stored under their title.
+func (f *Fetcher) fetchOCI(ctx context.Context, loc, dir string) error {
+	host, rest, ok := strings.Cut(loc, "/")
+	if !ok || rest == "" {
+		return fmt.Errorf("expected oci://<registry>/<repository>[:tag|@digest]")
+	}
+	repo, reference := rest, "latest"
+	if i := strings.LastIndex(rest, "@"); i >= 0 {
+		repo, reference = rest[:i], rest[i+1:]
+	} else if i := strings.LastIndex(rest, ":"); i > strings.LastIndex(rest, "/") {
+		repo, reference = rest[:i], rest[i+1:]
+	}
+
+	c := &ociClient{f: f, host: host, repo: repo}
+	body, err := c.get(ctx, "manifests/"+reference, ociManifestAccept)
+	if err != nil {
+		return err
+	}
+	var m ociManifest
+	if err := json.Unmarshal(body, &m); err != nil {
+		return fmt.Errorf("decode manifest: %w", err)
+	}
+	if len(m.Layers) == 0 {
+		return fmt.Errorf("manifest has no layers")
+	}
+
+	for _, l := range m.Layers {
+		blob, err := c.get(ctx, "blobs/"+l.Digest, "")
+		if err != nil {
+			return err
+		}
+		if err := verifyDigest(l.Digest, blob); err != nil {
+			return err
+		}
+		switch {
+		case strings.Contains(l.MediaType, "tar"):
+			if err := untar(dir, blob, strings.Contains(l.MediaType, "gzip")); err != nil {
+				return fmt.Errorf("unpack %s: %w", l.Digest, err)
+			}
+		case l.Annotations[ociTitle] != "":
+			if err := writeFile(dir, l.Annotations[ociTitle], bytes.NewReader(blob)); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("layer %s is neither a tar archive nor titled", l.Digest)
+		}
+	}
+	return nil
+}
+
+// get fetches /v2/<repo>/<p>, answering a bearer challenge once.
+func (c *ociClient) get(ctx context.Context, p, accept string) ([]byte, error) {
+	u := "https://" + c.host + "/v2/" + c.repo + "/" + p
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+		if err != nil {
+			return nil, err
+		}
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		if c.token != "" {
+			req.Header.Set("Authorization", "Bearer "+c.token)
+		} else if c.f.cfg.OCIUsername != "" {
+			req.SetBasicAuth(c.f.cfg.OCIUsername, c.f.cfg.OCIPassword)
+		}
+
+		resp, err := c.f.client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode == http.StatusUnauthorized && attempt == 0 {
+			challenge := resp.Header.Get("WWW-Authenticate")
+			_ = resp.Body.Close()
+			if err := c.authenticate(ctx, challenge); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if err := checkStatus(resp); err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		return io.ReadAll(resp.Body)
+	}
+}
+
+// authenticate obtains a token for a `Bearer realm=...,service=...,scope=...`
+// challenge, with basic credentials when configured.
+func (c *ociClient) authenticate(ctx context.Context, challenge string) error {
+	scheme, params, _ := strings.Cut(challenge, " ")
+	if !strings.EqualFold(scheme, "Bearer") {
+		return fmt.Errorf("registry requires unsupported authentication %q", scheme)
+	}
+	attrs := map[string]string{}
+	for _, part := range strings.Split(params, ",") {
+		if k, v, ok := strings.Cut(strings.TrimSpace(part), "="); ok {
+			attrs[strings.ToLower(k)] = strings.Trim(v, `"`)
+		}
+	}
+	if attrs["realm"] == "" {
+		return fmt.Errorf("bearer challenge without realm")
+	}
+
+	q := url.Values{}
+	if attrs["service"] != "" {
+		q.Set("service", attrs["service"])
+	}
+	if attrs["scope"] != "" {
+		q.Set("scope", attrs["scope"])
+	} else {
+		q.Set("scope", "repository:"+c.repo+":pull")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, attrs["realm"]+"?"+q.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	if c.f.cfg.OCIUsername != "" {
+		req.SetBasicAuth(c.f.cfg.OCIUsername, c.f.cfg.OCIPassword)
+	}
+	resp, err := c.f.client.Do(req)
+	if err != nil {
+		return err
+	}
+	if err := checkStatus(resp); err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var tok struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tok); err != nil {
+		return fmt.Errorf("decode token: %w", err)
+	}
+	c.token = tok.Token
+	if c.token == "" {
+		c.token = tok.AccessToken
+	}
+	if c.token == "" {
+		return fmt.Errorf("token endpoint returned no token")
+	}
+	return nil
+}
+
+func verifyDigest(digest string, blob []byte) error {
+	algo, want, _ := strings.Cut(digest, ":")
+	if algo != "sha256" {
+		return nil
+	}
+	sum := sha256.Sum256(blob)
+	if hex.EncodeToString(sum[:]) != want {
+		return fmt.Errorf("blob %s does not match its digest", digest)
+	}
+	return nil
+}
+
+// untar unpacks the regular files of a (gzipped) tar archive into dir.
+func untar(dir string, blob []byte, gzipped bool) error {
+	var r io.Reader = bytes.NewReader(blob)
+	if gzipped {
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		if err := writeFile(dir, hdr.Name, tr); err != nil {
+			return err
+		}
+	}
+}
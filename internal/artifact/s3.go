@@ -0,0 +1,235 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package artifact
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// emptyPayloadHash is the SHA-256 of an empty body, sent with every GET.
+const emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// fetchS3 downloads bucket/key into dir. For a file it returns the object's
+// base name; otherwise every object below the key prefix is stored relative
+// to the prefix.
+func (f *Fetcher) fetchS3(ctx context.Context, loc, dir string, file bool) (string, error) {
+	bucket, key, _ := strings.Cut(loc, "/")
+	if bucket == "" {
+		return "", fmt.Errorf("missing bucket")
+	}
+
+	if file {
+		if key == "" || strings.HasSuffix(key, "/") {
+			return "", fmt.Errorf("missing object key")
+		}
+		if err := f.s3Download(ctx, bucket, key, dir, path.Base(key)); err != nil {
+			return "", err
+		}
+		return path.Base(key), nil
+	}
+
+	prefix := key
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	keys, err := f.s3List(ctx, bucket, prefix)
+	if err != nil {
+		return "", err
+	}
+	if len(keys) == 0 {
+		return "", fmt.Errorf("no objects below %q", prefix)
+	}
+	for _, k := range keys {
+		if err := f.s3Download(ctx, bucket, k, dir, strings.TrimPrefix(k, prefix)); err != nil {
+			return "", err
+		}
+	}
+	return "", nil
+}
+
+func (f *Fetcher) s3Download(ctx context.Context, bucket, key, dir, rel string) error {
+	resp, err := f.s3Get(ctx, bucket, key, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return writeFile(dir, rel, resp.Body)
+}
+
+// s3List returns the object keys below prefix using ListObjectsV2.
+func (f *Fetcher) s3List(ctx context.Context, bucket, prefix string) ([]string, error) {
+	var out []string
+	token := ""
+	for {
+		q := url.Values{"list-type": {"2"}, "prefix": {prefix}}
+		if token != "" {
+			q.Set("continuation-token", token)
+		}
+		resp, err := f.s3Get(ctx, bucket, "", q)
+		if err != nil {
+			return nil, err
+		}
+		var page struct {
+			Contents []struct {
+				Key string `xml:"Key"`
+			} `xml:"Contents"`
+			IsTruncated           bool   `xml:"IsTruncated"`
+			NextContinuationToken string `xml:"NextContinuationToken"`
+		}
+		err = xml.NewDecoder(resp.Body).Decode(&page)
+		_ = resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("list %s: %w", bucket, err)
+		}
+		for _, c := range page.Contents {
+			if !strings.HasSuffix(c.Key, "/") {
+				out = append(out, c.Key)
+			}
+		}
+		if !page.IsTruncated || page.NextContinuationToken == "" {
+			return out, nil
+		}
+		token = page.NextContinuationToken
+	}
+}
+
+func (f *Fetcher) s3Get(ctx context.Context, bucket, key string, query url.Values) (*http.Response, error) {
+	var u string
+	if f.cfg.S3Endpoint != "" {
+		u = strings.TrimSuffix(f.cfg.S3Endpoint, "/") + "/" + bucket
+		if key != "" {
+			u += "/" + s3Escape(key)
+		}
+	} else {
+		u = fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", bucket, f.s3Region(), s3Escape(key))
+	}
+	if len(query) > 0 {
+		u += "?" + s3Query(query)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	f.signS3(req, time.Now())
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkStatus(resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (f *Fetcher) s3Region() string {
+	if f.cfg.S3Region == "" {
+		return "us-east-1"
+	}
+	return f.cfg.S3Region
+}
+
+// signS3 adds an AWS Signature Version 4 Authorization header. Requests
+// stay anonymous when no access key is configured.
+func (f *Fetcher) signS3(req *http.Request, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", emptyPayloadHash)
+	if f.cfg.S3AccessKey == "" {
+		return
+	}
+
+	headers := map[string]string{
+		"host":                 req.URL.Host,
+		"x-amz-content-sha256": emptyPayloadHash,
+		"x-amz-date":           amzDate,
+	}
+	if f.cfg.S3SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", f.cfg.S3SessionToken)
+		headers["x-amz-security-token"] = f.cfg.S3SessionToken
+	}
+	names := make([]string, 0, len(headers))
+	for k := range headers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, k := range names {
+		canonicalHeaders.WriteString(k + ":" + headers[k] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		s3Query(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		emptyPayloadHash,
+	}, "\n")
+
+	day := amzDate[:8]
+	scope := day + "/" + f.s3Region() + "/s3/aws4_request"
+	digest := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(digest[:])
+
+	key := hmacSHA256([]byte("AWS4"+f.cfg.S3SecretKey), day)
+	for _, part := range []string{f.s3Region(), "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s,SignedHeaders=%s,Signature=%s",
+		f.cfg.S3AccessKey, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// s3Escape URI-encodes every segment of an object key as SigV4 expects.
+func s3Escape(key string) string {
+	parts := strings.Split(key, "/")
+	for i, p := range parts {
+		parts[i] = s3EscapeComponent(p)
+	}
+	return strings.Join(parts, "/")
+}
+
+// s3Query encodes query parameters sorted by name with RFC 3986 escaping.
+func s3Query(q url.Values) string {
+	names := make([]string, 0, len(q))
+	for k := range q {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	var parts []string
+	for _, k := range names {
+		vals := append([]string(nil), q[k]...)
+		sort.Strings(vals)
+		for _, v := range vals {
+			parts = append(parts, s3EscapeComponent(k)+"="+s3EscapeComponent(v))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+func s3EscapeComponent(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
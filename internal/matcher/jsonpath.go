@@ -0,0 +1,102 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package matcher
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// jsonPath is a parsed JSONPath of the subset used by body matchers: the
+// root $ followed by .name, ['name'] and [index] steps. Each step is a
+// string (object member) or an int (array index).
+type jsonPath []any
+
+// parseJSONPath parses expressions like $.order.items[0]['unit price'].
+func parseJSONPath(expr string) (jsonPath, error) {
+	rest, ok := strings.CutPrefix(strings.TrimSpace(expr), "$")
+	if !ok {
+		return nil, fmt.Errorf("invalid JSONPath %q: must start with $", expr)
+	}
+
+	var out jsonPath
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			end := strings.IndexAny(rest[1:], ".[")
+			if end < 0 {
+				end = len(rest) - 1
+			}
+			name := rest[1 : end+1]
+			if name == "" {
+				return nil, fmt.Errorf("invalid JSONPath %q: empty member name", expr)
+			}
+			out = append(out, name)
+			rest = rest[end+1:]
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("invalid JSONPath %q: missing ]", expr)
+			}
+			inner := rest[1:end]
+			if len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0] {
+				out = append(out, inner[1:len(inner)-1])
+			} else if idx, err := strconv.Atoi(inner); err == nil && idx >= 0 {
+				out = append(out, idx)
+			} else {
+				return nil, fmt.Errorf("invalid JSONPath %q: unsupported step [%s]", expr, inner)
+			}
+			rest = rest[end+1:]
+		default:
+			return nil, fmt.Errorf("invalid JSONPath %q: unexpected %q", expr, rest[0])
+		}
+	}
+	return out, nil
+}
+
+// lookup returns the value at p in a decoded JSON document.
+func (p jsonPath) lookup(doc any) (any, bool) {
+	v := doc
+	for _, step := range p {
+		switch s := step.(type) {
+		case string:
+			m, ok := v.(map[string]any)
+			if !ok {
+				return nil, false
+			}
+			if v, ok = m[s]; !ok {
+				return nil, false
+			}
+		case int:
+			a, ok := v.([]any)
+			if !ok || s >= len(a) {
+				return nil, false
+			}
+			v = a[s]
+		}
+	}
+	return v, true
+}
+
+// jsonScalarString renders a decoded JSON value for matching: strings as they
+// are, numbers and booleans as written, null as "null" and objects and
+// arrays as compact JSON.
+func jsonScalarString(v any) string {
+	switch x := v.(type) {
+	case string:
+		return x
+	case json.Number:
+		return x.String()
+	case nil:
+		return "null"
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
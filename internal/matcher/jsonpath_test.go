@@ -0,0 +1,57 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package matcher
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseJSONPath(t *testing.T) {
+	p, err := parseJSONPath(`$.order.items[1]['unit price']`)
+	require.NoError(t, err)
+	require.Equal(t, jsonPath{"order", "items", 1, "unit price"}, p)
+
+	p, err = parseJSONPath("$")
+	require.NoError(t, err)
+	require.Empty(t, p)
+
+	for _, bad := range []string{"action", "$..action", "$.items[-1]", "$.items[*]", "$.items[0", "$x"} {
+		_, err := parseJSONPath(bad)
+		require.Error(t, err, bad)
+	}
+}
+
+func TestJSONPath_Lookup(t *testing.T) {
+	dec := json.NewDecoder(bytes.NewReader([]byte(`{"action":"cancel","order":{"items":[{"qty":2},{"qty":5,"gift":true}]},"note":null}`)))
+	dec.UseNumber()
+	var doc any
+	require.NoError(t, dec.Decode(&doc))
+
+	cases := map[string]string{
+		"$.action":              "cancel",
+		"$.order.items[1].qty":  "5",
+		"$.order.items[1].gift": "true",
+		"$.note":                "null",
+		"$.order.items[0]":      `{"qty":2}`,
+	}
+	for expr, want := range cases {
+		p, err := parseJSONPath(expr)
+		require.NoError(t, err)
+		v, ok := p.lookup(doc)
+		require.True(t, ok, expr)
+		require.Equal(t, want, jsonScalarString(v), expr)
+	}
+
+	for _, expr := range []string{"$.missing", "$.order.items[2]", "$.action.x", "$.order[0]"} {
+		p, err := parseJSONPath(expr)
+		require.NoError(t, err)
+		_, ok := p.lookup(doc)
+		require.False(t, ok, expr)
+	}
+}
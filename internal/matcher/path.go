@@ -0,0 +1,60 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package matcher
+
+import "strings"
+
+// MatchMethod reports whether method satisfies pattern: equal ignoring
+// case, or any method for "" and "*".
+func MatchMethod(pattern, method string) bool {
+	pattern = strings.TrimSpace(pattern)
+	return pattern == "" || pattern == "*" || strings.EqualFold(pattern, strings.TrimSpace(method))
+}
+
+// MatchPath reports whether path satisfies the path template pattern. A
+// {name} segment matches any single segment, and a trailing /* matches the
+// path above it and everything below. An empty pattern matches any path.
+func MatchPath(pattern, path string) bool {
+	pattern = strings.TrimSpace(pattern)
+	if pattern == "" {
+		return true
+	}
+	pat, below := strings.CutSuffix(pattern, "/*")
+	want, got := segments(pat), segments(path)
+	if len(got) != len(want) && !(below && len(got) > len(want)) {
+		return false
+	}
+	return segmentsMatch(want, got[:len(want)])
+}
+
+// MatchPathSuffix is MatchPath against the end of path, so a pattern need
+// not repeat a base path: "/scans/{id}" matches "/api/v1/scans/7".
+func MatchPathSuffix(pattern, path string) bool {
+	want, got := segments(pattern), segments(path)
+	if len(got) < len(want) {
+		return false
+	}
+	return segmentsMatch(want, got[len(got)-len(want):])
+}
+
+func segments(p string) []string {
+	p = strings.Trim(strings.TrimSpace(p), "/")
+	if p == "" {
+		return nil
+	}
+	return strings.Split(p, "/")
+}
+
+func segmentsMatch(want, got []string) bool {
+	for i, w := range want {
+		if strings.HasPrefix(w, "{") && strings.HasSuffix(w, "}") {
+			continue
+		}
+		if w != got[i] {
+			return false
+		}
+	}
+	return true
+}
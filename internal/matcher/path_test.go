@@ -0,0 +1,74 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package matcher
+
+import "testing"
+
+func TestMatchMethod(t *testing.T) {
+	cases := []struct {
+		pattern, method string
+		match           bool
+	}{
+		{"GET", "GET", true},
+		{"get", "GET", true},
+		{"*", "DELETE", true},
+		{"", "POST", true},
+		{"GET", "POST", false},
+	}
+	for _, tc := range cases {
+		if got := MatchMethod(tc.pattern, tc.method); got != tc.match {
+			t.Fatalf("MatchMethod(%q, %q): expected %v, got %v", tc.pattern, tc.method, tc.match, got)
+		}
+	}
+}
+
+func TestMatchPath(t *testing.T) {
+	cases := []struct {
+		pattern, path string
+		match         bool
+	}{
+		{"/scans", "/scans", true},
+		{"/scans/{id}", "/scans/123", true},
+		{"/scans/{id}", "/scans/{scanId}", true},
+		{"/scans/{id}", "/api/scans/123", false},
+		{"/scans/{id}", "/scans/123/status", false},
+		{"/scans/*", "/scans", true},
+		{"/scans/*", "/scans/123/status", true},
+		{"/scans/*", "/scansx", false},
+		{"/*", "/anything/at/all", true},
+		{"", "/anything", true},
+	}
+	for _, tc := range cases {
+		if got := MatchPath(tc.pattern, tc.path); got != tc.match {
+			t.Fatalf("MatchPath(%q, %q): expected %v, got %v", tc.pattern, tc.path, tc.match, got)
+		}
+	}
+}
+
+func TestMatchPathSuffix(t *testing.T) {
+	cases := []struct {
+		tpl   string
+		act   string
+		match bool
+	}{
+		{"/scans/{id}", "/scans/123", true},
+		{"/scans/{id}", "/api/v1/scans/123", true},
+		{"/scans/{id}/status", "/scans/123/status", true},
+		{"/scans/{id}/status", "/x/y/scans/123/status", true},
+		{"/scans/{id}", "/scans", false},
+		{"/scans/{id}", "/scans/123/status", false},
+		{"/scans/{id}/status", "/scans/123/results", false},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.tpl+"->"+tc.act, func(t *testing.T) {
+			got := MatchPathSuffix(tc.tpl, tc.act)
+			if got != tc.match {
+				t.Fatalf("expected %v, got %v", tc.match, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package matcher
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Route is one "METHOD /path" entry of a route list such as ROUTES_DISABLE,
+// matched with MatchMethod and MatchPath.
+type Route struct {
+	Method string
+	Path   string
+}
+
+// ParseRoutes parses "METHOD /path" entries.
+func ParseRoutes(entries []string) ([]Route, error) {
+	out := make([]Route, 0, len(entries))
+	for _, e := range entries {
+		method, p, ok := strings.Cut(strings.TrimSpace(e), " ")
+		p = strings.TrimSpace(p)
+		if !ok || !strings.HasPrefix(p, "/") {
+			return nil, fmt.Errorf("invalid route %q (want \"METHOD /path\")", e)
+		}
+		out = append(out, Route{Method: strings.ToUpper(method), Path: p})
+	}
+	return out, nil
+}
+
+// Match reports whether the route covers method and path.
+func (r Route) Match(method, path string) bool {
+	return MatchMethod(r.Method, method) && MatchPath(r.Path, path)
+}
+
+// MatchAnyRoute reports whether any of routes covers method and path.
+func MatchAnyRoute(routes []Route, method, path string) bool {
+	for _, r := range routes {
+		if r.Match(method, path) {
+			return true
+		}
+	}
+	return false
+}
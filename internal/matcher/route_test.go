@@ -0,0 +1,29 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package matcher
+
+import "testing"
+
+func TestParseRoutes(t *testing.T) {
+	routes, err := ParseRoutes([]string{"get /scans/{id}", "* /admin/*"})
+	if err != nil {
+		t.Fatalf("ParseRoutes: %v", err)
+	}
+	if routes[0] != (Route{Method: "GET", Path: "/scans/{id}"}) {
+		t.Fatalf("unexpected route %+v", routes[0])
+	}
+	if !MatchAnyRoute(routes, "GET", "/scans/{id}") || !MatchAnyRoute(routes, "DELETE", "/admin/users/{id}") {
+		t.Fatalf("expected the routes to match")
+	}
+	if MatchAnyRoute(routes, "POST", "/scans/{id}") {
+		t.Fatalf("expected the method to be checked")
+	}
+
+	for _, bad := range []string{"GET", "/scans", "GET scans"} {
+		if _, err := ParseRoutes([]string{bad}); err == nil {
+			t.Fatalf("expected %q to be rejected", bad)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package matcher
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+)
+
+// Request is the view of a request that rules are matched against. Header
+// and Query hold the first value by name; header names are canonical.
+type Request struct {
+	Method string
+	Path   string
+	Header map[string]string
+	Query  map[string]string
+	// Body is the decoded JSON request body, nil when there is none.
+	Body any
+}
+
+// Rule is a set of request conditions that must all hold. Method and Path
+// follow MatchMethod and MatchPath; Headers and Query map names to the
+// matcher for their first value, Body maps JSONPath expressions over the
+// JSON request body (the root $ followed by .name, ['name'] and [index]
+// steps) to the matcher for the value found there.
+type Rule struct {
+	Method  string           `json:"method,omitempty"`
+	Path    string           `json:"path,omitempty"`
+	Headers map[string]Value `json:"headers,omitempty"`
+	Query   map[string]Value `json:"query,omitempty"`
+	Body    map[string]Value `json:"body,omitempty"`
+
+	bodyPaths map[string]jsonPath
+}
+
+func (r *Rule) UnmarshalJSON(b []byte) error {
+	type plain Rule
+	var p plain
+	if err := json.Unmarshal(b, &p); err != nil {
+		return err
+	}
+	*r = Rule(p)
+	return r.Compile()
+}
+
+// Compile parses the Body expressions of a rule built in code. Rules decoded
+// from JSON are compiled already.
+func (r *Rule) Compile() error {
+	r.bodyPaths = make(map[string]jsonPath, len(r.Body))
+	for expr := range r.Body {
+		path, err := parseJSONPath(expr)
+		if err != nil {
+			return err
+		}
+		r.bodyPaths[expr] = path
+	}
+	return nil
+}
+
+// Conditions counts the header, query and body conditions, to rank rules by
+// specificity.
+func (r *Rule) Conditions() int {
+	if r == nil {
+		return 0
+	}
+	return len(r.Headers) + len(r.Query) + len(r.Body)
+}
+
+// Matches reports whether req meets every condition. A nil rule matches any
+// request.
+func (r *Rule) Matches(req Request) bool {
+	return len(r.Mismatches(req)) == 0
+}
+
+// Mismatches describes every condition req fails, in the order method,
+// path, headers, query and body, each sorted by name.
+func (r *Rule) Mismatches(req Request) []string {
+	if r == nil {
+		return nil
+	}
+	var out []string
+	if !MatchMethod(r.Method, req.Method) {
+		out = append(out, fmt.Sprintf("method %s is not %s", req.Method, r.Method))
+	}
+	if !MatchPath(r.Path, req.Path) {
+		out = append(out, fmt.Sprintf("path %s does not match %s", req.Path, r.Path))
+	}
+	for _, name := range sortedKeys(r.Headers) {
+		v, ok := req.Header[http.CanonicalHeaderKey(name)]
+		out = appendMismatch(out, "header "+name, v, ok, r.Headers[name])
+	}
+	for _, name := range sortedKeys(r.Query) {
+		v, ok := req.Query[name]
+		out = appendMismatch(out, "query "+name, v, ok, r.Query[name])
+	}
+	for _, expr := range sortedKeys(r.Body) {
+		v, ok := r.bodyPaths[expr].lookup(req.Body)
+		out = appendMismatch(out, "body "+expr, jsonScalarString(v), ok, r.Body[expr])
+	}
+	return out
+}
+
+func appendMismatch(out []string, what, got string, ok bool, want Value) []string {
+	if !ok {
+		return append(out, what+" is missing")
+	}
+	if !want.Match(got) {
+		return append(out, fmt.Sprintf("%s %q does not match %s", what, got, want))
+	}
+	return out
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,72 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package matcher
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRule_Headers(t *testing.T) {
+	var r Rule
+	require.NoError(t, json.Unmarshal([]byte(`{"headers":{"x-role":"viewer","Accept-Language":{"prefix":"de"}}}`), &r))
+	require.Equal(t, 2, r.Conditions())
+
+	require.True(t, r.Matches(Request{Header: map[string]string{"X-Role": "viewer", "Accept-Language": "de"}}))
+	require.False(t, r.Matches(Request{Header: map[string]string{"X-Role": "viewer"}}))
+
+	var none *Rule
+	require.True(t, none.Matches(Request{}))
+	require.Equal(t, 0, none.Conditions())
+}
+
+func TestRule_Body(t *testing.T) {
+	var r Rule
+	require.NoError(t, json.Unmarshal([]byte(`{"body":{"$.action":"cancel","$.items[0].sku":{"prefix":"SKU-"}}}`), &r))
+	require.Equal(t, 2, r.Conditions())
+
+	body := map[string]any{"action": "cancel", "items": []any{map[string]any{"sku": "SKU-1"}}}
+	require.True(t, r.Matches(Request{Body: body}))
+	body["action"] = "approve"
+	require.False(t, r.Matches(Request{Body: body}))
+	require.False(t, r.Matches(Request{}))
+
+	require.Error(t, json.Unmarshal([]byte(`{"body":{"action":"cancel"}}`), &r))
+}
+
+func TestRule_Mismatches(t *testing.T) {
+	var r Rule
+	require.NoError(t, json.Unmarshal([]byte(`{
+	  "method": "POST",
+	  "path": "/scans/*",
+	  "headers": {"Content-Type": {"wildcard": "application/*json"}, "X-Request-Id": true},
+	  "query": {"dryRun": "true"},
+	  "body": {"$.kind": "full"}
+	}`), &r))
+
+	require.Empty(t, r.Mismatches(Request{
+		Method: "post",
+		Path:   "/scans/1/start",
+		Header: map[string]string{"Content-Type": "application/vnd.api+json", "X-Request-Id": "r1"},
+		Query:  map[string]string{"dryRun": "true"},
+		Body:   map[string]any{"kind": "full"},
+	}))
+
+	require.Equal(t, []string{
+		"method GET is not POST",
+		"path /items does not match /scans/*",
+		`header Content-Type "text/plain" does not match wildcard "application/*json"`,
+		"header X-Request-Id is missing",
+		"query dryRun is missing",
+		`body $.kind "quick" does not match exact "full"`,
+	}, r.Mismatches(Request{
+		Method: "GET",
+		Path:   "/items",
+		Header: map[string]string{"Content-Type": "text/plain"},
+		Body:   map[string]any{"kind": "quick"},
+	}))
+}
@@ -0,0 +1,106 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+// Package matcher holds the request matching shared by sample variants,
+// sample expectations, scenario rules and route lists, so that all of them
+// accept the same syntax.
+package matcher
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Value matches a string exactly, by prefix, by wildcard or by regular
+// expression. In JSON it is a plain string for an exact match, true for any
+// value, or an object with exactly one of "exact", "prefix", "wildcard" and
+// "regex".
+type Value struct {
+	kind  string
+	value string
+	re    *regexp.Regexp
+}
+
+// Exact returns a Value matching s only.
+func Exact(s string) Value {
+	return Value{kind: "exact", value: s}
+}
+
+// Any returns a Value matching every string.
+func Any() Value {
+	return Value{kind: "any"}
+}
+
+// Wildcard returns a Value where * in pattern stands for any run of
+// characters, e.g. "application/*+json". The whole string must match.
+func Wildcard(pattern string) Value {
+	parts := strings.Split(pattern, "*")
+	for i, p := range parts {
+		parts[i] = regexp.QuoteMeta(p)
+	}
+	re := regexp.MustCompile("^" + strings.Join(parts, ".*") + "$")
+	return Value{kind: "wildcard", value: pattern, re: re}
+}
+
+func (m *Value) UnmarshalJSON(b []byte) error {
+	var present bool
+	if err := json.Unmarshal(b, &present); err == nil && present {
+		*m = Any()
+		return nil
+	}
+
+	var exact string
+	if err := json.Unmarshal(b, &exact); err == nil {
+		*m = Exact(exact)
+		return nil
+	}
+
+	var obj map[string]string
+	if err := json.Unmarshal(b, &obj); err != nil || len(obj) != 1 {
+		return fmt.Errorf("matcher must be a string, true or an object with one of exact, prefix, wildcard, regex")
+	}
+	for kind, value := range obj {
+		switch kind {
+		case "exact", "prefix":
+			*m = Value{kind: kind, value: value}
+		case "wildcard":
+			*m = Wildcard(value)
+		case "regex":
+			re, err := regexp.Compile(value)
+			if err != nil {
+				return fmt.Errorf("invalid regex matcher %q: %w", value, err)
+			}
+			*m = Value{kind: kind, value: value, re: re}
+		default:
+			return fmt.Errorf("unknown matcher %q (want exact, prefix, wildcard or regex)", kind)
+		}
+	}
+	return nil
+}
+
+// Match reports whether s satisfies the matcher. Regular expressions are
+// unanchored; use ^ and $ to match the whole value.
+func (m Value) Match(s string) bool {
+	switch m.kind {
+	case "any":
+		return true
+	case "exact":
+		return s == m.value
+	case "prefix":
+		return strings.HasPrefix(s, m.value)
+	case "wildcard", "regex":
+		return m.re.MatchString(s)
+	}
+	return false
+}
+
+// String describes the matcher for diagnostics, e.g. `prefix "Bearer "`.
+func (m Value) String() string {
+	if m.kind == "any" {
+		return "any value"
+	}
+	return fmt.Sprintf("%s %q", m.kind, m.value)
+}
@@ -0,0 +1,48 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package matcher
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValue_Unmarshal(t *testing.T) {
+	cases := []struct {
+		in    string
+		value string
+		want  bool
+	}{
+		{`"de"`, "de", true},
+		{`"de"`, "de-CH", false},
+		{`{"exact":"de"}`, "de", true},
+		{`{"prefix":"de"}`, "de-CH", true},
+		{`{"prefix":"de"}`, "en", false},
+		{`{"regex":"^beta-[0-9]+$"}`, "beta-7", true},
+		{`{"regex":"beta"}`, "x-beta-y", true},
+		{`{"wildcard":"application/*+json"}`, "application/problem+json", true},
+		{`{"wildcard":"application/*+json"}`, "application/json", false},
+		{`{"wildcard":"v1.*"}`, "v1x2", false},
+		{`true`, "anything", true},
+	}
+	for _, tc := range cases {
+		var m Value
+		require.NoError(t, json.Unmarshal([]byte(tc.in), &m), tc.in)
+		require.Equal(t, tc.want, m.Match(tc.value), "%s vs %q", tc.in, tc.value)
+	}
+
+	for _, bad := range []string{`{"regex":"("}`, `{"suffix":"x"}`, `{"exact":"a","prefix":"b"}`, `{}`, `1`, `false`} {
+		var m Value
+		require.Error(t, json.Unmarshal([]byte(bad), &m), bad)
+	}
+}
+
+func TestValue_String(t *testing.T) {
+	require.Equal(t, `prefix "Bearer "`, Value{kind: "prefix", value: "Bearer "}.String())
+	require.Equal(t, `wildcard "*.csv"`, Wildcard("*.csv").String())
+	require.Equal(t, "any value", Any().String())
+}
@@ -0,0 +1,159 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package openapi
+
+import (
+	"math"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// BoundaryKind selects which edge of the schema TryGetBoundaryBody generates.
+type BoundaryKind string
+
+const (
+	// BoundaryMin yields minimum numbers, the shortest strings, the fewest
+	// array items and null wherever the schema is nullable.
+	BoundaryMin BoundaryKind = "min"
+	// BoundaryMax yields maximum numbers, the longest strings and the most
+	// array items.
+	BoundaryMax BoundaryKind = "max"
+)
+
+// defaultMaxStringLength is the length of "max" strings without maxLength.
+const defaultMaxStringLength = 1024
+
+// TryGetBoundaryBody generates the operation's success response body from
+// its schema with boundary values of the given kind. Spec examples are
+// ignored; operations without a response schema return false.
+func (p *SpecProvider) TryGetBoundaryBody(swaggerPath, method string, kind BoundaryKind) ([]byte, string, bool) {
+	op := p.FindOperation(swaggerPath, method)
+	if op == nil || op.Responses == nil {
+		return nil, "", false
+	}
+	respRef := p.pickBestResponseRef(op.Responses)
+	if respRef == nil {
+		return nil, "", false
+	}
+	gen := schemaExampleGenerator{src: boundarySource{max: kind == BoundaryMax}}
+	return p.generateFromResponseSchema(gen, swaggerPath, method, respRef.Value)
+}
+
+// boundarySource makes every choice at one edge of the schema.
+type boundarySource struct {
+	max bool
+}
+
+func (b boundarySource) choose(n int) int {
+	if b.max {
+		return n - 1
+	}
+	return 0
+}
+
+func (b boundarySource) arrayLen(s *openapi3.Schema) int {
+	if b.max {
+		return maxItemsOf(s)
+	}
+	return int(s.MinItems)
+}
+
+func (b boundarySource) mapKey() string {
+	if b.max {
+		return strings.Repeat("k", defaultMaxStringLength)
+	}
+	return ""
+}
+
+// null answers nullable schemas with null in min mode.
+func (b boundarySource) null(s *openapi3.Schema) bool {
+	return !b.max && (s.Nullable || (s.Type != nil && s.Type.Includes("null")))
+}
+
+func (b boundarySource) scalar(s *openapi3.Schema) (any, bool) {
+	switch {
+	case s.Type == nil:
+		return nil, false
+	case s.Type.Is("string"):
+		return b.string(s), true
+	case s.Type.Is("integer"):
+		lo, hi := int64(math.MinInt64), int64(math.MaxInt64)
+		if s.Format == "int32" {
+			lo, hi = math.MinInt32, math.MaxInt32
+		}
+		if b.max {
+			if s.Max == nil {
+				return hi, true
+			}
+			// A maximum at 2^63 saturates; float64 cannot hold MaxInt64 itself.
+			v := clampInt64(math.Floor(*s.Max))
+			if s.ExclusiveMax && float64(v) == *s.Max && v != math.MaxInt64 {
+				v--
+			}
+			return v, true
+		}
+		if s.Min == nil {
+			return lo, true
+		}
+		v := clampInt64(math.Ceil(*s.Min))
+		if s.ExclusiveMin && float64(v) == *s.Min {
+			v++
+		}
+		return v, true
+	case s.Type.Is("number"):
+		limit := math.MaxFloat64
+		if s.Format == "float" {
+			limit = math.MaxFloat32
+		}
+		if b.max {
+			if s.Max == nil {
+				return limit, true
+			}
+			if s.ExclusiveMax {
+				return math.Nextafter(*s.Max, math.Inf(-1)), true
+			}
+			return *s.Max, true
+		}
+		if s.Min == nil {
+			return -limit, true
+		}
+		if s.ExclusiveMin {
+			return math.Nextafter(*s.Min, math.Inf(1)), true
+		}
+		return *s.Min, true
+	case s.Type.Is("boolean"):
+		return b.max, true
+	}
+	return nil, false
+}
+
+func (b boundarySource) string(s *openapi3.Schema) string {
+	switch s.Format {
+	case "date-time":
+		if b.max {
+			return "9999-12-31T23:59:59Z"
+		}
+		return "0001-01-01T00:00:00Z"
+	case "date":
+		if b.max {
+			return "9999-12-31"
+		}
+		return "0001-01-01"
+	case "uuid":
+		if b.max {
+			return "ffffffff-ffff-ffff-ffff-ffffffffffff"
+		}
+		return "00000000-0000-0000-0000-000000000000"
+	}
+	if b.max {
+		n := defaultMaxStringLength
+		if s.MaxLength != nil {
+			n = int(*s.MaxLength)
+		}
+		return strings.Repeat("x", max(n, int(s.MinLength)))
+	}
+	return strings.Repeat("x", int(s.MinLength))
+}
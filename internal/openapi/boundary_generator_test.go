@@ -0,0 +1,113 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func boundaryTestSchema() *openapi3.Schema {
+	maxLen, maxItems := uint64(8), uint64(2)
+	lo, hi := 1.0, 10.0
+	return &openapi3.Schema{
+		Type: &openapi3.Types{"object"},
+		Properties: openapi3.Schemas{
+			"count":  {Value: &openapi3.Schema{Type: &openapi3.Types{"integer"}, Min: &lo, Max: &hi, ExclusiveMax: true}},
+			"id":     {Value: &openapi3.Schema{Type: &openapi3.Types{"integer"}, Format: "int32"}},
+			"name":   {Value: &openapi3.Schema{Type: &openapi3.Types{"string"}, MinLength: 2, MaxLength: &maxLen}},
+			"note":   {Value: &openapi3.Schema{Type: &openapi3.Types{"string"}, Nullable: true}},
+			"tags":   {Value: &openapi3.Schema{Type: &openapi3.Types{"array"}, MaxItems: &maxItems, Items: &openapi3.SchemaRef{Value: &openapi3.Schema{Type: &openapi3.Types{"string"}}}}},
+			"status": {Value: &openapi3.Schema{Type: &openapi3.Types{"string"}, Enum: []any{"open", "closed"}}},
+		},
+	}
+}
+
+func decodeBoundary(t *testing.T, b []byte) map[string]any {
+	t.Helper()
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var m map[string]any
+	if err := dec.Decode(&m); err != nil {
+		t.Fatalf("invalid json %s: %v", b, err)
+	}
+	return m
+}
+
+func TestTryGetBoundaryBody_Min(t *testing.T) {
+	p := randomTestProvider(boundaryTestSchema())
+
+	b, ct, ok := p.TryGetBoundaryBody("/x", "get", BoundaryMin)
+	if !ok || ct != "application/json" {
+		t.Fatalf("expected JSON body, got ok=%v ct=%q", ok, ct)
+	}
+	m := decodeBoundary(t, b)
+	if m["count"] != json.Number("1") || m["id"] != json.Number(strconv.Itoa(math.MinInt32)) {
+		t.Fatalf("unexpected numbers: %s", b)
+	}
+	if m["name"] != "xx" || m["note"] != nil || m["status"] != "open" {
+		t.Fatalf("unexpected strings: %s", b)
+	}
+	if tags, _ := m["tags"].([]any); tags == nil || len(tags) != 0 {
+		t.Fatalf("expected empty tags, got %s", b)
+	}
+}
+
+func TestTryGetBoundaryBody_Max(t *testing.T) {
+	p := randomTestProvider(boundaryTestSchema())
+
+	b, _, ok := p.TryGetBoundaryBody("/x", "get", BoundaryMax)
+	if !ok {
+		t.Fatalf("expected ok")
+	}
+	m := decodeBoundary(t, b)
+	if m["count"] != json.Number("9") || m["id"] != json.Number(strconv.Itoa(math.MaxInt32)) {
+		t.Fatalf("unexpected numbers: %s", b)
+	}
+	if m["name"] != strings.Repeat("x", 8) || m["status"] != "closed" {
+		t.Fatalf("unexpected strings: %s", b)
+	}
+	if note, _ := m["note"].(string); len(note) != defaultMaxStringLength {
+		t.Fatalf("expected %d character note, got %d", defaultMaxStringLength, len(note))
+	}
+	if tags, _ := m["tags"].([]any); len(tags) != 2 {
+		t.Fatalf("expected 2 tags, got %s", b)
+	}
+}
+
+func TestTryGetBoundaryBody_Int64Limits(t *testing.T) {
+	top := float64(math.MaxInt64)
+	p := randomTestProvider(&openapi3.Schema{
+		Type: &openapi3.Types{"object"},
+		Properties: openapi3.Schemas{
+			"plain":    {Value: &openapi3.Schema{Type: &openapi3.Types{"integer"}}},
+			"big":      {Value: &openapi3.Schema{Type: &openapi3.Types{"integer"}, Format: "int64"}},
+			"declared": {Value: &openapi3.Schema{Type: &openapi3.Types{"integer"}, Format: "int64", Max: &top}},
+		},
+	})
+
+	maxStr, minStr := strconv.FormatInt(math.MaxInt64, 10), strconv.FormatInt(math.MinInt64, 10)
+	b, _, _ := p.TryGetBoundaryBody("/x", "get", BoundaryMax)
+	m := decodeBoundary(t, b)
+	for _, k := range []string{"plain", "big", "declared"} {
+		if m[k] != json.Number(maxStr) {
+			t.Fatalf("max %s: expected %s, got %s", k, maxStr, b)
+		}
+	}
+
+	b, _, _ = p.TryGetBoundaryBody("/x", "get", BoundaryMin)
+	m = decodeBoundary(t, b)
+	for _, k := range []string{"plain", "big"} {
+		if m[k] != json.Number(minStr) {
+			t.Fatalf("min %s: expected %s, got %s", k, minStr, b)
+		}
+	}
+}
@@ -0,0 +1,269 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package openapi
+
+import (
+	"math"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// maxGenerateDepth bounds recursion through nested and self-referencing schemas.
+const maxGenerateDepth = 6
+
+// SpecOption customizes a SpecProvider created by NewSpecProvider.
+type SpecOption func(*SpecProvider)
+
+// WithExampleGenerator replaces the generator behind FALLBACK_MODE=openapi_examples
+// for responses without an explicit spec example.
+func WithExampleGenerator(g IExampleGenerator) SpecOption {
+	return func(p *SpecProvider) { p.exampleGen = g }
+}
+
+// WithRandomGenerator replaces the generator behind FALLBACK_MODE=random.
+func WithRandomGenerator(g IExampleGenerator) SpecOption {
+	return func(p *SpecProvider) { p.randomGen = g }
+}
+
+// NewSchemaExampleGenerator returns the built-in deterministic generator:
+// first enum values, one array item and fixed placeholder scalars.
+func NewSchemaExampleGenerator() IExampleGenerator {
+	return schemaExampleGenerator{src: staticSource{}}
+}
+
+// NewRandomExampleGenerator returns the built-in generator for random bodies
+// within the schema's constraints.
+func NewRandomExampleGenerator() IExampleGenerator {
+	return schemaExampleGenerator{src: randomSource{}}
+}
+
+func (p *SpecProvider) exampleGenerator() IExampleGenerator {
+	if p.exampleGen != nil {
+		return p.exampleGen
+	}
+	return NewSchemaExampleGenerator()
+}
+
+func (p *SpecProvider) randomGenerator() IExampleGenerator {
+	if p.randomGen != nil {
+		return p.randomGen
+	}
+	return NewRandomExampleGenerator()
+}
+
+type schemaExampleGenerator struct {
+	src valueSource
+}
+
+func (g schemaExampleGenerator) GenerateExample(req ExampleRequest) (any, bool) {
+	if req.Schema == nil || req.Schema.Value == nil {
+		return nil, false
+	}
+	return generateValue(g.src, req.Schema, 0), true
+}
+
+// valueSource makes the choices a schema leaves open. generateValue walks the
+// schema and asks the source whenever there is more than one valid answer.
+type valueSource interface {
+	// choose picks one of n enum values or oneOf/anyOf alternatives.
+	choose(n int) int
+	arrayLen(s *openapi3.Schema) int
+	// mapKey names the entry generated for additionalProperties.
+	mapKey() string
+	// scalar returns a string, integer, number or boolean value, or false
+	// for other schema types.
+	scalar(s *openapi3.Schema) (any, bool)
+}
+
+// nullSource is implemented by sources that may answer a nullable schema
+// with null.
+type nullSource interface {
+	null(s *openapi3.Schema) bool
+}
+
+func generateValue(src valueSource, ref *openapi3.SchemaRef, depth int) any {
+	if depth > maxGenerateDepth || ref == nil || ref.Value == nil {
+		return map[string]any{}
+	}
+	s := ref.Value
+
+	if ns, ok := src.(nullSource); ok && ns.null(s) {
+		return nil
+	}
+
+	if len(s.Enum) > 0 {
+		return s.Enum[src.choose(len(s.Enum))]
+	}
+	if len(s.OneOf) > 0 {
+		return generateValue(src, s.OneOf[src.choose(len(s.OneOf))], depth+1)
+	}
+	if len(s.AnyOf) > 0 {
+		return generateValue(src, s.AnyOf[src.choose(len(s.AnyOf))], depth+1)
+	}
+	if len(s.AllOf) > 0 {
+		out := map[string]any{}
+		for _, part := range s.AllOf {
+			if m, ok := generateValue(src, part, depth+1).(map[string]any); ok {
+				for k, v := range m {
+					out[k] = v
+				}
+			}
+		}
+		return out
+	}
+
+	switch {
+	case s.Type != nil && s.Type.Is("array"):
+		if s.Items == nil {
+			return []any{}
+		}
+		n := src.arrayLen(s)
+		out := make([]any, 0, n)
+		for i := 0; i < n; i++ {
+			out = append(out, generateValue(src, s.Items, depth+1))
+		}
+		return out
+
+	case (s.Type != nil && s.Type.Is("object")) || len(s.Properties) > 0 || s.AdditionalProperties.Schema != nil:
+		out := map[string]any{}
+		if s.AdditionalProperties.Schema != nil {
+			out[src.mapKey()] = generateValue(src, s.AdditionalProperties.Schema, depth+1)
+			return out
+		}
+		if s.AdditionalProperties.Has != nil && *s.AdditionalProperties.Has {
+			out[src.mapKey()] = "value"
+		}
+		// Walk properties in sorted order so the source is asked the same
+		// questions in the same order on every run.
+		for _, name := range sortedKeys(s.Properties) {
+			out[name] = generateValue(src, s.Properties[name], depth+1)
+		}
+		return out
+	}
+
+	if v, ok := src.scalar(s); ok {
+		return v
+	}
+	return map[string]any{"ok": true}
+}
+
+// staticSource always makes the same choices, so bodies are stable across
+// requests and restarts.
+type staticSource struct{}
+
+func (staticSource) choose(int) int                { return 0 }
+func (staticSource) arrayLen(*openapi3.Schema) int { return 1 }
+func (staticSource) mapKey() string                { return "key" }
+func (staticSource) scalar(s *openapi3.Schema) (any, bool) {
+	switch {
+	case s.Type == nil:
+		return nil, false
+	case s.Type.Is("string"):
+		if s.Format == "date-time" {
+			return "2026-01-28T00:00:00Z", true
+		}
+		return "string", true
+	case s.Type.Is("integer"):
+		switch s.Format {
+		case "int32":
+			return clampInt(s, math.MaxInt32), true
+		case "int64":
+			return clampInt(s, math.MaxInt64), true
+		}
+		return 0, true
+	case s.Type.Is("number"):
+		switch s.Format {
+		case "float":
+			return float32(clampFloat(s, float64(float32(math.Pi)))), true
+		case "double":
+			return clampFloat(s, math.Pi), true
+		}
+		return 0.0, true
+	case s.Type.Is("boolean"):
+		return true, true
+	}
+	return nil, false
+}
+
+// clampInt keeps a format sentinel within the schema's minimum and maximum.
+func clampInt(s *openapi3.Schema, v int64) int64 {
+	if s.Max != nil && float64(v) > *s.Max {
+		v = int64(math.Floor(*s.Max))
+		if s.ExclusiveMax {
+			v--
+		}
+	}
+	if s.Min != nil && float64(v) < *s.Min {
+		v = int64(math.Ceil(*s.Min))
+		if s.ExclusiveMin {
+			v++
+		}
+	}
+	return v
+}
+
+func clampFloat(s *openapi3.Schema, v float64) float64 {
+	if s.Max != nil && v > *s.Max {
+		v = *s.Max
+	}
+	if s.Min != nil && v < *s.Min {
+		v = *s.Min
+	}
+	return v
+}
+
+// randomSource draws every choice at random within the schema's constraints.
+type randomSource struct{}
+
+func (randomSource) choose(n int) int { return randIntN(n) }
+
+func (randomSource) arrayLen(s *openapi3.Schema) int {
+	return randBetween(int(s.MinItems), maxItemsOf(s))
+}
+
+func (randomSource) mapKey() string { return randString(4, 8) }
+
+func (randomSource) scalar(s *openapi3.Schema) (any, bool) {
+	switch {
+	case s.Type == nil:
+		return nil, false
+	case s.Type.Is("string"):
+		return randStringForSchema(s), true
+	case s.Type.Is("integer"):
+		if s.Format == "int64" && s.Max == nil {
+			// Above 2^53, where float64-based JSON decoders lose precision.
+			lo := int64(1)<<53 + 1
+			if s.Min != nil && *s.Min > float64(lo) {
+				lo = clampInt64(math.Ceil(*s.Min))
+			}
+			span := min(int64(math.MaxInt64)-lo, 1<<53)
+			if span <= 0 {
+				return lo, true
+			}
+			return lo + randInt64N(span), true
+		}
+		lo, hi := numberBounds(s, 0, 1000)
+		l, h := clampInt64(math.Ceil(lo)), clampInt64(math.Floor(hi))
+		if s.Format == "int32" {
+			l, h = min(max(l, math.MinInt32), math.MaxInt32), min(max(h, math.MinInt32), math.MaxInt32)
+		}
+		return randInt64Between(l, h), true
+	case s.Type.Is("number"):
+		lo, hi := numberBounds(s, 0, 1000)
+		raw := lo + randFloat64()*(hi-lo)
+		v := math.Round(raw*100) / 100
+		if v < lo || v > hi {
+			// Rounding to cents stepped over a narrow or exclusive bound.
+			v = raw
+		}
+		if s.Format == "float" {
+			return float32(v), true
+		}
+		return v, true
+	case s.Type.Is("boolean"):
+		return randIntN(2) == 1, true
+	}
+	return nil, false
+}
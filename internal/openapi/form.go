@@ -0,0 +1,119 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime"
+	"mime/multipart"
+	"net/url"
+	"strconv"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+const (
+	mediaTypeForm      = "application/x-www-form-urlencoded"
+	mediaTypeMultipart = "multipart/form-data"
+	maxMultipartMemory = 32 << 20
+)
+
+// validateFormBody decodes a URL-encoded or multipart body into an object
+// and validates it against the schema. Field values are converted to the
+// property types where possible; values that do not convert are kept as
+// strings so the schema reports them. File parts count as strings holding
+// the file name, which satisfies format: binary properties.
+func validateFormBody(body []byte, contentType string, schema *openapi3.Schema) []FieldError {
+	values, files, err := decodeForm(body, contentType)
+	if err != nil {
+		return []FieldError{{Field: "/", Reason: "malformed form body: " + err.Error()}}
+	}
+
+	obj := map[string]any{}
+	for name, raw := range values {
+		obj[name] = formValue(raw, propertySchema(schema, name))
+	}
+	for name, fhs := range files {
+		names := make([]string, 0, len(fhs))
+		for _, fh := range fhs {
+			names = append(names, fh.Filename)
+		}
+		obj[name] = formValue(names, propertySchema(schema, name))
+	}
+
+	return fieldErrors(schema.VisitJSON(obj, openapi3.MultiErrors(), openapi3.VisitAsRequest()))
+}
+
+func decodeForm(body []byte, contentType string) (url.Values, map[string][]*multipart.FileHeader, error) {
+	base, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, nil, err
+	}
+	if base != mediaTypeMultipart {
+		values, err := url.ParseQuery(string(body))
+		return values, nil, err
+	}
+
+	form, err := multipart.NewReader(bytes.NewReader(body), params["boundary"]).ReadForm(maxMultipartMemory)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() { _ = form.RemoveAll() }()
+	return form.Value, form.File, nil
+}
+
+func propertySchema(schema *openapi3.Schema, name string) *openapi3.Schema {
+	if ref := schema.Properties[name]; ref != nil {
+		return ref.Value
+	}
+	return nil
+}
+
+// formValue converts the raw values of one field: arrays keep every value,
+// everything else takes the first.
+func formValue(raw []string, schema *openapi3.Schema) any {
+	if schema != nil && schema.Type.Is(openapi3.TypeArray) {
+		var items *openapi3.Schema
+		if schema.Items != nil {
+			items = schema.Items.Value
+		}
+		out := make([]any, 0, len(raw))
+		for _, s := range raw {
+			out = append(out, scalarFormValue(s, items))
+		}
+		return out
+	}
+	if len(raw) == 0 {
+		return ""
+	}
+	return scalarFormValue(raw[0], schema)
+}
+
+func scalarFormValue(s string, schema *openapi3.Schema) any {
+	if schema == nil {
+		return s
+	}
+	switch {
+	case schema.Type.Is(openapi3.TypeInteger):
+		if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return n
+		}
+	case schema.Type.Is(openapi3.TypeNumber):
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return f
+		}
+	case schema.Type.Is(openapi3.TypeBoolean):
+		if b, err := strconv.ParseBool(s); err == nil {
+			return b
+		}
+	case schema.Type.Is(openapi3.TypeObject):
+		var v any
+		if err := json.Unmarshal([]byte(s), &v); err == nil {
+			return v
+		}
+	}
+	return s
+}
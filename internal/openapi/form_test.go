@@ -0,0 +1,111 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package openapi
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func formTestValidator(t *testing.T) IValidator {
+	t.Helper()
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(`{
+	  "openapi":"3.0.3",
+	  "info":{"title":"t","version":"1"},
+	  "paths":{"/upload":{"post":{
+	    "requestBody":{"required":true,"content":{
+	      "application/x-www-form-urlencoded":{"schema":{
+	        "type":"object","required":["name"],
+	        "properties":{"name":{"type":"string"},"age":{"type":"integer","minimum":0},"tags":{"type":"array","items":{"type":"string"}}}
+	      }},
+	      "multipart/form-data":{"schema":{
+	        "type":"object","required":["file","title"],
+	        "properties":{"title":{"type":"string"},"file":{"type":"string","format":"binary"},"draft":{"type":"boolean"}}
+	      }}
+	    }},
+	    "responses":{"200":{"description":"ok"}}
+	  }}}
+	}`))
+	require.NoError(t, err)
+	return NewValidator(&SpecProvider{spec: &Spec{Doc3: doc}, log: logrus.New()})
+}
+
+func TestValidator_ValidateRequestBody_FormURLEncoded(t *testing.T) {
+	v := formTestValidator(t)
+
+	post := func(body string) []FieldError {
+		r := httptest.NewRequest(http.MethodPost, "/upload", strings.NewReader(body))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		errs, err := v.ValidateRequestBody(r, "/upload", "post")
+		require.NoError(t, err)
+		return errs
+	}
+
+	require.Empty(t, post("name=a&age=3&tags=x&tags=y"))
+
+	errs := post("age=abc")
+	fields := map[string]bool{}
+	for _, e := range errs {
+		fields[e.Field] = true
+	}
+	require.Equal(t, map[string]bool{"/name": true, "/age": true}, fields)
+
+	errs = post("name=a&age=-1")
+	require.Len(t, errs, 1)
+	require.Equal(t, "/age", errs[0].Field)
+}
+
+func TestValidator_ValidateRequestBody_Multipart(t *testing.T) {
+	v := formTestValidator(t)
+
+	post := func(write func(mw *multipart.Writer)) []FieldError {
+		var buf bytes.Buffer
+		mw := multipart.NewWriter(&buf)
+		write(mw)
+		require.NoError(t, mw.Close())
+
+		r := httptest.NewRequest(http.MethodPost, "/upload", &buf)
+		r.Header.Set("Content-Type", mw.FormDataContentType())
+		errs, err := v.ValidateRequestBody(r, "/upload", "post")
+		require.NoError(t, err)
+		return errs
+	}
+
+	require.Empty(t, post(func(mw *multipart.Writer) {
+		_ = mw.WriteField("title", "report")
+		_ = mw.WriteField("draft", "true")
+		fw, _ := mw.CreateFormFile("file", "report.pdf")
+		_, _ = fw.Write([]byte("%PDF"))
+	}))
+
+	errs := post(func(mw *multipart.Writer) {
+		_ = mw.WriteField("title", "report")
+		_ = mw.WriteField("draft", "maybe")
+	})
+	fields := map[string]bool{}
+	for _, e := range errs {
+		fields[e.Field] = true
+	}
+	require.Equal(t, map[string]bool{"/file": true, "/draft": true}, fields)
+}
+
+func TestValidator_ValidateRequestBody_MalformedMultipart(t *testing.T) {
+	v := formTestValidator(t)
+
+	r := httptest.NewRequest(http.MethodPost, "/upload", strings.NewReader("not multipart"))
+	r.Header.Set("Content-Type", "multipart/form-data; boundary=xyz")
+	errs, err := v.ValidateRequestBody(r, "/upload", "post")
+	require.NoError(t, err)
+	require.Len(t, errs, 1)
+	require.Contains(t, errs[0].Reason, "malformed form body")
+}
@@ -0,0 +1,163 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package openapi
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// FuzzRequestBody generates n schema-valid request bodies and n bodies that
+// each violate the request schema in exactly one way.
+func (p *SpecProvider) FuzzRequestBody(swaggerPath, method string, n int) (*FuzzResult, bool) {
+	schema := p.requestBodySchema(swaggerPath, method)
+	if schema == nil {
+		return nil, false
+	}
+
+	out := &FuzzResult{Valid: []any{}, Invalid: []FuzzCase{}}
+	for i := 0; i < n; i++ {
+		out.Valid = append(out.Valid, generateValue(randomSource{}, schema, 0))
+	}
+
+	mutations := schemaMutations(schema.Value)
+	for i := 0; i < n && len(mutations) > 0; i++ {
+		m := mutations[i%len(mutations)]
+		out.Invalid = append(out.Invalid, FuzzCase{
+			Reason: m.reason,
+			Body:   m.apply(generateValue(randomSource{}, schema, 0)),
+		})
+	}
+	return out, true
+}
+
+func (p *SpecProvider) requestBodySchema(swaggerPath, method string) *openapi3.SchemaRef {
+	op := p.FindOperation(swaggerPath, method)
+	if op == nil || op.RequestBody == nil || op.RequestBody.Value == nil {
+		return nil
+	}
+	for _, ct := range []string{"application/json", "application/problem+json", "*/*"} {
+		if mt := op.RequestBody.Value.Content.Get(ct); mt != nil && mt.Schema != nil && mt.Schema.Value != nil {
+			return mt.Schema
+		}
+	}
+	return nil
+}
+
+type mutation struct {
+	reason string
+	apply  func(valid any) any
+}
+
+func schemaMutations(s *openapi3.Schema) []mutation {
+	var out []mutation
+
+	if s.Type != nil && s.Type.Is("array") {
+		return []mutation{{"body is an object, expected array", func(any) any { return map[string]any{} }}}
+	}
+	if (s.Type == nil || !s.Type.Is("object")) && len(s.Properties) == 0 {
+		return []mutation{{"body has wrong type", func(any) any { return wrongTypeValue(s) }}}
+	}
+
+	out = append(out, mutation{"body is an array, expected object", func(any) any { return []any{} }})
+
+	required := append([]string(nil), s.Required...)
+	sort.Strings(required)
+	for _, name := range required {
+		out = append(out, mutation{
+			reason: fmt.Sprintf("missing required property %q", name),
+			apply:  withObject(func(m map[string]any) { delete(m, name) }),
+		})
+	}
+
+	names := make([]string, 0, len(s.Properties))
+	for name := range s.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		ref := s.Properties[name]
+		if ref == nil || ref.Value == nil {
+			continue
+		}
+		prop := ref.Value
+
+		if prop.Type != nil && len(prop.Type.Slice()) > 0 {
+			out = append(out, mutation{
+				reason: fmt.Sprintf("property %q has wrong type, expected %s", name, strings.Join(prop.Type.Slice(), "|")),
+				apply:  setProperty(name, wrongTypeValue(prop)),
+			})
+		}
+		if len(prop.Enum) > 0 {
+			out = append(out, mutation{
+				reason: fmt.Sprintf("property %q is not one of the enum values", name),
+				apply:  setProperty(name, "__not_in_enum__"),
+			})
+		}
+		if prop.Min != nil {
+			out = append(out, mutation{
+				reason: fmt.Sprintf("property %q is below minimum %v", name, *prop.Min),
+				apply:  setProperty(name, *prop.Min-1),
+			})
+		}
+		if prop.Max != nil {
+			out = append(out, mutation{
+				reason: fmt.Sprintf("property %q is above maximum %v", name, *prop.Max),
+				apply:  setProperty(name, *prop.Max+1),
+			})
+		}
+		if prop.MaxLength != nil {
+			out = append(out, mutation{
+				reason: fmt.Sprintf("property %q exceeds maxLength %d", name, *prop.MaxLength),
+				apply:  setProperty(name, strings.Repeat("x", int(*prop.MaxLength)+1)),
+			})
+		}
+		if prop.MinLength > 0 {
+			out = append(out, mutation{
+				reason: fmt.Sprintf("property %q is shorter than minLength %d", name, prop.MinLength),
+				apply:  setProperty(name, ""),
+			})
+		}
+	}
+
+	return out
+}
+
+func withObject(fn func(m map[string]any)) func(any) any {
+	return func(v any) any {
+		m, ok := v.(map[string]any)
+		if !ok {
+			m = map[string]any{}
+		}
+		fn(m)
+		return m
+	}
+}
+
+func setProperty(name string, value any) func(any) any {
+	return withObject(func(m map[string]any) { m[name] = value })
+}
+
+func wrongTypeValue(s *openapi3.Schema) any {
+	switch {
+	case s.Type == nil:
+		return nil
+	case s.Type.Is("string"):
+		return 12345
+	case s.Type.Is("integer"), s.Type.Is("number"):
+		return "not-a-number"
+	case s.Type.Is("boolean"):
+		return "not-a-boolean"
+	case s.Type.Is("array"):
+		return map[string]any{}
+	case s.Type.Is("object"):
+		return "not-an-object"
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package openapi
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/sirupsen/logrus"
+)
+
+func fuzzTestProvider(schema *openapi3.Schema) *SpecProvider {
+	paths := openapi3.NewPaths()
+	paths.Set("/items", &openapi3.PathItem{
+		Post: &openapi3.Operation{
+			RequestBody: &openapi3.RequestBodyRef{Value: &openapi3.RequestBody{
+				Required: true,
+				Content: openapi3.Content{
+					"application/json": &openapi3.MediaType{Schema: &openapi3.SchemaRef{Value: schema}},
+				},
+			}},
+			Responses: openapi3.NewResponses(),
+		},
+		Get: &openapi3.Operation{Responses: openapi3.NewResponses()},
+	})
+
+	return &SpecProvider{
+		spec: &Spec{Doc3: &openapi3.T{Paths: paths}},
+		log:  logrus.New(),
+	}
+}
+
+func TestFuzzRequestBody_ValidAndInvalid(t *testing.T) {
+	maxV := 10.0
+	p := fuzzTestProvider(&openapi3.Schema{
+		Type:     &openapi3.Types{"object"},
+		Required: []string{"name"},
+		Properties: openapi3.Schemas{
+			"name":  {Value: &openapi3.Schema{Type: &openapi3.Types{"string"}}},
+			"count": {Value: &openapi3.Schema{Type: &openapi3.Types{"integer"}, Max: &maxV}},
+		},
+	})
+
+	res, ok := p.FuzzRequestBody("/items", "post", 6)
+	if !ok {
+		t.Fatalf("expected ok")
+	}
+	if len(res.Valid) != 6 || len(res.Invalid) != 6 {
+		t.Fatalf("expected 6 valid and 6 invalid, got %d/%d", len(res.Valid), len(res.Invalid))
+	}
+
+	for _, v := range res.Valid {
+		m, ok := v.(map[string]any)
+		if !ok || m["name"] == nil {
+			t.Fatalf("valid body missing required name: %#v", v)
+		}
+	}
+
+	reasons := map[string]bool{}
+	for _, c := range res.Invalid {
+		reasons[c.Reason] = true
+	}
+	for _, want := range []string{
+		`missing required property "name"`,
+		`property "count" is above maximum 10`,
+		`property "name" has wrong type, expected string`,
+	} {
+		if !reasons[want] {
+			t.Fatalf("expected reason %q in %v", want, reasons)
+		}
+	}
+}
+
+func TestFuzzRequestBody_RequiredPropertyRemoved(t *testing.T) {
+	p := fuzzTestProvider(&openapi3.Schema{
+		Type:     &openapi3.Types{"object"},
+		Required: []string{"name"},
+		Properties: openapi3.Schemas{
+			"name": {Value: &openapi3.Schema{Type: &openapi3.Types{"string"}}},
+		},
+	})
+
+	res, _ := p.FuzzRequestBody("/items", "post", 10)
+	for _, c := range res.Invalid {
+		if !strings.HasPrefix(c.Reason, "missing required") {
+			continue
+		}
+		if _, has := c.Body.(map[string]any)["name"]; has {
+			t.Fatalf("expected name removed, got %#v", c.Body)
+		}
+		return
+	}
+	t.Fatalf("no missing-required case generated: %#v", res.Invalid)
+}
+
+func TestFuzzRequestBody_NoRequestBody(t *testing.T) {
+	p := fuzzTestProvider(&openapi3.Schema{Type: &openapi3.Types{"object"}})
+
+	if _, ok := p.FuzzRequestBody("/items", "get", 1); ok {
+		t.Fatalf("expected not ok for operation without request body")
+	}
+	if _, ok := p.FuzzRequestBody("/missing", "post", 1); ok {
+		t.Fatalf("expected not ok for missing operation")
+	}
+}
+
+func TestSchemaMutations_ArrayRoot(t *testing.T) {
+	muts := schemaMutations(&openapi3.Schema{Type: &openapi3.Types{"array"}})
+	if len(muts) != 1 {
+		t.Fatalf("expected 1 mutation, got %d", len(muts))
+	}
+	if _, ok := muts[0].apply(nil).(map[string]any); !ok {
+		t.Fatalf("expected object body for array root")
+	}
+}
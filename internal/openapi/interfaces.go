@@ -12,16 +12,38 @@ import (
 
 type IRouterProvider interface {
 	FindRoute(method, path string) *Route
+	MatchRoute(method, path string) (*Route, map[string]string)
+	AllowedMethods(path string) []string
 	GetRoutes() []Route
 }
 
 type ISpecProvider interface {
-	TryGetExampleBody(swaggerPath, method string) ([]byte, bool)
+	TryGetExampleBody(swaggerPath, method string) ([]byte, string, bool)
+	TryGetExampleBodyFor(swaggerPath, method, mediaType string) ([]byte, string, bool)
+	TryGetRandomBody(swaggerPath, method string) ([]byte, string, bool)
+	TryGetBoundaryBody(swaggerPath, method string, kind BoundaryKind) ([]byte, string, bool)
 	FindOperation(swaggerPath, method string) *openapi3.Operation
+	GetEmulatorExtensions(swaggerPath, method string) EmulatorExtensions
+	FuzzRequestBody(swaggerPath, method string, n int) (*FuzzResult, bool)
+	OperationExamples(operationID string) (*OperationExamples, bool)
 	GetSpec() *Spec
+	Reload() error
+}
+
+// IExampleGenerator produces response bodies from the spec when no sample
+// file exists. Implementations return false when they have nothing for a
+// request; the emulator then falls back to its default body.
+type IExampleGenerator interface {
+	GenerateExample(req ExampleRequest) (any, bool)
 }
 
 type IValidator interface {
 	HasRequiredBodyParam(swaggerPath, method string) bool
 	IsEmptyBody(r *http.Request) (bool, error)
+	ValidateRequestBody(r *http.Request, swaggerPath, method string) ([]FieldError, error)
+	ValidateParameters(r *http.Request, swaggerPath, method, in string) []FieldError
+	UndeclaredQueryParams(r *http.Request, swaggerPath, method string) []FieldError
+	ValidateContentType(r *http.Request, swaggerPath, method string) (supported []string, ok bool)
+	ValidateResponseBody(swaggerPath, method string, status int, contentType string, body []byte) []FieldError
+	ValidateSecurity(r *http.Request, swaggerPath, method string, accept func(credential string) bool) *SecurityFailure
 }
@@ -0,0 +1,81 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package openapi
+
+import (
+	"encoding/json"
+	"mime"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+const defaultContentType = "application/json"
+
+// responseMediaTypes orders the media types of a response by preference:
+// JSON first, then the */* wildcard, then everything else alphabetically.
+func responseMediaTypes(content openapi3.Content) []string {
+	keys := sortedKeys(content)
+	sort.SliceStable(keys, func(i, j int) bool {
+		return mediaTypeRank(keys[i]) < mediaTypeRank(keys[j])
+	})
+	return keys
+}
+
+func mediaTypeRank(ct string) int {
+	switch base := baseMediaType(ct); {
+	case base == "application/json":
+		return 0
+	case base == "application/problem+json":
+		return 1
+	case isJSONMediaType(base):
+		return 2
+	case base == "*/*":
+		return 3
+	default:
+		return 4
+	}
+}
+
+func baseMediaType(ct string) string {
+	if base, _, err := mime.ParseMediaType(ct); err == nil {
+		return base
+	}
+	return strings.ToLower(strings.TrimSpace(ct))
+}
+
+func isJSONMediaType(ct string) bool {
+	base := baseMediaType(ct)
+	return base == "application/json" || strings.HasSuffix(base, "+json")
+}
+
+// servedContentType is the content-type header for a body generated from
+// the given spec media type. Wildcards are served as JSON.
+func servedContentType(ct string) string {
+	if strings.Contains(baseMediaType(ct), "*") {
+		return defaultContentType
+	}
+	return ct
+}
+
+// encodeExample serializes an example value for a media type. String values
+// of non-JSON media types (text/plain, text/csv, application/octet-stream, ...)
+// are written verbatim; everything else is JSON encoded.
+func encodeExample(ct string, v any) ([]byte, error) {
+	if s, ok := v.(string); ok && !isJSONMediaType(servedContentType(ct)) {
+		return []byte(s), nil
+	}
+	return json.Marshal(v)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -5,7 +5,7 @@
 package openapi
 
 import (
-	"regexp"
+	"strings"
 
 	"github.com/getkin/kin-openapi/openapi2"
 	"github.com/getkin/kin-openapi/openapi3"
@@ -14,8 +14,25 @@ import (
 type Route struct {
 	Method     string
 	Swagger    string
-	Regex      *regexp.Regexp
 	SampleFile string
+	// TagFile is <tag>/<operationId>.json for LAYOUT_MODE=tags, or empty
+	// when the operation has no operationId.
+	TagFile string
+	// Greedy marks the last path parameter as spanning the rest of the
+	// path: a {name+} segment or a parameter with x-emulator-greedy.
+	Greedy bool
+	// constraints holds the enum and pattern restrictions of path
+	// parameters by name.
+	constraints map[string]*paramConstraint
+}
+
+// ExampleRequest describes the response body an IExampleGenerator is asked
+// for. The returned value is encoded as ContentType.
+type ExampleRequest struct {
+	SwaggerPath string
+	Method      string
+	ContentType string
+	Schema      *openapi3.SchemaRef
 }
 
 type Spec struct {
@@ -23,7 +40,137 @@ type Spec struct {
 	Doc2 *openapi2.T
 }
 
+// Vendor extensions understood by the emulator on operations (or path items).
+const (
+	ExtDelayMs    = "x-emulator-delay-ms"
+	ExtStatus     = "x-emulator-status"
+	ExtSample     = "x-emulator-sample"
+	ExtBatch      = "x-emulator-batch"
+	ExtJob        = "x-emulator-job"
+	ExtLongPollMs = "x-emulator-long-poll-ms"
+	ExtBackoff    = "x-emulator-backoff"
+	ExtPadBytes   = "x-emulator-pad-bytes"
+	// ExtGreedy is set on a path parameter rather than the operation.
+	ExtGreedy = "x-emulator-greedy"
+)
+
+// EmulatorExtensions is the per-operation behavior declared via x-emulator-* extensions.
+type EmulatorExtensions struct {
+	DelayMs int
+	Status  int
+	Sample  string
+	// Batch marks the operation as a batch endpoint whose body is an array
+	// of sub-requests.
+	Batch bool
+	// Job turns a POST into an async job whose status and result routes
+	// are answered by the emulator.
+	Job *JobConfig
+	// LongPollMs holds the request until its scenario state changes or
+	// this many milliseconds pass.
+	LongPollMs int
+	// Backoff makes the operation fail with Retry-After a number of times
+	// before it succeeds.
+	Backoff *BackoffConfig
+	// PadBytes pads JSON object bodies with a filler field to this size.
+	PadBytes int
+}
+
+// JobConfig is the value of x-emulator-job (or true for all defaults). Zero
+// fields take the defaults applied by Normalize.
+type JobConfig struct {
+	DurationSec int      `json:"durationSec"`
+	States      []string `json:"states,omitempty"`
+	StatusPath  string   `json:"statusPath,omitempty"`
+	ResultPath  string   `json:"resultPath,omitempty"`
+}
+
+// Normalize fills in defaults for a job created at createPath: 10 seconds,
+// queued -> running -> succeeded, <createPath>/{id} and <statusPath>/result.
+func (c *JobConfig) Normalize(createPath string) {
+	if c.DurationSec <= 0 {
+		c.DurationSec = 10
+	}
+	if len(c.States) == 0 {
+		c.States = []string{"queued", "running", "succeeded"}
+	}
+	if strings.TrimSpace(c.StatusPath) == "" {
+		c.StatusPath = strings.TrimSuffix(createPath, "/") + "/{id}"
+	}
+	if strings.TrimSpace(c.ResultPath) == "" {
+		c.ResultPath = strings.TrimSuffix(c.StatusPath, "/") + "/result"
+	}
+}
+
+// BackoffConfig is the value of x-emulator-backoff (or true for all
+// defaults). Zero fields take the defaults applied by Normalize.
+type BackoffConfig struct {
+	Failures      int `json:"failures"`
+	Status        int `json:"status"`
+	RetryAfterSec int `json:"retryAfterSec"`
+}
+
+// Normalize fills in defaults: 2 failures answered with 503 and
+// Retry-After: 1. Statuses other than 429 and 503 are replaced by 503.
+func (c *BackoffConfig) Normalize() {
+	if c.Failures <= 0 {
+		c.Failures = 2
+	}
+	if c.Status != 429 && c.Status != 503 {
+		c.Status = 503
+	}
+	if c.RetryAfterSec <= 0 {
+		c.RetryAfterSec = 1
+	}
+}
+
+// SecurityFailure describes a request that satisfies none of an operation's
+// security requirements.
+type SecurityFailure struct {
+	// Schemes lists the security schemes of all requirements, in spec order.
+	Schemes []string
+	// Challenges holds WWW-Authenticate values for the http/oauth2 schemes.
+	Challenges []string
+}
+
+// OperationExamples holds example payloads for one operation, as served by
+// /__admin/examples/{operationId}.
+type OperationExamples struct {
+	OperationID string          `json:"operationId"`
+	Method      string          `json:"method"`
+	SwaggerPath string          `json:"swaggerPath"`
+	Request     *ExamplePayload `json:"request,omitempty"`
+	Response    *ExamplePayload `json:"response,omitempty"`
+}
+
+// ExamplePayload is an example body with its content type. JSON bodies are
+// kept as raw JSON, anything else as a string.
+type ExamplePayload struct {
+	ContentType string `json:"contentType"`
+	Body        any    `json:"body"`
+}
+
+// FuzzResult holds generated request bodies for an operation.
+type FuzzResult struct {
+	Valid   []any      `json:"valid"`
+	Invalid []FuzzCase `json:"invalid"`
+}
+
+// FuzzCase is a request body violating the schema, with the reason why.
+type FuzzCase struct {
+	Reason string `json:"reason"`
+	Body   any    `json:"body"`
+}
+
 type versionProbe struct {
 	Swagger string `json:"swagger"`
 	OpenAPI string `json:"openapi"`
 }
+
+// FieldError is a single request validation failure. For parameters, In is
+// the parameter location and Field its name; for the body, In is empty and
+// Field is a JSON pointer ("/" for the body as a whole).
+type FieldError struct {
+	In     string `json:"in,omitempty"`
+	Field  string `json:"field"`
+	Reason string `json:"reason"`
+}
@@ -0,0 +1,59 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package openapi
+
+import (
+	"encoding/json"
+)
+
+// OperationExamples returns a request and a response example for the
+// operation with the given operationId. Explicit spec examples win; other
+// bodies come from the example generator. Request is nil for operations
+// without a request body.
+func (p *SpecProvider) OperationExamples(operationID string) (*OperationExamples, bool) {
+	spec := p.GetSpec()
+	if spec == nil || spec.Doc3 == nil || spec.Doc3.Paths == nil {
+		return nil, false
+	}
+
+	paths := spec.Doc3.Paths.Map()
+	for _, swaggerPath := range sortedKeys(paths) {
+		ops := paths[swaggerPath].Operations()
+		for _, method := range sortedKeys(ops) {
+			op := ops[method]
+			if op == nil || op.OperationID != operationID {
+				continue
+			}
+
+			out := &OperationExamples{
+				OperationID: operationID,
+				Method:      method,
+				SwaggerPath: swaggerPath,
+			}
+			if op.RequestBody != nil && op.RequestBody.Value != nil {
+				content := op.RequestBody.Value.Content
+				b, ct, ok := extractExample(content)
+				if !ok {
+					b, ct, ok = generateFromContent(p.exampleGenerator(), swaggerPath, method, content)
+				}
+				if ok {
+					out.Request = examplePayload(b, ct)
+				}
+			}
+			if b, ct, ok := p.TryGetExampleBody(swaggerPath, method); ok {
+				out.Response = examplePayload(b, ct)
+			}
+			return out, true
+		}
+	}
+	return nil, false
+}
+
+func examplePayload(b []byte, ct string) *ExamplePayload {
+	if isJSONMediaType(ct) && json.Valid(b) {
+		return &ExamplePayload{ContentType: ct, Body: json.RawMessage(b)}
+	}
+	return &ExamplePayload{ContentType: ct, Body: string(b)}
+}
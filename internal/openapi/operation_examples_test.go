@@ -0,0 +1,69 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package openapi
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/sirupsen/logrus"
+)
+
+func TestOperationExamples(t *testing.T) {
+	responses := openapi3.NewResponses()
+	responses.Set("201", &openapi3.ResponseRef{Value: &openapi3.Response{
+		Content: openapi3.Content{
+			"application/json": &openapi3.MediaType{Example: map[string]any{"id": "abc"}},
+		},
+	}})
+
+	paths := openapi3.NewPaths()
+	paths.Set("/items", &openapi3.PathItem{
+		Post: &openapi3.Operation{
+			OperationID: "createItem",
+			RequestBody: &openapi3.RequestBodyRef{Value: &openapi3.RequestBody{
+				Content: openapi3.Content{
+					"application/json": &openapi3.MediaType{Schema: &openapi3.SchemaRef{Value: &openapi3.Schema{
+						Type:       &openapi3.Types{"object"},
+						Properties: openapi3.Schemas{"name": {Value: &openapi3.Schema{Type: &openapi3.Types{"string"}}}},
+					}}},
+				},
+			}},
+			Responses: responses,
+		},
+		Get: &openapi3.Operation{OperationID: "listItems", Responses: openapi3.NewResponses()},
+	})
+	p := &SpecProvider{spec: &Spec{Doc3: &openapi3.T{Paths: paths}}, log: logrus.New()}
+
+	ex, ok := p.OperationExamples("createItem")
+	if !ok {
+		t.Fatalf("expected createItem to be found")
+	}
+	if ex.Method != "POST" || ex.SwaggerPath != "/items" {
+		t.Fatalf("unexpected operation: %s %s", ex.Method, ex.SwaggerPath)
+	}
+	if ex.Request == nil || ex.Request.ContentType != "application/json" {
+		t.Fatalf("expected generated JSON request, got %#v", ex.Request)
+	}
+	if b, _ := json.Marshal(ex.Request.Body); string(b) != `{"name":"string"}` {
+		t.Fatalf("unexpected request body: %s", b)
+	}
+	if ex.Response == nil {
+		t.Fatalf("expected response example")
+	}
+	if b, _ := json.Marshal(ex.Response.Body); string(b) != `{"id":"abc"}` {
+		t.Fatalf("unexpected response body: %s", b)
+	}
+
+	ex, ok = p.OperationExamples("listItems")
+	if !ok || ex.Request != nil {
+		t.Fatalf("expected listItems without request example, got %#v", ex)
+	}
+
+	if _, ok := p.OperationExamples("nope"); ok {
+		t.Fatalf("expected unknown operationId to be reported")
+	}
+}
@@ -0,0 +1,91 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package openapi
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// paramConstraint restricts the values a path parameter segment matches,
+// from the enum and pattern of the parameter's schema.
+type paramConstraint struct {
+	enum    []string
+	pattern *regexp.Regexp
+	// key identifies equal constraints, which share one route tree node.
+	key string
+}
+
+func (c *paramConstraint) matches(seg string) bool {
+	if v, err := url.PathUnescape(seg); err == nil {
+		seg = v
+	}
+	if c.enum != nil && !slices.Contains(c.enum, seg) {
+		return false
+	}
+	return c.pattern == nil || c.pattern.MatchString(seg)
+}
+
+// pathParamConstraints collects the constraints of the {name} segments of
+// swaggerPath. Operation parameters come after path item parameters and
+// override them. Invalid patterns are ignored.
+func pathParamConstraints(swaggerPath string, params ...openapi3.Parameters) map[string]*paramConstraint {
+	var out map[string]*paramConstraint
+	for _, seg := range splitSwaggerPath(swaggerPath) {
+		if !isParamSegment(seg) || isGreedySegment(seg) {
+			continue
+		}
+		name := paramName(seg)
+
+		var schema *openapi3.Schema
+		for _, list := range params {
+			for _, ref := range list {
+				if ref == nil || ref.Value == nil || ref.Value.In != openapi3.ParameterInPath || ref.Value.Name != name {
+					continue
+				}
+				if ref.Value.Schema != nil {
+					schema = ref.Value.Schema.Value
+				}
+			}
+		}
+		if c := newParamConstraint(schema); c != nil {
+			if out == nil {
+				out = map[string]*paramConstraint{}
+			}
+			out[name] = c
+		}
+	}
+	return out
+}
+
+func newParamConstraint(s *openapi3.Schema) *paramConstraint {
+	if s == nil || (len(s.Enum) == 0 && s.Pattern == "") {
+		return nil
+	}
+	c := &paramConstraint{}
+	var key []string
+	if len(s.Enum) > 0 {
+		for _, v := range s.Enum {
+			c.enum = append(c.enum, fmt.Sprint(v))
+		}
+		key = append(key, "enum:"+strings.Join(c.enum, "\x00"))
+	}
+	if s.Pattern != "" {
+		if re, err := regexp.Compile(s.Pattern); err == nil {
+			c.pattern = re
+			key = append(key, "pattern:"+s.Pattern)
+		}
+	}
+	if len(key) == 0 {
+		return nil
+	}
+	c.key = strings.Join(key, "\x00")
+	return c
+}
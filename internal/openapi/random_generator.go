@@ -0,0 +1,158 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package openapi
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+const randomAlphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// TryGetRandomBody generates a fresh body from the response schema on every call.
+// Operations without a response schema fall back to TryGetExampleBody.
+func (p *SpecProvider) TryGetRandomBody(swaggerPath, method string) ([]byte, string, bool) {
+	op := p.FindOperation(swaggerPath, method)
+	if op == nil || op.Responses == nil {
+		return nil, "", false
+	}
+
+	if respRef := p.pickBestResponseRef(op.Responses); respRef != nil {
+		if b, ct, ok := p.generateFromResponseSchema(p.randomGenerator(), swaggerPath, method, respRef.Value); ok {
+			return b, ct, true
+		}
+	}
+
+	return p.TryGetExampleBody(swaggerPath, method)
+}
+
+func randStringForSchema(s *openapi3.Schema) string {
+	switch s.Format {
+	case "date-time":
+		return randTime().Format(time.RFC3339)
+	case "date":
+		return randTime().Format("2006-01-02")
+	case "uuid":
+		return fmt.Sprintf("%08x-%04x-4%03x-%04x-%012x",
+			uint32(randInt64N(1<<32)), randIntN(1<<16), randIntN(1<<12),
+			0x8000|randIntN(1<<14), randInt64N(1<<48))
+	case "email":
+		return randString(5, 10) + "@example.com"
+	case "uri", "url":
+		return "https://example.com/" + randString(5, 10)
+	}
+
+	minLen := int(s.MinLength)
+	maxLen := minLen + 12
+	if s.MaxLength != nil {
+		maxLen = int(*s.MaxLength)
+	}
+	if minLen == 0 && maxLen > 0 {
+		minLen = 1
+	}
+	return randString(minLen, maxLen)
+}
+
+func randString(minLen, maxLen int) string {
+	n := randBetween(minLen, maxLen)
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = randomAlphabet[randIntN(len(randomAlphabet))]
+	}
+	return string(b)
+}
+
+func randTime() time.Time {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	return base.Add(time.Duration(randInt64N(365*24*3600)) * time.Second)
+}
+
+// Mock payloads do not need a cryptographic source.
+func randIntN(n int) int { return rand.IntN(n) } //nolint:gosec
+
+func randInt64N(n int64) int64 { return rand.Int64N(n) } //nolint:gosec
+
+func randFloat64() float64 { return rand.Float64() } //nolint:gosec
+
+// randInt64Between draws from [lo, hi] in uint64 arithmetic, so the span
+// of the whole int64 range does not overflow. It returns lo when the range
+// is empty.
+func randInt64Between(lo, hi int64) int64 {
+	if hi <= lo {
+		return lo
+	}
+	span := uint64(hi) - uint64(lo)
+	if span == math.MaxUint64 {
+		return int64(rand.Uint64()) //nolint:gosec
+	}
+	return int64(uint64(lo) + rand.Uint64N(span+1)) //nolint:gosec
+}
+
+// clampInt64 converts f to int64, saturating at the int64 limits where a
+// plain conversion would overflow; float64(math.MaxInt64) is already 2^63.
+func clampInt64(f float64) int64 {
+	switch {
+	case math.IsNaN(f):
+		return 0
+	case f >= math.MaxInt64:
+		return math.MaxInt64
+	case f <= math.MinInt64:
+		return math.MinInt64
+	}
+	return int64(f)
+}
+
+func randBetween(lo, hi int) int {
+	if hi <= lo {
+		return lo
+	}
+	return lo + randIntN(hi-lo+1)
+}
+
+func maxItemsOf(s *openapi3.Schema) int {
+	if s.MaxItems != nil {
+		return int(*s.MaxItems)
+	}
+	return int(s.MinItems) + 3
+}
+
+func numberBounds(s *openapi3.Schema, defLo, defHi float64) (float64, float64) {
+	lo, hi := defLo, defHi
+	if s.Min != nil {
+		lo = *s.Min
+		if s.ExclusiveMin {
+			lo = exclusiveStep(s, lo, math.Inf(1))
+		}
+		if s.Max == nil && hi < lo {
+			hi = lo + defHi
+		}
+	}
+	if s.Max != nil {
+		hi = *s.Max
+		if s.ExclusiveMax {
+			hi = exclusiveStep(s, hi, math.Inf(-1))
+		}
+		if s.Min == nil && lo > hi {
+			lo = hi - defHi
+		}
+	}
+	if hi < lo {
+		hi = lo
+	}
+	return lo, hi
+}
+
+// exclusiveStep moves an exclusive bound v towards dir: by 1 for integers,
+// and to the next float64 for numbers, so fractional ranges stay valid.
+func exclusiveStep(s *openapi3.Schema, v, dir float64) float64 {
+	if s.Type.Is("integer") {
+		return v + math.Copysign(1, dir)
+	}
+	return math.Nextafter(v, dir)
+}
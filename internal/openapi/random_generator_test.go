@@ -0,0 +1,234 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/sirupsen/logrus"
+)
+
+func randomTestProvider(schema *openapi3.Schema) *SpecProvider {
+	paths := openapi3.NewPaths()
+	paths.Set("/x", &openapi3.PathItem{
+		Get: &openapi3.Operation{
+			Responses: func() *openapi3.Responses {
+				r := openapi3.NewResponses()
+				r.Set("200", &openapi3.ResponseRef{
+					Value: &openapi3.Response{
+						Content: openapi3.Content{
+							"application/json": &openapi3.MediaType{
+								Schema: &openapi3.SchemaRef{Value: schema},
+							},
+						},
+					},
+				})
+				return r
+			}(),
+		},
+	})
+
+	return &SpecProvider{
+		spec: &Spec{Doc3: &openapi3.T{Paths: paths}},
+		log:  logrus.New(),
+	}
+}
+
+func TestTryGetRandomBody_RespectsSchemaConstraints(t *testing.T) {
+	minV, maxV := 10.0, 20.0
+	maxLen := uint64(6)
+	maxItems := uint64(4)
+
+	p := randomTestProvider(&openapi3.Schema{
+		Type: &openapi3.Types{"object"},
+		Properties: openapi3.Schemas{
+			"count":  {Value: &openapi3.Schema{Type: &openapi3.Types{"integer"}, Min: &minV, Max: &maxV}},
+			"name":   {Value: &openapi3.Schema{Type: &openapi3.Types{"string"}, MinLength: 3, MaxLength: &maxLen}},
+			"status": {Value: &openapi3.Schema{Type: &openapi3.Types{"string"}, Enum: []any{"a", "b"}}},
+			"tags": {Value: &openapi3.Schema{
+				Type:     &openapi3.Types{"array"},
+				MinItems: 2,
+				MaxItems: &maxItems,
+				Items:    &openapi3.SchemaRef{Value: &openapi3.Schema{Type: &openapi3.Types{"boolean"}}},
+			}},
+		},
+	})
+
+	for i := 0; i < 50; i++ {
+		b, _, ok := p.TryGetRandomBody("/x", "get")
+		if !ok {
+			t.Fatalf("expected ok")
+		}
+
+		var m map[string]any
+		if err := json.Unmarshal(b, &m); err != nil {
+			t.Fatalf("invalid json: %v", err)
+		}
+
+		if n := m["count"].(float64); n < 10 || n > 20 {
+			t.Fatalf("count out of range: %v", n)
+		}
+		if l := len(m["name"].(string)); l < 3 || l > 6 {
+			t.Fatalf("name length out of range: %d", l)
+		}
+		if st := m["status"]; st != "a" && st != "b" {
+			t.Fatalf("status not from enum: %v", st)
+		}
+		if l := len(m["tags"].([]any)); l < 2 || l > 4 {
+			t.Fatalf("tags length out of range: %d", l)
+		}
+	}
+}
+
+func TestTryGetRandomBody_ProducesDifferentValues(t *testing.T) {
+	p := randomTestProvider(&openapi3.Schema{
+		Type: &openapi3.Types{"object"},
+		Properties: openapi3.Schemas{
+			"id": {Value: &openapi3.Schema{Type: &openapi3.Types{"string"}, Format: "uuid"}},
+		},
+	})
+
+	first, _, _ := p.TryGetRandomBody("/x", "get")
+	for i := 0; i < 10; i++ {
+		next, _, _ := p.TryGetRandomBody("/x", "get")
+		if string(next) != string(first) {
+			return
+		}
+	}
+	t.Fatalf("expected randomized bodies, always got %s", first)
+}
+
+func TestTryGetRandomBody_NoSchema_FallsBackToExample(t *testing.T) {
+	paths := openapi3.NewPaths()
+	paths.Set("/x", &openapi3.PathItem{
+		Get: &openapi3.Operation{Responses: openapi3.NewResponses()},
+	})
+	p := &SpecProvider{spec: &Spec{Doc3: &openapi3.T{Paths: paths}}, log: logrus.New()}
+
+	b, _, ok := p.TryGetRandomBody("/x", "get")
+	if !ok || string(b) != `{"ok":true}` {
+		t.Fatalf("unexpected: ok=%v body=%s", ok, b)
+	}
+
+	if _, _, ok := p.TryGetRandomBody("/missing", "get"); ok {
+		t.Fatalf("expected not ok for missing operation")
+	}
+}
+
+func TestNumberBounds(t *testing.T) {
+	minV, maxV := 5.0, 7.0
+	lo, hi := numberBounds(&openapi3.Schema{Type: &openapi3.Types{"integer"}, Min: &minV, Max: &maxV, ExclusiveMax: true}, 0, 1000)
+	if lo != 5 || hi != 6 {
+		t.Fatalf("unexpected bounds: %v %v", lo, hi)
+	}
+
+	big := 5000.0
+	lo, hi = numberBounds(&openapi3.Schema{Min: &big}, 0, 1000)
+	if lo != 5000 || hi != 6000 {
+		t.Fatalf("unexpected bounds: %v %v", lo, hi)
+	}
+}
+
+func TestTryGetRandomBody_ExclusiveFractionalRange(t *testing.T) {
+	minV, maxV := 0.0, 0.5
+	p := randomTestProvider(&openapi3.Schema{
+		Type: &openapi3.Types{"object"},
+		Properties: openapi3.Schemas{
+			"ratio": {Value: &openapi3.Schema{Type: &openapi3.Types{"number"}, Min: &minV, ExclusiveMin: true, Max: &maxV}},
+		},
+	})
+
+	for i := 0; i < 200; i++ {
+		b, _, ok := p.TryGetRandomBody("/x", "get")
+		if !ok {
+			t.Fatalf("expected ok")
+		}
+		var m map[string]float64
+		if err := json.Unmarshal(b, &m); err != nil {
+			t.Fatalf("invalid json: %v", err)
+		}
+		if v := m["ratio"]; v <= 0 || v > 0.5 {
+			t.Fatalf("expected ratio in (0, 0.5], got %v", v)
+		}
+	}
+}
+
+func TestTryGetRandomBody_NumericFormats(t *testing.T) {
+	p := randomTestProvider(&openapi3.Schema{
+		Type: &openapi3.Types{"object"},
+		Properties: openapi3.Schemas{
+			"big":   {Value: &openapi3.Schema{Type: &openapi3.Types{"integer"}, Format: "int64"}},
+			"small": {Value: &openapi3.Schema{Type: &openapi3.Types{"integer"}, Format: "int32"}},
+		},
+	})
+
+	for i := 0; i < 50; i++ {
+		b, _, ok := p.TryGetRandomBody("/x", "get")
+		if !ok {
+			t.Fatalf("expected ok")
+		}
+
+		dec := json.NewDecoder(bytes.NewReader(b))
+		dec.UseNumber()
+		var m map[string]json.Number
+		if err := dec.Decode(&m); err != nil {
+			t.Fatalf("invalid json: %v", err)
+		}
+		if n, err := m["big"].Int64(); err != nil || n <= 1<<53 {
+			t.Fatalf("expected int64 beyond 2^53, got %s", m["big"])
+		}
+		if n, err := m["small"].Int64(); err != nil || n < math.MinInt32 || n > math.MaxInt32 {
+			t.Fatalf("int32 out of range: %s", m["small"])
+		}
+	}
+}
+
+func TestTryGetRandomBody_IntegerRangeEdges(t *testing.T) {
+	maxInt64, minInt64 := float64(math.MaxInt64), float64(math.MinInt64)
+	zero, half, tooBig := 0.0, 0.5, 5e9
+	cases := map[string]struct {
+		schema *openapi3.Schema
+		check  func(int64) bool
+	}{
+		"0..MaxInt64": {
+			schema: &openapi3.Schema{Format: "int64", Min: &zero, Max: &maxInt64},
+			check:  func(n int64) bool { return n >= 0 },
+		},
+		"MinInt64..MaxInt64": {
+			schema: &openapi3.Schema{Format: "int64", Min: &minInt64, Max: &maxInt64},
+			check:  func(int64) bool { return true },
+		},
+		"MinInt64..0": {
+			schema: &openapi3.Schema{Min: &minInt64, Max: &zero},
+			check:  func(n int64) bool { return n <= 0 },
+		},
+		"no integer in range": {
+			schema: &openapi3.Schema{Min: &half, Max: &half},
+			check:  func(n int64) bool { return n == 1 },
+		},
+		"int32 beyond its limits": {
+			schema: &openapi3.Schema{Format: "int32", Min: &tooBig},
+			check:  func(n int64) bool { return n == math.MaxInt32 },
+		},
+		"min at MaxInt64": {
+			schema: &openapi3.Schema{Format: "int64", Min: &maxInt64},
+			check:  func(n int64) bool { return n == math.MaxInt64 },
+		},
+	}
+	for name, tc := range cases {
+		tc.schema.Type = &openapi3.Types{"integer"}
+		for i := 0; i < 20; i++ {
+			v, ok := randomSource{}.scalar(tc.schema)
+			n, isInt := v.(int64)
+			if !ok || !isInt || !tc.check(n) {
+				t.Fatalf("%s: unexpected value %v", name, v)
+			}
+		}
+	}
+}
@@ -0,0 +1,185 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package openapi
+
+import (
+	"context"
+	"strings"
+)
+
+// routeNode is one path segment of the route tree. Static children are
+// looked up by segment; a {param} segment of any name is the param child,
+// unless its schema has an enum or pattern, which makes it a constrained
+// child, and a greedy last segment such as {path+} is the greedy child.
+type routeNode struct {
+	static      map[string]*routeNode
+	constrained []constrainedChild
+	param       *routeNode
+	greedy      *routeNode
+	// leaves maps the methods of the routes ending here to their index in
+	// RouterProvider.routes.
+	leaves map[string]int
+}
+
+type constrainedChild struct {
+	constraint *paramConstraint
+	node       *routeNode
+}
+
+func newRouteNode() *routeNode {
+	return &routeNode{static: map[string]*routeNode{}}
+}
+
+// insert adds a route. With greedy, its last segment is a parameter that
+// captures the rest of the path. constraints restrict parameter segments by
+// name.
+func (n *routeNode) insert(segs []string, method string, idx int, greedy bool, constraints map[string]*paramConstraint) {
+	for i, seg := range segs {
+		if greedy && i == len(segs)-1 {
+			if n.greedy == nil {
+				n.greedy = newRouteNode()
+			}
+			n = n.greedy
+			break
+		}
+		if c := constraints[paramName(seg)]; c != nil && isParamSegment(seg) {
+			n = n.constrainedChild(c)
+			continue
+		}
+		if isParamSegment(seg) {
+			if n.param == nil {
+				n.param = newRouteNode()
+			}
+			n = n.param
+			continue
+		}
+		child, ok := n.static[seg]
+		if !ok {
+			child = newRouteNode()
+			n.static[seg] = child
+		}
+		n = child
+	}
+	if n.leaves == nil {
+		n.leaves = map[string]int{}
+	}
+	// first route wins, as with duplicate templates like /a/{x} and /a/{y}
+	if _, ok := n.leaves[method]; !ok {
+		n.leaves[method] = idx
+	}
+}
+
+func (n *routeNode) constrainedChild(c *paramConstraint) *routeNode {
+	for _, cc := range n.constrained {
+		if cc.constraint.key == c.key {
+			return cc.node
+		}
+	}
+	child := newRouteNode()
+	n.constrained = append(n.constrained, constrainedChild{constraint: c, node: child})
+	return child
+}
+
+// walkScore counts, for a matched route, its static segments and its
+// constrained parameter segments.
+type walkScore struct {
+	statics     int
+	constrained int
+}
+
+func (s walkScore) beats(o walkScore) bool {
+	if s.statics != o.statics {
+		return s.statics > o.statics
+	}
+	return s.constrained > o.constrained
+}
+
+// walk calls visit for every node whose routes match segs, static children
+// before constrained children before the param child before the greedy
+// child.
+func (n *routeNode) walk(segs []string, score walkScore, visit func(n *routeNode, score walkScore)) {
+	if len(segs) == 0 {
+		if len(n.leaves) > 0 {
+			visit(n, score)
+		}
+		return
+	}
+	if child, ok := n.static[segs[0]]; ok {
+		child.walk(segs[1:], walkScore{score.statics + 1, score.constrained}, visit)
+	}
+	for _, cc := range n.constrained {
+		if cc.constraint.matches(segs[0]) {
+			cc.node.walk(segs[1:], walkScore{score.statics, score.constrained + 1}, visit)
+		}
+	}
+	if n.param != nil {
+		n.param.walk(segs[1:], score, visit)
+	}
+	if n.greedy != nil && len(n.greedy.leaves) > 0 {
+		visit(n.greedy, score)
+	}
+}
+
+// splitRequestPath splits an actual request path into segments. One
+// trailing slash is ignored; other empty segments never match a route.
+func splitRequestPath(path string) ([]string, bool) {
+	if !strings.HasPrefix(path, "/") {
+		return nil, false
+	}
+	path = strings.TrimSuffix(path[1:], "/")
+	if path == "" {
+		return nil, true
+	}
+	segs := strings.Split(path, "/")
+	for _, s := range segs {
+		if s == "" {
+			return nil, false
+		}
+	}
+	return segs, true
+}
+
+// splitSwaggerPath splits a spec path template into its non-empty segments.
+func splitSwaggerPath(swaggerPath string) []string {
+	var out []string
+	for _, s := range strings.Split(swaggerPath, "/") {
+		if s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func isParamSegment(seg string) bool {
+	return strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}")
+}
+
+// isGreedySegment reports a {name+} parameter, which spans one or more
+// path segments.
+func isGreedySegment(seg string) bool {
+	return isParamSegment(seg) && strings.HasSuffix(seg, "+}")
+}
+
+// paramName is the name of a {name} or {name+} segment.
+func paramName(seg string) string {
+	return strings.TrimSuffix(strings.Trim(seg, "{}"), "+")
+}
+
+type pathParamsKey struct{}
+
+// WithPathParams returns a context carrying the path parameters captured
+// for the matched route.
+func WithPathParams(ctx context.Context, params map[string]string) context.Context {
+	return context.WithValue(ctx, pathParamsKey{}, params)
+}
+
+// ContextPathParams returns the path parameters stored by WithPathParams,
+// falling back to matching swaggerPath against actualPath.
+func ContextPathParams(ctx context.Context, swaggerPath, actualPath string) map[string]string {
+	if params, ok := ctx.Value(pathParamsKey{}).(map[string]string); ok {
+		return params
+	}
+	return PathParams(swaggerPath, actualPath)
+}
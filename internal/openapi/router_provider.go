@@ -6,15 +6,43 @@ package openapi
 
 import (
 	"fmt"
-	"regexp"
+	"slices"
 	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
 )
 
 type RouterProvider struct {
 	routes []Route
+	tree   *routeNode
+	// priority holds a pinned priority per route index; nil when no
+	// priorities are configured.
+	priority []int
+}
+
+// RouterOption customizes a RouterProvider created by NewRouterProvider.
+type RouterOption func(*RouterProvider)
+
+// WithRoutePriorities pins route priorities by "METHOD /swagger/path" key,
+// with "*" as method for all methods of a path. A higher priority wins over
+// specificity; unlisted routes have priority 0.
+func WithRoutePriorities(priorities map[string]int) RouterOption {
+	return func(p *RouterProvider) {
+		if len(priorities) == 0 {
+			return
+		}
+		p.priority = make([]int, len(p.routes))
+		for i, r := range p.routes {
+			if n, ok := priorities[r.Method+" "+r.Swagger]; ok {
+				p.priority[i] = n
+			} else {
+				p.priority[i] = priorities["* "+r.Swagger]
+			}
+		}
+	}
 }
 
-func NewRouterProvider(spec *Spec) IRouterProvider {
+func NewRouterProvider(spec *Spec, opts ...RouterOption) IRouterProvider {
 	if spec == nil || spec.Doc3 == nil || spec.Doc3.Paths == nil {
 		return nil
 	}
@@ -25,61 +53,113 @@ func NewRouterProvider(spec *Spec) IRouterProvider {
 			continue
 		}
 
-		for method := range item.Operations() {
+		for method, op := range item.Operations() {
 			m := strings.ToUpper(method)
 			out = append(out, Route{
-				Method:     m,
-				Swagger:    swaggerPath,
-				Regex:      swaggerPathToRegex(swaggerPath),
-				SampleFile: swaggerPathToSampleName(m, swaggerPath),
+				Method:      m,
+				Swagger:     swaggerPath,
+				SampleFile:  swaggerPathToSampleName(m, swaggerPath),
+				TagFile:     operationTagFile(op),
+				Greedy:      hasGreedyParam(swaggerPath, item.Parameters, op.Parameters),
+				constraints: pathParamConstraints(swaggerPath, item.Parameters, op.Parameters),
 			})
 		}
 	}
-	return &RouterProvider{routes: out}
+	p := newRouterProvider(out)
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// newRouterProvider compiles routes into the route tree.
+func newRouterProvider(routes []Route) *RouterProvider {
+	tree := newRouteNode()
+	for i, r := range routes {
+		segs := splitSwaggerPath(r.Swagger)
+		greedy := len(segs) > 0 && (isGreedySegment(segs[len(segs)-1]) || r.Greedy && isParamSegment(segs[len(segs)-1]))
+		tree.insert(segs, r.Method, i, greedy, r.constraints)
+		routes[i].Greedy = greedy
+	}
+	return &RouterProvider{routes: routes, tree: tree}
 }
 
 func (p *RouterProvider) FindRoute(method, path string) *Route {
-	method = strings.ToUpper(method)
+	rt, _ := p.MatchRoute(method, path)
+	return rt
+}
 
-	var best *Route
-	bestScore := -1
+// MatchRoute returns the most specific route for method and path together
+// with the captured path parameters. A higher pinned priority wins first;
+// then routes with more static segments win, then routes with more enum or
+// pattern constrained parameters, and on a tie the one matching static
+// segments earlier in the path.
+func (p *RouterProvider) MatchRoute(method, path string) (*Route, map[string]string) {
+	segs, ok := splitRequestPath(path)
+	if !ok {
+		return nil, nil
+	}
+	method = strings.ToUpper(method)
 
-	for i := range p.routes {
-		r := &p.routes[i]
-		if r.Method != method {
-			continue
+	best, bestPriority, bestScore := -1, 0, walkScore{}
+	p.tree.walk(segs, walkScore{}, func(n *routeNode, score walkScore) {
+		idx, ok := n.leaves[method]
+		if !ok {
+			return
 		}
-		if !r.Regex.MatchString(path) {
-			continue
+		prio := p.priorityOf(idx)
+		if best < 0 || prio > bestPriority || (prio == bestPriority && score.beats(bestScore)) {
+			best, bestPriority, bestScore = idx, prio, score
 		}
+	})
+	if best < 0 {
+		return nil, nil
+	}
 
-		score := p.routeSpecificityScore(r.Swagger)
-		if score > bestScore {
-			best = r
-			bestScore = score
+	rt := &p.routes[best]
+	params := map[string]string{}
+	tpl := splitSwaggerPath(rt.Swagger)
+	for i, seg := range tpl {
+		if !isParamSegment(seg) {
+			continue
+		}
+		if rt.Greedy && i == len(tpl)-1 {
+			params[paramName(seg)] = strings.Join(segs[i:], "/")
+			break
 		}
+		params[paramName(seg)] = segs[i]
 	}
-
-	return best
+	return rt, params
 }
 
-func (p *RouterProvider) GetRoutes() []Route {
-	return p.routes
+func (p *RouterProvider) priorityOf(idx int) int {
+	if p.priority == nil {
+		return 0
+	}
+	return p.priority[idx]
 }
 
-func (p *RouterProvider) routeSpecificityScore(swaggerPath string) int {
-	parts := strings.Split(strings.Trim(swaggerPath, "/"), "/")
-	score := 0
-	for _, p := range parts {
-		if strings.HasPrefix(p, "{") && strings.HasSuffix(p, "}") {
-			score += 0 // param segment
-		} else {
-			score += 10
-		}
+// AllowedMethods lists, sorted, the methods FindRoute would accept for path.
+func (p *RouterProvider) AllowedMethods(path string) []string {
+	segs, ok := splitRequestPath(path)
+	if !ok {
+		return nil
 	}
 
-	score += len(parts)
-	return score
+	var out []string
+	p.tree.walk(segs, walkScore{}, func(n *routeNode, _ walkScore) {
+		for m := range n.leaves {
+			if !slices.Contains(out, m) {
+				out = append(out, m)
+			}
+		}
+	})
+	slices.Sort(out)
+	return out
+}
+
+func (p *RouterProvider) GetRoutes() []Route {
+	return p.routes
 }
 
 func swaggerPathToSampleName(method, swaggerPath string) string {
@@ -88,21 +168,71 @@ func swaggerPathToSampleName(method, swaggerPath string) string {
 	return fmt.Sprintf("%s__%s.json", strings.ToUpper(method), s)
 }
 
-func swaggerPathToRegex(swaggerPath string) *regexp.Regexp {
-	parts := strings.Split(swaggerPath, "/")
-	var out []string
+// operationTagFile names the sample of an operation in the tags layout:
+// <first tag>/<operationId>.json, with "default" for untagged operations.
+func operationTagFile(op *openapi3.Operation) string {
+	if op == nil || strings.TrimSpace(op.OperationID) == "" {
+		return ""
+	}
+	tag := "default"
+	if len(op.Tags) > 0 && strings.TrimSpace(op.Tags[0]) != "" {
+		tag = op.Tags[0]
+	}
+	return safeFileName(tag) + "/" + safeFileName(op.OperationID) + ".json"
+}
+
+// safeFileName replaces characters that are not portable in file names.
+func safeFileName(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '\\', ':', '*', '?', '"', '<', '>', '|':
+			return '_'
+		}
+		return r
+	}, strings.TrimSpace(s))
+}
 
-	for _, p := range parts {
-		if p == "" {
+// PathParams maps the {name} segments of swaggerPath to the matching
+// segments of actualPath. Values stay percent-encoded.
+func PathParams(swaggerPath, actualPath string) map[string]string {
+	tpl := strings.Split(strings.Trim(swaggerPath, "/"), "/")
+	act := strings.Split(strings.Trim(actualPath, "/"), "/")
+	greedy := isGreedySegment(tpl[len(tpl)-1])
+	if len(tpl) != len(act) && !(greedy && len(act) > len(tpl)) {
+		return nil
+	}
+
+	out := map[string]string{}
+	for i, seg := range tpl {
+		if !isParamSegment(seg) {
 			continue
 		}
-		if strings.HasPrefix(p, "{") && strings.HasSuffix(p, "}") {
-			out = append(out, `([^/]+)`)
-		} else {
-			out = append(out, regexp.QuoteMeta(p))
+		if greedy && i == len(tpl)-1 {
+			out[paramName(seg)] = strings.Join(act[i:], "/")
+			break
 		}
+		out[paramName(seg)] = act[i]
 	}
+	return out
+}
 
-	pat := "^/" + strings.Join(out, "/") + "/?$"
-	return regexp.MustCompile(pat)
+// hasGreedyParam reports whether the last segment of swaggerPath is a path
+// parameter marked with x-emulator-greedy, on the operation or path item.
+func hasGreedyParam(swaggerPath string, params ...openapi3.Parameters) bool {
+	segs := splitSwaggerPath(swaggerPath)
+	if len(segs) == 0 || !isParamSegment(segs[len(segs)-1]) {
+		return false
+	}
+	name := paramName(segs[len(segs)-1])
+	for _, list := range params {
+		for _, ref := range list {
+			if ref == nil || ref.Value == nil || ref.Value.In != openapi3.ParameterInPath || ref.Value.Name != name {
+				continue
+			}
+			if b, ok := ref.Value.Extensions[ExtGreedy].(bool); ok && b {
+				return true
+			}
+		}
+	}
+	return false
 }
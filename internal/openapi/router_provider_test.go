@@ -5,40 +5,235 @@
 package openapi
 
 import (
+	"fmt"
+	"reflect"
 	"testing"
 
 	"github.com/getkin/kin-openapi/openapi3"
 )
 
-func TestSwaggerPathToRegex_Static(t *testing.T) {
-	re := swaggerPathToRegex("/v1/health")
+func TestRouterProvider_FindRoute_Static(t *testing.T) {
+	p := newRouterProvider([]Route{{Method: "GET", Swagger: "/v1/health"}})
 
 	ok := []string{"/v1/health", "/v1/health/"}
-	for _, p := range ok {
-		if !re.MatchString(p) {
-			t.Fatalf("expected %q to match %q", re.String(), p)
+	for _, path := range ok {
+		if p.FindRoute("GET", path) == nil {
+			t.Fatalf("expected %q to match", path)
 		}
 	}
 
-	bad := []string{"/v1/health/x", "/v1/healt", "/v1/health//"}
-	for _, p := range bad {
-		if re.MatchString(p) {
-			t.Fatalf("expected %q to NOT match %q", re.String(), p)
+	bad := []string{"/v1/health/x", "/v1/healt", "/v1/health//", "v1/health", "//v1/health"}
+	for _, path := range bad {
+		if p.FindRoute("GET", path) != nil {
+			t.Fatalf("expected %q to NOT match", path)
 		}
 	}
 }
 
-func TestSwaggerPathToRegex_Param(t *testing.T) {
-	re := swaggerPathToRegex("/users/{id}")
+func TestRouterProvider_FindRoute_Param(t *testing.T) {
+	p := newRouterProvider([]Route{{Method: "GET", Swagger: "/users/{id}"}})
 
-	if !re.MatchString("/users/123") {
+	if p.FindRoute("GET", "/users/123") == nil {
 		t.Fatalf("expected match")
 	}
-	if re.MatchString("/users/123/profile") {
+	if p.FindRoute("GET", "/users/123/profile") != nil {
+		t.Fatalf("expected no match")
+	}
+	if p.FindRoute("GET", "/users/") != nil {
+		t.Fatalf("expected no match for an empty param")
+	}
+}
+
+func TestRouterProvider_FindRoute_Root(t *testing.T) {
+	p := newRouterProvider([]Route{{Method: "GET", Swagger: "/"}})
+
+	if p.FindRoute("GET", "/") == nil {
+		t.Fatalf("expected match")
+	}
+	if p.FindRoute("GET", "/x") != nil {
 		t.Fatalf("expected no match")
 	}
 }
 
+func TestRouterProvider_MatchRoute_SpecificityAndParams(t *testing.T) {
+	p := newRouterProvider([]Route{
+		{Method: "GET", Swagger: "/users/{id}/posts/{postId}"},
+		{Method: "GET", Swagger: "/users/me/posts/{postId}"},
+		{Method: "GET", Swagger: "/{kind}/{id}/posts/latest"},
+		{Method: "GET", Swagger: "/users/{id}"},
+	})
+
+	cases := []struct {
+		path    string
+		swagger string
+		params  map[string]string
+	}{
+		{"/users/7/posts/3", "/users/{id}/posts/{postId}", map[string]string{"id": "7", "postId": "3"}},
+		{"/users/me/posts/3", "/users/me/posts/{postId}", map[string]string{"postId": "3"}},
+		// two static segments each; the earlier static segment wins
+		{"/users/7/posts/latest", "/users/{id}/posts/{postId}", map[string]string{"id": "7", "postId": "latest"}},
+		{"/teams/7/posts/latest", "/{kind}/{id}/posts/latest", map[string]string{"kind": "teams", "id": "7"}},
+		{"/users/me", "/users/{id}", map[string]string{"id": "me"}},
+	}
+	for _, tc := range cases {
+		rt, params := p.MatchRoute("get", tc.path)
+		if rt == nil || rt.Swagger != tc.swagger {
+			t.Fatalf("%s: expected %s, got %#v", tc.path, tc.swagger, rt)
+		}
+		if !reflect.DeepEqual(params, tc.params) {
+			t.Fatalf("%s: unexpected params %v", tc.path, params)
+		}
+	}
+
+	if rt, params := p.MatchRoute("GET", "/nope"); rt != nil || params != nil {
+		t.Fatalf("expected no match, got %#v %v", rt, params)
+	}
+}
+
+func TestRouterProvider_MatchRoute_PinnedPriority(t *testing.T) {
+	p := newRouterProvider([]Route{
+		{Method: "GET", Swagger: "/users/{id}/posts/{postId}"},
+		{Method: "GET", Swagger: "/{kind}/{id}/posts/latest"},
+		{Method: "GET", Swagger: "/items/latest"},
+		{Method: "GET", Swagger: "/items/{id}"},
+		{Method: "DELETE", Swagger: "/items/{id}"},
+	})
+	WithRoutePriorities(map[string]int{
+		"GET /{kind}/{id}/posts/latest": 1,
+		"* /items/{id}":                 1,
+	})(p)
+
+	cases := []struct{ method, path, swagger string }{
+		{"GET", "/users/7/posts/latest", "/{kind}/{id}/posts/latest"},
+		{"GET", "/users/7/posts/3", "/users/{id}/posts/{postId}"},
+		{"GET", "/items/latest", "/items/{id}"},
+		{"DELETE", "/items/latest", "/items/{id}"},
+	}
+	for _, tc := range cases {
+		rt, _ := p.MatchRoute(tc.method, tc.path)
+		if rt == nil || rt.Swagger != tc.swagger {
+			t.Fatalf("%s %s: expected %s, got %#v", tc.method, tc.path, tc.swagger, rt)
+		}
+	}
+}
+
+func TestRouterProvider_MatchRoute_GreedyParam(t *testing.T) {
+	p := newRouterProvider([]Route{
+		{Method: "GET", Swagger: "/files/{path+}"},
+		{Method: "GET", Swagger: "/files/{id}"},
+		{Method: "GET", Swagger: "/files/{id}/meta"},
+		{Method: "PUT", Swagger: "/buckets/{bucket}/{key}", Greedy: true},
+	})
+
+	cases := []struct {
+		method, path, swagger string
+		params                map[string]string
+	}{
+		{"GET", "/files/a", "/files/{id}", map[string]string{"id": "a"}},
+		{"GET", "/files/a/meta", "/files/{id}/meta", map[string]string{"id": "a"}},
+		{"GET", "/files/a/b/c.txt", "/files/{path+}", map[string]string{"path": "a/b/c.txt"}},
+		{"PUT", "/buckets/b1/x/y/z", "/buckets/{bucket}/{key}", map[string]string{"bucket": "b1", "key": "x/y/z"}},
+	}
+	for _, tc := range cases {
+		rt, params := p.MatchRoute(tc.method, tc.path)
+		if rt == nil || rt.Swagger != tc.swagger {
+			t.Fatalf("%s %s: expected %s, got %#v", tc.method, tc.path, tc.swagger, rt)
+		}
+		if !reflect.DeepEqual(params, tc.params) {
+			t.Fatalf("%s %s: unexpected params %v", tc.method, tc.path, params)
+		}
+	}
+
+	if rt, _ := p.MatchRoute("GET", "/files"); rt != nil {
+		t.Fatalf("a greedy parameter needs at least one segment, got %#v", rt)
+	}
+	if got := PathParams("/files/{path+}", "/files/a/b"); got["path"] != "a/b" {
+		t.Fatalf("unexpected PathParams: %v", got)
+	}
+}
+
+func TestNewRouterProvider_GreedyExtension(t *testing.T) {
+	paths := openapi3.NewPaths()
+	paths.Set("/objects/{key}", &openapi3.PathItem{
+		Parameters: openapi3.Parameters{{Value: &openapi3.Parameter{
+			Name:       "key",
+			In:         openapi3.ParameterInPath,
+			Extensions: map[string]any{ExtGreedy: true},
+		}}},
+		Get: &openapi3.Operation{Responses: openapi3.NewResponses()},
+	})
+
+	p := NewRouterProvider(&Spec{Doc3: &openapi3.T{Paths: paths}})
+	rt, params := p.MatchRoute("GET", "/objects/2026/01/report.pdf")
+	if rt == nil || params["key"] != "2026/01/report.pdf" {
+		t.Fatalf("expected greedy match, got %#v %v", rt, params)
+	}
+}
+
+func TestNewRouterProvider_ParamEnumAndPattern(t *testing.T) {
+	pathParam := func(name string, schema *openapi3.Schema) openapi3.Parameters {
+		return openapi3.Parameters{{Value: &openapi3.Parameter{
+			Name:   name,
+			In:     openapi3.ParameterInPath,
+			Schema: openapi3.NewSchemaRef("", schema),
+		}}}
+	}
+	paths := openapi3.NewPaths()
+	paths.Set("/orders/{id}", &openapi3.PathItem{
+		Parameters: pathParam("id", &openapi3.Schema{Pattern: `^[0-9]+$`}),
+		Get:        &openapi3.Operation{Responses: openapi3.NewResponses()},
+	})
+	paths.Set("/orders/{status}", &openapi3.PathItem{
+		Parameters: pathParam("status", &openapi3.Schema{Enum: []any{"open", "closed"}}),
+		Get:        &openapi3.Operation{Responses: openapi3.NewResponses()},
+	})
+	paths.Set("/orders/{ref}", &openapi3.PathItem{
+		Get: &openapi3.Operation{Responses: openapi3.NewResponses()},
+	})
+
+	p := NewRouterProvider(&Spec{Doc3: &openapi3.T{Paths: paths}})
+	cases := map[string]string{
+		"/orders/open":  "/orders/{status}",
+		"/orders/42":    "/orders/{id}",
+		"/orders/other": "/orders/{ref}",
+	}
+	for path, want := range cases {
+		rt, params := p.MatchRoute("GET", path)
+		if rt == nil || rt.Swagger != want {
+			t.Fatalf("%s: expected %s, got %#v", path, want, rt)
+		}
+		if len(params) != 1 {
+			t.Fatalf("%s: unexpected params %v", path, params)
+		}
+	}
+}
+
+func TestNewRouterProvider_ParamEnumRejectsUndeclared(t *testing.T) {
+	paths := openapi3.NewPaths()
+	paths.Set("/orders/{status}", &openapi3.PathItem{
+		Get: &openapi3.Operation{
+			Parameters: openapi3.Parameters{{Value: &openapi3.Parameter{
+				Name:   "status",
+				In:     openapi3.ParameterInPath,
+				Schema: openapi3.NewSchemaRef("", &openapi3.Schema{Enum: []any{"open"}}),
+			}}},
+			Responses: openapi3.NewResponses(),
+		},
+		Delete: &openapi3.Operation{Responses: openapi3.NewResponses()},
+	})
+
+	p := NewRouterProvider(&Spec{Doc3: &openapi3.T{Paths: paths}})
+	if rt := p.FindRoute("GET", "/orders/closed"); rt != nil {
+		t.Fatalf("expected no match for undeclared enum value, got %#v", rt)
+	}
+	if rt := p.FindRoute("GET", "/orders/open"); rt == nil {
+		t.Fatalf("expected match for declared enum value")
+	}
+	if got := p.AllowedMethods("/orders/closed"); len(got) != 1 || got[0] != "DELETE" {
+		t.Fatalf("expected only DELETE allowed, got %v", got)
+	}
+}
+
 func TestSwaggerPathToSampleName(t *testing.T) {
 	got := swaggerPathToSampleName("get", "/users/{id}")
 	want := "GET__users_{id}.json"
@@ -47,23 +242,36 @@ func TestSwaggerPathToSampleName(t *testing.T) {
 	}
 }
 
+func TestOperationTagFile(t *testing.T) {
+	cases := []struct {
+		op   *openapi3.Operation
+		want string
+	}{
+		{&openapi3.Operation{Tags: []string{"scans", "admin"}, OperationID: "getScan"}, "scans/getScan.json"},
+		{&openapi3.Operation{OperationID: "listItems"}, "default/listItems.json"},
+		{&openapi3.Operation{Tags: []string{"a/b"}, OperationID: "x:y"}, "a_b/x_y.json"},
+		{&openapi3.Operation{Tags: []string{"scans"}}, ""},
+	}
+	for _, tc := range cases {
+		if got := operationTagFile(tc.op); got != tc.want {
+			t.Fatalf("got %q want %q", got, tc.want)
+		}
+	}
+}
+
 func TestRouterProvider_FindRoute(t *testing.T) {
-	p := &RouterProvider{
-		routes: []Route{
-			{
-				Method:     "GET",
-				Swagger:    "/users/{id}",
-				Regex:      swaggerPathToRegex("/users/{id}"),
-				SampleFile: "GET__users_{id}.json",
-			},
-			{
-				Method:     "POST",
-				Swagger:    "/users",
-				Regex:      swaggerPathToRegex("/users"),
-				SampleFile: "POST__users.json",
-			},
+	p := newRouterProvider([]Route{
+		{
+			Method:     "GET",
+			Swagger:    "/users/{id}",
+			SampleFile: "GET__users_{id}.json",
 		},
-	}
+		{
+			Method:     "POST",
+			Swagger:    "/users",
+			SampleFile: "POST__users.json",
+		},
+	})
 
 	r := p.FindRoute("get", "/users/55")
 	if r == nil || r.Swagger != "/users/{id}" {
@@ -75,6 +283,22 @@ func TestRouterProvider_FindRoute(t *testing.T) {
 	}
 }
 
+func TestRouterProvider_AllowedMethods(t *testing.T) {
+	p := newRouterProvider([]Route{
+		{Method: "GET", Swagger: "/users/{id}"},
+		{Method: "DELETE", Swagger: "/users/{id}"},
+		{Method: "PUT", Swagger: "/users/me"},
+		{Method: "POST", Swagger: "/users"},
+	})
+
+	if got := p.AllowedMethods("/users/me"); !reflect.DeepEqual(got, []string{"DELETE", "GET", "PUT"}) {
+		t.Fatalf("unexpected methods: %v", got)
+	}
+	if got := p.AllowedMethods("/nope"); got != nil {
+		t.Fatalf("expected nil, got %v", got)
+	}
+}
+
 func TestNewRouterProvider_BuildRoutes(t *testing.T) {
 	paths := openapi3.NewPaths()
 	paths.Set("/users/{id}", &openapi3.PathItem{
@@ -104,8 +328,8 @@ func TestNewRouterProvider_BuildRoutes(t *testing.T) {
 			if r.SampleFile != "GET__users_{id}.json" {
 				t.Fatalf("bad sample file: %q", r.SampleFile)
 			}
-			if !r.Regex.MatchString("/users/1") {
-				t.Fatalf("regex should match")
+			if rt := rp.FindRoute("GET", "/users/1"); rt == nil || rt.Swagger != r.Swagger {
+				t.Fatalf("route should match, got %#v", rt)
 			}
 		}
 		if r.Method == "POST" && r.Swagger == "/users" {
@@ -113,8 +337,8 @@ func TestNewRouterProvider_BuildRoutes(t *testing.T) {
 			if r.SampleFile != "POST__users.json" {
 				t.Fatalf("bad sample file: %q", r.SampleFile)
 			}
-			if !r.Regex.MatchString("/users") {
-				t.Fatalf("regex should match")
+			if rt := rp.FindRoute("POST", "/users"); rt == nil || rt.Swagger != r.Swagger {
+				t.Fatalf("route should match, got %#v", rt)
 			}
 		}
 	}
@@ -139,12 +363,10 @@ func TestNewRouterProvider_NilGuards(t *testing.T) {
 }
 
 func TestRouterProvider_GetRoutes_ReturnsRoutes(t *testing.T) {
-	p := &RouterProvider{
-		routes: []Route{
-			{Method: "GET", Swagger: "/x", Regex: swaggerPathToRegex("/x"), SampleFile: "GET__x.json"},
-			{Method: "POST", Swagger: "/y", Regex: swaggerPathToRegex("/y"), SampleFile: "POST__y.json"},
-		},
-	}
+	p := newRouterProvider([]Route{
+		{Method: "GET", Swagger: "/x", SampleFile: "GET__x.json"},
+		{Method: "POST", Swagger: "/y", SampleFile: "POST__y.json"},
+	})
 
 	got := p.GetRoutes()
 	if len(got) != 2 {
@@ -154,3 +376,20 @@ func TestRouterProvider_GetRoutes_ReturnsRoutes(t *testing.T) {
 		t.Fatalf("unexpected routes: %#v", got)
 	}
 }
+
+func BenchmarkRouterProvider_FindRoute(b *testing.B) {
+	var routes []Route
+	for i := 0; i < 500; i++ {
+		for _, tpl := range []string{"/api/v%d/items", "/api/v%d/items/{id}", "/api/v%d/items/{id}/children/{childId}", "/api/v%d/users/me"} {
+			routes = append(routes, Route{Method: "GET", Swagger: fmt.Sprintf(tpl, i)})
+		}
+	}
+	p := newRouterProvider(routes)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if p.FindRoute("GET", "/api/v499/items/42/children/7") == nil {
+			b.Fatal("expected match")
+		}
+	}
+}
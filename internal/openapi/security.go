@@ -0,0 +1,155 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package openapi
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// ValidateSecurity checks the request against the operation's security
+// requirements (or the document-level ones). Requirements are alternatives;
+// the schemes within one must all be satisfied. A credential satisfies a
+// scheme when it is present and accept (if set) reports true for it.
+// It returns nil when the request may pass.
+func (v *Validator) ValidateSecurity(r *http.Request, swaggerPath, method string, accept func(credential string) bool) *SecurityFailure {
+	spec := v.spec.GetSpec()
+	op := v.spec.FindOperation(swaggerPath, method)
+	if spec == nil || spec.Doc3 == nil || op == nil {
+		return nil
+	}
+
+	reqs := spec.Doc3.Security
+	if op.Security != nil {
+		reqs = *op.Security
+	}
+	if len(reqs) == 0 {
+		return nil
+	}
+
+	var schemes openapi3.SecuritySchemes
+	if spec.Doc3.Components != nil {
+		schemes = spec.Doc3.Components.SecuritySchemes
+	}
+
+	failure := &SecurityFailure{}
+	for _, req := range reqs {
+		ok := true
+		for _, name := range sortedKeys(req) {
+			ref := schemes[name]
+			if ref == nil || ref.Value == nil {
+				continue
+			}
+			if !failure.hasScheme(name) {
+				failure.Schemes = append(failure.Schemes, name)
+				if c := authChallenge(ref.Value); c != "" && !slices.Contains(failure.Challenges, c) {
+					failure.Challenges = append(failure.Challenges, c)
+				}
+			}
+			if !schemeSatisfied(r, ref.Value, accept) {
+				ok = false
+			}
+		}
+		if ok {
+			return nil
+		}
+	}
+	return failure
+}
+
+func (f *SecurityFailure) hasScheme(name string) bool {
+	return slices.Contains(f.Schemes, name)
+}
+
+func schemeSatisfied(r *http.Request, s *openapi3.SecurityScheme, accept func(string) bool) bool {
+	cred, checkable := schemeCredential(r, s)
+	if !checkable {
+		// mutualTLS and unknown types cannot be checked here.
+		return true
+	}
+	if cred == "" {
+		return false
+	}
+	return accept == nil || accept(cred)
+}
+
+// schemeCredential returns the request's credential for the scheme. checkable
+// is false for scheme types that carry no credential in the request.
+func schemeCredential(r *http.Request, s *openapi3.SecurityScheme) (cred string, checkable bool) {
+	switch strings.ToLower(s.Type) {
+	case "apikey":
+		switch s.In {
+		case openapi3.ParameterInHeader:
+			cred = r.Header.Get(s.Name)
+		case openapi3.ParameterInQuery:
+			cred = r.URL.Query().Get(s.Name)
+		case openapi3.ParameterInCookie:
+			if c, err := r.Cookie(s.Name); err == nil {
+				cred = c.Value
+			}
+		}
+	case "http":
+		cred = authorizationCredential(r, s.Scheme)
+	case "oauth2", "openidconnect":
+		cred = authorizationCredential(r, "bearer")
+	default:
+		return "", false
+	}
+	return strings.TrimSpace(cred), true
+}
+
+// RequestCredential returns the first credential the request carries for any
+// of the spec's security schemes (in name order), falling back to the raw
+// Authorization header. It returns "" when there is none.
+func RequestCredential(r *http.Request, spec *Spec) string {
+	if spec != nil && spec.Doc3 != nil && spec.Doc3.Components != nil {
+		schemes := spec.Doc3.Components.SecuritySchemes
+		for _, name := range sortedKeys(schemes) {
+			ref := schemes[name]
+			if ref == nil || ref.Value == nil {
+				continue
+			}
+			if cred, _ := schemeCredential(r, ref.Value); cred != "" {
+				return cred
+			}
+		}
+	}
+	return strings.TrimSpace(r.Header.Get("Authorization"))
+}
+
+// authorizationCredential returns the credential of an Authorization header
+// using the given scheme (case-insensitive), e.g. the token of "Bearer <token>".
+func authorizationCredential(r *http.Request, scheme string) string {
+	kind, cred, ok := strings.Cut(strings.TrimSpace(r.Header.Get("Authorization")), " ")
+	if !ok || !strings.EqualFold(kind, scheme) {
+		return ""
+	}
+	return cred
+}
+
+func authChallenge(s *openapi3.SecurityScheme) string {
+	switch strings.ToLower(s.Type) {
+	case "http":
+		switch strings.ToLower(s.Scheme) {
+		case "basic":
+			return `Basic realm="openapi-emulator"`
+		case "":
+			return ""
+		default:
+			return capitalize(s.Scheme)
+		}
+	case "oauth2", "openidconnect":
+		return "Bearer"
+	}
+	return ""
+}
+
+func capitalize(s string) string {
+	s = strings.ToLower(s)
+	return strings.ToUpper(s[:1]) + s[1:]
+}
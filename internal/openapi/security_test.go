@@ -0,0 +1,81 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package openapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func securityTestValidator(opSecurity *openapi3.SecurityRequirements) IValidator {
+	paths := openapi3.NewPaths()
+	paths.Set("/items", &openapi3.PathItem{
+		Get: &openapi3.Operation{Security: opSecurity, Responses: openapi3.NewResponses()},
+	})
+	doc := &openapi3.T{
+		Paths:    paths,
+		Security: openapi3.SecurityRequirements{{"bearerAuth": {}}},
+		Components: &openapi3.Components{SecuritySchemes: openapi3.SecuritySchemes{
+			"bearerAuth": {Value: &openapi3.SecurityScheme{Type: "http", Scheme: "bearer"}},
+			"apiKey":     {Value: &openapi3.SecurityScheme{Type: "apiKey", In: "query", Name: "api_key"}},
+		}},
+	}
+	return NewValidator(&SpecProvider{spec: &Spec{Doc3: doc}, log: logrus.New()})
+}
+
+func TestValidator_ValidateSecurity_DocumentLevelBearer(t *testing.T) {
+	v := securityTestValidator(nil)
+
+	r := httptest.NewRequest(http.MethodGet, "/items", nil)
+	failure := v.ValidateSecurity(r, "/items", "get", nil)
+	require.NotNil(t, failure)
+	require.Equal(t, []string{"bearerAuth"}, failure.Schemes)
+	require.Equal(t, []string{"Bearer"}, failure.Challenges)
+
+	r.Header.Set("Authorization", "Bearer anything")
+	require.Nil(t, v.ValidateSecurity(r, "/items", "get", nil))
+
+	onlySecret := func(cred string) bool { return cred == "secret" }
+	require.NotNil(t, v.ValidateSecurity(r, "/items", "get", onlySecret))
+	r.Header.Set("Authorization", "bearer secret")
+	require.Nil(t, v.ValidateSecurity(r, "/items", "get", onlySecret))
+}
+
+func TestValidator_ValidateSecurity_OperationAlternatives(t *testing.T) {
+	v := securityTestValidator(&openapi3.SecurityRequirements{{"apiKey": {}}, {"bearerAuth": {}}})
+
+	r := httptest.NewRequest(http.MethodGet, "/items?api_key=k1", nil)
+	require.Nil(t, v.ValidateSecurity(r, "/items", "get", nil))
+
+	failure := v.ValidateSecurity(httptest.NewRequest(http.MethodGet, "/items", nil), "/items", "get", nil)
+	require.NotNil(t, failure)
+	require.Equal(t, []string{"apiKey", "bearerAuth"}, failure.Schemes)
+}
+
+func TestValidator_ValidateSecurity_EmptyRequirementAllowsAnonymous(t *testing.T) {
+	v := securityTestValidator(&openapi3.SecurityRequirements{})
+
+	require.Nil(t, v.ValidateSecurity(httptest.NewRequest(http.MethodGet, "/items", nil), "/items", "get", nil))
+}
+
+func TestRequestCredential(t *testing.T) {
+	spec := &Spec{Doc3: &openapi3.T{Components: &openapi3.Components{SecuritySchemes: openapi3.SecuritySchemes{
+		"apiKey": {Value: &openapi3.SecurityScheme{Type: "apiKey", In: "header", Name: "X-API-Key"}},
+	}}}}
+
+	r := httptest.NewRequest(http.MethodGet, "/items", nil)
+	require.Equal(t, "", RequestCredential(r, spec))
+
+	r.Header.Set("Authorization", "Token abc")
+	require.Equal(t, "Token abc", RequestCredential(r, spec))
+
+	r.Header.Set("X-API-Key", "k1")
+	require.Equal(t, "k1", RequestCredential(r, spec))
+}
@@ -14,6 +14,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/sirupsen/logrus"
 
@@ -26,9 +27,31 @@ type SpecProvider struct {
 	path string
 	spec *Spec
 	log  *logrus.Logger
+
+	// mu guards spec and the memoized example bodies. generation counts
+	// reloads, so a body built from a replaced spec is not memoized.
+	mu         sync.RWMutex
+	examples   map[string]cachedExample
+	generation uint64
+
+	// exampleGen and randomGen fill in bodies from response schemas; nil
+	// uses the built-in generators.
+	exampleGen IExampleGenerator
+	randomGen  IExampleGenerator
+}
+
+func NewSpecProvider(path string, log *logrus.Logger, opts ...SpecOption) (ISpecProvider, error) {
+	p, err := loadSpec(path, log)
+	if err != nil {
+		return nil, err
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p, nil
 }
 
-func NewSpecProvider(path string, log *logrus.Logger) (ISpecProvider, error) {
+func loadSpec(path string, log *logrus.Logger) (*SpecProvider, error) {
 	b, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("read spec: %w", err)
@@ -92,44 +115,226 @@ func NewSpecProvider(path string, log *logrus.Logger) (ISpecProvider, error) {
 }
 
 func (sp *SpecProvider) GetSpec() *Spec {
+	sp.mu.RLock()
+	defer sp.mu.RUnlock()
 	return sp.spec
 }
 
-func (p *SpecProvider) TryGetExampleBody(swaggerPath, method string) ([]byte, bool) {
+// Reload re-reads the spec from disk and drops all memoized example bodies.
+func (p *SpecProvider) Reload() error {
+	fresh, err := loadSpec(p.path, p.log)
+	if err != nil {
+		return err
+	}
+	spec := fresh.GetSpec()
+
+	p.mu.Lock()
+	p.spec = spec
+	p.examples = nil
+	p.generation++
+	p.mu.Unlock()
+	return nil
+}
+
+// TryGetExampleBody returns the spec example (or schema-generated body) for an
+// operation together with the content type it should be served as. Bodies are
+// memoized per operation and media type.
+func (p *SpecProvider) TryGetExampleBody(swaggerPath, method string) ([]byte, string, bool) {
+	return p.TryGetExampleBodyFor(swaggerPath, method, "")
+}
+
+// TryGetExampleBodyFor is TryGetExampleBody for one media type of the
+// response, e.g. the one a client asked for in Accept. It returns false when
+// the response does not declare mediaType; an empty mediaType picks the
+// preferred one.
+func (p *SpecProvider) TryGetExampleBodyFor(swaggerPath, method, mediaType string) ([]byte, string, bool) {
+	key := exampleCacheKey(swaggerPath, method, mediaType)
+
+	p.mu.RLock()
+	ex, ok := p.examples[key]
+	gen := p.generation
+	p.mu.RUnlock()
+	if ok {
+		return ex.body, ex.contentType, true
+	}
+
+	b, ct, ok := p.buildExampleBody(swaggerPath, method, mediaType)
+	if !ok {
+		return nil, "", false
+	}
+
+	p.mu.Lock()
+	if p.generation == gen {
+		if p.examples == nil {
+			p.examples = map[string]cachedExample{}
+		}
+		p.examples[key] = cachedExample{body: b, contentType: ct}
+	}
+	p.mu.Unlock()
+	return b, ct, true
+}
+
+type cachedExample struct {
+	body        []byte
+	contentType string
+}
+
+func exampleCacheKey(swaggerPath, method, mediaType string) string {
+	return strings.ToUpper(method) + " " + swaggerPath + " " + baseMediaType(mediaType)
+}
+
+func (p *SpecProvider) buildExampleBody(swaggerPath, method, mediaType string) ([]byte, string, bool) {
 	op := p.FindOperation(swaggerPath, method)
 	if op == nil || op.Responses == nil {
-		return nil, false
+		return nil, "", false
 	}
 
 	respRef := p.pickBestResponseRef(op.Responses)
+	if mediaType != "" {
+		if respRef == nil || respRef.Value == nil {
+			return nil, "", false
+		}
+		content := contentFor(respRef.Value.Content, mediaType)
+		if content == nil {
+			return nil, "", false
+		}
+		if b, ct, ok := extractExample(content); ok {
+			return b, ct, true
+		}
+		return generateFromContent(p.exampleGenerator(), swaggerPath, method, content)
+	}
+
 	if respRef == nil || respRef.Value == nil {
 		b, _ := json.Marshal(map[string]any{"ok": true})
-		return b, true
+		return b, defaultContentType, true
 	}
 
-	if b, ok := p.extractExampleFromResponse(respRef.Value); ok {
-		return b, true
+	if b, ct, ok := p.extractExampleFromResponse(respRef.Value); ok {
+		return b, ct, true
 	}
 
-	if b, ok := p.generateFromResponseSchema(respRef.Value); ok {
-		return b, true
+	if b, ct, ok := p.generateFromResponseSchema(p.exampleGenerator(), swaggerPath, method, respRef.Value); ok {
+		return b, ct, true
 	}
 
 	b, _ := json.Marshal(map[string]any{"ok": true})
-	return b, true
+	return b, defaultContentType, true
+}
+
+// contentFor narrows content to the media types whose base type is
+// mediaType, or returns nil when there is none.
+func contentFor(content openapi3.Content, mediaType string) openapi3.Content {
+	want := baseMediaType(mediaType)
+	var out openapi3.Content
+	for ct, mt := range content {
+		if baseMediaType(ct) != want {
+			continue
+		}
+		if out == nil {
+			out = openapi3.Content{}
+		}
+		out[ct] = mt
+	}
+	return out
 }
 
 func (p *SpecProvider) FindOperation(swaggerPath, method string) *openapi3.Operation {
-	if p.spec == nil || p.spec.Doc3 == nil {
+	spec := p.GetSpec()
+	if spec == nil || spec.Doc3 == nil {
 		return nil
 	}
-	item := p.spec.Doc3.Paths.Find(swaggerPath)
+	item := spec.Doc3.Paths.Find(swaggerPath)
 	if item == nil {
 		return nil
 	}
 	return item.GetOperation(strings.ToUpper(method))
 }
 
+// GetEmulatorExtensions reads x-emulator-* extensions for an operation.
+// Values declared on the operation win over the ones on its path item.
+func (p *SpecProvider) GetEmulatorExtensions(swaggerPath, method string) EmulatorExtensions {
+	var out EmulatorExtensions
+	spec := p.GetSpec()
+	if spec == nil || spec.Doc3 == nil {
+		return out
+	}
+	item := spec.Doc3.Paths.Find(swaggerPath)
+	if item == nil {
+		return out
+	}
+
+	applyEmulatorExtensions(&out, item.Extensions)
+	if op := item.GetOperation(strings.ToUpper(method)); op != nil {
+		applyEmulatorExtensions(&out, op.Extensions)
+	}
+	return out
+}
+
+func applyEmulatorExtensions(out *EmulatorExtensions, ext map[string]any) {
+	if n, ok := extensionInt(ext[ExtDelayMs]); ok && n >= 0 {
+		out.DelayMs = n
+	}
+	if n, ok := extensionInt(ext[ExtStatus]); ok && n >= 100 && n <= 599 {
+		out.Status = n
+	}
+	if s, ok := ext[ExtSample].(string); ok && strings.TrimSpace(s) != "" {
+		out.Sample = strings.TrimSpace(s)
+	}
+	if n, ok := extensionInt(ext[ExtLongPollMs]); ok && n >= 0 {
+		out.LongPollMs = n
+	}
+	if n, ok := extensionInt(ext[ExtPadBytes]); ok && n >= 0 {
+		out.PadBytes = n
+	}
+	if b, ok := ext[ExtBatch].(bool); ok {
+		out.Batch = b
+	}
+	switch v := ext[ExtJob].(type) {
+	case nil:
+	case bool:
+		if v {
+			out.Job = &JobConfig{}
+		}
+	default:
+		var job JobConfig
+		if b, err := json.Marshal(v); err == nil && json.Unmarshal(b, &job) == nil {
+			out.Job = &job
+		}
+	}
+	switch v := ext[ExtBackoff].(type) {
+	case nil:
+	case bool:
+		if v {
+			out.Backoff = &BackoffConfig{}
+			out.Backoff.Normalize()
+		}
+	default:
+		var backoff BackoffConfig
+		if b, err := json.Marshal(v); err == nil && json.Unmarshal(b, &backoff) == nil {
+			backoff.Normalize()
+			out.Backoff = &backoff
+		}
+	}
+}
+
+func extensionInt(v any) (int, bool) {
+	switch n := v.(type) {
+	case float64:
+		return int(n), true
+	case int:
+		return n, true
+	case int64:
+		return int(n), true
+	case json.Number:
+		i, err := n.Int64()
+		return int(i), err == nil
+	case string:
+		i, err := strconv.Atoi(strings.TrimSpace(n))
+		return i, err == nil
+	}
+	return 0, false
+}
+
 func (p *SpecProvider) pickBestResponseRef(resps *openapi3.Responses) *openapi3.ResponseRef {
 	if resps == nil {
 		return nil
@@ -161,133 +366,90 @@ func (p *SpecProvider) pickBestResponseRef(resps *openapi3.Responses) *openapi3.
 		return r
 	}
 
-	// Otherwise: return any
-	for _, r := range resps.Map() {
-		if r != nil {
+	// Otherwise: the first by status code, so the choice is stable
+	all := resps.Map()
+	for _, code := range sortedKeys(all) {
+		if r := all[code]; r != nil {
 			return r
 		}
 	}
 	return nil
 }
 
-func (p *SpecProvider) extractExampleFromResponse(resp *openapi3.Response) ([]byte, bool) {
-	if resp == nil || resp.Content == nil {
-		return nil, false
+func (p *SpecProvider) extractExampleFromResponse(resp *openapi3.Response) ([]byte, string, bool) {
+	if resp == nil {
+		return nil, "", false
+	}
+	return extractExample(resp.Content)
+}
+
+// extractExample returns the first explicit example of content, trying
+// media types in responseMediaTypes order.
+func extractExample(content openapi3.Content) ([]byte, string, bool) {
+	if content == nil {
+		return nil, "", false
 	}
 
-	// Try common JSON-like content types
-	for _, ct := range []string{"application/json", "application/problem+json", "*/*"} {
-		mt := resp.Content.Get(ct)
+	for _, ct := range responseMediaTypes(content) {
+		mt := content[ct]
 		if mt == nil {
 			continue
 		}
 
 		// MediaType.Example
 		if mt.Example != nil {
-			if b, err := json.Marshal(mt.Example); err == nil {
-				return b, true
+			if b, err := encodeExample(ct, mt.Example); err == nil {
+				return b, servedContentType(ct), true
 			}
 		}
 
-		if len(mt.Examples) > 0 {
-			for _, exRef := range mt.Examples {
-				if exRef == nil || exRef.Value == nil {
-					continue
-				}
-				if exRef.Value.Value != nil {
-					if b, err := json.Marshal(exRef.Value.Value); err == nil {
-						return b, true
-					}
-				}
+		for _, name := range sortedKeys(mt.Examples) {
+			exRef := mt.Examples[name]
+			if exRef == nil || exRef.Value == nil || exRef.Value.Value == nil {
+				continue
+			}
+			if b, err := encodeExample(ct, exRef.Value.Value); err == nil {
+				return b, servedContentType(ct), true
 			}
 		}
 	}
 
-	return nil, false
+	return nil, "", false
 }
 
-func (p *SpecProvider) generateFromResponseSchema(resp *openapi3.Response) ([]byte, bool) {
-	if resp == nil || resp.Content == nil {
-		return nil, false
-	}
-
-	for _, ct := range []string{"application/json", "application/problem+json", "*/*"} {
-		mt := resp.Content.Get(ct)
-		if mt == nil || mt.Schema == nil {
-			continue
-		}
-
-		val := p.genFromSchemaRef(mt.Schema, map[string]bool{}, 0)
-		b, err := json.Marshal(val)
-		return b, err == nil
+func (p *SpecProvider) generateFromResponseSchema(gen IExampleGenerator, swaggerPath, method string, resp *openapi3.Response) ([]byte, string, bool) {
+	if resp == nil {
+		return nil, "", false
 	}
-
-	return nil, false
+	return generateFromContent(gen, swaggerPath, method, resp.Content)
 }
 
-func (p *SpecProvider) genFromSchemaRef(ref *openapi3.SchemaRef, visiting map[string]bool, depth int) any {
-	if depth > 6 || ref == nil || ref.Value == nil {
-		return map[string]any{}
-	}
-
-	s := ref.Value
-
-	// enum wins
-	if len(s.Enum) > 0 {
-		return s.Enum[0]
+// generateFromContent asks gen for a body of the first media type of content
+// with a schema that gen can serve.
+func generateFromContent(gen IExampleGenerator, swaggerPath, method string, content openapi3.Content) ([]byte, string, bool) {
+	if content == nil {
+		return nil, "", false
 	}
 
-	// ARRAY
-	if s.Type != nil && s.Type.Is("array") {
-		if s.Items == nil {
-			return []any{}
+	for _, ct := range responseMediaTypes(content) {
+		mt := content[ct]
+		if mt == nil || mt.Schema == nil {
+			continue
 		}
-		return []any{p.genFromSchemaRef(s.Items, visiting, depth+1)}
-	}
 
-	// OBJECT
-	if (s.Type != nil && s.Type.Is("object")) || len(s.Properties) > 0 || s.AdditionalProperties.Schema != nil {
-		return p.genObject(s, visiting, depth)
-	}
-
-	// PRIMITIVES
-	if s.Type != nil && s.Type.Is("string") {
-		if s.Format == "date-time" {
-			return "2026-01-28T00:00:00Z"
+		val, ok := gen.GenerateExample(ExampleRequest{
+			SwaggerPath: swaggerPath,
+			Method:      method,
+			ContentType: ct,
+			Schema:      mt.Schema,
+		})
+		if !ok {
+			continue
+		}
+		if b, err := encodeExample(ct, val); err == nil {
+			return b, servedContentType(ct), true
 		}
-		return "string"
-	}
-	if s.Type != nil && s.Type.Is("integer") {
-		return 0
-	}
-	if s.Type != nil && s.Type.Is("number") {
-		return 0.0
-	}
-	if s.Type != nil && s.Type.Is("boolean") {
-		return true
-	}
-
-	// fallback
-	return map[string]any{"ok": true}
-}
-
-func (p *SpecProvider) genObject(s *openapi3.Schema, visiting map[string]bool, depth int) any {
-	out := map[string]any{}
-
-	// additionalProperties: schema form
-	if s.AdditionalProperties.Schema != nil {
-		out["key"] = p.genFromSchemaRef(s.AdditionalProperties.Schema, visiting, depth+1)
-		return out
-	}
-
-	if s.AdditionalProperties.Has != nil && *s.AdditionalProperties.Has {
-		out["key"] = "value"
-	}
-
-	// properties
-	for name, prop := range s.Properties {
-		out[name] = p.genFromSchemaRef(prop, visiting, depth+1)
 	}
 
-	return out
+	return nil, "", false
 }
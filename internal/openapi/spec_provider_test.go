@@ -8,6 +8,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/getkin/kin-openapi/openapi3"
@@ -151,6 +152,23 @@ func TestPickBestResponseRef_PicksLowest2xx(t *testing.T) {
 	}
 }
 
+func TestPickBestResponseRef_WithoutSuccessPicksLowestCode(t *testing.T) {
+	p := &SpecProvider{log: logrus.New()}
+
+	for i := 0; i < 20; i++ {
+		resps := openapi3.NewResponses()
+		resps.Delete("default")
+		resps.Set("500", &openapi3.ResponseRef{Value: &openapi3.Response{Description: ptr("500")}})
+		resps.Set("404", &openapi3.ResponseRef{Value: &openapi3.Response{Description: ptr("404")}})
+		resps.Set("409", &openapi3.ResponseRef{Value: &openapi3.Response{Description: ptr("409")}})
+
+		got := p.pickBestResponseRef(resps)
+		if got == nil || got.Value == nil || *got.Value.Description != "404" {
+			t.Fatalf("expected 404, got %#v", got)
+		}
+	}
+}
+
 func TestExtractExampleFromResponse_Example(t *testing.T) {
 	p := &SpecProvider{log: logrus.New()}
 
@@ -160,7 +178,7 @@ func TestExtractExampleFromResponse_Example(t *testing.T) {
 		},
 	}
 
-	b, ok := p.extractExampleFromResponse(resp)
+	b, _, ok := p.extractExampleFromResponse(resp)
 	if !ok {
 		t.Fatalf("expected ok")
 	}
@@ -185,7 +203,7 @@ func TestExtractExampleFromResponse_ExamplesMap(t *testing.T) {
 		},
 	}
 
-	b, ok := p.extractExampleFromResponse(resp)
+	b, _, ok := p.extractExampleFromResponse(resp)
 	if !ok {
 		t.Fatalf("expected ok")
 	}
@@ -196,7 +214,7 @@ func TestExtractExampleFromResponse_ExamplesMap(t *testing.T) {
 	}
 }
 
-func TestExtractExampleFromResponse_NoJSONContent(t *testing.T) {
+func TestExtractExampleFromResponse_TextPlainServedVerbatim(t *testing.T) {
 	p := &SpecProvider{log: logrus.New()}
 
 	resp := &openapi3.Response{
@@ -204,19 +222,70 @@ func TestExtractExampleFromResponse_NoJSONContent(t *testing.T) {
 			"text/plain": &openapi3.MediaType{Example: "hi"},
 		},
 	}
-	_, ok := p.extractExampleFromResponse(resp)
-	if ok {
-		t.Fatalf("expected false for non-json content types")
+	b, ct, ok := p.extractExampleFromResponse(resp)
+	if !ok {
+		t.Fatalf("expected ok for text/plain example")
+	}
+	if string(b) != "hi" || ct != "text/plain" {
+		t.Fatalf("unexpected body/content-type: %q %q", string(b), ct)
+	}
+}
+
+func TestExtractExampleFromResponse_PrefersJSONOverOtherMediaTypes(t *testing.T) {
+	p := &SpecProvider{log: logrus.New()}
+
+	resp := &openapi3.Response{
+		Content: openapi3.Content{
+			"text/csv":         &openapi3.MediaType{Example: "a,b\n1,2\n"},
+			"application/json": &openapi3.MediaType{Example: map[string]any{"a": 1}},
+		},
+	}
+	b, ct, ok := p.extractExampleFromResponse(resp)
+	if !ok || ct != "application/json" || string(b) != `{"a":1}` {
+		t.Fatalf("expected JSON example, got ok=%v ct=%q body=%s", ok, ct, string(b))
+	}
+}
+
+func TestExtractExampleFromResponse_NamedExamplesOctetStream(t *testing.T) {
+	p := &SpecProvider{log: logrus.New()}
+
+	resp := &openapi3.Response{
+		Content: openapi3.Content{
+			"application/octet-stream": &openapi3.MediaType{
+				Examples: openapi3.Examples{
+					"b": {Value: &openapi3.Example{Value: "second"}},
+					"a": {Value: &openapi3.Example{Value: "first"}},
+				},
+			},
+		},
+	}
+	b, ct, ok := p.extractExampleFromResponse(resp)
+	if !ok || ct != "application/octet-stream" || string(b) != "first" {
+		t.Fatalf("unexpected: ok=%v ct=%q body=%q", ok, ct, string(b))
+	}
+}
+
+func TestGenerateFromResponseSchema_WildcardServedAsJSON(t *testing.T) {
+	p := &SpecProvider{log: logrus.New()}
+
+	resp := &openapi3.Response{
+		Content: openapi3.Content{
+			"*/*": &openapi3.MediaType{Schema: &openapi3.SchemaRef{Value: &openapi3.Schema{Type: &openapi3.Types{"string"}}}},
+		},
+	}
+	b, ct, ok := p.generateFromResponseSchema(NewSchemaExampleGenerator(), "", "", resp)
+	if !ok || ct != "application/json" || string(b) != `"string"` {
+		t.Fatalf("unexpected: ok=%v ct=%q body=%s", ok, ct, string(b))
 	}
 }
 
 func TestExtractExampleFromResponse_NilGuards(t *testing.T) {
 	p := &SpecProvider{log: logrus.New()}
 
-	if _, ok := p.extractExampleFromResponse(nil); ok {
+	if _, _, ok := p.extractExampleFromResponse(nil); ok {
 		t.Fatalf("expected false")
 	}
-	if _, ok := p.extractExampleFromResponse(&openapi3.Response{}); ok {
+	if _, _, ok := p.extractExampleFromResponse(&openapi3.Response{}); ok {
 		t.Fatalf("expected false")
 	}
 }
@@ -237,7 +306,7 @@ func TestGenerateFromResponseSchema_JSON(t *testing.T) {
 		},
 	}
 
-	b, ok := p.generateFromResponseSchema(resp)
+	b, _, ok := p.generateFromResponseSchema(NewSchemaExampleGenerator(), "", "", resp)
 	if !ok {
 		t.Fatalf("expected ok")
 	}
@@ -258,7 +327,7 @@ func TestGenerateFromResponseSchema_ProblemJSON(t *testing.T) {
 			},
 		},
 	}
-	b, ok := p.generateFromResponseSchema(resp)
+	b, _, ok := p.generateFromResponseSchema(NewSchemaExampleGenerator(), "", "", resp)
 	if !ok {
 		t.Fatalf("expected ok")
 	}
@@ -279,7 +348,7 @@ func TestGenerateFromResponseSchema_StarStar(t *testing.T) {
 			},
 		},
 	}
-	b, ok := p.generateFromResponseSchema(resp)
+	b, _, ok := p.generateFromResponseSchema(NewSchemaExampleGenerator(), "", "", resp)
 	if !ok {
 		t.Fatalf("expected ok")
 	}
@@ -298,7 +367,7 @@ func TestGenerateFromResponseSchema_NoSchema(t *testing.T) {
 			"application/json": &openapi3.MediaType{},
 		},
 	}
-	_, ok := p.generateFromResponseSchema(resp)
+	_, _, ok := p.generateFromResponseSchema(NewSchemaExampleGenerator(), "", "", resp)
 	if ok {
 		t.Fatalf("expected false")
 	}
@@ -307,29 +376,25 @@ func TestGenerateFromResponseSchema_NoSchema(t *testing.T) {
 func TestGenerateFromResponseSchema_NilGuards(t *testing.T) {
 	p := &SpecProvider{log: logrus.New()}
 
-	if _, ok := p.generateFromResponseSchema(nil); ok {
+	if _, _, ok := p.generateFromResponseSchema(NewSchemaExampleGenerator(), "", "", nil); ok {
 		t.Fatalf("expected false")
 	}
-	if _, ok := p.generateFromResponseSchema(&openapi3.Response{}); ok {
+	if _, _, ok := p.generateFromResponseSchema(NewSchemaExampleGenerator(), "", "", &openapi3.Response{}); ok {
 		t.Fatalf("expected false")
 	}
 }
 
-func TestGenFromSchemaRef_EnumWins(t *testing.T) {
-	p := &SpecProvider{log: logrus.New()}
-
-	v := p.genFromSchemaRef(&openapi3.SchemaRef{Value: &openapi3.Schema{
+func TestGenerateValue_EnumWins(t *testing.T) {
+	v := generateValue(staticSource{}, &openapi3.SchemaRef{Value: &openapi3.Schema{
 		Enum: []any{"a", "b"},
-	}}, map[string]bool{}, 0)
+	}}, 0)
 
 	if v != "a" {
 		t.Fatalf("expected first enum, got %#v", v)
 	}
 }
 
-func TestGenFromSchemaRef_Primitives(t *testing.T) {
-	p := &SpecProvider{log: logrus.New()}
-
+func TestGenerateValue_Primitives(t *testing.T) {
 	tests := []struct {
 		name string
 		s    *openapi3.Schema
@@ -344,7 +409,7 @@ func TestGenFromSchemaRef_Primitives(t *testing.T) {
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			got := p.genFromSchemaRef(&openapi3.SchemaRef{Value: tc.s}, map[string]bool{}, 0)
+			got := generateValue(staticSource{}, &openapi3.SchemaRef{Value: tc.s}, 0)
 			if got != tc.want {
 				t.Fatalf("got %#v want %#v", got, tc.want)
 			}
@@ -352,13 +417,38 @@ func TestGenFromSchemaRef_Primitives(t *testing.T) {
 	}
 }
 
-func TestGenFromSchemaRef_Array(t *testing.T) {
-	p := &SpecProvider{log: logrus.New()}
+func TestGenerateValue_NumericFormats(t *testing.T) {
+	maxV := 100.0
+	tests := []struct {
+		name string
+		s    *openapi3.Schema
+		want string
+	}{
+		{"int32", &openapi3.Schema{Type: &openapi3.Types{"integer"}, Format: "int32"}, "2147483647"},
+		{"int64", &openapi3.Schema{Type: &openapi3.Types{"integer"}, Format: "int64"}, "9223372036854775807"},
+		{"int64 bounded", &openapi3.Schema{Type: &openapi3.Types{"integer"}, Format: "int64", Max: &maxV}, "100"},
+		{"float", &openapi3.Schema{Type: &openapi3.Types{"number"}, Format: "float"}, "3.1415927"},
+		{"double", &openapi3.Schema{Type: &openapi3.Types{"number"}, Format: "double"}, "3.141592653589793"},
+	}
 
-	got := p.genFromSchemaRef(&openapi3.SchemaRef{Value: &openapi3.Schema{
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			b, err := json.Marshal(generateValue(staticSource{}, &openapi3.SchemaRef{Value: tc.s}, 0))
+			if err != nil {
+				t.Fatalf("marshal: %v", err)
+			}
+			if string(b) != tc.want {
+				t.Fatalf("got %s want %s", b, tc.want)
+			}
+		})
+	}
+}
+
+func TestGenerateValue_Array(t *testing.T) {
+	got := generateValue(staticSource{}, &openapi3.SchemaRef{Value: &openapi3.Schema{
 		Type:  &openapi3.Types{"array"},
 		Items: &openapi3.SchemaRef{Value: &openapi3.Schema{Type: &openapi3.Types{"string"}}},
-	}}, map[string]bool{}, 0)
+	}}, 0)
 
 	arr, ok := got.([]any)
 	if !ok || len(arr) != 1 || arr[0] != "string" {
@@ -366,16 +456,14 @@ func TestGenFromSchemaRef_Array(t *testing.T) {
 	}
 }
 
-func TestGenFromSchemaRef_ObjectProperties(t *testing.T) {
-	p := &SpecProvider{log: logrus.New()}
-
-	got := p.genFromSchemaRef(&openapi3.SchemaRef{Value: &openapi3.Schema{
+func TestGenerateValue_ObjectProperties(t *testing.T) {
+	got := generateValue(staticSource{}, &openapi3.SchemaRef{Value: &openapi3.Schema{
 		Type: &openapi3.Types{"object"},
 		Properties: openapi3.Schemas{
 			"id":   {Value: &openapi3.Schema{Type: &openapi3.Types{"integer"}}},
 			"name": {Value: &openapi3.Schema{Type: &openapi3.Types{"string"}}},
 		},
-	}}, map[string]bool{}, 0)
+	}}, 0)
 
 	m, ok := got.(map[string]any)
 	if !ok || m["id"] != 0 || m["name"] != "string" {
@@ -383,24 +471,20 @@ func TestGenFromSchemaRef_ObjectProperties(t *testing.T) {
 	}
 }
 
-func TestGenObject_AdditionalPropertiesSchema(t *testing.T) {
-	p := &SpecProvider{log: logrus.New()}
-
+func TestGenerateValue_AdditionalPropertiesSchema(t *testing.T) {
 	s := &openapi3.Schema{}
 	s.AdditionalProperties.Schema = &openapi3.SchemaRef{
 		Value: &openapi3.Schema{Type: &openapi3.Types{"string"}},
 	}
 
-	got := p.genObject(s, map[string]bool{}, 0)
+	got := generateValue(staticSource{}, &openapi3.SchemaRef{Value: s}, 0)
 	m, ok := got.(map[string]any)
 	if !ok || m["key"] != "string" {
 		t.Fatalf("unexpected: %#v", got)
 	}
 }
 
-func TestGenObject_AdditionalPropertiesTrue(t *testing.T) {
-	p := &SpecProvider{log: logrus.New()}
-
+func TestGenerateValue_AdditionalPropertiesTrue(t *testing.T) {
 	s := &openapi3.Schema{
 		Properties: openapi3.Schemas{
 			"a": {Value: &openapi3.Schema{Type: &openapi3.Types{"integer"}}},
@@ -409,7 +493,7 @@ func TestGenObject_AdditionalPropertiesTrue(t *testing.T) {
 	b := true
 	s.AdditionalProperties.Has = &b
 
-	got := p.genObject(s, map[string]bool{}, 0)
+	got := generateValue(staticSource{}, &openapi3.SchemaRef{Value: s}, 0)
 	m, ok := got.(map[string]any)
 	if !ok {
 		t.Fatalf("unexpected: %#v", got)
@@ -422,12 +506,10 @@ func TestGenObject_AdditionalPropertiesTrue(t *testing.T) {
 	}
 }
 
-func TestGenFromSchemaRef_DepthLimit(t *testing.T) {
-	p := &SpecProvider{log: logrus.New()}
-
-	got := p.genFromSchemaRef(&openapi3.SchemaRef{Value: &openapi3.Schema{
+func TestGenerateValue_DepthLimit(t *testing.T) {
+	got := generateValue(staticSource{}, &openapi3.SchemaRef{Value: &openapi3.Schema{
 		Type: &openapi3.Types{"object"},
-	}}, map[string]bool{}, 7)
+	}}, 7)
 
 	m, ok := got.(map[string]any)
 	if !ok || len(m) != 0 {
@@ -435,27 +517,52 @@ func TestGenFromSchemaRef_DepthLimit(t *testing.T) {
 	}
 }
 
-func TestGenFromSchemaRef_NilGuards(t *testing.T) {
-	p := &SpecProvider{log: logrus.New()}
-
-	got := p.genFromSchemaRef(nil, map[string]bool{}, 0)
+func TestGenerateValue_NilGuards(t *testing.T) {
+	got := generateValue(staticSource{}, nil, 0)
 	if _, ok := got.(map[string]any); !ok {
 		t.Fatalf("expected map fallback, got %#v", got)
 	}
 
-	got = p.genFromSchemaRef(&openapi3.SchemaRef{Value: nil}, map[string]bool{}, 0)
+	got = generateValue(staticSource{}, &openapi3.SchemaRef{Value: nil}, 0)
 	if _, ok := got.(map[string]any); !ok {
 		t.Fatalf("expected map fallback, got %#v", got)
 	}
 }
 
+// orderSource records the order in which scalar properties are generated.
+type orderSource struct {
+	staticSource
+	seen []string
+}
+
+func (o *orderSource) scalar(s *openapi3.Schema) (any, bool) {
+	o.seen = append(o.seen, s.Description)
+	return o.staticSource.scalar(s)
+}
+
+func TestGenerateValue_PropertiesInSortedOrder(t *testing.T) {
+	props := openapi3.Schemas{}
+	for _, name := range []string{"zeta", "alpha", "mid", "beta"} {
+		props[name] = &openapi3.SchemaRef{Value: &openapi3.Schema{Type: &openapi3.Types{"string"}, Description: name}}
+	}
+	schema := &openapi3.SchemaRef{Value: &openapi3.Schema{Type: &openapi3.Types{"object"}, Properties: props}}
+
+	for i := 0; i < 20; i++ {
+		src := &orderSource{}
+		generateValue(src, schema, 0)
+		if strings.Join(src.seen, ",") != "alpha,beta,mid,zeta" {
+			t.Fatalf("unexpected order: %v", src.seen)
+		}
+	}
+}
+
 func TestTryGetExampleBody_NoOperation(t *testing.T) {
 	p := &SpecProvider{
 		spec: &Spec{Doc3: &openapi3.T{}},
 		log:  logrus.New(),
 	}
 
-	_, ok := p.TryGetExampleBody("/missing", "get")
+	_, _, ok := p.TryGetExampleBody("/missing", "get")
 	if ok {
 		t.Fatalf("expected false when operation not found or responses nil")
 	}
@@ -480,7 +587,7 @@ func TestTryGetExampleBody_ResponseMissingValue_FallbackOK(t *testing.T) {
 		log:  logrus.New(),
 	}
 
-	b, ok := p.TryGetExampleBody("/x", "get")
+	b, _, ok := p.TryGetExampleBody("/x", "get")
 	if !ok {
 		t.Fatalf("expected ok")
 	}
@@ -519,7 +626,7 @@ func TestTryGetExampleBody_ExplicitExampleWins(t *testing.T) {
 		log:  logrus.New(),
 	}
 
-	b, ok := p.TryGetExampleBody("/x", "get")
+	b, _, ok := p.TryGetExampleBody("/x", "get")
 	if !ok {
 		t.Fatalf("expected ok")
 	}
@@ -563,7 +670,7 @@ func TestTryGetExampleBody_SchemaGeneratedWhenNoExample(t *testing.T) {
 		log:  logrus.New(),
 	}
 
-	b, ok := p.TryGetExampleBody("/x", "get")
+	b, _, ok := p.TryGetExampleBody("/x", "get")
 	if !ok {
 		t.Fatalf("expected ok")
 	}
@@ -589,7 +696,7 @@ func TestTryGetExampleBody_FallbackOk(t *testing.T) {
 		log:  logrus.New(),
 	}
 
-	b, ok := p.TryGetExampleBody("/health", "get")
+	b, _, ok := p.TryGetExampleBody("/health", "get")
 	if !ok {
 		t.Fatalf("expected ok")
 	}
@@ -642,4 +749,361 @@ func TestNewSpecProvider_GetSpec_OpenAPI3(t *testing.T) {
 	}
 }
 
+func TestGetEmulatorExtensions_OperationOverridesPathItem(t *testing.T) {
+	dir := t.TempDir()
+	p := filepath.Join(dir, "oas3.json")
+
+	specJSON := `{
+	  "openapi":"3.0.3",
+	  "info":{"title":"t","version":"1"},
+	  "paths":{
+		"/jobs/{id}":{
+		  "x-emulator-delay-ms": 50,
+		  "x-emulator-status": 200,
+		  "get":{
+			"x-emulator-status": 202,
+			"x-emulator-sample": "jobs/pending.json",
+			"x-emulator-batch": true,
+			"x-emulator-pad-bytes": 4096,
+			"responses":{"200":{"description":"ok"}}
+		  },
+		  "delete":{
+			"x-emulator-status": "nope",
+			"responses":{"204":{"description":"gone"}}
+		  }
+		}
+	  }
+	}`
+
+	if err := os.WriteFile(p, []byte(specJSON), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	provider, err := NewSpecProvider(p, logrus.New())
+	if err != nil {
+		t.Fatalf("NewSpecProvider: %v", err)
+	}
+
+	got := provider.GetEmulatorExtensions("/jobs/{id}", "get")
+	want := EmulatorExtensions{DelayMs: 50, Status: 202, Sample: "jobs/pending.json", Batch: true, PadBytes: 4096}
+	if got != want {
+		t.Fatalf("expected %#v, got %#v", want, got)
+	}
+
+	got = provider.GetEmulatorExtensions("/jobs/{id}", "delete")
+	want = EmulatorExtensions{DelayMs: 50, Status: 200}
+	if got != want {
+		t.Fatalf("expected %#v, got %#v", want, got)
+	}
+
+	if got := provider.GetEmulatorExtensions("/missing", "get"); got != (EmulatorExtensions{}) {
+		t.Fatalf("expected zero value, got %#v", got)
+	}
+}
+
+func TestGetEmulatorExtensions_Job(t *testing.T) {
+	dir := t.TempDir()
+	p := filepath.Join(dir, "oas3.json")
+
+	specJSON := `{
+	  "openapi":"3.0.3",
+	  "info":{"title":"t","version":"1"},
+	  "paths":{
+		"/scans":{"post":{"x-emulator-job": true, "responses":{"202":{"description":"ok"}}}},
+		"/exports":{"post":{
+		  "x-emulator-job": {"durationSec": 3, "states": ["pending", "ready"], "resultPath": "/downloads/{id}"},
+		  "responses":{"202":{"description":"ok"}}
+		}}
+	  }
+	}`
+	if err := os.WriteFile(p, []byte(specJSON), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	provider, err := NewSpecProvider(p, logrus.New())
+	if err != nil {
+		t.Fatalf("NewSpecProvider: %v", err)
+	}
+
+	job := provider.GetEmulatorExtensions("/scans", "post").Job
+	if job == nil {
+		t.Fatalf("expected job config")
+	}
+	job.Normalize("/scans")
+	if job.DurationSec != 10 || len(job.States) != 3 || job.StatusPath != "/scans/{id}" || job.ResultPath != "/scans/{id}/result" {
+		t.Fatalf("unexpected defaults: %#v", job)
+	}
+
+	job = provider.GetEmulatorExtensions("/exports", "post").Job
+	job.Normalize("/exports")
+	if job.DurationSec != 3 || job.States[1] != "ready" || job.StatusPath != "/exports/{id}" || job.ResultPath != "/downloads/{id}" {
+		t.Fatalf("unexpected config: %#v", job)
+	}
+}
+
+func TestGetEmulatorExtensions_Backoff(t *testing.T) {
+	dir := t.TempDir()
+	p := filepath.Join(dir, "oas3.json")
+
+	specJSON := `{
+	  "openapi":"3.0.3",
+	  "info":{"title":"t","version":"1"},
+	  "paths":{
+		"/a":{"get":{"x-emulator-backoff": true, "responses":{"200":{"description":"ok"}}}},
+		"/b":{"get":{
+		  "x-emulator-backoff": {"failures": 4, "status": 429, "retryAfterSec": 7},
+		  "responses":{"200":{"description":"ok"}}
+		}},
+		"/c":{"get":{"x-emulator-backoff": {"status": 500}, "responses":{"200":{"description":"ok"}}}}
+	  }
+	}`
+	if err := os.WriteFile(p, []byte(specJSON), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	provider, err := NewSpecProvider(p, logrus.New())
+	if err != nil {
+		t.Fatalf("NewSpecProvider: %v", err)
+	}
+
+	want := map[string]BackoffConfig{
+		"/a": {Failures: 2, Status: 503, RetryAfterSec: 1},
+		"/b": {Failures: 4, Status: 429, RetryAfterSec: 7},
+		"/c": {Failures: 2, Status: 503, RetryAfterSec: 1},
+	}
+	for path, exp := range want {
+		got := provider.GetEmulatorExtensions(path, "get").Backoff
+		if got == nil || *got != exp {
+			t.Fatalf("%s: unexpected backoff: %#v", path, got)
+		}
+	}
+}
+
+func TestTryGetExampleBody_MemoizedUntilReload(t *testing.T) {
+	dir := t.TempDir()
+	p := filepath.Join(dir, "oas3.json")
+
+	specWithExample := func(v string) string {
+		return `{
+		  "openapi":"3.0.3",
+		  "info":{"title":"t","version":"1"},
+		  "paths":{
+			"/x":{
+			  "get":{
+				"responses":{
+				  "200":{
+					"description":"ok",
+					"content":{"application/json":{"example":{"v":"` + v + `"}}}
+				  }
+				}
+			  }
+			}
+		  }
+		}`
+	}
+
+	if err := os.WriteFile(p, []byte(specWithExample("one")), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	provider, err := NewSpecProvider(p, logrus.New())
+	if err != nil {
+		t.Fatalf("NewSpecProvider: %v", err)
+	}
+
+	b, _, _ := provider.TryGetExampleBody("/x", "get")
+	if string(b) != `{"v":"one"}` {
+		t.Fatalf("unexpected body: %s", b)
+	}
+
+	// Mutating the loaded document must not change the memoized body.
+	op := provider.FindOperation("/x", "get")
+	op.Responses.Value("200").Value.Content.Get("application/json").Example = map[string]any{"v": "mutated"}
+	b, _, _ = provider.TryGetExampleBody("/x", "get")
+	if string(b) != `{"v":"one"}` {
+		t.Fatalf("expected memoized body, got %s", b)
+	}
+
+	if err := os.WriteFile(p, []byte(specWithExample("two")), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := provider.Reload(); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	b, _, _ = provider.TryGetExampleBody("/x", "get")
+	if string(b) != `{"v":"two"}` {
+		t.Fatalf("expected body from reloaded spec, got %s", b)
+	}
+}
+
+func TestReload_InvalidSpecKeepsOld(t *testing.T) {
+	dir := t.TempDir()
+	p := filepath.Join(dir, "oas3.json")
+	if err := os.WriteFile(p, []byte(`{"openapi":"3.0.3","info":{"title":"t","version":"1"},"paths":{"/x":{"get":{"responses":{"200":{"description":"ok"}}}}}}`), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	provider, err := NewSpecProvider(p, logrus.New())
+	if err != nil {
+		t.Fatalf("NewSpecProvider: %v", err)
+	}
+
+	if err := os.WriteFile(p, []byte(`{not json`), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := provider.Reload(); err == nil {
+		t.Fatalf("expected reload error")
+	}
+	if provider.FindOperation("/x", "get") == nil {
+		t.Fatalf("expected previous spec to stay active")
+	}
+}
+
 func ptr(s string) *string { return &s }
+
+type fixedGenerator struct {
+	val any
+	req ExampleRequest
+}
+
+func (g *fixedGenerator) GenerateExample(req ExampleRequest) (any, bool) {
+	g.req = req
+	if g.val == nil {
+		return nil, false
+	}
+	return g.val, true
+}
+
+func schemaOnlyDoc() *openapi3.T {
+	paths := openapi3.NewPaths()
+	paths.Set("/x", &openapi3.PathItem{
+		Get: &openapi3.Operation{
+			Responses: func() *openapi3.Responses {
+				r := openapi3.NewResponses()
+				r.Set("200", &openapi3.ResponseRef{
+					Value: &openapi3.Response{
+						Content: openapi3.Content{
+							"application/json": &openapi3.MediaType{
+								Schema: &openapi3.SchemaRef{Value: &openapi3.Schema{Type: &openapi3.Types{"string"}}},
+							},
+						},
+					},
+				})
+				return r
+			}(),
+		},
+	})
+	return &openapi3.T{Paths: paths}
+}
+
+func TestTryGetExampleBody_InjectedGenerator(t *testing.T) {
+	gen := &fixedGenerator{val: map[string]any{"from": "plugin"}}
+	p := &SpecProvider{spec: &Spec{Doc3: schemaOnlyDoc()}, log: logrus.New()}
+	WithExampleGenerator(gen)(p)
+
+	b, ct, ok := p.TryGetExampleBody("/x", "get")
+	if !ok || ct != "application/json" {
+		t.Fatalf("ok=%v ct=%q", ok, ct)
+	}
+	if string(b) != `{"from":"plugin"}` {
+		t.Fatalf("unexpected body: %s", b)
+	}
+	if gen.req.SwaggerPath != "/x" || gen.req.Method != "get" || gen.req.ContentType != "application/json" || gen.req.Schema == nil {
+		t.Fatalf("unexpected request: %#v", gen.req)
+	}
+}
+
+func TestTryGetExampleBody_InjectedGeneratorDeclines(t *testing.T) {
+	p := &SpecProvider{spec: &Spec{Doc3: schemaOnlyDoc()}, log: logrus.New()}
+	WithExampleGenerator(&fixedGenerator{})(p)
+
+	b, _, ok := p.TryGetExampleBody("/x", "get")
+	if !ok || string(b) != `{"ok":true}` {
+		t.Fatalf("expected default body, got ok=%v %s", ok, b)
+	}
+}
+
+func TestTryGetRandomBody_InjectedGenerator(t *testing.T) {
+	p := &SpecProvider{spec: &Spec{Doc3: schemaOnlyDoc()}, log: logrus.New()}
+	WithRandomGenerator(&fixedGenerator{val: "recorded"})(p)
+
+	b, _, ok := p.TryGetRandomBody("/x", "get")
+	if !ok || string(b) != `"recorded"` {
+		t.Fatalf("unexpected: ok=%v %s", ok, b)
+	}
+}
+
+// blockingGenerator holds its first call until release is closed.
+type blockingGenerator struct {
+	calls   int
+	started chan struct{}
+	release chan struct{}
+}
+
+func (g *blockingGenerator) GenerateExample(ExampleRequest) (any, bool) {
+	g.calls++
+	if g.calls == 1 {
+		close(g.started)
+		<-g.release
+		return "old", true
+	}
+	return "new", true
+}
+
+func TestTryGetExampleBody_ReloadDuringBuildIsNotMemoized(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "oas3.json")
+	spec := `{"openapi":"3.0.3","info":{"title":"t","version":"1"},"paths":{"/x":{"get":{"responses":{"200":{
+	  "description":"ok","content":{"application/json":{"schema":{"type":"string"}}}}}}}}}`
+	if err := os.WriteFile(p, []byte(spec), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	gen := &blockingGenerator{started: make(chan struct{}), release: make(chan struct{})}
+	provider, err := NewSpecProvider(p, logrus.New(), WithExampleGenerator(gen))
+	if err != nil {
+		t.Fatalf("NewSpecProvider: %v", err)
+	}
+
+	done := make(chan []byte)
+	go func() {
+		b, _, _ := provider.TryGetExampleBody("/x", "get")
+		done <- b
+	}()
+	<-gen.started
+	if err := provider.Reload(); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	close(gen.release)
+	if b := <-done; string(b) != `"old"` {
+		t.Fatalf("unexpected in-flight body: %s", b)
+	}
+
+	if b, _, _ := provider.TryGetExampleBody("/x", "get"); string(b) != `"new"` {
+		t.Fatalf("expected a body built from the reloaded spec, got %s", b)
+	}
+}
+
+func TestTryGetExampleBodyFor_MemoizedPerMediaType(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "oas3.json")
+	spec := `{"openapi":"3.0.3","info":{"title":"t","version":"1"},"paths":{"/x":{"get":{"responses":{"200":{
+	  "description":"ok","content":{
+	    "application/json":{"example":{"v":1}},
+	    "text/csv; charset=utf-8":{"example":"v\n1\n"}
+	  }}}}}}}`
+	if err := os.WriteFile(p, []byte(spec), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	provider, err := NewSpecProvider(p, logrus.New())
+	if err != nil {
+		t.Fatalf("NewSpecProvider: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		b, ct, ok := provider.TryGetExampleBodyFor("/x", "get", "text/csv")
+		if !ok || !strings.HasPrefix(ct, "text/csv") || string(b) != "v\n1\n" {
+			t.Fatalf("expected the CSV example, got ok=%v %q %q", ok, ct, b)
+		}
+		b, ct, ok = provider.TryGetExampleBody("/x", "get")
+		if !ok || ct != "application/json" || string(b) != `{"v":1}` {
+			t.Fatalf("expected the JSON example, got ok=%v %q %s", ok, ct, b)
+		}
+	}
+	if _, _, ok := provider.TryGetExampleBodyFor("/x", "get", "application/xml"); ok {
+		t.Fatalf("expected no body for an undeclared media type")
+	}
+}
@@ -6,11 +6,26 @@ package openapi
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
 )
 
+// formatUUID accepts any RFC 9562 UUID layout, regardless of version.
+const formatUUID = `^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`
+
+func init() {
+	// kin-openapi only checks byte/date/date-time out of the box; ids in
+	// paths are commonly declared as uuid.
+	openapi3.DefineStringFormatValidator("uuid", openapi3.NewRegexpFormatValidator(formatUUID))
+}
+
 type Validator struct {
 	spec ISpecProvider
 }
@@ -30,13 +45,321 @@ func (v *Validator) HasRequiredBodyParam(swaggerPath, method string) bool {
 }
 
 func (v *Validator) IsEmptyBody(r *http.Request) (bool, error) {
+	b, err := readBody(r)
+	if err != nil {
+		return false, err
+	}
+	return len(strings.TrimSpace(string(b))) == 0, nil
+}
+
+// ValidateRequestBody validates the request body against the operation's
+// requestBody schema. Requests without a Content-Type are treated as JSON.
+// The returned error is only set when the body cannot be read.
+func (v *Validator) ValidateRequestBody(r *http.Request, swaggerPath, method string) ([]FieldError, error) {
+	op := v.spec.FindOperation(swaggerPath, method)
+	if op == nil || op.RequestBody == nil || op.RequestBody.Value == nil {
+		return nil, nil
+	}
+
+	body, err := readBody(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if ct := r.Header.Get("Content-Type"); ct != "" {
+		if schema, ok := formSchema(op.RequestBody.Value, ct); ok {
+			return validateFormBody(body, ct, schema), nil
+		}
+	}
+
+	req := r.Clone(r.Context())
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	req.GetBody = nil
+	if req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	input := &openapi3filter.RequestValidationInput{
+		Request: req,
+		Options: &openapi3filter.Options{
+			MultiError:          true,
+			SkipSettingDefaults: true,
+		},
+	}
+	return fieldErrors(openapi3filter.ValidateRequestBody(r.Context(), input, op.RequestBody.Value)), nil
+}
+
+// formSchema returns the schema the operation declares for a URL-encoded or
+// multipart request of the given content type.
+func formSchema(body *openapi3.RequestBody, contentType string) (*openapi3.Schema, bool) {
+	base := baseMediaType(contentType)
+	if base != mediaTypeForm && base != mediaTypeMultipart {
+		return nil, false
+	}
+	media := body.Content.Get(base)
+	if media == nil || media.Schema == nil || media.Schema.Value == nil {
+		return nil, false
+	}
+	return media.Schema.Value, true
+}
+
+// ValidateContentType checks the request Content-Type against the media types
+// of the operation's requestBody. Requests without a Content-Type, and
+// operations without request content, always pass. Declared wildcards such as
+// */* or text/* match accordingly.
+func (v *Validator) ValidateContentType(r *http.Request, swaggerPath, method string) ([]string, bool) {
+	op := v.spec.FindOperation(swaggerPath, method)
+	if op == nil || op.RequestBody == nil || op.RequestBody.Value == nil || len(op.RequestBody.Value.Content) == 0 {
+		return nil, true
+	}
+	ct := strings.TrimSpace(r.Header.Get("Content-Type"))
+	if ct == "" {
+		return nil, true
+	}
+
+	supported := sortedKeys(op.RequestBody.Value.Content)
+	base := baseMediaType(ct)
+	for _, declared := range supported {
+		if mediaTypeMatches(baseMediaType(declared), base) {
+			return supported, true
+		}
+	}
+	return supported, false
+}
+
+// ValidateResponseBody checks a response body against the schema the spec
+// declares for the status (or the default response) and content type. An
+// empty content type means JSON. Bodies of non-JSON media types and responses
+// without a schema always pass; an undeclared status or content type is
+// reported as an error.
+func (v *Validator) ValidateResponseBody(swaggerPath, method string, status int, contentType string, body []byte) []FieldError {
+	op := v.spec.FindOperation(swaggerPath, method)
+	if op == nil || op.Responses == nil {
+		return nil
+	}
+
+	ref := op.Responses.Status(status)
+	if ref == nil {
+		ref = op.Responses.Default()
+	}
+	if ref == nil || ref.Value == nil {
+		return []FieldError{{Field: "/", Reason: fmt.Sprintf("status %d is not declared", status)}}
+	}
+	if len(ref.Value.Content) == 0 {
+		return nil
+	}
+
+	base := defaultContentType
+	if strings.TrimSpace(contentType) != "" {
+		base = baseMediaType(contentType)
+	}
+	var media *openapi3.MediaType
+	for _, declared := range responseMediaTypes(ref.Value.Content) {
+		if mediaTypeMatches(baseMediaType(declared), base) {
+			media = ref.Value.Content[declared]
+			break
+		}
+	}
+	if media == nil {
+		return []FieldError{{Field: "/", Reason: fmt.Sprintf("content type %q is not declared for status %d", base, status)}}
+	}
+	if media.Schema == nil || media.Schema.Value == nil || !isJSONMediaType(base) {
+		return nil
+	}
+
+	var value any
+	if err := json.Unmarshal(body, &value); err != nil {
+		return []FieldError{{Field: "/", Reason: "body is not valid JSON: " + err.Error()}}
+	}
+	return fieldErrors(media.Schema.Value.VisitJSON(value, openapi3.MultiErrors(), openapi3.VisitAsResponse()))
+}
+
+func mediaTypeMatches(declared, actual string) bool {
+	if declared == actual || declared == "*/*" {
+		return true
+	}
+	if prefix, ok := strings.CutSuffix(declared, "/*"); ok {
+		return strings.HasPrefix(actual, prefix+"/")
+	}
+	return false
+}
+
+// ValidateParameters validates the request's parameters of one location
+// ("query", "path", "header" or "cookie") against the operation and path item
+// parameter declarations.
+func (v *Validator) ValidateParameters(r *http.Request, swaggerPath, method, in string) []FieldError {
+	params := v.operationParameters(swaggerPath, method)
+	if len(params) == 0 {
+		return nil
+	}
+
+	input := &openapi3filter.RequestValidationInput{
+		Request:    r,
+		PathParams: ContextPathParams(r.Context(), swaggerPath, r.URL.Path),
+		Options: &openapi3filter.Options{
+			MultiError:          true,
+			SkipSettingDefaults: true,
+		},
+	}
+
+	var out []FieldError
+	for _, p := range params {
+		if p.In != in || ignoredHeaderParameter(p) {
+			continue
+		}
+		if err := openapi3filter.ValidateParameter(r.Context(), input, p); err != nil {
+			out = append(out, FieldError{In: p.In, Field: p.Name, Reason: parameterReason(err)})
+		}
+	}
+	return out
+}
+
+// UndeclaredQueryParams reports query parameters the operation does not
+// declare. deepObject parameters cover their name[key] forms, and the query
+// names of the spec's apiKey security schemes count as declared.
+func (v *Validator) UndeclaredQueryParams(r *http.Request, swaggerPath, method string) []FieldError {
+	declared := map[string]bool{}
+	var deepObjects []string
+	for _, p := range v.operationParameters(swaggerPath, method) {
+		if p.In != openapi3.ParameterInQuery {
+			continue
+		}
+		declared[p.Name] = true
+		if p.Style == openapi3.SerializationDeepObject {
+			deepObjects = append(deepObjects, p.Name+"[")
+		}
+	}
+	if spec := v.spec.GetSpec(); spec != nil && spec.Doc3 != nil && spec.Doc3.Components != nil {
+		for _, ref := range spec.Doc3.Components.SecuritySchemes {
+			if ref != nil && ref.Value != nil && strings.EqualFold(ref.Value.Type, "apiKey") &&
+				ref.Value.In == openapi3.ParameterInQuery {
+				declared[ref.Value.Name] = true
+			}
+		}
+	}
+
+	var out []FieldError
+	for _, name := range sortedKeys(r.URL.Query()) {
+		if declared[name] || hasAnyPrefix(name, deepObjects) {
+			continue
+		}
+		out = append(out, FieldError{In: openapi3.ParameterInQuery, Field: name, Reason: "parameter is not declared in the API spec"})
+	}
+	return out
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(s, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// operationParameters merges path item and operation parameters; operation
+// parameters override path item ones with the same name and location.
+func (v *Validator) operationParameters(swaggerPath, method string) []*openapi3.Parameter {
+	spec := v.spec.GetSpec()
+	if spec == nil || spec.Doc3 == nil || spec.Doc3.Paths == nil {
+		return nil
+	}
+	item := spec.Doc3.Paths.Find(swaggerPath)
+	if item == nil {
+		return nil
+	}
+	op := item.GetOperation(strings.ToUpper(method))
+	if op == nil {
+		return nil
+	}
+
+	var out []*openapi3.Parameter
+	seen := map[string]bool{}
+	for _, list := range []openapi3.Parameters{op.Parameters, item.Parameters} {
+		for _, ref := range list {
+			if ref == nil || ref.Value == nil {
+				continue
+			}
+			key := ref.Value.In + ":" + ref.Value.Name
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			out = append(out, ref.Value)
+		}
+	}
+	return out
+}
+
+// ignoredHeaderParameter reports header parameters the OpenAPI spec says to
+// ignore; they are described by requestBody, responses and securitySchemes.
+func ignoredHeaderParameter(p *openapi3.Parameter) bool {
+	if p.In != openapi3.ParameterInHeader {
+		return false
+	}
+	switch http.CanonicalHeaderKey(p.Name) {
+	case "Accept", "Content-Type", "Authorization":
+		return true
+	}
+	return false
+}
+
+func parameterReason(err error) string {
+	var schemaErr *openapi3.SchemaError
+	if errors.As(err, &schemaErr) {
+		return schemaErr.Reason
+	}
+	var reqErr *openapi3filter.RequestError
+	if errors.As(err, &reqErr) {
+		if reqErr.Reason != "" {
+			return reqErr.Reason
+		}
+		if reqErr.Err != nil {
+			return reqErr.Err.Error()
+		}
+	}
+	return err.Error()
+}
+
+// readBody reads the request body and puts it back so later readers see it.
+func readBody(r *http.Request) ([]byte, error) {
 	if r.Body == nil {
-		return true, nil
+		return nil, nil
 	}
 	b, err := io.ReadAll(r.Body)
 	if err != nil {
-		return false, err
+		return nil, err
 	}
 	r.Body = io.NopCloser(bytes.NewReader(b))
-	return len(strings.TrimSpace(string(b))) == 0, nil
+	return b, nil
+}
+
+// fieldErrors flattens kin-openapi validation errors into FieldErrors.
+func fieldErrors(err error) []FieldError {
+	if err == nil {
+		return nil
+	}
+
+	var multi openapi3.MultiError
+	if errors.As(err, &multi) {
+		var out []FieldError
+		for _, e := range multi {
+			out = append(out, fieldErrors(e)...)
+		}
+		return out
+	}
+
+	var schemaErr *openapi3.SchemaError
+	if errors.As(err, &schemaErr) {
+		return []FieldError{{
+			Field:  "/" + strings.Join(schemaErr.JSONPointer(), "/"),
+			Reason: schemaErr.Reason,
+		}}
+	}
+
+	var reqErr *openapi3filter.RequestError
+	if errors.As(err, &reqErr) && reqErr.Err == nil {
+		return []FieldError{{Field: "/", Reason: reqErr.Reason}}
+	}
+	return []FieldError{{Field: "/", Reason: err.Error()}}
 }
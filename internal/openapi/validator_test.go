@@ -9,9 +9,11 @@ import (
 	"errors"
 	"io"
 	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 
+	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
@@ -22,10 +24,28 @@ type MockSpecProvider struct {
 	mock.Mock
 }
 
-func (m *MockSpecProvider) TryGetExampleBody(swaggerPath, method string) ([]byte, bool) {
+func (m *MockSpecProvider) TryGetExampleBody(swaggerPath, method string) ([]byte, string, bool) {
 	args := m.Called(swaggerPath, method)
 	b, _ := args.Get(0).([]byte)
-	return b, args.Bool(1)
+	return b, args.String(1), args.Bool(2)
+}
+
+func (m *MockSpecProvider) TryGetExampleBodyFor(swaggerPath, method, mediaType string) ([]byte, string, bool) {
+	args := m.Called(swaggerPath, method, mediaType)
+	b, _ := args.Get(0).([]byte)
+	return b, args.String(1), args.Bool(2)
+}
+
+func (m *MockSpecProvider) TryGetRandomBody(swaggerPath, method string) ([]byte, string, bool) {
+	args := m.Called(swaggerPath, method)
+	b, _ := args.Get(0).([]byte)
+	return b, args.String(1), args.Bool(2)
+}
+
+func (m *MockSpecProvider) TryGetBoundaryBody(swaggerPath, method string, kind BoundaryKind) ([]byte, string, bool) {
+	args := m.Called(swaggerPath, method, kind)
+	b, _ := args.Get(0).([]byte)
+	return b, args.String(1), args.Bool(2)
 }
 
 func (m *MockSpecProvider) FindOperation(swaggerPath, method string) *openapi3.Operation {
@@ -34,12 +54,35 @@ func (m *MockSpecProvider) FindOperation(swaggerPath, method string) *openapi3.O
 	return op
 }
 
+func (m *MockSpecProvider) GetEmulatorExtensions(swaggerPath, method string) EmulatorExtensions {
+	args := m.Called(swaggerPath, method)
+	ext, _ := args.Get(0).(EmulatorExtensions)
+	return ext
+}
+
+func (m *MockSpecProvider) FuzzRequestBody(swaggerPath, method string, n int) (*FuzzResult, bool) {
+	args := m.Called(swaggerPath, method, n)
+	res, _ := args.Get(0).(*FuzzResult)
+	return res, args.Bool(1)
+}
+
+func (m *MockSpecProvider) OperationExamples(operationID string) (*OperationExamples, bool) {
+	args := m.Called(operationID)
+	res, _ := args.Get(0).(*OperationExamples)
+	return res, args.Bool(1)
+}
+
 func (m *MockSpecProvider) GetSpec() *Spec {
 	args := m.Called()
 	op, _ := args.Get(0).(*Spec)
 	return op
 }
 
+func (m *MockSpecProvider) Reload() error {
+	args := m.Called()
+	return args.Error(0)
+}
+
 type errReader struct{}
 
 func (errReader) Read(p []byte) (int, error) { return 0, errors.New("boom") }
@@ -181,3 +224,298 @@ func TestValidator_IsEmptyBody_ReadError(t *testing.T) {
 	_, err := v.IsEmptyBody(req)
 	require.Error(t, err)
 }
+
+func schemaBodyOperation() *openapi3.Operation {
+	minAge := 0.0
+	return &openapi3.Operation{
+		RequestBody: &openapi3.RequestBodyRef{Value: &openapi3.RequestBody{
+			Required: true,
+			Content: openapi3.Content{
+				"application/json": &openapi3.MediaType{Schema: &openapi3.SchemaRef{Value: &openapi3.Schema{
+					Type:     &openapi3.Types{"object"},
+					Required: []string{"name"},
+					Properties: openapi3.Schemas{
+						"name": {Value: &openapi3.Schema{Type: &openapi3.Types{"string"}}},
+						"age":  {Value: &openapi3.Schema{Type: &openapi3.Types{"integer"}, Min: &minAge}},
+					},
+				}}},
+			},
+		}},
+		Responses: openapi3.NewResponses(),
+	}
+}
+
+func TestValidator_ValidateRequestBody_Valid(t *testing.T) {
+	m := new(MockSpecProvider)
+	v := NewValidator(m)
+	m.On("FindOperation", "/x", "post").Return(schemaBodyOperation()).Once()
+
+	r := httptest.NewRequest(http.MethodPost, "/x", strings.NewReader(`{"name":"a","age":3}`))
+
+	errs, err := v.ValidateRequestBody(r, "/x", "post")
+	require.NoError(t, err)
+	require.Empty(t, errs)
+
+	b, _ := io.ReadAll(r.Body)
+	require.Equal(t, `{"name":"a","age":3}`, string(b))
+}
+
+func TestValidator_ValidateRequestBody_FieldErrors(t *testing.T) {
+	m := new(MockSpecProvider)
+	v := NewValidator(m)
+	m.On("FindOperation", "/x", "post").Return(schemaBodyOperation()).Once()
+
+	r := httptest.NewRequest(http.MethodPost, "/x", strings.NewReader(`{"age":-1}`))
+	r.Header.Set("Content-Type", "application/json")
+
+	errs, err := v.ValidateRequestBody(r, "/x", "post")
+	require.NoError(t, err)
+
+	fields := map[string]bool{}
+	for _, e := range errs {
+		fields[e.Field] = true
+	}
+	require.True(t, fields["/name"], "missing /name in %v", errs)
+	require.True(t, fields["/age"], "missing /age in %v", errs)
+}
+
+func TestValidator_ValidateRequestBody_MalformedJSON(t *testing.T) {
+	m := new(MockSpecProvider)
+	v := NewValidator(m)
+	m.On("FindOperation", "/x", "post").Return(schemaBodyOperation()).Once()
+
+	r := httptest.NewRequest(http.MethodPost, "/x", strings.NewReader(`{`))
+
+	errs, err := v.ValidateRequestBody(r, "/x", "post")
+	require.NoError(t, err)
+	require.Len(t, errs, 1)
+	require.Equal(t, "/", errs[0].Field)
+}
+
+func TestValidator_ValidateRequestBody_NoRequestBody(t *testing.T) {
+	m := new(MockSpecProvider)
+	v := NewValidator(m)
+	m.On("FindOperation", "/x", "get").Return(&openapi3.Operation{Responses: openapi3.NewResponses()}).Once()
+
+	errs, err := v.ValidateRequestBody(httptest.NewRequest(http.MethodGet, "/x", nil), "/x", "get")
+	require.NoError(t, err)
+	require.Empty(t, errs)
+}
+
+func paramTestValidator(params ...*openapi3.Parameter) IValidator {
+	var refs openapi3.Parameters
+	for _, p := range params {
+		refs = append(refs, &openapi3.ParameterRef{Value: p})
+	}
+	paths := openapi3.NewPaths()
+	paths.Set("/items/{id}", &openapi3.PathItem{
+		Get: &openapi3.Operation{Parameters: refs, Responses: openapi3.NewResponses()},
+	})
+	return NewValidator(&SpecProvider{
+		spec: &Spec{Doc3: &openapi3.T{Paths: paths}},
+		log:  logrus.New(),
+	})
+}
+
+func TestValidator_ValidateParameters_Query(t *testing.T) {
+	v := paramTestValidator(
+		&openapi3.Parameter{In: "query", Name: "limit", Required: true,
+			Schema: &openapi3.SchemaRef{Value: &openapi3.Schema{Type: &openapi3.Types{"integer"}}}},
+		&openapi3.Parameter{In: "query", Name: "sort",
+			Schema: &openapi3.SchemaRef{Value: &openapi3.Schema{Type: &openapi3.Types{"string"}, Enum: []any{"asc", "desc"}}}},
+		&openapi3.Parameter{In: "query", Name: "since",
+			Schema: &openapi3.SchemaRef{Value: &openapi3.Schema{Type: &openapi3.Types{"string"}, Format: "date"}}},
+	)
+
+	r := httptest.NewRequest(http.MethodGet, "/items/1?limit=10&sort=asc&since=2024-01-02", nil)
+	require.Empty(t, v.ValidateParameters(r, "/items/{id}", "get", "query"))
+
+	r = httptest.NewRequest(http.MethodGet, "/items/1?sort=sideways&since=yesterday", nil)
+	errs := v.ValidateParameters(r, "/items/{id}", "get", "query")
+
+	fields := map[string]bool{}
+	for _, e := range errs {
+		require.Equal(t, "query", e.In)
+		fields[e.Field] = true
+	}
+	require.Equal(t, map[string]bool{"limit": true, "sort": true, "since": true}, fields)
+
+	r = httptest.NewRequest(http.MethodGet, "/items/1?limit=ten", nil)
+	errs = v.ValidateParameters(r, "/items/{id}", "get", "query")
+	require.Len(t, errs, 1)
+	require.Equal(t, "limit", errs[0].Field)
+}
+
+func TestValidator_UndeclaredQueryParams(t *testing.T) {
+	v := paramTestValidator(
+		&openapi3.Parameter{In: "query", Name: "limit"},
+		&openapi3.Parameter{In: "query", Name: "filter", Style: "deepObject"},
+		&openapi3.Parameter{In: "header", Name: "sort"},
+	)
+
+	r := httptest.NewRequest(http.MethodGet, "/items/1?limit=10&filter[name]=a", nil)
+	require.Empty(t, v.UndeclaredQueryParams(r, "/items/{id}", "get"))
+
+	r = httptest.NewRequest(http.MethodGet, "/items/1?limit=10&sort=asc&pageSize=5", nil)
+	errs := v.UndeclaredQueryParams(r, "/items/{id}", "get")
+	require.Equal(t, []FieldError{
+		{In: "query", Field: "pageSize", Reason: "parameter is not declared in the API spec"},
+		{In: "query", Field: "sort", Reason: "parameter is not declared in the API spec"},
+	}, errs)
+}
+
+func TestValidator_ValidateParameters_OperationOverridesPathItem(t *testing.T) {
+	paths := openapi3.NewPaths()
+	paths.Set("/x", &openapi3.PathItem{
+		Parameters: openapi3.Parameters{{Value: &openapi3.Parameter{In: "query", Name: "q", Required: true,
+			Schema: &openapi3.SchemaRef{Value: &openapi3.Schema{Type: &openapi3.Types{"string"}}}}}},
+		Get: &openapi3.Operation{
+			Parameters: openapi3.Parameters{{Value: &openapi3.Parameter{In: "query", Name: "q",
+				Schema: &openapi3.SchemaRef{Value: &openapi3.Schema{Type: &openapi3.Types{"string"}}}}}},
+			Responses: openapi3.NewResponses(),
+		},
+	})
+	v := NewValidator(&SpecProvider{spec: &Spec{Doc3: &openapi3.T{Paths: paths}}, log: logrus.New()})
+
+	require.Empty(t, v.ValidateParameters(httptest.NewRequest(http.MethodGet, "/x", nil), "/x", "get", "query"))
+}
+
+func TestPathParams(t *testing.T) {
+	require.Equal(t, map[string]string{"id": "7", "sub": "a"}, PathParams("/items/{id}/x/{sub}", "/items/7/x/a/"))
+	require.Nil(t, PathParams("/items/{id}", "/items"))
+}
+
+func TestValidator_ValidateParameters_Path(t *testing.T) {
+	v := paramTestValidator(
+		&openapi3.Parameter{In: "path", Name: "id", Required: true,
+			Schema: &openapi3.SchemaRef{Value: &openapi3.Schema{Type: &openapi3.Types{"integer"}}}},
+	)
+
+	require.Empty(t, v.ValidateParameters(httptest.NewRequest(http.MethodGet, "/items/42", nil), "/items/{id}", "get", "path"))
+
+	errs := v.ValidateParameters(httptest.NewRequest(http.MethodGet, "/items/abc", nil), "/items/{id}", "get", "path")
+	require.Len(t, errs, 1)
+	require.Equal(t, "path", errs[0].In)
+	require.Equal(t, "id", errs[0].Field)
+}
+
+func TestValidator_ValidateParameters_PathUUID(t *testing.T) {
+	v := paramTestValidator(
+		&openapi3.Parameter{In: "path", Name: "id", Required: true,
+			Schema: &openapi3.SchemaRef{Value: &openapi3.Schema{Type: &openapi3.Types{"string"}, Format: "uuid"}}},
+	)
+
+	ok := httptest.NewRequest(http.MethodGet, "/items/1f0e8d4c-7b8a-6c1d-9e2f-3a4b5c6d7e8f", nil)
+	require.Empty(t, v.ValidateParameters(ok, "/items/{id}", "get", "path"))
+
+	bad := httptest.NewRequest(http.MethodGet, "/items/abc", nil)
+	require.Len(t, v.ValidateParameters(bad, "/items/{id}", "get", "path"), 1)
+}
+
+func TestValidator_ValidateParameters_Header(t *testing.T) {
+	v := paramTestValidator(
+		&openapi3.Parameter{In: "header", Name: "X-Tenant-ID", Required: true,
+			Schema: &openapi3.SchemaRef{Value: &openapi3.Schema{Type: &openapi3.Types{"string"}, Format: "uuid"}}},
+		&openapi3.Parameter{In: "header", Name: "Authorization", Required: true,
+			Schema: &openapi3.SchemaRef{Value: &openapi3.Schema{Type: &openapi3.Types{"string"}}}},
+	)
+
+	r := httptest.NewRequest(http.MethodGet, "/items/1", nil)
+	errs := v.ValidateParameters(r, "/items/{id}", "get", "header")
+	require.Len(t, errs, 1)
+	require.Equal(t, FieldError{In: "header", Field: "X-Tenant-ID", Reason: errs[0].Reason}, errs[0])
+
+	r.Header.Set("x-tenant-id", "not-a-uuid")
+	require.Len(t, v.ValidateParameters(r, "/items/{id}", "get", "header"), 1)
+
+	r.Header.Set("x-tenant-id", "1f0e8d4c-7b8a-6c1d-9e2f-3a4b5c6d7e8f")
+	require.Empty(t, v.ValidateParameters(r, "/items/{id}", "get", "header"))
+}
+
+func TestValidator_ValidateParameters_Cookie(t *testing.T) {
+	v := paramTestValidator(
+		&openapi3.Parameter{In: "cookie", Name: "session", Required: true,
+			Schema: &openapi3.SchemaRef{Value: &openapi3.Schema{Type: &openapi3.Types{"string"}}}},
+		&openapi3.Parameter{In: "cookie", Name: "theme",
+			Schema: &openapi3.SchemaRef{Value: &openapi3.Schema{Type: &openapi3.Types{"string"}, Enum: []any{"light", "dark"}}}},
+	)
+
+	r := httptest.NewRequest(http.MethodGet, "/items/1", nil)
+	errs := v.ValidateParameters(r, "/items/{id}", "get", "cookie")
+	require.Len(t, errs, 1)
+	require.Equal(t, FieldError{In: "cookie", Field: "session", Reason: errs[0].Reason}, errs[0])
+
+	r.AddCookie(&http.Cookie{Name: "session", Value: "abc"})
+	r.AddCookie(&http.Cookie{Name: "theme", Value: "neon"})
+	errs = v.ValidateParameters(r, "/items/{id}", "get", "cookie")
+	require.Len(t, errs, 1)
+	require.Equal(t, "theme", errs[0].Field)
+
+	r = httptest.NewRequest(http.MethodGet, "/items/1", nil)
+	r.AddCookie(&http.Cookie{Name: "session", Value: "abc"})
+	r.AddCookie(&http.Cookie{Name: "theme", Value: "dark"})
+	require.Empty(t, v.ValidateParameters(r, "/items/{id}", "get", "cookie"))
+}
+
+func TestValidator_ValidateContentType(t *testing.T) {
+	paths := openapi3.NewPaths()
+	paths.Set("/items", &openapi3.PathItem{Post: &openapi3.Operation{
+		RequestBody: &openapi3.RequestBodyRef{Value: &openapi3.RequestBody{Content: openapi3.Content{
+			"application/json": openapi3.NewMediaType(),
+			"text/*":           openapi3.NewMediaType(),
+		}}},
+		Responses: openapi3.NewResponses(),
+	}})
+	v := NewValidator(&SpecProvider{spec: &Spec{Doc3: &openapi3.T{Paths: paths}}, log: logrus.New()})
+
+	cases := []struct {
+		contentType string
+		want        bool
+	}{
+		{"", true},
+		{"application/json; charset=utf-8", true},
+		{"text/csv", true},
+		{"application/xml", false},
+	}
+	for _, tc := range cases {
+		r := httptest.NewRequest(http.MethodPost, "/items", strings.NewReader("x"))
+		if tc.contentType != "" {
+			r.Header.Set("Content-Type", tc.contentType)
+		}
+		supported, ok := v.ValidateContentType(r, "/items", "post")
+		require.Equal(t, tc.want, ok, tc.contentType)
+		if !ok {
+			require.Equal(t, []string{"application/json", "text/*"}, supported)
+		}
+	}
+}
+
+func TestValidator_ValidateResponseBody(t *testing.T) {
+	schema := openapi3.NewObjectSchema().
+		WithProperty("id", openapi3.NewStringSchema()).
+		WithRequired([]string{"id"})
+	responses := openapi3.NewResponses()
+	responses.Set("200", &openapi3.ResponseRef{Value: openapi3.NewResponse().
+		WithContent(openapi3.NewContentWithJSONSchema(schema))})
+	responses.Set("204", &openapi3.ResponseRef{Value: openapi3.NewResponse()})
+	responses.Delete("default")
+
+	paths := openapi3.NewPaths()
+	paths.Set("/items/{id}", &openapi3.PathItem{Get: &openapi3.Operation{Responses: responses}})
+	v := NewValidator(&SpecProvider{spec: &Spec{Doc3: &openapi3.T{Paths: paths}}, log: logrus.New()})
+
+	require.Empty(t, v.ValidateResponseBody("/items/{id}", "get", 200, "", []byte(`{"id":"1"}`)))
+	require.Empty(t, v.ValidateResponseBody("/items/{id}", "get", 204, "", nil))
+
+	errs := v.ValidateResponseBody("/items/{id}", "get", 200, "application/json", []byte(`{"id":1}`))
+	require.Len(t, errs, 1)
+	require.Equal(t, "/id", errs[0].Field)
+
+	errs = v.ValidateResponseBody("/items/{id}", "get", 200, "text/plain", []byte(`x`))
+	require.Len(t, errs, 1)
+	require.Contains(t, errs[0].Reason, "content type")
+
+	errs = v.ValidateResponseBody("/items/{id}", "get", 404, "", []byte(`{}`))
+	require.Len(t, errs, 1)
+	require.Contains(t, errs[0].Reason, "status 404")
+}
@@ -0,0 +1,103 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package samples
+
+import (
+	"bytes"
+	"container/list"
+	"os"
+	"sync"
+)
+
+// sampleCache keeps the parsed responses of recently served samples, least
+// recently used first out. An entry is valid while the file keeps the
+// modification time and size it had when it was parsed. Templates and
+// samples with $include are rendered per request; their entries only
+// remember that, so the file is not read twice.
+type sampleCache struct {
+	mu    sync.Mutex
+	size  int
+	order *list.List
+	items map[string]*list.Element
+}
+
+type cachedSample struct {
+	path    string
+	modTime int64
+	size    int64
+	// resp is nil for samples that must be loaded per request.
+	resp *Response
+}
+
+// newSampleCache returns a cache of up to size samples, or nil when size is
+// below 1; a nil cache caches nothing.
+func newSampleCache(size int) *sampleCache {
+	if size < 1 {
+		return nil
+	}
+	return &sampleCache{size: size, order: list.New(), items: map[string]*list.Element{}}
+}
+
+// get returns a copy of the cached response of path. hit is false when the
+// file changed since it was cached or was never cached; a hit without a
+// response means the sample is loaded per request.
+func (c *sampleCache) get(path string, info os.FileInfo) (resp *Response, hit bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, found := c.items[path]
+	if !found {
+		return nil, false
+	}
+	e := el.Value.(*cachedSample)
+	if e.modTime != info.ModTime().UnixNano() || e.size != info.Size() {
+		c.order.Remove(el)
+		delete(c.items, path)
+		return nil, false
+	}
+	c.order.MoveToFront(el)
+	if e.resp == nil {
+		return nil, true
+	}
+	return e.resp.clone(), true
+}
+
+// put stores resp for path as of info; a nil resp marks a per-request sample.
+func (c *sampleCache) put(path string, info os.FileInfo, resp *Response) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e := &cachedSample{path: path, modTime: info.ModTime().UnixNano(), size: info.Size(), resp: resp}
+	if el, found := c.items[path]; found {
+		el.Value = e
+		c.order.MoveToFront(el)
+		return
+	}
+	c.items[path] = c.order.PushFront(e)
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*cachedSample).path)
+	}
+}
+
+// perRequest reports whether a sample must be rendered for every request.
+func perRequest(raw []byte) bool {
+	return bytes.Contains(raw, []byte("{{")) || bytes.Contains(raw, []byte(`"$include"`))
+}
+
+// clone copies r so callers can set headers without touching the cached
+// response. The body is shared; it is never modified in place.
+func (r *Response) clone() *Response {
+	out := *r
+	out.Headers = r.Headers.Clone()
+	return &out
+}
@@ -0,0 +1,87 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package samples
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ozgen/openapi-emulator/config"
+	"github.com/ozgen/openapi-emulator/logger"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSampleProvider_Cache_ReusesUntilFileChanges(t *testing.T) {
+	baseDir := t.TempDir()
+	path := writeFile(t, baseDir, "item.json", `{"status": 200, "headers": {"X-A": "1"}, "body": {"v": 1}}`)
+	p := NewSampleProvider(ProviderConfig{BaseDir: baseDir, Layout: config.LayoutFolders, CacheSize: 4}, logger.GetLogger())
+
+	resp, err := p.LoadSample("item.json", LoadOptions{})
+	require.NoError(t, err)
+	resp.Headers["X-B"] = []string{"2"}
+
+	// A copy is served; changes to an earlier response do not leak.
+	resp, err = p.LoadSample("item.json", LoadOptions{})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"v": 1}`, string(resp.Body))
+	require.Equal(t, []string{"1"}, resp.Headers["X-A"])
+	require.NotContains(t, resp.Headers, "X-B")
+	require.Equal(t, 1, p.(*SampleProvider).cache.order.Len())
+
+	// An edit changes the size or modification time and is picked up.
+	writeF(t, path, `{"status": 200, "body": {"v": 22}}`)
+	resp, err = p.LoadSample("item.json", LoadOptions{})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"v": 22}`, string(resp.Body))
+}
+
+func TestSampleProvider_Cache_RendersTemplatesPerRequest(t *testing.T) {
+	baseDir := t.TempDir()
+	writeFile(t, baseDir, "item.json", `{"body": {"q": "{{ .Query.q }}"}}`)
+	p := NewSampleProvider(ProviderConfig{BaseDir: baseDir, Layout: config.LayoutFolders, CacheSize: 4}, logger.GetLogger())
+
+	for _, q := range []string{"a", "b"} {
+		resp, err := p.LoadSample("item.json", LoadOptions{Query: map[string]string{"q": q}})
+		require.NoError(t, err)
+		require.JSONEq(t, `{"q": "`+q+`"}`, string(resp.Body))
+	}
+}
+
+func TestSampleCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	dir := t.TempDir()
+	c := newSampleCache(2)
+	stat := func(name string) os.FileInfo {
+		info, err := os.Stat(writeFile(t, dir, name, `{}`))
+		require.NoError(t, err)
+		return info
+	}
+	a, b, d := stat("a.json"), stat("b.json"), stat("d.json")
+
+	c.put("a", a, &Response{Status: 200})
+	c.put("b", b, &Response{Status: 201})
+	_, hit := c.get("a", a)
+	require.True(t, hit)
+	c.put("d", d, &Response{Status: 202})
+
+	_, hit = c.get("b", b)
+	require.False(t, hit, "b was used least recently")
+	resp, hit := c.get("a", a)
+	require.True(t, hit)
+	require.Equal(t, 200, resp.Status)
+	_, hit = c.get("d", d)
+	require.True(t, hit)
+}
+
+func TestSampleCache_NilWhenDisabled(t *testing.T) {
+	c := newSampleCache(0)
+	require.Nil(t, c)
+
+	info, err := os.Stat(filepath.Join(t.TempDir()))
+	require.NoError(t, err)
+	c.put("a", info, &Response{})
+	_, hit := c.get("a", info)
+	require.False(t, hit)
+}
@@ -0,0 +1,74 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package samples
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Cookie is one entry of the envelope's "cookies", sent as its own
+// Set-Cookie header. MaxAge below 0 deletes the cookie on the client;
+// Expires is an RFC 3339 or HTTP date.
+type Cookie struct {
+	Name     string `json:"name"`
+	Value    string `json:"value"`
+	Path     string `json:"path,omitempty"`
+	Domain   string `json:"domain,omitempty"`
+	MaxAge   int    `json:"maxAge,omitempty"`
+	Expires  string `json:"expires,omitempty"`
+	Secure   bool   `json:"secure,omitempty"`
+	HTTPOnly bool   `json:"httpOnly,omitempty"`
+	SameSite string `json:"sameSite,omitempty"`
+}
+
+// httpCookies converts the envelope cookies, rejecting names, values and
+// attributes net/http would drop or mangle.
+func httpCookies(in []Cookie) ([]*http.Cookie, error) {
+	out := make([]*http.Cookie, 0, len(in))
+	for i, c := range in {
+		hc := &http.Cookie{
+			Name:     c.Name,
+			Value:    c.Value,
+			Path:     c.Path,
+			Domain:   c.Domain,
+			MaxAge:   c.MaxAge,
+			Secure:   c.Secure,
+			HttpOnly: c.HTTPOnly,
+		}
+		if c.Expires != "" {
+			t, err := parseCookieTime(c.Expires)
+			if err != nil {
+				return nil, fmt.Errorf("cookies[%d]: expires %q is not an RFC 3339 or HTTP date", i, c.Expires)
+			}
+			hc.Expires = t
+		}
+		switch strings.ToLower(c.SameSite) {
+		case "":
+		case "lax":
+			hc.SameSite = http.SameSiteLaxMode
+		case "strict":
+			hc.SameSite = http.SameSiteStrictMode
+		case "none":
+			hc.SameSite = http.SameSiteNoneMode
+		default:
+			return nil, fmt.Errorf("cookies[%d]: sameSite %q is not Lax, Strict or None", i, c.SameSite)
+		}
+		if err := hc.Valid(); err != nil {
+			return nil, fmt.Errorf("cookies[%d]: %w", i, err)
+		}
+		out = append(out, hc)
+	}
+	return out, nil
+}
+
+func parseCookieTime(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	return http.ParseTime(s)
+}
@@ -0,0 +1,49 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package samples
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"time"
+)
+
+// Delay is the envelope's "delayMs": a fixed number of milliseconds or a
+// {"min": 100, "max": 900} range drawn from uniformly for each response.
+type Delay struct {
+	MinMs int `json:"min"`
+	MaxMs int `json:"max"`
+}
+
+func (d *Delay) UnmarshalJSON(b []byte) error {
+	var fixed int
+	if err := json.Unmarshal(b, &fixed); err == nil {
+		d.MinMs, d.MaxMs = fixed, fixed
+	} else {
+		type plain Delay
+		var p plain
+		if err := json.Unmarshal(b, &p); err != nil {
+			return fmt.Errorf("delayMs must be a number or {\"min\", \"max\"}")
+		}
+		*d = Delay(p)
+	}
+	if d.MinMs < 0 || d.MaxMs < d.MinMs {
+		return fmt.Errorf("delayMs needs 0 <= min <= max, got %d..%d", d.MinMs, d.MaxMs)
+	}
+	return nil
+}
+
+// Duration returns the delay for one response.
+func (d *Delay) Duration() time.Duration {
+	if d == nil {
+		return 0
+	}
+	ms := d.MinMs
+	if d.MaxMs > d.MinMs {
+		ms += rand.IntN(d.MaxMs - d.MinMs + 1)
+	}
+	return time.Duration(ms) * time.Millisecond
+}
@@ -0,0 +1,51 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package samples
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ozgen/openapi-emulator/config"
+	"github.com/ozgen/openapi-emulator/logger"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDelay_Unmarshal(t *testing.T) {
+	var d Delay
+	require.NoError(t, json.Unmarshal([]byte(`250`), &d))
+	require.Equal(t, 250*time.Millisecond, d.Duration())
+
+	require.NoError(t, json.Unmarshal([]byte(`{"min":100,"max":200}`), &d))
+	for i := 0; i < 50; i++ {
+		got := d.Duration()
+		require.GreaterOrEqual(t, got, 100*time.Millisecond)
+		require.LessOrEqual(t, got, 200*time.Millisecond)
+	}
+
+	for _, bad := range []string{`-1`, `{"min":300,"max":200}`, `"slow"`} {
+		require.Error(t, json.Unmarshal([]byte(bad), &d), bad)
+	}
+
+	var none *Delay
+	require.Zero(t, none.Duration())
+}
+
+func TestLoadSample_DelayFromEnvelope(t *testing.T) {
+	baseDir := t.TempDir()
+	writeFile(t, filepath.Join(baseDir, "scans"), "GET.json", `{"status":200,"delayMs":{"min":5,"max":5},"body":{}}`)
+	writeFile(t, filepath.Join(baseDir, "scans"), "POST.json", `{"version":1,"delayMs":{"min":5},"body":{}}`)
+
+	p := NewSampleProvider(ProviderConfig{BaseDir: baseDir, Layout: config.LayoutFolders}, logger.GetLogger())
+	resp, err := p.ResolveAndLoad("GET", "/scans", "/scans", "", LoadOptions{})
+	require.NoError(t, err)
+	require.Equal(t, 5*time.Millisecond, resp.Delay.Duration())
+
+	_, err = p.ResolveAndLoad("POST", "/scans", "/scans", "", LoadOptions{})
+	var envErr *SampleEnvelopeError
+	require.ErrorAs(t, err, &envErr)
+}
@@ -0,0 +1,79 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package samples
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// echoFields copies the fields named by the envelope's echoFields from the
+// decoded request body into the response body. A name may be a dotted path
+// into nested objects. Fields missing from the request keep the sample's
+// value; a response body that is not a JSON object is left unchanged.
+// reqBody is only called when there are fields to copy.
+func echoFields(resp *Response, reqBody func() any) error {
+	if len(resp.EchoFields) == 0 {
+		return nil
+	}
+	req, ok := reqBody().(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(resp.Body))
+	dec.UseNumber()
+	var body map[string]any
+	if err := dec.Decode(&body); err != nil || body == nil {
+		return nil
+	}
+
+	changed := false
+	for _, field := range resp.EchoFields {
+		path := strings.Split(field, ".")
+		if v, ok := lookupField(req, path); ok {
+			setField(body, path, v)
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+
+	b, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	resp.Body = b
+	return nil
+}
+
+func lookupField(m map[string]any, path []string) (any, bool) {
+	v, ok := m[path[0]]
+	if !ok || len(path) == 1 {
+		return v, ok
+	}
+	next, ok := v.(map[string]any)
+	if !ok {
+		return nil, false
+	}
+	return lookupField(next, path[1:])
+}
+
+// setField stores v at path, replacing anything on the way that is not an
+// object.
+func setField(m map[string]any, path []string, v any) {
+	if len(path) == 1 {
+		m[path[0]] = v
+		return
+	}
+	next, ok := m[path[0]].(map[string]any)
+	if !ok {
+		next = map[string]any{}
+		m[path[0]] = next
+	}
+	setField(next, path[1:], v)
+}
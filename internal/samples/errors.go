@@ -0,0 +1,143 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package samples
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// SampleSyntaxError reports a .json sample that is not valid JSON.
+type SampleSyntaxError struct {
+	Path   string
+	Line   int
+	Column int
+	Err    error
+}
+
+func (e *SampleSyntaxError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("invalid JSON in sample %s at line %d, column %d: %v", e.Path, e.Line, e.Column, e.Err)
+	}
+	return fmt.Sprintf("invalid JSON in sample %s: %v", e.Path, e.Err)
+}
+
+func (e *SampleSyntaxError) Unwrap() error {
+	return e.Err
+}
+
+func newSampleSyntaxError(path string, raw []byte, err error) *SampleSyntaxError {
+	out := &SampleSyntaxError{Path: path, Err: err}
+
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		out.Line, out.Column = lineColumn(raw, syntaxErr.Offset)
+	}
+	return out
+}
+
+// lineColumn converts the offset of a json.SyntaxError, the number of bytes
+// read including the offending one, into the 1-based line and column of
+// that byte. An offset past the end points just behind the last byte.
+func lineColumn(raw []byte, offset int64) (int, int) {
+	pos := min(max(offset-1, 0), int64(len(raw)))
+	line, col := 1, 1
+	for _, c := range raw[:pos] {
+		if c == '\n' {
+			line++
+			col = 1
+			continue
+		}
+		col++
+	}
+	return line, col
+}
+
+// SampleEnvelopeError reports a versioned envelope that does not match
+// schema/envelope.json.
+type SampleEnvelopeError struct {
+	Path string
+	Err  error
+}
+
+func (e *SampleEnvelopeError) Error() string {
+	return fmt.Sprintf("invalid envelope in sample %s: %v", e.Path, e.Err)
+}
+
+func (e *SampleEnvelopeError) Unwrap() error {
+	return e.Err
+}
+
+// SampleIncludeError reports an $include reference in the sample at Path
+// that cannot be resolved.
+type SampleIncludeError struct {
+	Path    string
+	Include string
+	Err     error
+}
+
+func (e *SampleIncludeError) Error() string {
+	if e.Include == "" {
+		return fmt.Sprintf("include in sample %s: %v", e.Path, e.Err)
+	}
+	return fmt.Sprintf("include %s in sample %s: %v", e.Include, e.Path, e.Err)
+}
+
+func (e *SampleIncludeError) Unwrap() error {
+	return e.Err
+}
+
+// SampleTemplateError reports a sample template that failed to parse or render.
+type SampleTemplateError struct {
+	Path string
+	Err  error
+}
+
+func (e *SampleTemplateError) Error() string {
+	return fmt.Sprintf("render template in sample %s: %v", e.Path, e.Err)
+}
+
+func (e *SampleTemplateError) Unwrap() error {
+	return e.Err
+}
+
+// RequestExpectationError reports a request that does not meet the "expect"
+// block of the sample it was routed to. Status is the status to answer with.
+type RequestExpectationError struct {
+	Path     string
+	Status   int
+	Failures []string
+}
+
+func (e *RequestExpectationError) Error() string {
+	return fmt.Sprintf("request does not meet expectations of sample %s: %s", e.Path, strings.Join(e.Failures, "; "))
+}
+
+// ScenarioStoreError reports a shared scenario store that could not be read
+// or written.
+type ScenarioStoreError struct {
+	Err error
+}
+
+func (e *ScenarioStoreError) Error() string {
+	return fmt.Sprintf("scenario store: %v", e.Err)
+}
+
+func (e *ScenarioStoreError) Unwrap() error {
+	return e.Err
+}
+
+// UnknownScenarioStateError reports a forced scenario state that the scenario
+// does not define.
+type UnknownScenarioStateError struct {
+	State string
+	Known []string
+}
+
+func (e *UnknownScenarioStateError) Error() string {
+	return fmt.Sprintf("unknown scenario state %q (known: %s)", e.State, strings.Join(e.Known, ", "))
+}
@@ -0,0 +1,56 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package samples
+
+import (
+	"encoding/json"
+
+	"github.com/ozgen/openapi-emulator/internal/matcher"
+)
+
+// defaultExpectStatus answers a request that fails an "expect" block
+// without a status.
+const defaultExpectStatus = 400
+
+// SampleExpect is the "expect" block of a sample's envelope: conditions of
+// the same shape as "match" that the request must meet for the sample to be
+// served. Unlike a variant's match, a failed expectation is answered with
+// Status and the list of unmet conditions.
+type SampleExpect struct {
+	matcher.Rule
+	// Status answers a request that fails an expectation, 400 by default.
+	Status int `json:"status,omitempty"`
+}
+
+func (e *SampleExpect) UnmarshalJSON(b []byte) error {
+	var status struct {
+		Status int `json:"status"`
+	}
+	if err := json.Unmarshal(b, &status); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(b, &e.Rule); err != nil {
+		return err
+	}
+	e.Status = status.Status
+	return nil
+}
+
+// checkExpect fails with a RequestExpectationError when the request does
+// not meet the expectations of the sample at path.
+func checkExpect(path string, resp *Response, opts LoadOptions) error {
+	if resp.Expect == nil || opts.IgnoreExpect {
+		return nil
+	}
+	failures := resp.Expect.Mismatches(matchRequest(opts))
+	if len(failures) == 0 {
+		return nil
+	}
+	status := resp.Expect.Status
+	if status == 0 {
+		status = defaultExpectStatus
+	}
+	return &RequestExpectationError{Path: path, Status: status, Failures: failures}
+}
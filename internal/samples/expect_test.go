@@ -0,0 +1,74 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package samples
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/ozgen/openapi-emulator/config"
+	"github.com/ozgen/openapi-emulator/logger"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSampleProvider_Expect(t *testing.T) {
+	baseDir := t.TempDir()
+	writeFile(t, filepath.Join(baseDir, "scans"), "POST.json", `{
+	  "version": 1,
+	  "status": 201,
+	  "expect": {
+	    "status": 422,
+	    "headers": {"Authorization": {"prefix": "Bearer "}, "X-Request-Id": true},
+	    "query": {"dryRun": {"wildcard": "*"}},
+	    "body": {"$.target.hosts": true, "$.kind": "full"}
+	  },
+	  "body": {"id": "s1"}
+	}`)
+	p := NewSampleProvider(ProviderConfig{BaseDir: baseDir, Layout: config.LayoutFolders}, logger.GetLogger())
+
+	load := func(opts LoadOptions) (*Response, error) {
+		return p.ResolveAndLoad("POST", "/scans", "/scans", "", opts)
+	}
+
+	resp, err := load(LoadOptions{
+		Header: map[string]string{"Authorization": "Bearer t", "X_Request_Id": "r1"},
+		Query:  map[string]string{"dryRun": "false"},
+		Body:   bodyOf(map[string]any{"kind": "full", "target": map[string]any{"hosts": "10.0.0.1"}}),
+	})
+	require.NoError(t, err)
+	require.Equal(t, 201, resp.Status)
+
+	_, err = load(LoadOptions{
+		Header: map[string]string{"Authorization": "Basic x"},
+		Body:   bodyOf(map[string]any{"kind": "quick"}),
+	})
+	var expectErr *RequestExpectationError
+	require.True(t, errors.As(err, &expectErr), "got %v", err)
+	require.Equal(t, 422, expectErr.Status)
+	require.Equal(t, []string{
+		`header Authorization "Basic x" does not match prefix "Bearer "`,
+		"header X-Request-Id is missing",
+		"query dryRun is missing",
+		`body $.kind "quick" does not match exact "full"`,
+		"body $.target.hosts is missing",
+	}, expectErr.Failures)
+
+	resp, err = load(LoadOptions{IgnoreExpect: true})
+	require.NoError(t, err)
+	require.Equal(t, 201, resp.Status)
+}
+
+func TestSampleProvider_Expect_DefaultStatus(t *testing.T) {
+	baseDir := t.TempDir()
+	writeFile(t, baseDir, "ping.json", `{"expect": {"headers": {"X-Tenant": true}}, "body": {}}`)
+	p := NewSampleProvider(ProviderConfig{BaseDir: baseDir, Layout: config.LayoutFolders}, logger.GetLogger())
+
+	_, err := p.LoadSample("ping.json", LoadOptions{})
+	var expectErr *RequestExpectationError
+	require.True(t, errors.As(err, &expectErr), "got %v", err)
+	require.Equal(t, 400, expectErr.Status)
+	require.Equal(t, []string{"header X-Tenant is missing"}, expectErr.Failures)
+}
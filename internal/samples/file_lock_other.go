@@ -0,0 +1,15 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+//go:build !unix
+
+package samples
+
+import "errors"
+
+// lockFile is only implemented with flock; elsewhere file:// scenario state
+// fails instead of writing without a lock.
+func lockFile(string) (func(), error) {
+	return nil, errors.New("file locking is not supported on this platform")
+}
@@ -0,0 +1,208 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package samples
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FileScenarioStore keeps scenario state in a JSON snapshot on a volume that
+// replicas share. Every operation holds an exclusive lock on <path>.lock
+// while it reads, changes and rewrites the snapshot, so only one replica
+// writes at a time; the snapshot is replaced by rename, so a reader never
+// sees a half-written file.
+type FileScenarioStore struct {
+	path string
+	// mu serializes the goroutines of this process; the file lock the
+	// replicas.
+	mu sync.Mutex
+}
+
+// fileSnapshot is the on-disk form of FileScenarioStore.
+type fileSnapshot struct {
+	Steps  map[string]int   `json:"steps,omitempty"`
+	Starts map[string]int64 `json:"starts,omitempty"`
+}
+
+// NewFileScenarioStore parses a file:///path/to/state.json URL, or a
+// relative file:state.json resolved against stateDir. The snapshot is
+// created on first write.
+func NewFileScenarioStore(rawURL, stateDir string) (*FileScenarioStore, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid file url: %w", err)
+	}
+	if u.Scheme == "file" && u.Opaque != "" {
+		if stateDir == "" {
+			return nil, fmt.Errorf("invalid file url %q: a relative path needs a state directory", rawURL)
+		}
+		return &FileScenarioStore{path: filepath.Join(stateDir, filepath.FromSlash(u.Opaque))}, nil
+	}
+	if u.Scheme != "file" || u.Path == "" || (u.Host != "" && u.Host != "localhost") {
+		return nil, fmt.Errorf("invalid file url %q: must look like file:///path/to/state.json", rawURL)
+	}
+	return &FileScenarioStore{path: filepath.FromSlash(u.Path)}, nil
+}
+
+// NewScenarioStore opens the shared scenario store named by rawURL: a
+// redis:// or rediss:// server, or a file: snapshot, relative ones in
+// stateDir.
+func NewScenarioStore(rawURL, stateDir string) (IScenarioStore, error) {
+	if strings.HasPrefix(rawURL, "file:") {
+		return NewFileScenarioStore(rawURL, stateDir)
+	}
+	return NewRedisScenarioStore(rawURL)
+}
+
+// Path returns the snapshot file.
+func (s *FileScenarioStore) Path() string {
+	return s.path
+}
+
+func (s *FileScenarioStore) Step(key string) (int, error) {
+	var idx int
+	err := s.view(func(snap *fileSnapshot) {
+		idx = snap.Steps[key]
+	})
+	return idx, err
+}
+
+func (s *FileScenarioStore) SetStep(key string, idx int) error {
+	return s.update(func(snap *fileSnapshot) bool {
+		snap.Steps[key] = idx
+		return true
+	})
+}
+
+// CompareAndSetStep compares and writes under the same file lock, so a
+// replica changing the step in between is not possible.
+func (s *FileScenarioStore) CompareAndSetStep(key string, old, idx int) (bool, error) {
+	swapped := false
+	err := s.update(func(snap *fileSnapshot) bool {
+		if snap.Steps[key] != old {
+			return false
+		}
+		snap.Steps[key] = idx
+		swapped = true
+		return true
+	})
+	return swapped, err
+}
+
+func (s *FileScenarioStore) Start(key string, now time.Time) (time.Time, error) {
+	t0 := now
+	err := s.update(func(snap *fileSnapshot) bool {
+		if ns, ok := snap.Starts[key]; ok {
+			t0 = time.Unix(0, ns)
+			return false
+		}
+		snap.Starts[key] = now.UnixNano()
+		return true
+	})
+	return t0, err
+}
+
+func (s *FileScenarioStore) SetStart(key string, t time.Time) error {
+	return s.update(func(snap *fileSnapshot) bool {
+		snap.Starts[key] = t.UnixNano()
+		return true
+	})
+}
+
+func (s *FileScenarioStore) Delete(key string) error {
+	return s.update(func(snap *fileSnapshot) bool {
+		_, hasStep := snap.Steps[key]
+		_, hasStart := snap.Starts[key]
+		delete(snap.Steps, key)
+		delete(snap.Starts, key)
+		return hasStep || hasStart
+	})
+}
+
+func (s *FileScenarioStore) view(fn func(*fileSnapshot)) error {
+	return s.update(func(snap *fileSnapshot) bool {
+		fn(snap)
+		return false
+	})
+}
+
+// update runs fn on the current snapshot with the file lock held and writes
+// the snapshot back when fn reports a change.
+func (s *FileScenarioStore) update(fn func(*fileSnapshot) bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	unlock, err := lockFile(s.path + ".lock")
+	if err != nil {
+		return &ScenarioStoreError{Err: fmt.Errorf("lock %s: %w", s.path, err)}
+	}
+	defer unlock()
+
+	snap, err := s.read()
+	if err != nil {
+		return &ScenarioStoreError{Err: err}
+	}
+	if !fn(snap) {
+		return nil
+	}
+	if err := s.write(snap); err != nil {
+		return &ScenarioStoreError{Err: err}
+	}
+	return nil
+}
+
+func (s *FileScenarioStore) read() (*fileSnapshot, error) {
+	snap := &fileSnapshot{}
+	b, err := os.ReadFile(s.path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	if len(b) > 0 {
+		if err := json.Unmarshal(b, snap); err != nil {
+			return nil, fmt.Errorf("parse %s: %w", s.path, err)
+		}
+	}
+	if snap.Steps == nil {
+		snap.Steps = map[string]int{}
+	}
+	if snap.Starts == nil {
+		snap.Starts = map[string]int64{}
+	}
+	return snap, nil
+}
+
+func (s *FileScenarioStore) write(snap *fileSnapshot) error {
+	b, err := json.Marshal(snap)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(b); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
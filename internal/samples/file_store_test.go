@@ -0,0 +1,126 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+//go:build unix
+
+package samples
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewScenarioStore_PicksBackendByScheme(t *testing.T) {
+	s, err := NewScenarioStore("file:///shared/state.json", "")
+	require.NoError(t, err)
+	require.Equal(t, filepath.FromSlash("/shared/state.json"), s.(*FileScenarioStore).path)
+
+	s, err = NewScenarioStore("redis://cache:6379", "")
+	require.NoError(t, err)
+	require.IsType(t, &RedisScenarioStore{}, s)
+
+	for _, bad := range []string{"file://", "file://remote-host/state.json", "file:state.json"} {
+		_, err := NewScenarioStore(bad, "")
+		require.Error(t, err, bad)
+	}
+}
+
+func TestNewScenarioStore_RelativeFileInStateDir(t *testing.T) {
+	s, err := NewScenarioStore("file:scenarios/state.json", "/var/lib/emulator")
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join("/var/lib/emulator", "scenarios", "state.json"), s.(*FileScenarioStore).Path())
+}
+
+func TestFileScenarioStore_StepStartAndCompareAndSet(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	a, err := NewFileScenarioStore("file://"+filepath.ToSlash(path), "")
+	require.NoError(t, err)
+	b, err := NewFileScenarioStore("file://"+filepath.ToSlash(path), "")
+	require.NoError(t, err)
+
+	idx, err := a.Step("k")
+	require.NoError(t, err)
+	require.Equal(t, 0, idx)
+
+	ok, err := a.CompareAndSetStep("k", 0, 1)
+	require.NoError(t, err)
+	require.True(t, ok, "a missing key counts as step 0")
+	ok, err = b.CompareAndSetStep("k", 0, 2)
+	require.NoError(t, err)
+	require.False(t, ok, "the other replica sees the swapped step")
+
+	idx, err = b.Step("k")
+	require.NoError(t, err)
+	require.Equal(t, 1, idx)
+
+	first := time.Unix(100, 0)
+	t0, err := a.Start("k", first)
+	require.NoError(t, err)
+	require.True(t, t0.Equal(first))
+	t0, err = b.Start("k", time.Unix(200, 0))
+	require.NoError(t, err)
+	require.True(t, t0.Equal(first), "the first start time wins")
+
+	require.NoError(t, b.Delete("k"))
+	idx, err = a.Step("k")
+	require.NoError(t, err)
+	require.Equal(t, 0, idx)
+}
+
+func TestFileScenarioStore_CorruptSnapshot_ReturnsStoreError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	require.NoError(t, os.WriteFile(path, []byte("{not json"), 0o600))
+	s, err := NewFileScenarioStore("file://"+filepath.ToSlash(path), "")
+	require.NoError(t, err)
+
+	_, err = s.Step("k")
+	var storeErr *ScenarioStoreError
+	require.ErrorAs(t, err, &storeErr)
+}
+
+func TestScenarioResolver_FileStore_ConcurrentReplicasSeeEachEntryOnce(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	const n = 12
+
+	sc := &Scenario{Version: 1, Mode: "step"}
+	sc.Key.PathParam = "id"
+	for i := 0; i < n; i++ {
+		sc.Sequence = append(sc.Sequence, ScenarioEntry{State: fmt.Sprintf("s%d", i), File: "GET.json"})
+	}
+	sc.Behavior.AdvanceOn = []MatchRule{{Method: "GET"}}
+
+	replicas := make([]IScenarioResolver, 3)
+	for i := range replicas {
+		store, err := NewFileScenarioStore("file://"+filepath.ToSlash(path), "")
+		require.NoError(t, err)
+		replicas[i] = NewScenarioResolver(WithScenarioStore(store))
+	}
+
+	var mu sync.Mutex
+	seen := map[string]int{}
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(r IScenarioResolver) {
+			defer wg.Done()
+			_, st, err := r.ResolveScenarioFile(sc, "GET", "/scans/{id}", "/scans/1", nil, "")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			mu.Lock()
+			seen[st.State]++
+			mu.Unlock()
+		}(replicas[i%len(replicas)])
+	}
+	wg.Wait()
+
+	require.Len(t, seen, n, "every entry is served exactly once: %v", seen)
+}
@@ -0,0 +1,73 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package samples
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/ozgen/openapi-emulator/config"
+	"github.com/ozgen/openapi-emulator/utils"
+)
+
+// flatSampleName matches legacy flat sample names such as GET__items_{id}.json.
+var flatSampleName = regexp.MustCompile(`^[A-Z]+__.*\.json$`)
+
+// AuditFlatSamples finds legacy flat samples in BaseDir that are never
+// served in auto layout: those shadowed by a folder sample of the same route
+// and those whose name matches no route. Query variants such as
+// GET__items[status=failed].json count as samples of GET__items.json. Other
+// layouts yield an empty report.
+func (p *SampleProvider) AuditFlatSamples(routes []RouteFiles) (*FlatSampleReport, error) {
+	report := &FlatSampleReport{Shadowed: []ShadowedSample{}, Orphaned: []string{}}
+	if p.cfg.Layout != config.LayoutAuto {
+		return report, nil
+	}
+
+	entries, err := os.ReadDir(p.cfg.BaseDir)
+	if err != nil {
+		return nil, err
+	}
+
+	known := map[string]RouteFiles{}
+	for _, rt := range routes {
+		known[rt.FlatFile] = rt
+	}
+
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !flatSampleName.MatchString(name) {
+			continue
+		}
+		key := name
+		if base, _, ok := variantBase(name); ok {
+			key = base
+		}
+		if base, _, ok := statusBase(key); ok {
+			key = base
+		}
+		rt, ok := known[key]
+		if !ok {
+			report.Orphaned = append(report.Orphaned, name)
+			continue
+		}
+		for _, rel := range buildCandidates(config.LayoutFolders, rt.Method, rt.SwaggerPath, "", "") {
+			if utils.FileExists(filepath.Join(p.cfg.BaseDir, rel)) {
+				report.Shadowed = append(report.Shadowed, ShadowedSample{
+					File:       name,
+					ShadowedBy: filepath.ToSlash(rel),
+				})
+				break
+			}
+		}
+	}
+
+	slices.SortFunc(report.Shadowed, func(a, b ShadowedSample) int { return strings.Compare(a.File, b.File) })
+	slices.Sort(report.Orphaned)
+	return report, nil
+}
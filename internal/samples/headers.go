@@ -0,0 +1,58 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package samples
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// HeaderValues is the value of a sample header: a string, or an array of
+// strings for a header sent repeatedly, such as Link or Warning.
+type HeaderValues []string
+
+func (v *HeaderValues) UnmarshalJSON(b []byte) error {
+	var one string
+	if err := json.Unmarshal(b, &one); err == nil {
+		*v = HeaderValues{one}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(b, &many); err != nil {
+		return fmt.Errorf("header value must be a string or an array of strings")
+	}
+	if len(many) == 0 {
+		return fmt.Errorf("header value must not be an empty array")
+	}
+	*v = many
+	return nil
+}
+
+// sampleHeaders converts the headers of a sample, each a string or an array
+// of strings, to an http.Header. Names keep the spelling of the sample, so
+// PRESERVE_HEADER_CASE can write them as spelled; look them up with
+// HeaderGet rather than Get.
+func sampleHeaders(in map[string]HeaderValues) http.Header {
+	out := make(http.Header, len(in))
+	for k, vs := range in {
+		if len(vs) > 0 {
+			out[k] = []string(vs)
+		}
+	}
+	return out
+}
+
+// HeaderGet returns the first value of the header key in h, matching the
+// name case-insensitively.
+func HeaderGet(h http.Header, key string) (string, bool) {
+	for k, vs := range h {
+		if strings.EqualFold(k, key) && len(vs) > 0 {
+			return vs[0], true
+		}
+	}
+	return "", false
+}
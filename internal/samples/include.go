@@ -0,0 +1,149 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package samples
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Keys of an include reference: {"$include": "../_shared/user.json",
+// "overrides": {...}}.
+const (
+	includeKey          = "$include"
+	includeOverridesKey = "overrides"
+)
+
+// maxIncludeDepth bounds nested includes.
+const maxIncludeDepth = 16
+
+// resolveIncludes replaces every include reference in the JSON sample raw,
+// loaded from path, by the fragment it names. Fragment paths are relative to
+// the file that references them; fragments are rendered with data like the
+// sample and may include further fragments.
+func resolveIncludes(path string, raw []byte, data *TemplateData) ([]byte, error) {
+	if !bytes.Contains(raw, []byte(`"`+includeKey+`"`)) {
+		return raw, nil
+	}
+	doc, err := decodeJSONNumbers(raw)
+	if err != nil {
+		return nil, newSampleSyntaxError(path, raw, err)
+	}
+	if doc, err = expandIncludes(doc, []string{filepath.Clean(path)}, data); err != nil {
+		return nil, err
+	}
+	return json.Marshal(doc)
+}
+
+func expandIncludes(v any, stack []string, data *TemplateData) (any, error) {
+	switch x := v.(type) {
+	case map[string]any:
+		if ref, ok := x[includeKey]; ok {
+			return expandInclude(x, ref, stack, data)
+		}
+		for k, item := range x {
+			expanded, err := expandIncludes(item, stack, data)
+			if err != nil {
+				return nil, err
+			}
+			x[k] = expanded
+		}
+	case []any:
+		for i, item := range x {
+			expanded, err := expandIncludes(item, stack, data)
+			if err != nil {
+				return nil, err
+			}
+			x[i] = expanded
+		}
+	}
+	return v, nil
+}
+
+func expandInclude(ref map[string]any, target any, stack []string, data *TemplateData) (any, error) {
+	from := stack[len(stack)-1]
+	name, ok := target.(string)
+	if !ok || strings.TrimSpace(name) == "" {
+		return nil, &SampleIncludeError{Path: from, Err: fmt.Errorf("%s must be a file path", includeKey)}
+	}
+	for k := range ref {
+		if k != includeKey && k != includeOverridesKey {
+			return nil, &SampleIncludeError{Path: from, Include: name, Err: fmt.Errorf("unexpected key %q next to %s", k, includeKey)}
+		}
+	}
+
+	full := filepath.Join(filepath.Dir(from), filepath.FromSlash(name))
+	for _, p := range stack {
+		if p == full {
+			return nil, &SampleIncludeError{Path: from, Include: name, Err: fmt.Errorf("include cycle")}
+		}
+	}
+	if len(stack) > maxIncludeDepth {
+		return nil, &SampleIncludeError{Path: from, Include: name, Err: fmt.Errorf("includes nested deeper than %d", maxIncludeDepth)}
+	}
+
+	b, err := os.ReadFile(full)
+	if err != nil {
+		return nil, &SampleIncludeError{Path: from, Include: name, Err: err}
+	}
+	if data != nil {
+		if b, err = renderTemplate(full, b, data); err != nil {
+			return nil, err
+		}
+	}
+	b = stripJSONC(b)
+	fragment, err := decodeJSONNumbers(b)
+	if err != nil {
+		return nil, newSampleSyntaxError(full, b, err)
+	}
+	if fragment, err = expandIncludes(fragment, append(stack, full), data); err != nil {
+		return nil, err
+	}
+
+	overrides, ok := ref[includeOverridesKey]
+	if !ok {
+		return fragment, nil
+	}
+	if overrides, err = expandIncludes(overrides, stack, data); err != nil {
+		return nil, err
+	}
+	return mergePatch(fragment, overrides), nil
+}
+
+// mergePatch applies patch to doc as a JSON merge patch (RFC 7386): objects
+// merge key by key, null removes a key, and anything else replaces.
+func mergePatch(doc, patch any) any {
+	p, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+	d, ok := doc.(map[string]any)
+	if !ok {
+		d = map[string]any{}
+	}
+	for k, v := range p {
+		if v == nil {
+			delete(d, k)
+			continue
+		}
+		d[k] = mergePatch(d[k], v)
+	}
+	return d
+}
+
+// decodeJSONNumbers decodes b keeping numbers as written.
+func decodeJSONNumbers(b []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
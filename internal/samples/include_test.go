@@ -0,0 +1,92 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package samples
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/ozgen/openapi-emulator/config"
+	"github.com/ozgen/openapi-emulator/logger"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSampleProvider_ResolvesIncludes(t *testing.T) {
+	baseDir := t.TempDir()
+	writeFile(t, filepath.Join(baseDir, "_shared"), "user.json", `{
+	  // shared by every user fixture
+	  "id": "{{ .Path.id }}",
+	  "name": "Ada",
+	  "roles": ["viewer"],
+	  "address": {"city": "Berlin", "zip": "10115"},
+	  "org": {"$include": "org.json"}
+	}`)
+	writeFile(t, filepath.Join(baseDir, "_shared"), "org.json", `{"id": 7, "name": "Greenbone"}`)
+	writeFile(t, filepath.Join(baseDir, "users", "{id}"), "GET.json", `{
+	  "status": 200,
+	  "headers": {"X-Fixture": "user"},
+	  "body": {
+	    "$include": "../../_shared/user.json",
+	    "overrides": {"roles": ["admin"], "address": {"zip": null}, "active": true}
+	  }
+	}`)
+
+	p := NewSampleProvider(ProviderConfig{BaseDir: baseDir, Layout: config.LayoutFolders}, logger.GetLogger())
+	resp, err := p.ResolveAndLoad("GET", "/users/{id}", "/users/42", "", LoadOptions{Path: map[string]string{"id": "42"}})
+	require.NoError(t, err)
+	require.Equal(t, []string{"user"}, resp.Headers["X-Fixture"])
+	require.JSONEq(t, `{
+	  "id": "42",
+	  "name": "Ada",
+	  "roles": ["admin"],
+	  "address": {"city": "Berlin"},
+	  "org": {"id": 7, "name": "Greenbone"},
+	  "active": true
+	}`, string(resp.Body))
+}
+
+func TestSampleProvider_IncludeErrors(t *testing.T) {
+	cases := map[string]struct {
+		sample    string
+		fragments map[string]string
+	}{
+		"missing file": {sample: `{"$include": "nope.json"}`},
+		"not a path":   {sample: `{"$include": 42}`},
+		"unknown key":  {sample: `{"$include": "a.json", "extra": 1}`, fragments: map[string]string{"a.json": `{}`}},
+		"cycle": {sample: `{"$include": "a.json"}`, fragments: map[string]string{
+			"a.json": `{"b": {"$include": "b.json"}}`,
+			"b.json": `{"a": {"$include": "a.json"}}`,
+		}},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			baseDir := t.TempDir()
+			dir := filepath.Join(baseDir, "items")
+			writeFile(t, dir, "GET.json", tc.sample)
+			for f, content := range tc.fragments {
+				writeFile(t, dir, f, content)
+			}
+
+			p := NewSampleProvider(ProviderConfig{BaseDir: baseDir, Layout: config.LayoutFolders}, logger.GetLogger())
+			_, err := p.ResolveAndLoad("GET", "/items", "/items", "", LoadOptions{})
+			var includeErr *SampleIncludeError
+			require.True(t, errors.As(err, &includeErr), "got %v", err)
+		})
+	}
+}
+
+func TestSampleProvider_IncludeFragmentSyntaxError(t *testing.T) {
+	baseDir := t.TempDir()
+	dir := filepath.Join(baseDir, "items")
+	writeFile(t, dir, "GET.json", `{"$include": "broken.json"}`)
+	fragment := writeFile(t, dir, "broken.json", "{\n  \"a\": \n}")
+
+	p := NewSampleProvider(ProviderConfig{BaseDir: baseDir, Layout: config.LayoutFolders}, logger.GetLogger())
+	_, err := p.ResolveAndLoad("GET", "/items", "/items", "", LoadOptions{})
+	var syntaxErr *SampleSyntaxError
+	require.True(t, errors.As(err, &syntaxErr), "got %v", err)
+	require.Equal(t, fragment, syntaxErr.Path)
+}
@@ -5,8 +5,15 @@
 package samples
 
 type ISampleProvider interface {
-	ResolveAndLoad(method, swaggerTpl, actualPath, legacyFlatFilename string) (*Response, error)
+	ResolveAndLoad(method, swaggerTpl, actualPath, legacyFlatFilename string, opts LoadOptions) (*Response, error)
 	ResolvePath(method, swaggerTpl, actualPath, legacyFlatFilename string) (string, error)
+	LoadSample(relPath string, opts LoadOptions) (*Response, error)
+	LoadDefault(method string) (*Response, bool, error)
+	SampleFiles(method, swaggerTpl, legacyFlatFilename, tagFile string) []SampleFile
+	AuditFlatSamples(routes []RouteFiles) (*FlatSampleReport, error)
+	AuditSampleCoverage(routes []RouteFiles) (*SampleCoverageReport, error)
+	PeekScenarioState(method, swaggerTpl, actualPath string, params map[string]string, client string) (*ScenarioState, error)
+	SetScenarioState(swaggerTpl, key, state string) error
 }
 
 type IScenarioResolver interface {
@@ -15,6 +22,13 @@ type IScenarioResolver interface {
 		method string,
 		swaggerTpl string,
 		actualPath string,
-	) (file string, state string, err error)
-	TryResetByRequest(method, actualPath string) bool
+		params map[string]string,
+		client string,
+	) (file string, state ScenarioState, err error)
+	PeekScenarioState(sc *Scenario, method, swaggerTpl, actualPath string, params map[string]string, client string) (ScenarioState, error)
+	TryResetByRequest(method, actualPath, client string) bool
+	SetScenarioState(sc *Scenario, swaggerTpl, key, state string) error
+	// ResetScenarios drops all runtime scenario state, e.g. after scenario
+	// files changed on disk.
+	ResetScenarios() int
 }
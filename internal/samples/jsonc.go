@@ -0,0 +1,87 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package samples
+
+// stripJSONC turns JSON with comments into plain JSON: // and /* */
+// comments and commas between a value and a closing } or ] are replaced
+// with spaces.
+// The output has the same length and newlines as the input, so line and
+// column numbers of syntax errors still point into the file as read (after
+// template rendering). Text inside strings is left alone.
+func stripJSONC(b []byte) []byte {
+	out := make([]byte, len(b))
+	copy(out, b)
+
+	blank := func(from, to int) {
+		for i := from; i < to; i++ {
+			if out[i] != '\n' {
+				out[i] = ' '
+			}
+		}
+	}
+
+	// comments
+	for i := 0; i < len(out); i++ {
+		switch {
+		case out[i] == '"':
+			i = skipString(out, i)
+		case out[i] == '/' && i+1 < len(out) && out[i+1] == '/':
+			end := i
+			for end < len(out) && out[end] != '\n' {
+				end++
+			}
+			blank(i, end)
+			i = end
+		case out[i] == '/' && i+1 < len(out) && out[i+1] == '*':
+			end := i + 2
+			for end+1 < len(out) && !(out[end] == '*' && out[end+1] == '/') {
+				end++
+			}
+			end = min(end+2, len(out))
+			blank(i, end)
+			i = end - 1
+		}
+	}
+
+	// trailing commas
+	for i := 0; i < len(out); i++ {
+		switch out[i] {
+		case '"':
+			i = skipString(out, i)
+		case ',':
+			j := i + 1
+			for j < len(out) && isJSONSpace(out[j]) {
+				j++
+			}
+			k := i - 1
+			for k >= 0 && isJSONSpace(out[k]) {
+				k--
+			}
+			afterValue := k >= 0 && out[k] != '{' && out[k] != '[' && out[k] != ','
+			if afterValue && j < len(out) && (out[j] == '}' || out[j] == ']') {
+				out[i] = ' '
+			}
+		}
+	}
+	return out
+}
+
+// skipString returns the index of the closing quote of the string starting
+// at b[start], or the last index when it is unterminated.
+func skipString(b []byte, start int) int {
+	for i := start + 1; i < len(b); i++ {
+		switch b[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return len(b) - 1
+}
+
+func isJSONSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
@@ -0,0 +1,28 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package samples
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/ozgen/openapi-emulator/internal/matcher"
+)
+
+// matchRequest is the request of opts as seen by match and expect blocks.
+func matchRequest(opts LoadOptions) matcher.Request {
+	header := make(map[string]string, len(opts.Header))
+	for k, v := range opts.Header {
+		header[http.CanonicalHeaderKey(strings.ReplaceAll(k, "_", "-"))] = v
+	}
+	return matcher.Request{Header: header, Query: opts.Query, Body: opts.body()}
+}
+
+// requestHeader looks name up in the template-style header map of
+// LoadOptions, whose keys are canonical names with "-" replaced by "_".
+func requestHeader(h map[string]string, name string) (string, bool) {
+	v, ok := h[strings.ReplaceAll(http.CanonicalHeaderKey(name), "-", "_")]
+	return v, ok
+}
@@ -4,18 +4,145 @@
 
 package samples
 
-import "github.com/ozgen/openapi-emulator/config"
+import (
+	"net/http"
+	"time"
+
+	"github.com/ozgen/openapi-emulator/config"
+	"github.com/ozgen/openapi-emulator/internal/matcher"
+)
 
 type Envelope struct {
-	Status  int               `json:"status"`
-	Headers map[string]string `json:"headers"`
-	Body    any               `json:"body"`
+	Version int                     `json:"version,omitempty"`
+	Status  int                     `json:"status"`
+	Headers map[string]HeaderValues `json:"headers"`
+	Body    any                     `json:"body"`
+	// Cookies are sent as one Set-Cookie header each.
+	Cookies []Cookie `json:"cookies,omitempty"`
+	// BodyEncoding "base64" declares Body a base64 string of raw bytes,
+	// served as they are.
+	BodyEncoding string `json:"bodyEncoding,omitempty"`
+	// EchoFields names request body fields copied into the response body.
+	EchoFields []string `json:"echoFields,omitempty"`
+	// Match holds the request conditions of a sample variant.
+	Match *matcher.Rule `json:"match,omitempty"`
+	// Expect holds request conditions answered with a 4xx when unmet.
+	Expect *SampleExpect `json:"expect,omitempty"`
+	// Weight enters an unconditional variant into a weighted random draw.
+	Weight int `json:"weight,omitempty"`
+	// DelayMs holds the response back to simulate a slow endpoint.
+	DelayMs *Delay `json:"delayMs,omitempty"`
+	// Stream writes the body in delayed chunks instead of at once.
+	Stream *Stream `json:"stream,omitempty"`
 }
 
 type Response struct {
-	Status  int
-	Headers map[string]string
+	Status int
+	// Headers holds every value of every header, in order, keyed by the
+	// name as spelled in the sample; see sampleHeaders.
+	Headers http.Header
 	Body    []byte
+	// Cookies are converted from the envelope's cookies.
+	Cookies []*http.Cookie
+	// EchoFields is copied from the envelope; see echoFields.
+	EchoFields []string
+	// Match and Weight are copied from the envelope; see selectVariant.
+	Match  *matcher.Rule
+	Weight int
+	// Expect is copied from the envelope; see checkExpect.
+	Expect *SampleExpect
+	// Delay is the envelope's delayMs, applied by the server before writing.
+	Delay *Delay
+	// Stream is the envelope's stream, applied by the server while writing.
+	Stream *Stream
+	// Scenario is the scenario state the sample was selected by, if any.
+	Scenario *ScenarioState
+}
+
+// CommitScenario advances a scenario with "advance": "response" past the
+// entry this response was selected by. The server calls it once the
+// response has been written; it does nothing for other responses.
+func (r *Response) CommitScenario() error {
+	if r.Scenario == nil || r.Scenario.commit == nil {
+		return nil
+	}
+	return r.Scenario.commit()
+}
+
+// SampleFile is one sample a route can serve, as listed by SampleFiles.
+type SampleFile struct {
+	Path string
+	// State is the scenario state the file belongs to; empty for plain samples.
+	State    string
+	Modified time.Time
+	// Response is the loaded sample, nil when Err is set.
+	Response *Response
+	Err      error
+}
+
+// RouteFiles names a route and its sample files, as input to
+// AuditFlatSamples and AuditSampleCoverage.
+type RouteFiles struct {
+	Method      string
+	SwaggerPath string
+	FlatFile    string
+	TagFile     string
+	// Sample is the route's x-emulator-sample file, relative to BaseDir.
+	Sample string
+}
+
+// FlatSampleReport lists legacy flat samples that are never served.
+type FlatSampleReport struct {
+	Shadowed []ShadowedSample `json:"shadowed"`
+	// Orphaned are flat files whose name matches no route of the spec.
+	Orphaned []string `json:"orphaned"`
+}
+
+// ShadowedSample is a flat sample hidden by a folder sample of the same route.
+type ShadowedSample struct {
+	File       string `json:"file"`
+	ShadowedBy string `json:"shadowedBy"`
+}
+
+// LoadOptions carries per-request inputs to ResolveAndLoad and LoadSample.
+type LoadOptions struct {
+	// ForceState serves the scenario entry with this state without reading or
+	// advancing the stored scenario state.
+	ForceState string
+	// Status serves the route's status sample for this status, e.g.
+	// GET.404.json, instead of its scenario or plain sample.
+	Status int
+	// ReadOnly serves the entry of the current scenario state without
+	// advancing or resetting any scenario, for requests such as HEAD that
+	// must not change what the next request sees.
+	ReadOnly bool
+	// IgnoreExpect serves a sample even when the request does not meet its
+	// "expect" block, for samples rendered without a real request.
+	IgnoreExpect bool
+	// Client isolates scenario state per client: scenarios advance and reset
+	// separately for every distinct value. Empty shares state.
+	Client string
+	// TagFile is the route's <tag>/<operationId>.json, used by LAYOUT_MODE=tags.
+	TagFile string
+	// Cookies are the request cookies by name, exposed to sample templates.
+	Cookies map[string]string
+	// Path, Query and Header are the request context exposed to sample
+	// templates; see TemplateData.
+	Path   map[string]string
+	Query  map[string]string
+	Header map[string]string
+	// Body returns the decoded JSON request body, or nil. It is only called
+	// when a sample uses the body: a template reading .Body, echoFields, or
+	// a match or expect block. A nil Body means no body.
+	Body func() any
+}
+
+// body returns the decoded request body of o, or nil.
+func (o LoadOptions) body() any {
+	if o.Body == nil {
+		return nil
+	}
+	return o.Body()
 }
 
 type ProviderConfig struct {
@@ -23,16 +150,30 @@ type ProviderConfig struct {
 	Layout           config.LayoutMode
 	ScenarioEnabled  bool
 	ScenarioFilename string
+	// ScenariosDir, when set, is searched for scenario files instead of
+	// BaseDir. Files named by a scenario are looked up next to it first and
+	// then in the matching BaseDir folder.
+	ScenariosDir     string
 	ScenarioResolver IScenarioResolver
+	// CacheSize bounds the parsed samples kept in memory; 0 disables the
+	// cache.
+	CacheSize int
+}
+
+// ScenarioKey names the path parameter whose value identifies a scenario
+// instance. Aliases are other names the same value goes by on related
+// endpoints, e.g. {scanId} in a resetOn path when the key is {id}.
+type ScenarioKey struct {
+	PathParam string   `json:"pathParam"`
+	Aliases   []string `json:"aliases,omitempty"`
 }
 
 type Scenario struct {
-	Version int    `json:"version"`
-	Mode    string `json:"mode"` // "step" | "time"
+	Version     int    `json:"version"`
+	Description string `json:"description,omitempty"`
+	Mode        string `json:"mode"` // "step" | "time"
 
-	Key struct {
-		PathParam string `json:"pathParam"`
-	} `json:"key"`
+	Key ScenarioKey `json:"key"`
 
 	// step mode
 	Sequence []ScenarioEntry `json:"sequence,omitempty"`
@@ -41,6 +182,26 @@ type Scenario struct {
 	Timeline []TimelineEntry `json:"timeline,omitempty"`
 
 	Behavior Behavior `json:"behavior"`
+
+	// anchor is the first `at` of an absolute timeline; its entries'
+	// AfterSec are then offsets from it. Zero for afterSec timelines.
+	anchor time.Time
+}
+
+// ScenarioState describes the scenario entry selected for a request. It is
+// exposed to sample templates as {{ .Scenario }}.
+type ScenarioState struct {
+	Mode  string
+	State string
+	Step  int
+
+	// time mode only: seconds since start, last afterSec and elapsed/total in 0..100
+	Elapsed int64
+	Total   int64
+	Percent int
+
+	// commit performs a deferred advance; see Response.CommitScenario.
+	commit func() error
 }
 
 type ScenarioEntry struct {
@@ -49,9 +210,12 @@ type ScenarioEntry struct {
 }
 
 type TimelineEntry struct {
-	AfterSec int64  `json:"afterSec"`
-	State    string `json:"state"`
-	File     string `json:"file"`
+	AfterSec int64 `json:"afterSec"`
+	// At is an RFC3339 wall-clock time the entry takes effect, instead of
+	// AfterSec. A timeline uses either at or afterSec for all entries.
+	At    string `json:"at,omitempty"`
+	State string `json:"state"`
+	File  string `json:"file"`
 }
 
 type Behavior struct {
@@ -60,6 +224,11 @@ type Behavior struct {
 	StartOn    []MatchRule `json:"startOn,omitempty"`
 	RepeatLast bool        `json:"repeatLast"`
 	Loop       bool        `json:"loop,omitempty"`
+	// Advance is when a step scenario moves on: "request" (the default)
+	// advances as the entry is read, so concurrent pollers each get the
+	// next entry; "response" advances once the response has been written,
+	// so pollers that read the same entry concurrently advance it once.
+	Advance string `json:"advance,omitempty"`
 }
 
 type MatchRule struct {
@@ -75,4 +244,5 @@ type ResetRule struct {
 type ResetBinding struct {
 	ScenarioTpl string
 	KeyParam    string
+	KeyAliases  []string
 }
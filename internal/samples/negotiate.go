@@ -0,0 +1,101 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package samples
+
+import (
+	"mime"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ozgen/openapi-emulator/utils"
+)
+
+// representations lists the files serving the sample at path in different
+// formats: path itself when it exists, then the siblings sharing its name
+// with another extension (GET.xml, GET.csv, GET.png), sorted by name.
+func representations(path string) []string {
+	var out []string
+	if utils.FileExists(path) {
+		out = append(out, path)
+	}
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		return out
+	}
+	prefix := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)) + "."
+
+	var siblings []string
+	for _, e := range entries {
+		ext, ok := strings.CutPrefix(e.Name(), prefix)
+		if e.IsDir() || !ok || ext == "" || strings.Contains(ext, ".") || strings.EqualFold(ext, "json") {
+			continue
+		}
+		siblings = append(siblings, filepath.Join(filepath.Dir(path), e.Name()))
+	}
+	sort.Strings(siblings)
+	return append(out, siblings...)
+}
+
+// mediaTypeOf is the media type a sample file is served as by default.
+func mediaTypeOf(path string) string {
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext == ".json" {
+		return "application/json"
+	}
+	if t, _, err := mime.ParseMediaType(mime.TypeByExtension(ext)); err == nil {
+		return t
+	}
+	return "application/octet-stream"
+}
+
+// negotiate picks the representation the Accept header prefers. Each file
+// gets the quality of the most specific range matching its media type; the
+// highest wins and ties go to the earlier file. Without an Accept header,
+// or when nothing is acceptable, the first file is served.
+func negotiate(reps []string, accept string) string {
+	best, bestQ := reps[0], 0.0
+	for _, rep := range reps {
+		if q := acceptQuality(accept, mediaTypeOf(rep)); q > bestQ {
+			best, bestQ = rep, q
+		}
+	}
+	return best
+}
+
+// acceptQuality returns the q value accept gives mediaType, 0 when it is
+// not acceptable.
+func acceptQuality(accept, mediaType string) float64 {
+	typ, _, _ := strings.Cut(mediaType, "/")
+	q, specificity := 0.0, -1
+	for _, part := range strings.Split(accept, ",") {
+		rng, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		rng = strings.ToLower(strings.TrimSpace(rng))
+
+		spec := -1
+		switch {
+		case rng == mediaType:
+			spec = 2
+		case rng == typ+"/*":
+			spec = 1
+		case rng == "*/*":
+			spec = 0
+		}
+		if spec <= specificity {
+			continue
+		}
+		specificity, q = spec, 1
+		for _, p := range strings.Split(params, ";") {
+			if v, ok := strings.CutPrefix(strings.TrimSpace(p), "q="); ok {
+				if f, err := strconv.ParseFloat(v, 64); err == nil {
+					q = f
+				}
+			}
+		}
+	}
+	return q
+}
@@ -0,0 +1,63 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package samples
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/ozgen/openapi-emulator/config"
+	"github.com/ozgen/openapi-emulator/logger"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAcceptQuality(t *testing.T) {
+	cases := []struct {
+		accept, mediaType string
+		want              float64
+	}{
+		{"", "application/json", 0},
+		{"application/json", "application/json", 1},
+		{"text/*;q=0.5", "text/csv", 0.5},
+		{"*/*;q=0.1, text/csv", "text/csv", 1},
+		{"text/csv;q=0.2, text/*", "text/csv", 0.2},
+		{"text/csv;q=0", "text/csv", 0},
+		{"Application/XML", "application/xml", 1},
+		{"image/png", "application/json", 0},
+	}
+	for _, c := range cases {
+		require.Equal(t, c.want, acceptQuality(c.accept, c.mediaType), "%q %q", c.accept, c.mediaType)
+	}
+}
+
+func TestSampleProvider_ResolveAndLoad_NegotiatesByAccept(t *testing.T) {
+	baseDir := t.TempDir()
+	dir := filepath.Join(baseDir, "reports")
+	writeFile(t, dir, "GET.json", `[{"id":1}]`)
+	writeFile(t, dir, "GET.xml", `<reports><report id="1"/></reports>`)
+	writeFile(t, dir, "GET.csv", "id\n1\n")
+
+	p := NewSampleProvider(ProviderConfig{BaseDir: baseDir, Layout: config.LayoutFolders}, logger.GetLogger())
+	load := func(accept string) *Response {
+		t.Helper()
+		header := map[string]string{}
+		if accept != "" {
+			header["Accept"] = accept
+		}
+		resp, err := p.ResolveAndLoad("GET", "/reports", "/reports", "", LoadOptions{Header: header})
+		require.NoError(t, err)
+		return resp
+	}
+
+	require.Equal(t, []string{"application/json"}, load("").Headers["Content-Type"])
+	require.Equal(t, []string{"application/json"}, load("*/*").Headers["Content-Type"])
+	require.Equal(t, []string{"application/json"}, load("image/png").Headers["Content-Type"], "nothing acceptable serves the default")
+	require.Equal(t, []string{"text/csv; charset=utf-8"}, load("text/csv").Headers["Content-Type"])
+	require.Equal(t, "id\n1", string(load("text/csv").Body))
+	require.Equal(t, `<reports><report id="1"/></reports>`, string(load("application/json;q=0.5, text/xml").Body))
+
+	files := p.SampleFiles("GET", "/reports", "", "")
+	require.Len(t, files, 3)
+}
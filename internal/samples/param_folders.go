@@ -0,0 +1,83 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package samples
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ozgen/openapi-emulator/utils"
+)
+
+var paramBraces = strings.NewReplacer("{", "_", "}", "_")
+
+// EncodeParamSegments spells the {name} parameters of a path as _name_, the
+// folder names that work on every filesystem and in every tool.
+func EncodeParamSegments(p string) string {
+	return paramBraces.Replace(p)
+}
+
+// FolderRename is one directory moved by MigrateParamFolders, with paths
+// relative to the samples dir as they were before the migration.
+type FolderRename struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// MigrateParamFolders renames every directory below baseDir whose name
+// contains { or } to its _name_ spelling. Deeper directories are renamed
+// first. With dryRun, nothing is changed and the planned renames are
+// returned. It stops at the first directory whose target already exists and
+// renames nothing when a parent directory is not writable.
+func MigrateParamFolders(baseDir string, dryRun bool) ([]FolderRename, error) {
+	var dirs []string
+	err := filepath.WalkDir(baseDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && path != baseDir && strings.ContainsAny(d.Name(), "{}") {
+			dirs = append(dirs, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(dirs, func(i, j int) bool {
+		return strings.Count(dirs[i], string(filepath.Separator)) > strings.Count(dirs[j], string(filepath.Separator))
+	})
+
+	if !dryRun {
+		// refuse up front instead of leaving a half-migrated tree behind
+		for _, dir := range dirs {
+			if parent := filepath.Dir(dir); !utils.DirWritable(parent) {
+				return nil, fmt.Errorf("%s is not writable (read-only mount?); use -dry-run or migrate a writable copy", parent)
+			}
+		}
+	}
+
+	out := make([]FolderRename, 0, len(dirs))
+	for _, dir := range dirs {
+		target := filepath.Join(filepath.Dir(dir), EncodeParamSegments(filepath.Base(dir)))
+		from, _ := filepath.Rel(baseDir, dir)
+		to, _ := filepath.Rel(baseDir, target)
+
+		if _, err := os.Stat(target); err == nil {
+			return out, fmt.Errorf("cannot rename %s: %s already exists", from, to)
+		}
+		if !dryRun {
+			if err := os.Rename(dir, target); err != nil {
+				return out, err
+			}
+		}
+		out = append(out, FolderRename{From: filepath.ToSlash(from), To: filepath.ToSlash(to)})
+	}
+	return out, nil
+}
@@ -0,0 +1,83 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package samples
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ozgen/openapi-emulator/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeParamSegments(t *testing.T) {
+	require.Equal(t, "scans/_id_/results/_rid_", EncodeParamSegments("scans/{id}/results/{rid}"))
+	require.Equal(t, "scans", EncodeParamSegments("scans"))
+}
+
+func TestBuildCandidates_LayoutFolders_EncodedParams(t *testing.T) {
+	got := buildCandidates(config.LayoutFolders, "GET", "/items/{id}", "GET__items_{id}.json", "")
+	require.Equal(t, []string{
+		filepath.Join("items", "{id}", "GET.json"),
+		filepath.Join("items", "_id_", "GET.json"),
+	}, got)
+}
+
+func TestSampleProvider_ResolveAndLoad_EncodedParamFolders(t *testing.T) {
+	baseDir := t.TempDir()
+	writeFile(t, baseDir, filepath.Join("items", "_id_", "GET.json"), `{"id":"1"}`)
+	writeFile(t, baseDir, filepath.Join("scans", "_id_", "scenario.json"), `{
+	  "version": 1,
+	  "mode": "step",
+	  "key": {"pathParam": "id"},
+	  "sequence": [{"state": "queued", "file": "queued.json"}],
+	  "behavior": {"repeatLast": true}
+	}`)
+	writeFile(t, baseDir, filepath.Join("scans", "_id_", "queued.json"), `{"state":"queued"}`)
+
+	p := NewSampleProvider(ProviderConfig{
+		BaseDir:          baseDir,
+		Layout:           config.LayoutFolders,
+		ScenarioEnabled:  true,
+		ScenarioFilename: "scenario.json",
+		ScenarioResolver: NewScenarioResolver(),
+	}, nil)
+
+	resp, err := p.ResolveAndLoad("GET", "/items/{id}", "/items/1", "", LoadOptions{})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"id":"1"}`, string(resp.Body))
+
+	resp, err = p.ResolveAndLoad("GET", "/scans/{id}", "/scans/7", "", LoadOptions{})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"state":"queued"}`, string(resp.Body))
+}
+
+func TestMigrateParamFolders(t *testing.T) {
+	baseDir := t.TempDir()
+	writeFile(t, baseDir, filepath.Join("a", "{id}", "b", "{bid}", "GET.json"), `{}`)
+
+	planned, err := MigrateParamFolders(baseDir, true)
+	require.NoError(t, err)
+	require.Equal(t, []FolderRename{
+		{From: "a/{id}/b/{bid}", To: "a/{id}/b/_bid_"},
+		{From: "a/{id}", To: "a/_id_"},
+	}, planned)
+	require.FileExists(t, filepath.Join(baseDir, "a", "{id}", "b", "{bid}", "GET.json"))
+
+	done, err := MigrateParamFolders(baseDir, false)
+	require.NoError(t, err)
+	require.Equal(t, planned, done)
+	require.FileExists(t, filepath.Join(baseDir, "a", "_id_", "b", "_bid_", "GET.json"))
+}
+
+func TestMigrateParamFolders_TargetExists(t *testing.T) {
+	baseDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(baseDir, "{id}"), 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Join(baseDir, "_id_"), 0o755))
+
+	_, err := MigrateParamFolders(baseDir, false)
+	require.ErrorContains(t, err, "already exists")
+}
@@ -0,0 +1,67 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package samples
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// rawMetaSuffix names the sidecar of a raw sample: GET.pdf.meta.json.
+const rawMetaSuffix = ".meta.json"
+
+// rawSampleMeta is the sidecar of a raw sample, overriding the status and
+// headers it is served with.
+type rawSampleMeta struct {
+	Status  int                     `json:"status"`
+	Headers map[string]HeaderValues `json:"headers"`
+}
+
+// isRawSample reports whether the file at path is served byte for byte
+// rather than read as JSON or text: any extension other than .json whose
+// media type is not text/*, e.g. .pdf, .png or .bin.
+func isRawSample(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext == "" || ext == ".json" {
+		return false
+	}
+	return !strings.HasPrefix(mime.TypeByExtension(ext), "text/")
+}
+
+// loadRawFile serves the file at path as it is, with the content type of
+// its extension unless the sidecar sets one.
+func loadRawFile(path string) (*Response, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read sample %s: %w", path, err)
+	}
+
+	resp := &Response{
+		Status:  fileStatus(path),
+		Headers: http.Header{},
+		Body:    b,
+	}
+
+	metaPath := path + rawMetaSuffix
+	if m, err := os.ReadFile(metaPath); err == nil {
+		var meta rawSampleMeta
+		if err := json.Unmarshal(stripJSONC(m), &meta); err != nil {
+			return nil, &SampleEnvelopeError{Path: metaPath, Err: err}
+		}
+		if meta.Status != 0 {
+			resp.Status = meta.Status
+		}
+		resp.Headers = sampleHeaders(meta.Headers)
+	}
+	if _, ok := HeaderGet(resp.Headers, "content-type"); !ok {
+		resp.Headers["Content-Type"] = []string{mediaTypeOf(path)}
+	}
+	return resp, nil
+}
@@ -0,0 +1,99 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package samples
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/ozgen/openapi-emulator/config"
+	"github.com/ozgen/openapi-emulator/logger"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsRawSample(t *testing.T) {
+	for _, name := range []string{"GET.pdf", "GET.png", "GET.bin", "GET.PNG"} {
+		require.True(t, isRawSample(name), name)
+	}
+	for _, name := range []string{"GET.json", "GET.txt", "GET.csv", "GET", "GET.pdf.meta.json"} {
+		require.False(t, isRawSample(name), name)
+	}
+}
+
+func TestLoadFile_RawSample_ServedByteExact(t *testing.T) {
+	dir := t.TempDir()
+	content := "  \x00\x01{{ not a template }}\n"
+	p := writeFile(t, dir, "GET.pdf", content)
+
+	resp, err := loadFile(p, &TemplateData{})
+	require.NoError(t, err)
+	require.Equal(t, 200, resp.Status)
+	require.Equal(t, []string{"application/pdf"}, resp.Headers["Content-Type"])
+	require.Equal(t, content, string(resp.Body))
+
+	p = writeFile(t, dir, "blob.bin", "\xff")
+	resp, err = loadFile(p, nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"application/octet-stream"}, resp.Headers["Content-Type"])
+}
+
+func TestLoadFile_RawSample_Sidecar(t *testing.T) {
+	dir := t.TempDir()
+	p := writeFile(t, dir, "GET.bin", "\x08\x96\x01")
+	writeFile(t, dir, "GET.bin.meta.json", `{
+	  // protobuf message
+	  "status": 202,
+	  "headers": {"content-type": "application/x-protobuf", "X-Proto-Message": "scan.v1.Status"},
+	}`)
+
+	resp, err := loadFile(p, nil)
+	require.NoError(t, err)
+	require.Equal(t, 202, resp.Status)
+	require.Equal(t, []string{"application/x-protobuf"}, resp.Headers["content-type"])
+	require.Equal(t, []string{"scan.v1.Status"}, resp.Headers["X-Proto-Message"])
+	_, injected := resp.Headers["Content-Type"]
+	require.False(t, injected)
+
+	writeFile(t, dir, "GET.bin.meta.json", `{"status": "accepted"}`)
+	_, err = loadFile(p, nil)
+	var envErr *SampleEnvelopeError
+	require.ErrorAs(t, err, &envErr)
+}
+
+func TestLoadFile_RawSample_SidecarRepeatedHeaders(t *testing.T) {
+	dir := t.TempDir()
+	p := writeFile(t, dir, "GET.png", "\x89PNG")
+	writeFile(t, dir, "GET.png.meta.json", `{"headers": {"Warning": ["199 - \"stale\"", "299 - \"deprecated\""]}}`)
+
+	resp, err := loadFile(p, nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"image/png"}, resp.Headers["Content-Type"])
+	require.Equal(t, []string{`199 - "stale"`, `299 - "deprecated"`}, resp.Headers["Warning"])
+}
+
+func TestSampleProvider_ResolveAndLoad_RawSample(t *testing.T) {
+	baseDir := t.TempDir()
+	dir := filepath.Join(baseDir, "reports", "{id}")
+	writeFile(t, dir, "GET.png", "\x89PNG")
+	writeFile(t, dir, "GET.png.meta.json", `{"headers":{"Cache-Control":"no-store"}}`)
+
+	p := NewSampleProvider(ProviderConfig{BaseDir: baseDir, Layout: config.LayoutFolders}, logger.GetLogger())
+
+	resp, err := p.ResolveAndLoad("GET", "/reports/{id}", "/reports/1", "", LoadOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "\x89PNG", string(resp.Body))
+	require.Equal(t, []string{"image/png"}, resp.Headers["Content-Type"])
+	require.Equal(t, []string{"no-store"}, resp.Headers["Cache-Control"])
+
+	files := p.SampleFiles("GET", "/reports/{id}", "", "")
+	require.Len(t, files, 1)
+	require.Equal(t, filepath.Join(dir, "GET.png"), files[0].Path)
+
+	// A JSON sample takes precedence.
+	writeFile(t, dir, "GET.json", `{"id":"1"}`)
+	resp, err = p.ResolveAndLoad("GET", "/reports/{id}", "/reports/1", "", LoadOptions{})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"id":"1"}`, string(resp.Body))
+}
@@ -0,0 +1,196 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package samples
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisKeyPrefix namespaces the keys written by RedisScenarioStore.
+const redisKeyPrefix = "openapi-emulator:scenario:"
+
+const redisTimeout = 5 * time.Second
+
+// RedisScenarioStore keeps scenario state in Redis, so replicas behind a load
+// balancer share scenario progress.
+type RedisScenarioStore struct {
+	client *redis.Client
+}
+
+// NewRedisScenarioStore parses a redis://[user:password@]host[:port][/db] or
+// rediss:// URL. Connections are dialed on first use.
+func NewRedisScenarioStore(rawURL string) (*RedisScenarioStore, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid redis url: %w", err)
+	}
+	if u.Scheme != "redis" && u.Scheme != "rediss" {
+		return nil, fmt.Errorf("invalid redis url %q: scheme must be redis or rediss", rawURL)
+	}
+	if u.Hostname() == "" {
+		return nil, fmt.Errorf("invalid redis url %q: missing host", rawURL)
+	}
+	if db := strings.Trim(u.Path, "/"); db != "" {
+		if n, err := strconv.Atoi(db); err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid redis url %q: database must be a non-negative number", rawURL)
+		}
+	}
+
+	opts, err := redis.ParseURL(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid redis url %q: %w", rawURL, err)
+	}
+	opts.DialTimeout = redisTimeout
+	opts.ReadTimeout = redisTimeout
+	opts.WriteTimeout = redisTimeout
+	// The client must not retry on its own: a transaction whose reply was
+	// lost may have been applied, and running it again would advance a
+	// scenario twice. Idempotent commands are retried by retry below.
+	opts.MaxRetries = -1
+	opts.DisableIdentity = true
+	return &RedisScenarioStore{client: redis.NewClient(opts)}, nil
+}
+
+func (s *RedisScenarioStore) Step(key string) (int, error) {
+	var v string
+	err := s.retry(func(ctx context.Context) (err error) {
+		v, err = s.client.Get(ctx, redisKeyPrefix+key+":step").Result()
+		return err
+	})
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	idx, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, &ScenarioStoreError{Err: fmt.Errorf("redis: invalid step index for %q: %w", key, err)}
+	}
+	return idx, nil
+}
+
+func (s *RedisScenarioStore) SetStep(key string, idx int) error {
+	return s.retry(func(ctx context.Context) error {
+		return s.client.Set(ctx, redisKeyPrefix+key+":step", strconv.Itoa(idx), 0).Err()
+	})
+}
+
+// CompareAndSetStep uses WATCH and MULTI/EXEC, so a replica changing the
+// step between the read and the write makes the swap fail. It is never
+// retried: when the connection breaks after EXEC was sent, the step may or
+// may not have moved, and that is reported as a ScenarioStoreError instead
+// of being guessed.
+func (s *RedisScenarioStore) CompareAndSetStep(key string, old, idx int) (bool, error) {
+	k := redisKeyPrefix + key + ":step"
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+
+	swapped := false
+	err := s.client.Watch(ctx, func(tx *redis.Tx) error {
+		cur := 0
+		v, err := tx.Get(ctx, k).Result()
+		switch {
+		case errors.Is(err, redis.Nil):
+		case err != nil:
+			return err
+		default:
+			if cur, err = strconv.Atoi(v); err != nil {
+				return fmt.Errorf("invalid step index for %q: %w", key, err)
+			}
+		}
+		if cur != old {
+			return nil
+		}
+		_, err = tx.TxPipelined(ctx, func(p redis.Pipeliner) error {
+			p.Set(ctx, k, strconv.Itoa(idx), 0)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		swapped = true
+		return nil
+	}, k)
+	if errors.Is(err, redis.TxFailedErr) {
+		return false, nil
+	}
+	if err != nil {
+		return false, &ScenarioStoreError{Err: fmt.Errorf("redis: compare-and-set of %q, outcome unknown: %w", key, err)}
+	}
+	return swapped, nil
+}
+
+func (s *RedisScenarioStore) Start(key string, now time.Time) (time.Time, error) {
+	k := redisKeyPrefix + key + ":start"
+	// SET NX keeps the start time of whichever replica saw the key first. A
+	// retry after a lost reply finds our own value, so both are safe to
+	// repeat.
+	var set bool
+	var v string
+	err := s.retry(func(ctx context.Context) (err error) {
+		if set, err = s.client.SetNX(ctx, k, strconv.FormatInt(now.UnixNano(), 10), 0).Result(); err != nil || set {
+			return err
+		}
+		v, err = s.client.Get(ctx, k).Result()
+		return err
+	})
+	if set || errors.Is(err, redis.Nil) {
+		return now, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	ns, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return time.Time{}, &ScenarioStoreError{Err: fmt.Errorf("redis: invalid start time for %q: %w", key, err)}
+	}
+	return time.Unix(0, ns), nil
+}
+
+func (s *RedisScenarioStore) SetStart(key string, t time.Time) error {
+	return s.retry(func(ctx context.Context) error {
+		return s.client.Set(ctx, redisKeyPrefix+key+":start", strconv.FormatInt(t.UnixNano(), 10), 0).Err()
+	})
+}
+
+func (s *RedisScenarioStore) Delete(key string) error {
+	return s.retry(func(ctx context.Context) error {
+		return s.client.Del(ctx, redisKeyPrefix+key+":step", redisKeyPrefix+key+":start").Err()
+	})
+}
+
+// Close closes the connection pool.
+func (s *RedisScenarioStore) Close() error {
+	return s.client.Close()
+}
+
+// retry runs an idempotent fn and runs it once more after a network error,
+// which covers a pooled connection the server closed while it sat idle.
+// redis.Nil is returned as is; other errors as a ScenarioStoreError.
+func (s *RedisScenarioStore) retry(fn func(ctx context.Context) error) error {
+	var err error
+	for attempt := 0; attempt < 2; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+		err = fn(ctx)
+		cancel()
+		if err == nil || errors.Is(err, redis.Nil) {
+			return err
+		}
+		var reply redis.Error
+		if errors.As(err, &reply) {
+			break
+		}
+	}
+	return &ScenarioStoreError{Err: fmt.Errorf("redis %s: %w", s.client.Options().Addr, err)}
+}
@@ -0,0 +1,125 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package samples
+
+import (
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/ozgen/openapi-emulator/config"
+	"github.com/ozgen/openapi-emulator/utils"
+)
+
+// SampleCoverageReport lists the routes without a sample in the configured
+// layout and the files under BaseDir and ScenariosDir no route can serve.
+type SampleCoverageReport struct {
+	// Missing are "METHOD /path" routes without a sample or scenario.
+	Missing []string `json:"missing"`
+	// Unused are slash-separated paths relative to their directory.
+	Unused []string `json:"unused"`
+}
+
+// AuditSampleCoverage compares routes with the sample tree. A file counts as
+// used when some route could serve it in any layout: as a folder, flat or
+// tag sample, a representation, variant or status sample of one, a raw
+// sample's metadata, or a scenario file or entry. Entries whose name starts
+// with "_" (other than _param_ folders), such as _default and shared
+// fragments, and hidden entries are skipped.
+func (p *SampleProvider) AuditSampleCoverage(routes []RouteFiles) (*SampleCoverageReport, error) {
+	report := &SampleCoverageReport{Missing: []string{}, Unused: []string{}}
+	used := map[string]bool{}
+
+	for _, rt := range routes {
+		if len(p.SampleFiles(rt.Method, rt.SwaggerPath, rt.FlatFile, rt.TagFile)) == 0 &&
+			!(rt.Sample != "" && utils.FileExists(filepath.Join(p.cfg.BaseDir, filepath.FromSlash(rt.Sample)))) {
+			report.Missing = append(report.Missing, rt.Method+" "+rt.SwaggerPath)
+		}
+		for _, path := range p.routeSampleFiles(rt) {
+			used[filepath.Clean(path)] = true
+			used[filepath.Clean(path+rawMetaSuffix)] = true
+		}
+	}
+
+	dirs := []string{p.cfg.BaseDir}
+	if p.cfg.ScenariosDir != "" {
+		dirs = append(dirs, p.cfg.ScenariosDir)
+	}
+	for _, dir := range dirs {
+		err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if path == dir {
+				return nil
+			}
+			if name := d.Name(); strings.HasPrefix(name, ".") || (strings.HasPrefix(name, "_") && !(d.IsDir() && isParamFolder(name))) {
+				if d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if d.IsDir() || used[filepath.Clean(path)] {
+				return nil
+			}
+			rel, _ := filepath.Rel(dir, path)
+			report.Unused = append(report.Unused, filepath.ToSlash(rel))
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	slices.Sort(report.Missing)
+	slices.Sort(report.Unused)
+	return report, nil
+}
+
+// routeSampleFiles returns every file rt could be served from, whichever
+// layout is configured.
+func (p *SampleProvider) routeSampleFiles(rt RouteFiles) []string {
+	var out []string
+	if rt.Sample != "" {
+		out = append(out, filepath.Join(p.cfg.BaseDir, filepath.FromSlash(rt.Sample)))
+	}
+	if scPath := p.scenarioPath(rt.SwaggerPath); utils.FileExists(scPath) {
+		out = append(out, scPath)
+		if sc, err := LoadScenario(scPath); err == nil {
+			sampleDir := filepath.Dir(ScenarioPathForSwagger(p.cfg.BaseDir, rt.SwaggerPath, p.cfg.ScenarioFilename))
+			for _, e := range scenarioEntries(sc) {
+				out = append(out, filepath.Join(filepath.Dir(scPath), e.File), filepath.Join(sampleDir, e.File))
+			}
+		}
+	}
+
+	method := strings.ToUpper(rt.Method)
+	candidates := buildCandidates(config.LayoutAuto, method, rt.SwaggerPath, rt.FlatFile, "")
+	candidates = append(candidates, buildCandidates(config.LayoutTags, method, rt.SwaggerPath, "", rt.TagFile)...)
+	for _, rel := range candidates {
+		if rel == "" {
+			continue
+		}
+		full := filepath.Join(p.cfg.BaseDir, rel)
+		out = append(out, representations(full)...)
+		for _, v := range sampleVariants(full) {
+			out = append(out, v.path)
+		}
+		for _, status := range statusSamples(full) {
+			out = append(out, status)
+			for _, v := range sampleVariants(status) {
+				out = append(out, v.path)
+			}
+		}
+	}
+	return out
+}
+
+// isParamFolder reports whether name is an encoded path parameter folder
+// such as _id_.
+func isParamFolder(name string) bool {
+	return len(name) > 2 && strings.HasSuffix(name, "_")
+}
@@ -0,0 +1,52 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package samples
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/ozgen/openapi-emulator/config"
+	"github.com/ozgen/openapi-emulator/logger"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditSampleCoverage(t *testing.T) {
+	baseDir := t.TempDir()
+	writeFile(t, filepath.Join(baseDir, "items", "_id_"), "GET.json", `{}`)
+	writeFile(t, filepath.Join(baseDir, "items", "_id_"), "GET.png", `png`)
+	writeFile(t, filepath.Join(baseDir, "items", "_id_"), "GET.png.meta.json", `{}`)
+	writeFile(t, filepath.Join(baseDir, "items", "_id_"), "PUT.json", `{}`)
+	writeFile(t, filepath.Join(baseDir, "items"), "POST.404.json", `{}`)
+	writeFile(t, filepath.Join(baseDir, "items"), "POST[status=open].json", `{}`)
+	writeFile(t, baseDir, "GET__orders_{id}.json", `{}`)
+	writeFile(t, filepath.Join(baseDir, "_default"), "ANY.json", `{}`)
+	writeFile(t, filepath.Join(baseDir, "_shared"), "user.json", `{}`)
+	writeFile(t, filepath.Join(baseDir, "scans", "_id_"), "scenario.json", `{
+	  "version": 1, "mode": "step", "key": {"pathParam": "id"},
+	  "sequence": [{"state": "queued", "file": "queued.json"}]
+	}`)
+	writeFile(t, filepath.Join(baseDir, "scans", "_id_"), "queued.json", `{}`)
+	writeFile(t, filepath.Join(baseDir, "scans", "_id_"), "old.json", `{}`)
+
+	p := NewSampleProvider(ProviderConfig{
+		BaseDir:          baseDir,
+		Layout:           config.LayoutFolders,
+		ScenarioEnabled:  true,
+		ScenarioFilename: "scenario.json",
+	}, logger.GetLogger())
+
+	report, err := p.AuditSampleCoverage([]RouteFiles{
+		{Method: "GET", SwaggerPath: "/items/{id}", FlatFile: "GET__items_{id}.json"},
+		{Method: "POST", SwaggerPath: "/items", FlatFile: "POST__items.json"},
+		{Method: "GET", SwaggerPath: "/orders/{id}", FlatFile: "GET__orders_{id}.json"},
+		{Method: "GET", SwaggerPath: "/scans/{id}", FlatFile: "GET__scans_{id}.json"},
+	})
+	require.NoError(t, err)
+
+	// The flat orders sample is not served in folder layout.
+	require.Equal(t, []string{"GET /orders/{id}"}, report.Missing)
+	require.Equal(t, []string{"items/_id_/PUT.json", "scans/_id_/old.json"}, report.Unused)
+}
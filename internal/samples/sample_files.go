@@ -0,0 +1,88 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package samples
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ozgen/openapi-emulator/utils"
+)
+
+// SampleFiles lists the samples a route can serve, without resolving or
+// advancing scenario state: every file named by the route's scenario, or
+// else the first layout candidate that exists or has query variants,
+// followed by those variants. Files are loaded without rendering templates.
+func (p *SampleProvider) SampleFiles(method, swaggerTpl, legacyFlatFilename, tagFile string) []SampleFile {
+	cfg := p.cfg
+	method = strings.ToUpper(method)
+
+	if cfg.ScenarioEnabled {
+		if scPath := p.scenarioPath(swaggerTpl); utils.FileExists(scPath) {
+			sc, err := LoadScenario(scPath)
+			if err != nil {
+				return []SampleFile{{Path: scPath, Err: err}}
+			}
+			var out []SampleFile
+			for _, e := range scenarioEntries(sc) {
+				full := filepath.Join(filepath.Dir(scPath), e.File)
+				if !utils.FileExists(full) && cfg.ScenariosDir != "" {
+					sampleDir := filepath.Dir(ScenarioPathForSwagger(cfg.BaseDir, swaggerTpl, cfg.ScenarioFilename))
+					if alt := filepath.Join(sampleDir, e.File); utils.FileExists(alt) {
+						full = alt
+					}
+				}
+				out = append(out, statSample(full, e.State))
+			}
+			return out
+		}
+	}
+
+	for _, rel := range buildCandidates(cfg.Layout, method, swaggerTpl, legacyFlatFilename, tagFile) {
+		full := filepath.Join(cfg.BaseDir, rel)
+		reps := representations(full)
+		variants := sampleVariants(full)
+		statuses := statusSamples(full)
+		if len(reps) == 0 && len(variants) == 0 && len(statuses) == 0 {
+			continue
+		}
+		var out []SampleFile
+		for _, path := range reps {
+			out = append(out, statSample(path, ""))
+		}
+		for _, v := range variants {
+			out = append(out, statSample(v.path, ""))
+		}
+		for _, path := range statuses {
+			out = append(out, statSample(path, ""))
+		}
+		return out
+	}
+	return nil
+}
+
+func scenarioEntries(sc *Scenario) []ScenarioEntry {
+	if sc.Mode == "time" {
+		out := make([]ScenarioEntry, 0, len(sc.Timeline))
+		for _, e := range sc.Timeline {
+			out = append(out, ScenarioEntry{State: e.State, File: e.File})
+		}
+		return out
+	}
+	return sc.Sequence
+}
+
+func statSample(path, state string) SampleFile {
+	out := SampleFile{Path: path, State: state}
+	info, err := os.Stat(path)
+	if err != nil {
+		out.Err = err
+		return out
+	}
+	out.Modified = info.ModTime()
+	out.Response, out.Err = loadFile(path, nil)
+	return out
+}
@@ -5,10 +5,14 @@
 package samples
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"mime"
+	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/ozgen/openapi-emulator/utils"
@@ -17,76 +21,240 @@ import (
 	"github.com/ozgen/openapi-emulator/config"
 )
 
+// ProgressHeader carries the completion percentage of a time-mode scenario.
+const ProgressHeader = "X-Emulator-Progress"
+
 type SampleProvider struct {
-	cfg ProviderConfig
-	log *logrus.Logger
+	cfg   ProviderConfig
+	log   *logrus.Logger
+	cache *sampleCache
 }
 
 func NewSampleProvider(cfg ProviderConfig, log *logrus.Logger) ISampleProvider {
-	return &SampleProvider{cfg: cfg, log: log}
+	return &SampleProvider{cfg: cfg, log: log, cache: newSampleCache(cfg.CacheSize)}
 }
 
-func (p *SampleProvider) ResolveAndLoad(method, swaggerTpl, actualPath, legacyFlatFilename string) (*Response, error) {
-	path, err := p.ResolvePath(method, swaggerTpl, actualPath, legacyFlatFilename)
+func (p *SampleProvider) ResolveAndLoad(method, swaggerTpl, actualPath, legacyFlatFilename string, opts LoadOptions) (*Response, error) {
+	path, state, err := p.resolve(method, swaggerTpl, actualPath, legacyFlatFilename, opts)
 	if err != nil {
 		p.log.WithError(err).Info("failed to resolve path")
 		return nil, err
 	}
-	return loadFile(path)
+	resp, err := p.load(path, templateData(state, opts))
+	if err != nil {
+		return nil, err
+	}
+	if err := checkExpect(path, resp, opts); err != nil {
+		return nil, err
+	}
+	if err := echoFields(resp, opts.body); err != nil {
+		return nil, err
+	}
+	if state == nil {
+		return resp, nil
+	}
+	resp.Scenario = state
+	if state.Mode == "time" {
+		if _, ok := HeaderGet(resp.Headers, ProgressHeader); !ok {
+			resp.Headers[ProgressHeader] = []string{strconv.Itoa(state.Percent)}
+		}
+	}
+	return resp, nil
+}
+
+// LoadSample loads a sample by its path relative to the samples dir.
+func (p *SampleProvider) LoadSample(relPath string, opts LoadOptions) (*Response, error) {
+	full := filepath.Join(p.cfg.BaseDir, filepath.FromSlash(strings.TrimPrefix(relPath, "/")))
+	if !utils.FileExists(full) {
+		return nil, fmt.Errorf("sample file not found: %s", full)
+	}
+	resp, err := p.load(full, templateData(nil, opts))
+	if err != nil {
+		return nil, err
+	}
+	if err := checkExpect(full, resp, opts); err != nil {
+		return nil, err
+	}
+	return resp, echoFields(resp, opts.body)
+}
+
+// DefaultDir holds the catch-all samples served for requests that match no
+// spec route.
+const DefaultDir = "_default"
+
+// LoadDefault loads the catch-all sample for method: _default/<METHOD>.json,
+// else _default/ANY.json. It reports false when neither exists.
+func (p *SampleProvider) LoadDefault(method string) (*Response, bool, error) {
+	for _, name := range []string{strings.ToUpper(method) + ".json", "ANY.json"} {
+		full := filepath.Join(p.cfg.BaseDir, DefaultDir, name)
+		if utils.FileExists(full) {
+			resp, err := p.load(full, nil)
+			return resp, true, err
+		}
+	}
+	return nil, false, nil
 }
 
 func (p *SampleProvider) ResolvePath(method, swaggerTpl, actualPath, legacyFlatFilename string) (string, error) {
+	path, _, err := p.resolve(method, swaggerTpl, actualPath, legacyFlatFilename, LoadOptions{})
+	return path, err
+}
+
+// PeekScenarioState returns the current state of the route's scenario without
+// advancing it, or nil when the route has no scenario.
+func (p *SampleProvider) PeekScenarioState(method, swaggerTpl, actualPath string, params map[string]string, client string) (*ScenarioState, error) {
+	cfg := p.cfg
+	if !cfg.ScenarioEnabled || cfg.ScenarioResolver == nil {
+		return nil, nil
+	}
+	scPath := p.scenarioPath(swaggerTpl)
+	if !utils.FileExists(scPath) {
+		return nil, nil
+	}
+	sc, err := LoadScenario(scPath)
+	if err != nil {
+		return nil, fmt.Errorf("load scenario %s: %w", scPath, err)
+	}
+	state, err := cfg.ScenarioResolver.PeekScenarioState(sc, method, swaggerTpl, actualPath, params, client)
+	if err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+// SetScenarioState moves the scenario of swaggerTpl for key to state, or
+// resets it when state is empty.
+func (p *SampleProvider) SetScenarioState(swaggerTpl, key, state string) error {
+	cfg := p.cfg
+	if !cfg.ScenarioEnabled || cfg.ScenarioResolver == nil {
+		return fmt.Errorf("scenarios are disabled")
+	}
+	scPath := p.scenarioPath(swaggerTpl)
+	if !utils.FileExists(scPath) {
+		return fmt.Errorf("no scenario for %s", swaggerTpl)
+	}
+	sc, err := LoadScenario(scPath)
+	if err != nil {
+		return fmt.Errorf("load scenario %s: %w", scPath, err)
+	}
+	return cfg.ScenarioResolver.SetScenarioState(sc, swaggerTpl, key, state)
+}
+
+// resolve returns the sample path and, for scenario-backed samples, the
+// selected scenario state.
+func (p *SampleProvider) resolve(method, swaggerTpl, actualPath, legacyFlatFilename string, opts LoadOptions) (string, *ScenarioState, error) {
 	cfg := p.cfg
 	method = strings.ToUpper(method)
 
+	if opts.Status != 0 {
+		path, err := p.resolveStatus(method, swaggerTpl, legacyFlatFilename, opts)
+		return path, nil, err
+	}
+
 	// Scenario priority
 	if cfg.ScenarioEnabled {
-		scPath := ScenarioPathForSwagger(cfg.BaseDir, swaggerTpl, cfg.ScenarioFilename)
+		scPath := p.scenarioPath(swaggerTpl)
 		if utils.FileExists(scPath) {
 			sc, err := LoadScenario(scPath)
 			if err != nil {
 				p.log.WithError(err).Warn("failed to load scenario")
-				return "", fmt.Errorf("load scenario %s: %w", scPath, err)
+				return "", nil, fmt.Errorf("load scenario %s: %w", scPath, err)
 			}
 			if cfg.ScenarioResolver == nil {
-				return "", fmt.Errorf("scenario enabled but engine is nil")
+				return "", nil, fmt.Errorf("scenario enabled but engine is nil")
 			}
 
-			file, _, err := cfg.ScenarioResolver.ResolveScenarioFile(sc, method, swaggerTpl, actualPath)
+			var file string
+			var state ScenarioState
+			switch {
+			case opts.ForceState != "":
+				file, state, err = ScenarioEntryByState(sc, opts.ForceState)
+			case opts.ReadOnly:
+				state, err = cfg.ScenarioResolver.PeekScenarioState(sc, method, swaggerTpl, actualPath, opts.Path, opts.Client)
+				if err == nil {
+					file = scenarioEntryFile(sc, state)
+				}
+			default:
+				file, state, err = cfg.ScenarioResolver.ResolveScenarioFile(sc, method, swaggerTpl, actualPath, opts.Path, opts.Client)
+			}
 			if err != nil {
 				p.log.WithError(err).Warn("failed to resolve scenario")
-				return "", fmt.Errorf("scenario resolve: %w", err)
+				return "", nil, fmt.Errorf("scenario resolve: %w", err)
 			}
 
 			full := filepath.Join(filepath.Dir(scPath), file)
 			if utils.FileExists(full) {
-				return full, nil
+				return full, &state, nil
+			}
+			if cfg.ScenariosDir != "" {
+				sampleDir := filepath.Dir(ScenarioPathForSwagger(cfg.BaseDir, swaggerTpl, cfg.ScenarioFilename))
+				if alt := filepath.Join(sampleDir, file); utils.FileExists(alt) {
+					return alt, &state, nil
+				}
 			}
-			return "", fmt.Errorf("scenario file not found: %s", full)
+			return "", nil, fmt.Errorf("scenario file not found: %s", full)
 		}
-		if cfg.ScenarioEnabled && cfg.ScenarioResolver != nil {
-			_ = cfg.ScenarioResolver.TryResetByRequest(method, actualPath)
+		if cfg.ScenarioResolver != nil && !opts.ReadOnly {
+			_ = cfg.ScenarioResolver.TryResetByRequest(method, actualPath, opts.Client)
 		}
 	}
 
 	// Non-scenario fallback: folder/flat
-	candidates := buildCandidates(cfg.Layout, method, swaggerTpl, legacyFlatFilename)
+	candidates := buildCandidates(cfg.Layout, method, swaggerTpl, legacyFlatFilename, opts.TagFile)
 	if len(candidates) == 0 {
-		return "", fmt.Errorf("no candidates for method=%s path=%s", method, swaggerTpl)
+		return "", nil, fmt.Errorf("no candidates for method=%s path=%s", method, swaggerTpl)
 	}
 
 	for _, rel := range candidates {
 		full := filepath.Join(cfg.BaseDir, rel)
+		variant, err := p.selectVariant(full, opts)
+		if err != nil {
+			return "", nil, err
+		}
+		if variant != "" {
+			return variant, nil, nil
+		}
+		if reps := representations(full); len(reps) > 0 {
+			accept, _ := requestHeader(opts.Header, "Accept")
+			return negotiate(reps, accept), nil, nil
+		}
+	}
+
+	p.log.WithField("path", actualPath).Info("no sample found; caller may fallback to spec example")
+	return "", nil, fmt.Errorf("no sample file found (tried: %v)", candidates)
+}
+
+// resolveStatus returns the status sample for opts.Status, e.g. GET.404.json
+// next to GET.json. Scenarios are neither consulted nor advanced.
+func (p *SampleProvider) resolveStatus(method, swaggerTpl, legacyFlatFilename string, opts LoadOptions) (string, error) {
+	var tried []string
+	for _, rel := range buildCandidates(p.cfg.Layout, method, swaggerTpl, legacyFlatFilename, opts.TagFile) {
+		full := statusSamplePath(filepath.Join(p.cfg.BaseDir, rel), opts.Status)
+		variant, err := p.selectVariant(full, opts)
+		if err != nil {
+			return "", err
+		}
+		if variant != "" {
+			return variant, nil
+		}
 		if utils.FileExists(full) {
 			return full, nil
 		}
+		tried = append(tried, statusSamplePath(rel, opts.Status))
 	}
+	return "", fmt.Errorf("no sample file for status %d (tried: %v)", opts.Status, tried)
+}
 
-	p.log.WithField("path", actualPath).Info("no sample found; caller may fallback to spec example")
-	return "", fmt.Errorf("no sample file found (tried: %v)", candidates)
+// scenarioPath returns where the route's scenario file is expected.
+func (p *SampleProvider) scenarioPath(swaggerTpl string) string {
+	dir := p.cfg.BaseDir
+	if p.cfg.ScenariosDir != "" {
+		dir = p.cfg.ScenariosDir
+	}
+	return ScenarioPathForSwagger(dir, swaggerTpl, p.cfg.ScenarioFilename)
 }
 
-func buildCandidates(layout config.LayoutMode, method, swaggerPath, legacyFlatFilename string) []string {
+func buildCandidates(layout config.LayoutMode, method, swaggerPath, legacyFlatFilename, tagFile string) []string {
 	if layout == "" {
 		layout = config.LayoutAuto
 	}
@@ -96,69 +264,214 @@ func buildCandidates(layout config.LayoutMode, method, swaggerPath, legacyFlatFi
 		pathDir := strings.TrimPrefix(swaggerPath, "/")
 		pathDir = filepath.FromSlash(pathDir)
 		out = append(out, filepath.Join(pathDir, fmt.Sprintf("%s.json", method)))
+		if encoded := EncodeParamSegments(pathDir); encoded != pathDir {
+			out = append(out, filepath.Join(encoded, fmt.Sprintf("%s.json", method)))
+		}
 	}
 	if layout == config.LayoutAuto || layout == config.LayoutFlat {
 		out = append(out, legacyFlatFilename)
 	}
+	if layout == config.LayoutTags && tagFile != "" {
+		out = append(out, filepath.FromSlash(tagFile))
+	}
 	return out
 }
 
-func loadFile(path string) (*Response, error) {
+// load is loadFile through the provider's sample cache, if it has one.
+func (p *SampleProvider) load(path string, data *TemplateData) (*Response, error) {
+	if p.cache == nil || isRawSample(path) {
+		return loadFile(path, data)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return loadFile(path, data)
+	}
+	if resp, hit := p.cache.get(path, info); hit {
+		if resp != nil {
+			return resp, nil
+		}
+		return loadFile(path, data)
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read sample %s: %w", path, err)
+	}
+	resp, err := parseSample(path, b, data)
+	if err != nil {
+		return nil, err
+	}
+	if perRequest(b) {
+		p.cache.put(path, info, nil)
+	} else {
+		p.cache.put(path, info, resp.clone())
+	}
+	return resp, nil
+}
+
+func loadFile(path string, data *TemplateData) (*Response, error) {
+	if isRawSample(path) {
+		return loadRawFile(path)
+	}
 	b, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("read sample %s: %w", path, err)
 	}
+	return parseSample(path, b, data)
+}
+
+// parseSample turns the content b of the sample at path into a response.
+func parseSample(path string, b []byte, data *TemplateData) (*Response, error) {
+	var err error
+	if data != nil {
+		if b, err = renderTemplate(path, b, data); err != nil {
+			return nil, err
+		}
+	}
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		b = stripJSONC(b)
+	}
 	raw := strings.TrimSpace(string(b))
 	if raw == "" {
 		return &Response{
-			Status:  200,
-			Headers: map[string]string{"content-type": "application/json"},
+			Status:  fileStatus(path),
+			Headers: http.Header{"Content-Type": {"application/json"}},
 			Body:    []byte("{}"),
 		}, nil
 	}
 
-	if isJSONObject(raw) && looksLikeEnvelope([]byte(raw)) {
-		var env Envelope
-		if err := json.Unmarshal([]byte(raw), &env); err == nil {
-			status := env.Status
-			if status == 0 {
-				status = 200
-			}
+	if strings.EqualFold(filepath.Ext(path), ".json") && !json.Valid([]byte(raw)) {
+		// Decode the untrimmed text so the position counts leading lines.
+		var probe any
+		err := json.Unmarshal(b, &probe)
+		return nil, newSampleSyntaxError(path, b, err)
+	}
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		resolved, err := resolveIncludes(path, []byte(raw), data)
+		if err != nil {
+			return nil, err
+		}
+		raw = string(resolved)
+	}
 
-			headers := env.Headers
-			if headers == nil {
-				headers = map[string]string{}
-			}
+	var env Envelope
+	if isJSONObject(raw) && isVersionedEnvelope([]byte(raw)) {
+		if err := decodeVersionedEnvelope([]byte(raw), &env); err != nil {
+			return nil, &SampleEnvelopeError{Path: path, Err: err}
+		}
+		if env.Status == 0 {
+			env.Status = fileStatus(path)
+		}
+		return envelopeResponse(path, env)
+	}
+	if isJSONObject(raw) && looksLikeEnvelope([]byte(raw)) && json.Unmarshal([]byte(raw), &env) == nil {
+		if env.Status == 0 {
+			env.Status = fileStatus(path)
+		}
+		return envelopeResponse(path, env)
+	}
 
-			if _, ok := headerGet(headers, "content-type"); !ok {
-				headers["content-type"] = "application/json"
-			}
+	contentType := "application/json"
+	if t := mime.TypeByExtension(filepath.Ext(path)); strings.HasPrefix(t, "text/") {
+		contentType = t
+	}
+	return &Response{
+		Status:  fileStatus(path),
+		Headers: http.Header{"Content-Type": {contentType}},
+		Body:    []byte(raw),
+	}, nil
+}
 
-			var bodyBytes []byte
-			if env.Body == nil {
-				bodyBytes = []byte("{}")
-			} else {
-				bodyBytes, err = json.Marshal(env.Body)
-				if err != nil {
-					return nil, fmt.Errorf("marshal envelope body: %w", err)
-				}
-			}
+func envelopeResponse(path string, env Envelope) (*Response, error) {
+	status := env.Status
+	if status == 0 {
+		status = 200
+	}
+
+	headers := sampleHeaders(env.Headers)
 
-			return &Response{
-				Status:  status,
-				Headers: headers,
-				Body:    bodyBytes,
-			}, nil
+	contentType := "application/json"
+	bodyBytes := []byte("{}")
+	if env.Body == nil && isRedirect(status) {
+		// A redirect without a body is sent empty, not as {}.
+		contentType, bodyBytes = "", []byte{}
+	}
+	switch env.BodyEncoding {
+	case "":
+		if env.Body != nil {
+			b, err := json.Marshal(env.Body)
+			if err != nil {
+				return nil, &SampleEnvelopeError{Path: path, Err: fmt.Errorf("marshal envelope body: %w", err)}
+			}
+			bodyBytes = b
+		}
+	case "base64":
+		b, err := decodeBase64Body(env.Body)
+		if err != nil {
+			return nil, &SampleEnvelopeError{Path: path, Err: err}
 		}
+		contentType, bodyBytes = "application/octet-stream", b
+	default:
+		return nil, &SampleEnvelopeError{Path: path, Err: fmt.Errorf("unsupported bodyEncoding %q", env.BodyEncoding)}
+	}
+
+	cookies, err := httpCookies(env.Cookies)
+	if err != nil {
+		return nil, &SampleEnvelopeError{Path: path, Err: err}
+	}
+
+	if _, ok := HeaderGet(headers, "content-type"); !ok && contentType != "" {
+		headers["Content-Type"] = []string{contentType}
 	}
 
 	return &Response{
-		Status:  200,
-		Headers: map[string]string{"content-type": "application/json"},
-		Body:    []byte(raw),
+		Status:     status,
+		Headers:    headers,
+		Body:       bodyBytes,
+		Cookies:    cookies,
+		EchoFields: env.EchoFields,
+		Match:      env.Match,
+		Expect:     env.Expect,
+		Weight:     env.Weight,
+		Delay:      env.DelayMs,
+		Stream:     env.Stream,
 	}, nil
 }
 
+func isRedirect(status int) bool {
+	return status >= 300 && status <= 399
+}
+
+// isVersionedEnvelope reports whether raw is an envelope that opts into
+// schema validation: an object with a numeric "version" and at least one of
+// status/headers/body. A raw body with a string "version" is not one.
+func isVersionedEnvelope(raw []byte) bool {
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return false
+	}
+	v, ok := m["version"]
+	if !ok {
+		return false
+	}
+	var n float64
+	if err := json.Unmarshal(v, &n); err != nil {
+		return false
+	}
+	return looksLikeEnvelope(raw)
+}
+
+func decodeVersionedEnvelope(raw []byte, env *Envelope) error {
+	var doc any
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return err
+	}
+	if err := validateEnvelope(doc); err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, env)
+}
+
 func isJSONObject(s string) bool {
 	s = strings.TrimSpace(s)
 	return strings.HasPrefix(s, "{") && strings.HasSuffix(s, "}")
@@ -175,12 +488,21 @@ func looksLikeEnvelope(raw []byte) bool {
 	return hasStatus || hasHeaders || hasBody
 }
 
-func headerGet(h map[string]string, key string) (string, bool) {
-	lk := strings.ToLower(key)
-	for k, v := range h {
-		if strings.ToLower(k) == lk {
-			return v, true
-		}
+// decodeBase64Body decodes the body of a "bodyEncoding": "base64" envelope.
+// Line breaks are allowed, so long payloads can be wrapped; an absent body
+// is empty.
+func decodeBase64Body(body any) ([]byte, error) {
+	if body == nil {
+		return []byte{}, nil
+	}
+	s, ok := body.(string)
+	if !ok {
+		return nil, fmt.Errorf("bodyEncoding base64 requires a string body, got %T", body)
+	}
+	s = strings.Join(strings.Fields(s), "")
+	b, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("decode base64 body: %w", err)
 	}
-	return "", false
+	return b, nil
 }
@@ -5,9 +5,12 @@
 package samples
 
 import (
+	"encoding/base64"
+	"net/http"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/ozgen/openapi-emulator/logger"
 
@@ -25,20 +28,38 @@ func (m *MockScenarioResolver) ResolveScenarioFile(
 	method string,
 	swaggerTpl string,
 	actualPath string,
-) (file string, state string, err error) {
-	args := m.Called(sc, method, swaggerTpl, actualPath)
+	params map[string]string,
+	client string,
+) (file string, state ScenarioState, err error) {
+	args := m.Called(sc, method, swaggerTpl, actualPath, client)
 
 	file, _ = args.Get(0).(string)
-	state, _ = args.Get(1).(string)
+	state, _ = args.Get(1).(ScenarioState)
 	err = args.Error(2)
 	return
 }
 
-func (m *MockScenarioResolver) TryResetByRequest(method, actualPath string) bool {
-	args := m.Called(method, actualPath)
+func (m *MockScenarioResolver) PeekScenarioState(sc *Scenario, method, swaggerTpl, actualPath string, params map[string]string, client string) (ScenarioState, error) {
+	args := m.Called(sc, method, swaggerTpl, actualPath, client)
+	state, _ := args.Get(0).(ScenarioState)
+	return state, args.Error(1)
+}
+
+func (m *MockScenarioResolver) TryResetByRequest(method, actualPath, client string) bool {
+	args := m.Called(method, actualPath, client)
 	return args.Bool(0)
 }
 
+func (m *MockScenarioResolver) SetScenarioState(sc *Scenario, swaggerTpl, key, state string) error {
+	args := m.Called(sc, swaggerTpl, key, state)
+	return args.Error(0)
+}
+
+func (m *MockScenarioResolver) ResetScenarios() int {
+	args := m.Called()
+	return args.Int(0)
+}
+
 func writeFile(t *testing.T, dir, name, content string) string {
 	t.Helper()
 	p := filepath.Join(dir, name)
@@ -49,8 +70,13 @@ func writeFile(t *testing.T, dir, name, content string) string {
 	return p
 }
 
+// bodyOf returns a LoadOptions.Body for a request whose decoded body is v.
+func bodyOf(v any) func() any {
+	return func() any { return v }
+}
+
 func TestLoadFile_ReadError(t *testing.T) {
-	_, err := loadFile("/no/such/dir/missing.json")
+	_, err := loadFile("/no/such/dir/missing.json", nil)
 	require.Error(t, err)
 }
 
@@ -58,11 +84,11 @@ func TestLoadFile_EmptyFile_ReturnsDefault(t *testing.T) {
 	dir := t.TempDir()
 	p := writeFile(t, dir, "empty.json", "   \n\t  ")
 
-	resp, err := loadFile(p)
+	resp, err := loadFile(p, nil)
 	require.NoError(t, err)
 
 	require.Equal(t, 200, resp.Status)
-	require.Equal(t, "application/json", resp.Headers["content-type"])
+	require.Equal(t, []string{"application/json"}, resp.Headers["Content-Type"])
 	require.Equal(t, "{}", string(resp.Body))
 }
 
@@ -70,11 +96,11 @@ func TestLoadFile_Envelope_DefaultsApplied(t *testing.T) {
 	dir := t.TempDir()
 	p := writeFile(t, dir, "sample.json", `{"body":{"ok":true}}`)
 
-	resp, err := loadFile(p)
+	resp, err := loadFile(p, nil)
 	require.NoError(t, err)
 
 	require.Equal(t, 200, resp.Status)
-	require.Equal(t, "application/json", resp.Headers["content-type"])
+	require.Equal(t, []string{"application/json"}, resp.Headers["Content-Type"])
 	require.Equal(t, `{"ok":true}`, string(resp.Body))
 }
 
@@ -86,12 +112,12 @@ func TestLoadFile_Envelope_ExplicitValues(t *testing.T) {
 	  "body": {"id": 123}
 	}`)
 
-	resp, err := loadFile(p)
+	resp, err := loadFile(p, nil)
 	require.NoError(t, err)
 
 	require.Equal(t, 201, resp.Status)
-	require.Equal(t, "application/problem+json", resp.Headers["content-type"])
-	require.Equal(t, "1", resp.Headers["x-test"])
+	require.Equal(t, []string{"application/problem+json"}, resp.Headers["content-type"])
+	require.Equal(t, []string{"1"}, resp.Headers["x-test"])
 	require.Equal(t, `{"id":123}`, string(resp.Body))
 }
 
@@ -99,23 +125,35 @@ func TestLoadFile_Envelope_StatusOnly_DefaultsHeaderAndBody(t *testing.T) {
 	dir := t.TempDir()
 	p := writeFile(t, dir, "sample.json", `{"status":204}`)
 
-	resp, err := loadFile(p)
+	resp, err := loadFile(p, nil)
 	require.NoError(t, err)
 
 	require.Equal(t, 204, resp.Status)
-	require.Equal(t, "application/json", resp.Headers["content-type"])
+	require.Equal(t, []string{"application/json"}, resp.Headers["Content-Type"])
 	require.Equal(t, `{}`, string(resp.Body))
 }
 
+func TestLoadFile_Envelope_RedirectWithoutBody_IsEmpty(t *testing.T) {
+	dir := t.TempDir()
+	p := writeFile(t, dir, "sample.json", `{"status":302,"headers":{"Location":"/items/1"}}`)
+
+	resp, err := loadFile(p, nil)
+	require.NoError(t, err)
+
+	require.Equal(t, 302, resp.Status)
+	require.Equal(t, http.Header{"Location": {"/items/1"}}, resp.Headers)
+	require.Empty(t, resp.Body)
+}
+
 func TestLoadFile_Envelope_HeadersPresentButBodyMissing(t *testing.T) {
 	dir := t.TempDir()
 	p := writeFile(t, dir, "hdrs.json", `{"headers":{"content-type":"text/plain"}}`)
 
-	resp, err := loadFile(p)
+	resp, err := loadFile(p, nil)
 	require.NoError(t, err)
 
 	require.Equal(t, 200, resp.Status)
-	require.Equal(t, "text/plain", resp.Headers["content-type"])
+	require.Equal(t, []string{"text/plain"}, resp.Headers["content-type"])
 	require.Equal(t, `{}`, string(resp.Body))
 }
 
@@ -126,59 +164,301 @@ func TestLoadFile_Envelope_ContentTypeNotInjectedIfAlreadyPresentCaseInsensitive
 	  "body": {"ok": true}
 	}`)
 
-	resp, err := loadFile(p)
+	resp, err := loadFile(p, nil)
 	require.NoError(t, err)
 
-	require.Equal(t, "text/plain", resp.Headers["Content-Type"])
+	require.Equal(t, []string{"text/plain"}, resp.Headers["Content-Type"])
 	_, injected := resp.Headers["content-type"]
 	require.False(t, injected, "did not expect injected lowercase content-type when Content-Type already exists")
 	require.Equal(t, `{"ok":true}`, string(resp.Body))
 }
 
+func TestLoadFile_Envelope_Base64Body(t *testing.T) {
+	dir := t.TempDir()
+	pdf := []byte("%PDF-1.7\n\xe2\xe3\xcf\xd3\n\x00\xff")
+	enc := base64.StdEncoding.EncodeToString(pdf)
+	p := writeFile(t, dir, "doc.json", `{
+	  "version": 1,
+	  "headers": {"Content-Type": "application/pdf"},
+	  "bodyEncoding": "base64",
+	  "body": "`+enc[:8]+`\n  `+enc[8:]+`"
+	}`)
+
+	resp, err := loadFile(p, nil)
+	require.NoError(t, err)
+	require.Equal(t, pdf, resp.Body)
+	require.Equal(t, []string{"application/pdf"}, resp.Headers["Content-Type"])
+
+	p = writeFile(t, dir, "blob.json", `{"bodyEncoding":"base64","body":"AAEC"}`)
+	resp, err = loadFile(p, nil)
+	require.NoError(t, err)
+	require.Equal(t, []byte{0, 1, 2}, resp.Body)
+	require.Equal(t, []string{"application/octet-stream"}, resp.Headers["Content-Type"])
+}
+
+func TestLoadFile_Envelope_Base64BodyInvalid(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"not-base64.json": `{"bodyEncoding":"base64","body":"not base64!"}`,
+		"object.json":     `{"bodyEncoding":"base64","body":{"a":1}}`,
+		"unknown.json":    `{"bodyEncoding":"gzip","body":"AAEC"}`,
+		"versioned.json":  `{"version":1,"bodyEncoding":"hex","body":"00"}`,
+	} {
+		_, err := loadFile(writeFile(t, dir, name, content), nil)
+		var envErr *SampleEnvelopeError
+		require.ErrorAs(t, err, &envErr, name)
+	}
+}
+
+func TestLoadFile_Envelope_RepeatedHeaders(t *testing.T) {
+	dir := t.TempDir()
+	p := writeFile(t, dir, "list.json", `{
+	  "version": 1,
+	  "headers": {
+	    "Link": ["</items?page=2>; rel=\"next\"", "</items?page=9>; rel=\"last\""],
+	    "X-Single": ["one"],
+	    "X-Plain": "plain"
+	  },
+	  "body": []
+	}`)
+
+	resp, err := loadFile(p, nil)
+	require.NoError(t, err)
+
+	require.Equal(t, []string{`</items?page=2>; rel="next"`, `</items?page=9>; rel="last"`}, resp.Headers["Link"])
+	require.Equal(t, []string{"one"}, resp.Headers["X-Single"])
+	require.Equal(t, []string{"plain"}, resp.Headers["X-Plain"])
+}
+
+func TestLoadFile_Envelope_RepeatedHeadersInvalid(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"empty.json":  `{"version":1,"headers":{"Warning":[]}}`,
+		"number.json": `{"version":1,"headers":{"Warning":[199]}}`,
+	} {
+		_, err := loadFile(writeFile(t, dir, name, content), nil)
+		var envErr *SampleEnvelopeError
+		require.ErrorAs(t, err, &envErr, name)
+	}
+}
+
+func TestLoadFile_Envelope_Cookies(t *testing.T) {
+	dir := t.TempDir()
+	p := writeFile(t, dir, "login.json", `{
+	  "version": 1,
+	  "status": 204,
+	  "cookies": [
+	    {"name": "session", "value": "abc", "path": "/", "httpOnly": true, "secure": true, "sameSite": "Lax"},
+	    {"name": "theme", "value": "dark", "maxAge": 3600, "expires": "2030-01-02T03:04:05Z"},
+	    {"name": "legacy", "value": "", "maxAge": -1}
+	  ]
+	}`)
+
+	resp, err := loadFile(p, nil)
+	require.NoError(t, err)
+
+	require.Len(t, resp.Cookies, 3)
+	require.Equal(t, "session=abc; Path=/; HttpOnly; Secure; SameSite=Lax", resp.Cookies[0].String())
+	require.Equal(t, "theme=dark; Expires=Wed, 02 Jan 2030 03:04:05 GMT; Max-Age=3600", resp.Cookies[1].String())
+	require.Equal(t, "legacy=; Max-Age=0", resp.Cookies[2].String())
+}
+
+func TestLoadFile_Envelope_CookiesInvalid(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"bad-name.json":      `{"status":200,"cookies":[{"name":"a b","value":"x"}]}`,
+		"bad-samesite.json":  `{"status":200,"cookies":[{"name":"a","value":"x","sameSite":"sometimes"}]}`,
+		"bad-expires.json":   `{"status":200,"cookies":[{"name":"a","value":"x","expires":"tomorrow"}]}`,
+		"missing-value.json": `{"version":1,"cookies":[{"name":"a"}],"body":{}}`,
+	} {
+		_, err := loadFile(writeFile(t, dir, name, content), nil)
+		var envErr *SampleEnvelopeError
+		require.ErrorAs(t, err, &envErr, name)
+	}
+}
+
 func TestLoadFile_Fallback_RawJSONOrText(t *testing.T) {
 	dir := t.TempDir()
 
 	t.Run("raw json without envelope", func(t *testing.T) {
 		p := writeFile(t, dir, "raw.json", `{}`)
 
-		resp, err := loadFile(p)
+		resp, err := loadFile(p, nil)
 		require.NoError(t, err)
 
 		require.Equal(t, 200, resp.Status)
-		require.Equal(t, "application/json", resp.Headers["content-type"])
+		require.Equal(t, []string{"application/json"}, resp.Headers["Content-Type"])
 		require.Equal(t, `{}`, string(resp.Body))
 	})
 
 	t.Run("plain text", func(t *testing.T) {
 		p := writeFile(t, dir, "raw.txt", `  hello world  `)
 
-		resp, err := loadFile(p)
+		resp, err := loadFile(p, nil)
 		require.NoError(t, err)
 
 		require.Equal(t, 200, resp.Status)
-		require.Equal(t, "application/json", resp.Headers["content-type"])
+		require.Equal(t, []string{"text/plain; charset=utf-8"}, resp.Headers["Content-Type"])
 		require.Equal(t, `hello world`, string(resp.Body))
 	})
 }
 
+func TestLoadFile_MalformedJSON_ReturnsSyntaxErrorWithPosition(t *testing.T) {
+	dir := t.TempDir()
+	p := writeFile(t, dir, "GET.json", "{\n  \"status\": 200,\n  \"body\": {\"ok\": true \"done\": false}\n}")
+
+	_, err := loadFile(p, nil)
+	require.Error(t, err)
+
+	var syntaxErr *SampleSyntaxError
+	require.ErrorAs(t, err, &syntaxErr)
+	require.Equal(t, p, syntaxErr.Path)
+	require.Equal(t, 3, syntaxErr.Line)
+	require.Contains(t, err.Error(), "line 3")
+}
+
+func TestLoadFile_MalformedJSON_PositionCountsLeadingBlankLines(t *testing.T) {
+	dir := t.TempDir()
+	p := writeFile(t, dir, "GET.json", "\n\n{\n  \"id\": 1,\n  x\n}")
+
+	_, err := loadFile(p, nil)
+	var syntaxErr *SampleSyntaxError
+	require.ErrorAs(t, err, &syntaxErr)
+	require.Equal(t, 5, syntaxErr.Line)
+	require.Equal(t, 3, syntaxErr.Column)
+}
+
+func TestLoadFile_JSONC_CommentsAndTrailingCommas(t *testing.T) {
+	dir := t.TempDir()
+	p := writeFile(t, dir, "GET.json", `{
+  // served while the scan is queued
+  "status": 202, /* Accepted */
+  "body": {"url": "http://example.com/a//b", "note": "/* kept */", "tags": ["a", "b",],},
+}`)
+
+	resp, err := loadFile(p, nil)
+	require.NoError(t, err)
+	require.Equal(t, 202, resp.Status)
+	require.JSONEq(t, `{"url":"http://example.com/a//b","note":"/* kept */","tags":["a","b"]}`, string(resp.Body))
+
+	p = writeFile(t, dir, "raw.json", "[1, 2, // two\n 3,]")
+	resp, err = loadFile(p, nil)
+	require.NoError(t, err)
+	require.JSONEq(t, `[1,2,3]`, string(resp.Body))
+
+	p = writeFile(t, dir, "GET.txt", "// not a comment")
+	resp, err = loadFile(p, nil)
+	require.NoError(t, err)
+	require.Equal(t, "// not a comment", string(resp.Body))
+}
+
+func TestLoadFile_JSONC_LeadingCommentKeepsErrorLine(t *testing.T) {
+	dir := t.TempDir()
+	p := writeFile(t, dir, "GET.json", "// queued scan\n/* second\n   comment */\n{\n  \"status\": 202\n  \"body\": {}\n}")
+
+	_, err := loadFile(p, nil)
+	var syntaxErr *SampleSyntaxError
+	require.ErrorAs(t, err, &syntaxErr)
+	require.Equal(t, 6, syntaxErr.Line)
+	require.Equal(t, 3, syntaxErr.Column)
+}
+
+func TestLoadFile_RawBodyWithStatusField_ServedVerbatim(t *testing.T) {
+	dir := t.TempDir()
+	p := writeFile(t, dir, "GET.json", `{"status":"running","progress":10}`)
+
+	resp, err := loadFile(p, nil)
+	require.NoError(t, err)
+	require.Equal(t, 200, resp.Status)
+	require.Equal(t, `{"status":"running","progress":10}`, string(resp.Body))
+}
+
+func TestLoadFile_NonJSONExtension_ServedVerbatim(t *testing.T) {
+	dir := t.TempDir()
+	p := writeFile(t, dir, "GET.txt", `{not json`)
+
+	resp, err := loadFile(p, nil)
+	require.NoError(t, err)
+	require.Equal(t, `{not json`, string(resp.Body))
+}
+
+func TestLoadFile_VersionedEnvelope_Valid(t *testing.T) {
+	dir := t.TempDir()
+	p := writeFile(t, dir, "GET.json", `{"version":1,"status":202,"headers":{"X-A":"b"},"body":{"ok":true}}`)
+
+	resp, err := loadFile(p, nil)
+	require.NoError(t, err)
+	require.Equal(t, 202, resp.Status)
+	require.Equal(t, []string{"b"}, resp.Headers["X-A"])
+	require.JSONEq(t, `{"ok":true}`, string(resp.Body))
+}
+
+func TestLoadFile_VersionedEnvelope_SchemaViolations(t *testing.T) {
+	cases := map[string]string{
+		"unsupported version": `{"version":2,"status":200}`,
+		"status out of range": `{"version":1,"status":42}`,
+		"non-string header":   `{"version":1,"headers":{"X-A":1}}`,
+		"unknown field":       `{"version":1,"body":{},"delay":5}`,
+	}
+
+	for name, content := range cases {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			p := writeFile(t, dir, "GET.json", content)
+
+			_, err := loadFile(p, nil)
+			var envErr *SampleEnvelopeError
+			require.ErrorAs(t, err, &envErr)
+			require.Equal(t, p, envErr.Path)
+		})
+	}
+}
+
+func TestLoadFile_StringVersion_ServedVerbatim(t *testing.T) {
+	dir := t.TempDir()
+	p := writeFile(t, dir, "GET.json", `{"version":"1.2.5","status":"ok"}`)
+
+	resp, err := loadFile(p, nil)
+	require.NoError(t, err)
+	require.Equal(t, `{"version":"1.2.5","status":"ok"}`, string(resp.Body))
+}
+
+func TestLineColumn(t *testing.T) {
+	// Offset 4 has read "ab\nc"; the offending byte is c.
+	line, col := lineColumn([]byte("ab\ncd"), 4)
+	require.Equal(t, 2, line)
+	require.Equal(t, 1, col)
+
+	line, col = lineColumn([]byte("ab"), 99)
+	require.Equal(t, 1, line)
+	require.Equal(t, 3, col)
+}
+
 func TestBuildCandidates_LayoutFolders(t *testing.T) {
-	got := buildCandidates(config.LayoutFolders, "GET", "/api/v1/items", "GET_api_v1_items.json")
+	got := buildCandidates(config.LayoutFolders, "GET", "/api/v1/items", "GET_api_v1_items.json", "")
 	require.Equal(t, []string{filepath.Join("api", "v1", "items", "GET.json")}, got)
 }
 
 func TestBuildCandidates_LayoutFlat(t *testing.T) {
-	got := buildCandidates(config.LayoutFlat, "GET", "/api/v1/items", "GET_api_v1_items.json")
+	got := buildCandidates(config.LayoutFlat, "GET", "/api/v1/items", "GET_api_v1_items.json", "")
 	require.Equal(t, []string{"GET_api_v1_items.json"}, got)
 }
 
 func TestBuildCandidates_LayoutAuto_PrefersFoldersThenFlat(t *testing.T) {
-	got := buildCandidates(config.LayoutAuto, "GET", "/api/v1/items", "GET_api_v1_items.json")
+	got := buildCandidates(config.LayoutAuto, "GET", "/api/v1/items", "GET_api_v1_items.json", "")
 	require.Equal(t, []string{
 		filepath.Join("api", "v1", "items", "GET.json"),
 		"GET_api_v1_items.json",
 	}, got)
 }
 
+func TestBuildCandidates_LayoutTags(t *testing.T) {
+	got := buildCandidates(config.LayoutTags, "GET", "/api/v1/items", "GET_api_v1_items.json", "items/listItems.json")
+	require.Equal(t, []string{filepath.Join("items", "listItems.json")}, got)
+
+	require.Empty(t, buildCandidates(config.LayoutTags, "GET", "/api/v1/items", "GET_api_v1_items.json", ""))
+}
+
 func TestSampleProvider_ResolveAndLoad_FoldersMode_LoadsFolderSample(t *testing.T) {
 	baseDir := t.TempDir()
 
@@ -194,11 +474,11 @@ func TestSampleProvider_ResolveAndLoad_FoldersMode_LoadsFolderSample(t *testing.
 		Layout:  config.LayoutFolders,
 	}, logger.GetLogger())
 
-	resp, err := p.ResolveAndLoad(method, swaggerTpl, actualPath, legacyFlat)
+	resp, err := p.ResolveAndLoad(method, swaggerTpl, actualPath, legacyFlat, LoadOptions{})
 	require.NoError(t, err)
 
 	require.Equal(t, 200, resp.Status)
-	require.Equal(t, "application/json", resp.Headers["content-type"])
+	require.Equal(t, []string{"application/json"}, resp.Headers["Content-Type"])
 	require.Equal(t, `{"ok":true}`, string(resp.Body))
 }
 
@@ -217,7 +497,7 @@ func TestSampleProvider_ResolveAndLoad_FlatMode_LoadsLegacyFlatSample(t *testing
 		Layout:  config.LayoutFlat,
 	}, logger.GetLogger())
 
-	resp, err := p.ResolveAndLoad(method, swaggerTpl, actualPath, legacyFlat)
+	resp, err := p.ResolveAndLoad(method, swaggerTpl, actualPath, legacyFlat, LoadOptions{})
 	require.NoError(t, err)
 
 	require.Equal(t, `{"from":"flat"}`, string(resp.Body))
@@ -239,7 +519,7 @@ func TestSampleProvider_ResolveAndLoad_AutoMode_PrefersFoldersOverFlat(t *testin
 		Layout:  config.LayoutAuto,
 	}, logger.GetLogger())
 
-	resp, err := p.ResolveAndLoad(method, swaggerTpl, actualPath, legacyFlat)
+	resp, err := p.ResolveAndLoad(method, swaggerTpl, actualPath, legacyFlat, LoadOptions{})
 	require.NoError(t, err)
 
 	require.Equal(t, `{"from":"folders"}`, string(resp.Body))
@@ -280,11 +560,11 @@ func TestSampleProvider_ScenarioEnabled_UsesScenarioEngine(t *testing.T) {
 
 	m := new(MockScenarioResolver)
 
-	m.On("ResolveScenarioFile", mock.Anything, "GET", swaggerTpl, actualPath).
-		Return("GET.requested.json", "requested", nil).
+	m.On("ResolveScenarioFile", mock.Anything, "GET", swaggerTpl, actualPath, "").
+		Return("GET.requested.json", ScenarioState{State: "requested"}, nil).
 		Once()
 
-	m.AssertNotCalled(t, "TryResetByRequest", mock.Anything, mock.Anything)
+	m.AssertNotCalled(t, "TryResetByRequest", mock.Anything, mock.Anything, mock.Anything)
 
 	p := NewSampleProvider(ProviderConfig{
 		BaseDir:          baseDir,
@@ -294,13 +574,328 @@ func TestSampleProvider_ScenarioEnabled_UsesScenarioEngine(t *testing.T) {
 		ScenarioResolver: m,
 	}, logger.GetLogger())
 
-	resp, err := p.ResolveAndLoad(method, swaggerTpl, actualPath, legacyFlat)
+	resp, err := p.ResolveAndLoad(method, swaggerTpl, actualPath, legacyFlat, LoadOptions{})
 	require.NoError(t, err)
 	require.Equal(t, `{"from":"scenario"}`, string(resp.Body))
 
 	m.AssertExpectations(t)
 }
 
+func TestSampleProvider_ScenariosDir_SeparateFromSamples(t *testing.T) {
+	samplesDir := t.TempDir()
+	scenariosDir := t.TempDir()
+
+	writeFile(t, filepath.Join(scenariosDir, "scans", "{id}"), "scenario.json", `{
+	  "version": 1,
+	  "mode": "step",
+	  "key": { "pathParam": "id" },
+	  "sequence": [
+	    {"state":"queued","file":"GET.queued.json"},
+	    {"state":"done","file":"GET.done.json"}
+	  ],
+	  "behavior": {"repeatLast": true, "advanceOn": [{"method": "GET"}]}
+	}`)
+	// one response lives next to the scenario, the other with the samples
+	writeFile(t, filepath.Join(scenariosDir, "scans", "{id}"), "GET.queued.json", `{"state":"queued"}`)
+	writeFile(t, filepath.Join(samplesDir, "scans", "{id}"), "GET.done.json", `{"state":"done"}`)
+	writeFile(t, filepath.Join(samplesDir, "scans", "{id}"), "GET.json", `{"state":"plain"}`)
+
+	p := NewSampleProvider(ProviderConfig{
+		BaseDir:          samplesDir,
+		Layout:           config.LayoutFolders,
+		ScenarioEnabled:  true,
+		ScenarioFilename: "scenario.json",
+		ScenariosDir:     scenariosDir,
+		ScenarioResolver: NewScenarioResolver(),
+	}, logger.GetLogger())
+
+	resp, err := p.ResolveAndLoad("GET", "/scans/{id}", "/scans/1", "", LoadOptions{})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"state":"queued"}`, string(resp.Body))
+
+	resp, err = p.ResolveAndLoad("GET", "/scans/{id}", "/scans/1", "", LoadOptions{})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"state":"done"}`, string(resp.Body))
+
+	// a scenario.json in SAMPLES_DIR is ignored once SCENARIOS_DIR is set
+	writeFile(t, filepath.Join(samplesDir, "items"), "scenario.json", `{"version":1,"mode":"step","key":{"pathParam":"id"},"sequence":[{"state":"x","file":"nope.json"}]}`)
+	writeFile(t, filepath.Join(samplesDir, "items"), "GET.json", `{"items":[]}`)
+	resp, err = p.ResolveAndLoad("GET", "/items", "/items", "", LoadOptions{})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"items":[]}`, string(resp.Body))
+}
+
+func TestSampleProvider_ScenarioTemplate_RendersStateAndStep(t *testing.T) {
+	baseDir := t.TempDir()
+	swaggerTpl := "/scans/{id}/status"
+	scPath := ScenarioPathForSwagger(baseDir, swaggerTpl, "scenario.json")
+
+	writeFile(t, filepath.Dir(scPath), filepath.Base(scPath), `{
+	  "version": 1,
+	  "mode": "step",
+	  "key": { "pathParam": "id" },
+	  "sequence": [
+	    {"state":"requested","file":"progress.json"},
+	    {"state":"running","file":"progress.json"},
+	    {"state":"succeeded","file":"progress.json"}
+	  ],
+	  "behavior": {"advanceOn": [{"method":"GET"}]}
+	}`)
+	writeFile(t, filepath.Dir(scPath), "progress.json", `{
+	  "headers": {"X-State": "{{ .Scenario.State }}"},
+	  "body": {
+	    "step": {{ .Scenario.Step }},
+	    "progress": {{ if eq .Scenario.State "requested" }}10{{ else if eq .Scenario.State "running" }}50{{ else }}100{{ end }}
+	  }
+	}`)
+
+	p := NewSampleProvider(ProviderConfig{
+		BaseDir:          baseDir,
+		Layout:           config.LayoutFolders,
+		ScenarioEnabled:  true,
+		ScenarioFilename: "scenario.json",
+		ScenarioResolver: NewScenarioResolver(),
+	}, logger.GetLogger())
+
+	for i, want := range []struct{ state, body string }{
+		{"requested", `{"step":0,"progress":10}`},
+		{"running", `{"step":1,"progress":50}`},
+		{"succeeded", `{"step":2,"progress":100}`},
+	} {
+		resp, err := p.ResolveAndLoad("GET", swaggerTpl, "/scans/1/status", "", LoadOptions{})
+		require.NoError(t, err, "request %d", i)
+		require.Equal(t, []string{want.state}, resp.Headers["X-State"])
+		require.JSONEq(t, want.body, string(resp.Body))
+	}
+}
+
+func TestSampleProvider_ScenarioTemplate_Cookies(t *testing.T) {
+	baseDir := t.TempDir()
+	writeFile(t, filepath.Join(baseDir, "users", "{id}"), "scenario.json", `{
+	  "version": 1,
+	  "mode": "step",
+	  "key": { "pathParam": "id" },
+	  "sequence": [{"state":"active","file":"GET.active.json"}],
+	  "behavior": {"repeatLast": true}
+	}`)
+	writeFile(t, filepath.Join(baseDir, "users", "{id}"), "GET.active.json",
+		`{"session": "{{ .Cookies.session }}", "theme": "{{ or (index .Cookies "theme") "light" }}"}`)
+
+	p := NewSampleProvider(ProviderConfig{
+		BaseDir:          baseDir,
+		Layout:           config.LayoutFolders,
+		ScenarioEnabled:  true,
+		ScenarioFilename: "scenario.json",
+		ScenarioResolver: NewScenarioResolver(),
+	}, logger.GetLogger())
+
+	resp, err := p.ResolveAndLoad("GET", "/users/{id}", "/users/1", "", LoadOptions{Cookies: map[string]string{"session": "s1"}})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"session":"s1","theme":"light"}`, string(resp.Body))
+
+	_, err = p.ResolveAndLoad("GET", "/users/{id}", "/users/1", "", LoadOptions{})
+	var tplErr *SampleTemplateError
+	require.ErrorAs(t, err, &tplErr)
+}
+
+func TestSampleProvider_Template_RequestContextOutsideScenario(t *testing.T) {
+	baseDir := t.TempDir()
+	writeFile(t, filepath.Join(baseDir, "users", "{id}"), "GET.json",
+		`{"headers":{"X-Tenant":"{{ .Header.X_Tenant }}"},"body":{"id":"{{ .Path.id }}","page":"{{ .Query.page }}","name":"{{ .Body.name }}"}}`)
+	writeFile(t, baseDir, "custom.json", `{"id":"{{ .Path.id }}"}`)
+
+	p := NewSampleProvider(ProviderConfig{BaseDir: baseDir, Layout: config.LayoutFolders}, logger.GetLogger())
+	opts := LoadOptions{
+		Path:   map[string]string{"id": "7"},
+		Query:  map[string]string{"page": "2"},
+		Header: map[string]string{"X_Tenant": "acme"},
+		Body:   bodyOf(map[string]any{"name": "bob"}),
+	}
+
+	resp, err := p.ResolveAndLoad("GET", "/users/{id}", "/users/7", "", opts)
+	require.NoError(t, err)
+	require.Nil(t, resp.Scenario)
+	require.Equal(t, []string{"acme"}, resp.Headers["X-Tenant"])
+	require.JSONEq(t, `{"id":"7","page":"2","name":"bob"}`, string(resp.Body))
+
+	resp, err = p.LoadSample("custom.json", opts)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"id":"7"}`, string(resp.Body))
+
+	_, err = p.ResolveAndLoad("GET", "/users/{id}", "/users/7", "", LoadOptions{})
+	var tplErr *SampleTemplateError
+	require.ErrorAs(t, err, &tplErr)
+}
+
+func TestSampleProvider_BodyOnlyReadWhenUsed(t *testing.T) {
+	baseDir := t.TempDir()
+	writeFile(t, filepath.Join(baseDir, "users"), "POST.json", `{"status":201,"body":{"id":"{{ .Query.id }}"}}`)
+	writeFile(t, filepath.Join(baseDir, "users", "{id}"), "GET.json", `{"name":"{{ .Body.name }}"}`)
+
+	p := NewSampleProvider(ProviderConfig{BaseDir: baseDir, Layout: config.LayoutFolders}, logger.GetLogger())
+	reads := 0
+	opts := LoadOptions{Query: map[string]string{"id": "u1"}, Body: func() any {
+		reads++
+		return map[string]any{"name": "bob"}
+	}}
+
+	_, err := p.ResolveAndLoad("POST", "/users", "/users", "", opts)
+	require.NoError(t, err)
+	require.Zero(t, reads, "a sample without .Body does not read the request body")
+
+	resp, err := p.ResolveAndLoad("GET", "/users/{id}", "/users/7", "", opts)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"name":"bob"}`, string(resp.Body))
+	require.Equal(t, 1, reads)
+}
+
+func TestSampleProvider_EchoFields(t *testing.T) {
+	baseDir := t.TempDir()
+	writeFile(t, filepath.Join(baseDir, "users"), "POST.json", `{
+	  "version": 1,
+	  "status": 201,
+	  "echoFields": ["name", "tags", "profile.city", "missing"],
+	  "body": {"id": "u1", "name": "default", "missing": "kept"}
+	}`)
+
+	p := NewSampleProvider(ProviderConfig{BaseDir: baseDir, Layout: config.LayoutFolders}, logger.GetLogger())
+
+	resp, err := p.ResolveAndLoad("POST", "/users", "/users", "", LoadOptions{Body: bodyOf(map[string]any{
+		"name":    "alice",
+		"tags":    []any{"a", "b"},
+		"profile": map[string]any{"city": "Berlin", "zip": "10115"},
+		"ignored": true,
+	})})
+	require.NoError(t, err)
+	require.Equal(t, 201, resp.Status)
+	require.JSONEq(t, `{"id":"u1","name":"alice","tags":["a","b"],"profile":{"city":"Berlin"},"missing":"kept"}`, string(resp.Body))
+
+	resp, err = p.ResolveAndLoad("POST", "/users", "/users", "", LoadOptions{})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"id":"u1","name":"default","missing":"kept"}`, string(resp.Body))
+}
+
+func TestLoadFile_EchoFieldsMustBeStrings(t *testing.T) {
+	dir := t.TempDir()
+	p := writeFile(t, dir, "POST.json", `{"version":1,"echoFields":[1],"body":{}}`)
+
+	_, err := loadFile(p, nil)
+	var envErr *SampleEnvelopeError
+	require.ErrorAs(t, err, &envErr)
+}
+
+func TestSampleProvider_TimeScenario_ProgressTemplateAndHeader(t *testing.T) {
+	baseDir := t.TempDir()
+	swaggerTpl := "/jobs/{id}"
+	scPath := ScenarioPathForSwagger(baseDir, swaggerTpl, "scenario.json")
+
+	writeFile(t, filepath.Dir(scPath), filepath.Base(scPath), `{
+	  "version": 1,
+	  "mode": "time",
+	  "key": { "pathParam": "id" },
+	  "timeline": [
+	    {"afterSec": 0, "state": "running", "file": "progress.json"},
+	    {"afterSec": 100, "state": "done", "file": "progress.json"}
+	  ],
+	  "behavior": {"repeatLast": true}
+	}`)
+	writeFile(t, filepath.Dir(scPath), "progress.json",
+		`{"progress": {{ .Scenario.Percent }}, "elapsed": {{ .Scenario.Elapsed }}, "total": {{ .Scenario.Total }}}`)
+
+	resolver := NewScenarioResolver().(*ScenarioResolver)
+	require.NoError(t, resolver.store.SetStart(scenarioRuntimeKey(swaggerTpl, "7"), time.Now().Add(-40*time.Second)))
+
+	p := NewSampleProvider(ProviderConfig{
+		BaseDir:          baseDir,
+		Layout:           config.LayoutFolders,
+		ScenarioEnabled:  true,
+		ScenarioFilename: "scenario.json",
+		ScenarioResolver: resolver,
+	}, logger.GetLogger())
+
+	resp, err := p.ResolveAndLoad("GET", swaggerTpl, "/jobs/7", "", LoadOptions{})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"progress":40,"elapsed":40,"total":100}`, string(resp.Body))
+	require.Equal(t, []string{"40"}, resp.Headers[ProgressHeader])
+}
+
+func TestSampleProvider_ScenarioTemplate_UnknownFieldIsTemplateError(t *testing.T) {
+	baseDir := t.TempDir()
+	swaggerTpl := "/scans/{id}/status"
+	scPath := ScenarioPathForSwagger(baseDir, swaggerTpl, "scenario.json")
+
+	writeFile(t, filepath.Dir(scPath), filepath.Base(scPath), `{
+	  "version": 1,
+	  "mode": "step",
+	  "key": { "pathParam": "id" },
+	  "sequence": [{"state":"requested","file":"GET.json"}]
+	}`)
+	writeFile(t, filepath.Dir(scPath), "GET.json", `{"state":"{{ .Scenario.Nope }}"}`)
+
+	p := NewSampleProvider(ProviderConfig{
+		BaseDir:          baseDir,
+		Layout:           config.LayoutFolders,
+		ScenarioEnabled:  true,
+		ScenarioFilename: "scenario.json",
+		ScenarioResolver: NewScenarioResolver(),
+	}, logger.GetLogger())
+
+	_, err := p.ResolveAndLoad("GET", swaggerTpl, "/scans/1/status", "", LoadOptions{})
+	var tplErr *SampleTemplateError
+	require.ErrorAs(t, err, &tplErr)
+}
+
+func TestSampleProvider_ForceState_DoesNotAdvance(t *testing.T) {
+	baseDir := t.TempDir()
+	swaggerTpl := "/scans/{id}/status"
+	scPath := ScenarioPathForSwagger(baseDir, swaggerTpl, "scenario.json")
+
+	writeFile(t, filepath.Dir(scPath), filepath.Base(scPath), `{
+	  "version": 1,
+	  "mode": "step",
+	  "key": { "pathParam": "id" },
+	  "sequence": [
+	    {"state":"requested","file":"requested.json"},
+	    {"state":"running","file":"running.json"},
+	    {"state":"done","file":"done.json"}
+	  ],
+	  "behavior": {"advanceOn": [{"method":"GET"}]}
+	}`)
+	writeFile(t, filepath.Dir(scPath), "requested.json", `{"state":"requested"}`)
+	writeFile(t, filepath.Dir(scPath), "running.json", `{"state":"running"}`)
+	writeFile(t, filepath.Dir(scPath), "done.json", `{"state":"done","step":{{ .Scenario.Step }}}`)
+
+	p := NewSampleProvider(ProviderConfig{
+		BaseDir:          baseDir,
+		Layout:           config.LayoutFolders,
+		ScenarioEnabled:  true,
+		ScenarioFilename: "scenario.json",
+		ScenarioResolver: NewScenarioResolver(),
+	}, logger.GetLogger())
+
+	resp, err := p.ResolveAndLoad("GET", swaggerTpl, "/scans/1/status", "", LoadOptions{ForceState: "done"})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"state":"done","step":2}`, string(resp.Body))
+
+	resp, err = p.ResolveAndLoad("GET", swaggerTpl, "/scans/1/status", "", LoadOptions{})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"state":"requested"}`, string(resp.Body))
+
+	_, err = p.ResolveAndLoad("GET", swaggerTpl, "/scans/1/status", "", LoadOptions{ForceState: "nope"})
+	var stateErr *UnknownScenarioStateError
+	require.ErrorAs(t, err, &stateErr)
+	require.Equal(t, []string{"requested", "running", "done"}, stateErr.Known)
+}
+
+func TestLoadFile_WithoutTemplateData_BracesServedVerbatim(t *testing.T) {
+	dir := t.TempDir()
+	p := writeFile(t, dir, "GET.json", `{"pattern":"{{ .Scenario.State }}"}`)
+
+	resp, err := loadFile(p, nil)
+	require.NoError(t, err)
+	require.Equal(t, `{"pattern":"{{ .Scenario.State }}"}`, string(resp.Body))
+}
+
 func TestSampleProvider_ScenarioEnabled_EngineNil_ReturnsError(t *testing.T) {
 	baseDir := t.TempDir()
 
@@ -351,8 +946,8 @@ func TestSampleProvider_ScenarioEnabled_FileReturnedButMissing_ReturnsError(t *t
 	}`)
 
 	m := new(MockScenarioResolver)
-	m.On("ResolveScenarioFile", mock.Anything, "GET", swaggerTpl, actualPath).
-		Return("GET.requested.json", "requested", nil).
+	m.On("ResolveScenarioFile", mock.Anything, "GET", swaggerTpl, actualPath, "").
+		Return("GET.requested.json", ScenarioState{State: "requested"}, nil).
 		Once()
 
 	p := NewSampleProvider(ProviderConfig{
@@ -380,7 +975,7 @@ func TestSampleProvider_ScenarioEnabled_NoScenarioFile_CallsTryResetByRequest(t
 	legacyFlat := "DELETE__scans_{id}.json"
 
 	m := new(MockScenarioResolver)
-	m.On("TryResetByRequest", "DELETE", actualPath).Return(true).Once()
+	m.On("TryResetByRequest", "DELETE", actualPath, "").Return(true).Once()
 
 	p := NewSampleProvider(ProviderConfig{
 		BaseDir:          baseDir,
@@ -404,7 +999,7 @@ func TestSampleProvider_ScenarioEnabled_NoScenarioFile_TryResetFalse_StillCalled
 	legacyFlat := "DELETE__scans_{id}.json"
 
 	m := new(MockScenarioResolver)
-	m.On("TryResetByRequest", "DELETE", actualPath).Return(false).Once()
+	m.On("TryResetByRequest", "DELETE", actualPath, "").Return(false).Once()
 
 	p := NewSampleProvider(ProviderConfig{
 		BaseDir:          baseDir,
@@ -441,8 +1036,8 @@ func TestSampleProvider_ScenarioEnabled_ScenarioFileExists_DoesNotCallTryResetBy
 	writeFile(t, filepath.Dir(scPath), "GET.requested.json", `{"body":{"ok":true}}`)
 
 	m := new(MockScenarioResolver)
-	m.On("ResolveScenarioFile", mock.Anything, "GET", swaggerTpl, actualPath).
-		Return("GET.requested.json", "requested", nil).
+	m.On("ResolveScenarioFile", mock.Anything, "GET", swaggerTpl, actualPath, "").
+		Return("GET.requested.json", ScenarioState{State: "requested"}, nil).
 		Once()
 
 	p := NewSampleProvider(ProviderConfig{
@@ -453,9 +1048,91 @@ func TestSampleProvider_ScenarioEnabled_ScenarioFileExists_DoesNotCallTryResetBy
 		ScenarioResolver: m,
 	}, logger.GetLogger())
 
-	_, err := p.ResolveAndLoad(method, swaggerTpl, actualPath, legacyFlat)
+	_, err := p.ResolveAndLoad(method, swaggerTpl, actualPath, legacyFlat, LoadOptions{})
 	require.NoError(t, err)
 
-	m.AssertNotCalled(t, "TryResetByRequest", mock.Anything, mock.Anything)
+	m.AssertNotCalled(t, "TryResetByRequest", mock.Anything, mock.Anything, mock.Anything)
 	m.AssertExpectations(t)
 }
+
+func TestSampleProvider_SampleFiles_ListsScenarioWithoutAdvancing(t *testing.T) {
+	samplesDir := t.TempDir()
+	scenariosDir := t.TempDir()
+
+	writeFile(t, filepath.Join(scenariosDir, "scans", "{id}"), "scenario.json", `{
+	  "version": 1,
+	  "mode": "step",
+	  "key": { "pathParam": "id" },
+	  "sequence": [
+	    {"state":"queued","file":"GET.queued.json"},
+	    {"state":"done","file":"GET.done.json"}
+	  ],
+	  "behavior": {"repeatLast": true, "advanceOn": [{"method": "GET"}]}
+	}`)
+	writeFile(t, filepath.Join(scenariosDir, "scans", "{id}"), "GET.queued.json", `{"state":"queued"}`)
+	writeFile(t, filepath.Join(samplesDir, "scans", "{id}"), "GET.done.json", `{"status":202,"body":{"state":"done"}}`)
+
+	m := new(MockScenarioResolver)
+	p := NewSampleProvider(ProviderConfig{
+		BaseDir:          samplesDir,
+		Layout:           config.LayoutFolders,
+		ScenarioEnabled:  true,
+		ScenarioFilename: "scenario.json",
+		ScenariosDir:     scenariosDir,
+		ScenarioResolver: m,
+	}, logger.GetLogger())
+
+	files := p.SampleFiles("GET", "/scans/{id}", "", "")
+	require.Len(t, files, 2)
+	require.Equal(t, "queued", files[0].State)
+	require.Equal(t, filepath.Join(scenariosDir, "scans", "{id}", "GET.queued.json"), files[0].Path)
+	require.Equal(t, "done", files[1].State)
+	require.Equal(t, filepath.Join(samplesDir, "scans", "{id}", "GET.done.json"), files[1].Path)
+	require.NoError(t, files[1].Err)
+	require.Equal(t, 202, files[1].Response.Status)
+	require.False(t, files[1].Modified.IsZero())
+
+	// listing must not touch scenario state
+	m.AssertNotCalled(t, "ResolveScenarioFile", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSampleProvider_SampleFiles_PlainSample(t *testing.T) {
+	baseDir := t.TempDir()
+	writeFile(t, filepath.Join(baseDir, "items", "{id}"), "GET.json", `{"id":"1"}`)
+
+	p := NewSampleProvider(ProviderConfig{BaseDir: baseDir, Layout: config.LayoutFolders}, logger.GetLogger())
+
+	files := p.SampleFiles("GET", "/items/{id}", "", "")
+	require.Len(t, files, 1)
+	require.Empty(t, files[0].State)
+	require.JSONEq(t, `{"id":"1"}`, string(files[0].Response.Body))
+
+	require.Empty(t, p.SampleFiles("DELETE", "/items/{id}", "", ""))
+}
+
+func TestSampleProvider_AuditFlatSamples(t *testing.T) {
+	baseDir := t.TempDir()
+	writeFile(t, baseDir, "GET__items_{id}.json", `{"id":"flat"}`)
+	writeFile(t, filepath.Join(baseDir, "items", "_id_"), "GET.json", `{"id":"folder"}`)
+	writeFile(t, baseDir, "POST__items.json", `{}`)
+	writeFile(t, baseDir, "POST__items.409.json", `{}`)
+	writeFile(t, baseDir, "DELETE__gone.json", `{}`)
+	writeFile(t, baseDir, "notes.json", `{}`)
+
+	routes := []RouteFiles{
+		{Method: "GET", SwaggerPath: "/items/{id}", FlatFile: "GET__items_{id}.json"},
+		{Method: "POST", SwaggerPath: "/items", FlatFile: "POST__items.json"},
+	}
+
+	p := NewSampleProvider(ProviderConfig{BaseDir: baseDir, Layout: config.LayoutAuto}, logger.GetLogger())
+	report, err := p.AuditFlatSamples(routes)
+	require.NoError(t, err)
+	require.Equal(t, []ShadowedSample{{File: "GET__items_{id}.json", ShadowedBy: "items/_id_/GET.json"}}, report.Shadowed)
+	require.Equal(t, []string{"DELETE__gone.json"}, report.Orphaned)
+
+	p = NewSampleProvider(ProviderConfig{BaseDir: baseDir, Layout: config.LayoutFlat}, logger.GetLogger())
+	report, err = p.AuditFlatSamples(routes)
+	require.NoError(t, err)
+	require.Empty(t, report.Shadowed)
+	require.Empty(t, report.Orphaned)
+}
@@ -7,41 +7,63 @@ package samples
 import (
 	"encoding/json"
 	"fmt"
+	"hash/fnv"
+	"math"
 	"os"
 	"path/filepath"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/ozgen/openapi-emulator/internal/matcher"
+	"github.com/ozgen/openapi-emulator/internal/openapi"
 	"github.com/ozgen/openapi-emulator/logger"
+	"github.com/ozgen/openapi-emulator/utils"
 	"github.com/sirupsen/logrus"
 )
 
-// ScenarioResolver holds runtime state (in-memory).
+// stepLockStripes is the number of locks step advances of different keys
+// are spread over.
+const stepLockStripes = 64
+
+// ScenarioResolver holds runtime state, in memory unless WithScenarioStore
+// names a shared store.
 type ScenarioResolver struct {
-	mu            sync.Mutex
-	stepIndex     map[string]int
-	startedAt     map[string]time.Time
+	// mu guards the local bookkeeping below; it is never held across store
+	// calls, which may be network round-trips.
+	mu sync.Mutex
+	// stepLocks serialize the step advances of one key within this process,
+	// so local requests do not burn compare-and-set attempts on each other.
+	// The store's compare-and-set keeps replicas consistent.
+	stepLocks     [stepLockStripes]sync.Mutex
+	store         IScenarioStore
 	resetRules    map[string][]ResetRule
 	resetByMethod map[string][]struct {
 		rule    ResetRule
 		binding ResetBinding
 	}
+	// keys are the runtime keys this resolver has handed to the store, so
+	// ResetScenarios can drop them again.
+	keys map[string]struct{}
 
 	log *logrus.Logger
 }
 
-func NewScenarioResolver() IScenarioResolver {
-	return &ScenarioResolver{
-		stepIndex:  map[string]int{},
-		startedAt:  map[string]time.Time{},
+func NewScenarioResolver(opts ...ResolverOption) IScenarioResolver {
+	e := &ScenarioResolver{
+		store:      newMemoryScenarioStore(),
 		resetRules: map[string][]ResetRule{},
 		resetByMethod: map[string][]struct {
 			rule    ResetRule
 			binding ResetBinding
 		}{},
-		log: logger.GetLogger(),
+		keys: map[string]struct{}{},
+		log:  logger.GetLogger(),
+	}
+	for _, opt := range opts {
+		opt(e)
 	}
+	return e
 }
 
 func LoadScenario(scenarioPath string) (*Scenario, error) {
@@ -51,6 +73,7 @@ func LoadScenario(scenarioPath string) (*Scenario, error) {
 	if err != nil {
 		return nil, err
 	}
+	b = stripJSONC(b)
 
 	var sc Scenario
 	if err := json.Unmarshal(b, &sc); err != nil {
@@ -63,6 +86,15 @@ func LoadScenario(scenarioPath string) (*Scenario, error) {
 		return nil, fmt.Errorf("unsupported scenario version: %d", sc.Version)
 	}
 
+	var doc any
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return nil, fmt.Errorf("parse scenario.json: %w", err)
+	}
+	if err := validateScenario(doc); err != nil {
+		log.WithError(err).Error("scenario.json does not match schema")
+		return nil, fmt.Errorf("invalid scenario.json: %w", err)
+	}
+
 	sc.Mode = strings.TrimSpace(sc.Mode)
 	if sc.Mode != "step" && sc.Mode != "time" {
 		log.WithField("mode", sc.Mode).Error("invalid scenario mode")
@@ -86,10 +118,14 @@ func LoadScenario(scenarioPath string) (*Scenario, error) {
 			log.Error("scenario.timeline is required")
 			return nil, fmt.Errorf("time mode requires non-empty timeline")
 		}
+		if err := anchorTimeline(&sc); err != nil {
+			log.WithError(err).Error("invalid scenario timeline")
+			return nil, err
+		}
 		// ensure sorted
 		for i := 1; i < len(sc.Timeline); i++ {
 			if sc.Timeline[i].AfterSec < sc.Timeline[i-1].AfterSec {
-				return nil, fmt.Errorf("timeline must be sorted by afterMs ascending")
+				return nil, fmt.Errorf("timeline must be sorted by afterSec or at ascending")
 			}
 		}
 	}
@@ -97,29 +133,53 @@ func LoadScenario(scenarioPath string) (*Scenario, error) {
 	return &sc, nil
 }
 
+// anchorTimeline turns the `at` times of an absolute timeline into AfterSec
+// offsets from its first entry, which becomes the scenario's anchor.
+func anchorTimeline(sc *Scenario) error {
+	absolute := 0
+	for _, t := range sc.Timeline {
+		if t.At != "" {
+			absolute++
+		}
+	}
+	if absolute == 0 {
+		return nil
+	}
+	if absolute != len(sc.Timeline) {
+		return fmt.Errorf("timeline entries must all use afterSec or all use at")
+	}
+	if sc.Behavior.Loop {
+		return fmt.Errorf("behavior.loop is not supported with absolute at times")
+	}
+
+	for i := range sc.Timeline {
+		at, err := time.Parse(time.RFC3339, sc.Timeline[i].At)
+		if err != nil {
+			return fmt.Errorf("timeline[%d].at: %w", i, err)
+		}
+		if i == 0 {
+			sc.anchor = at
+		}
+		sc.Timeline[i].AfterSec = int64(at.Sub(sc.anchor) / time.Second)
+	}
+	return nil
+}
+
 func (e *ScenarioResolver) ResolveScenarioFile(
 	sc *Scenario,
 	method string,
 	swaggerTpl string,
 	actualPath string,
-) (file string, state string, err error) {
+	params map[string]string,
+	client string,
+) (file string, state ScenarioState, err error) {
 	method = strings.ToUpper(method)
 
-	keyVal, ok := extractPathParam(swaggerTpl, actualPath, sc.Key.PathParam)
-	if !ok || strings.TrimSpace(keyVal) == "" {
-		e.log.WithFields(logrus.Fields{
-			"swaggerTpl": swaggerTpl,
-			"actualPath": actualPath,
-			"want":       sc.Key.PathParam,
-		}).Error("failed to extract key path param")
-		return "", "", fmt.Errorf(
-			"cannot extract key path param %q from path %q using template %q",
-			sc.Key.PathParam, actualPath, swaggerTpl,
-		)
+	k, err := e.runtimeKey(sc, swaggerTpl, actualPath, params, client)
+	if err != nil {
+		return "", ScenarioState{}, err
 	}
 
-	k := scenarioRuntimeKey(swaggerTpl, keyVal)
-
 	e.mu.Lock()
 	if _, ok := e.resetRules[k]; !ok {
 		var rules []ResetRule
@@ -154,6 +214,7 @@ func (e *ScenarioResolver) ResolveScenarioFile(
 				binding: ResetBinding{
 					ScenarioTpl: swaggerTpl,
 					KeyParam:    sc.Key.PathParam,
+					KeyAliases:  sc.Key.Aliases,
 				},
 			})
 		}
@@ -167,41 +228,153 @@ func (e *ScenarioResolver) ResolveScenarioFile(
 	case "time":
 		return e.resolveTime(k, sc, method, actualPath)
 	default:
-		return "", "", fmt.Errorf("unsupported mode %q", sc.Mode)
+		return "", ScenarioState{}, fmt.Errorf("unsupported mode %q", sc.Mode)
 	}
 }
 
-func (e *ScenarioResolver) TryResetByRequest(method, actualPath string) bool {
-	method = strings.ToUpper(method)
+// PeekScenarioState returns the current scenario state without advancing a
+// step scenario. Like any access, it starts the clock of a time scenario.
+func (e *ScenarioResolver) PeekScenarioState(sc *Scenario, method, swaggerTpl, actualPath string, params map[string]string, client string) (ScenarioState, error) {
+	k, err := e.runtimeKey(sc, swaggerTpl, actualPath, params, client)
+	if err != nil {
+		return ScenarioState{}, err
+	}
 
+	switch sc.Mode {
+	case "step":
+		if len(sc.Sequence) == 0 {
+			return ScenarioState{}, fmt.Errorf("step mode requires non-empty sequence")
+		}
+		idx, err := e.store.Step(k)
+		if err != nil {
+			return ScenarioState{}, err
+		}
+		idx = max(0, min(idx, len(sc.Sequence)-1))
+		return ScenarioState{Mode: sc.Mode, State: sc.Sequence[idx].State, Step: idx}, nil
+	case "time":
+		_, state, err := e.resolveTime(k, sc, strings.ToUpper(method), actualPath)
+		return state, err
+	default:
+		return ScenarioState{}, fmt.Errorf("unsupported mode %q", sc.Mode)
+	}
+}
+
+// SetScenarioState moves the shared (not client-scoped) runtime state of
+// swaggerTpl and key to state: a step scenario continues from that entry, a
+// time scenario restarts its clock as if the entry had just been reached. An
+// empty state resets the key.
+func (e *ScenarioResolver) SetScenarioState(sc *Scenario, swaggerTpl, key, state string) error {
+	k := scenarioRuntimeKey(swaggerTpl, key)
+	if state == "" {
+		return e.store.Delete(k)
+	}
+	e.remember(k)
+
+	_, st, err := ScenarioEntryByState(sc, state)
+	if err != nil {
+		return err
+	}
+
+	switch sc.Mode {
+	case "step":
+		return e.store.SetStep(k, st.Step)
+	case "time":
+		if !sc.anchor.IsZero() {
+			return fmt.Errorf("scenario timeline follows absolute at times; its state cannot be set")
+		}
+		return e.store.SetStart(k, time.Now().Add(-time.Duration(st.Elapsed)*time.Second))
+	}
+	return nil
+}
+
+// runtimeKey names the state of the scenario for the request. The key is
+// taken from params, the path parameters the router captured, so routes made
+// greedy by x-emulator-greedy resolve like {name+} ones; without them it is
+// extracted from actualPath.
+func (e *ScenarioResolver) runtimeKey(sc *Scenario, swaggerTpl, actualPath string, params map[string]string, client string) (string, error) {
+	keyVal, ok := keyFromParams(params, sc.Key.PathParam, sc.Key.Aliases)
+	if !ok {
+		keyVal, ok = extractKeyParam(swaggerTpl, actualPath, sc.Key.PathParam, sc.Key.Aliases)
+	}
+	if !ok || strings.TrimSpace(keyVal) == "" {
+		e.log.WithFields(logrus.Fields{
+			"swaggerTpl": swaggerTpl,
+			"actualPath": actualPath,
+			"want":       sc.Key.PathParam,
+		}).Error("failed to extract key path param")
+		return "", fmt.Errorf(
+			"cannot extract key path param %q from path %q using template %q",
+			sc.Key.PathParam, actualPath, swaggerTpl,
+		)
+	}
+	k := clientScoped(client, scenarioRuntimeKey(swaggerTpl, keyVal))
+	e.remember(k)
+	return k, nil
+}
+
+func (e *ScenarioResolver) remember(k string) {
 	e.mu.Lock()
-	defer e.mu.Unlock()
+	e.keys[k] = struct{}{}
+	e.mu.Unlock()
+}
 
-	list := e.resetByMethod[method]
-	if len(list) == 0 {
-		return false
+// ResetScenarios drops the runtime state of every scenario key this resolver
+// has served, along with the cached resetOn rules, so edited scenario files
+// start over from their first entry. It returns the number of keys reset.
+func (e *ScenarioResolver) ResetScenarios() int {
+	e.mu.Lock()
+	keys := make([]string, 0, len(e.keys))
+	for k := range e.keys {
+		keys = append(keys, k)
 	}
+	clear(e.resetRules)
+	clear(e.resetByMethod)
+	e.mu.Unlock()
 
-	resetAny := false
+	n := 0
+	for _, k := range keys {
+		if err := e.store.Delete(k); err != nil {
+			e.log.WithError(err).WithField("key", k).Error("failed to reset scenario state")
+			continue
+		}
+		e.mu.Lock()
+		delete(e.keys, k)
+		e.mu.Unlock()
+		n++
+	}
+	return n
+}
+
+func (e *ScenarioResolver) TryResetByRequest(method, actualPath, client string) bool {
+	method = strings.ToUpper(method)
 
-	for _, it := range list {
+	e.mu.Lock()
+	var targets []string
+	for _, it := range e.resetByMethod[method] {
 		rr := it.rule
 		b := it.binding
 
-		if rr.PathTpl != "" && !matchTemplatePathSuffix(rr.PathTpl, actualPath) {
+		if rr.PathTpl != "" && !matcher.MatchPathSuffix(rr.PathTpl, actualPath) {
 			continue
 		}
 
-		keyVal, ok := extractPathParam(rr.PathTpl, actualPath, b.KeyParam)
+		keyVal, ok := extractKeyParam(rr.PathTpl, actualPath, b.KeyParam, b.KeyAliases)
 		if !ok || strings.TrimSpace(keyVal) == "" {
 			continue
 		}
+		targets = append(targets, clientScoped(client, scenarioRuntimeKey(b.ScenarioTpl, keyVal)))
+	}
+	e.mu.Unlock()
 
-		runtimeKey := scenarioRuntimeKey(b.ScenarioTpl, keyVal)
-
-		delete(e.stepIndex, runtimeKey)
-		delete(e.startedAt, runtimeKey)
+	resetAny := false
+	for _, runtimeKey := range targets {
+		if err := e.store.Delete(runtimeKey); err != nil {
+			e.log.WithError(err).WithField("key", runtimeKey).Error("failed to reset scenario state")
+			continue
+		}
+		e.mu.Lock()
 		delete(e.resetRules, runtimeKey)
+		e.mu.Unlock()
 
 		resetAny = true
 	}
@@ -209,64 +382,91 @@ func (e *ScenarioResolver) TryResetByRequest(method, actualPath string) bool {
 	return resetAny
 }
 
-func (e *ScenarioResolver) resolveStep(k string, sc *Scenario, method string) (string, string, error) {
-	if len(sc.Sequence) == 0 {
-		return "", "", fmt.Errorf("step mode requires non-empty sequence")
-	}
+// maxAdvanceAttempts bounds the compare-and-set retries of a step advance
+// racing other replicas.
+const maxAdvanceAttempts = 10
 
-	e.mu.Lock()
-	defer e.mu.Unlock()
-
-	idx := e.stepIndex[k]
-	if idx < 0 {
-		idx = 0
-	}
-	if idx >= len(sc.Sequence) {
-		idx = len(sc.Sequence) - 1
+func (e *ScenarioResolver) resolveStep(k string, sc *Scenario, method string) (string, ScenarioState, error) {
+	if len(sc.Sequence) == 0 {
+		return "", ScenarioState{}, fmt.Errorf("step mode requires non-empty sequence")
 	}
 
-	entry := sc.Sequence[idx]
+	lock := &e.stepLocks[stepLockStripe(k)]
+	lock.Lock()
+	defer lock.Unlock()
 
-	if matchesAny(sc.Behavior.AdvanceOn, method, "") {
-		next := idx + 1
+	advance := matchesAny(sc.Behavior.AdvanceOn, method, "")
+	for attempt := 0; attempt < maxAdvanceAttempts; attempt++ {
+		stored, err := e.store.Step(k)
+		if err != nil {
+			return "", ScenarioState{}, err
+		}
+		idx := max(0, min(stored, len(sc.Sequence)-1))
+		entry := sc.Sequence[idx]
+		state := ScenarioState{Mode: sc.Mode, State: entry.State, Step: idx}
 
-		if next >= len(sc.Sequence) {
-			switch {
-			case sc.Behavior.Loop:
-				next = 0
-			case sc.Behavior.RepeatLast:
-				next = len(sc.Sequence) - 1
-			default:
-				next = len(sc.Sequence) - 1
+		if !advance {
+			return entry.File, state, nil
+		}
+		next := nextStep(sc, idx)
+		if sc.Behavior.Advance == "response" {
+			state.commit = func() error {
+				// Losing the race means another response already advanced
+				// past this entry.
+				_, err := e.store.CompareAndSetStep(k, stored, next)
+				return err
 			}
+			return entry.File, state, nil
 		}
 
-		e.stepIndex[k] = next
-	} else {
-		e.stepIndex[k] = idx
+		// Read and advance atomically: a replica that advanced in between
+		// makes the swap fail, and the entry is read again.
+		ok, err := e.store.CompareAndSetStep(k, stored, next)
+		if err != nil {
+			return "", ScenarioState{}, err
+		}
+		if ok {
+			return entry.File, state, nil
+		}
 	}
+	return "", ScenarioState{}, &ScenarioStoreError{Err: fmt.Errorf("scenario %s: step changed concurrently %d times", k, maxAdvanceAttempts)}
+}
 
-	return entry.File, entry.State, nil
+// stepLockStripe picks the stepLocks entry of runtime key k.
+func stepLockStripe(k string) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(k))
+	return int(h.Sum32() % stepLockStripes)
 }
 
-func (e *ScenarioResolver) resolveTime(k string, sc *Scenario, method string, actualPath string) (string, string, error) {
+// nextStep returns the step index following idx.
+func nextStep(sc *Scenario, idx int) int {
+	next := idx + 1
+	if next >= len(sc.Sequence) {
+		if sc.Behavior.Loop {
+			return 0
+		}
+		// repeatLast, and the default, stay on the last entry.
+		return len(sc.Sequence) - 1
+	}
+	return next
+}
+
+func (e *ScenarioResolver) resolveTime(k string, sc *Scenario, method string, actualPath string) (string, ScenarioState, error) {
 	if len(sc.Timeline) == 0 {
-		return "", "", fmt.Errorf("time mode requires non-empty timeline")
+		return "", ScenarioState{}, fmt.Errorf("time mode requires non-empty timeline")
 	}
 
-	e.mu.Lock()
-	t0, ok := e.startedAt[k]
-	if !ok {
-		if len(sc.Behavior.StartOn) == 0 || matchesAny(sc.Behavior.StartOn, method, actualPath) {
-			t0 = time.Now()
-			e.startedAt[k] = t0
-		} else {
-			t0 = time.Now()
-			e.startedAt[k] = t0
+	// Absolute timelines run on the wall clock rather than from the first
+	// request, so they need no stored start.
+	t0 := sc.anchor
+	if t0.IsZero() {
+		var err error
+		if t0, err = e.store.Start(k, time.Now()); err != nil {
+			return "", ScenarioState{}, err
 		}
 	}
-	elapsedSec := int64(time.Since(t0).Seconds())
-	e.mu.Unlock()
+	elapsed := max(time.Since(t0).Seconds(), 0)
 
 	total := sc.Timeline[len(sc.Timeline)-1].AfterSec
 	if total < 0 {
@@ -274,89 +474,142 @@ func (e *ScenarioResolver) resolveTime(k string, sc *Scenario, method string, ac
 	}
 
 	if sc.Behavior.Loop && total > 0 {
-		elapsedSec = elapsedSec % (total + 1)
-	} else if sc.Behavior.RepeatLast && elapsedSec > total {
-		elapsedSec = total
-	} else if elapsedSec > total {
-		elapsedSec = total
+		elapsed = math.Mod(elapsed, float64(total+1))
 	}
+	if elapsed > float64(total) {
+		elapsed = float64(total)
+	}
+	elapsedSec := int64(elapsed)
 
-	chosen := sc.Timeline[0]
-	for _, t := range sc.Timeline {
+	step := 0
+	for i, t := range sc.Timeline {
 		if t.AfterSec <= elapsedSec {
-			chosen = t
+			step = i
 		} else {
 			break
 		}
 	}
+	chosen := sc.Timeline[step]
+
+	return chosen.File, ScenarioState{
+		Mode:    sc.Mode,
+		State:   chosen.State,
+		Step:    step,
+		Elapsed: elapsedSec,
+		Total:   total,
+		Percent: progressPercent(elapsed, total),
+	}, nil
+}
+
+// scenarioEntryFile returns the file of the entry st was resolved to.
+func scenarioEntryFile(sc *Scenario, st ScenarioState) string {
+	if sc.Mode == "time" {
+		return sc.Timeline[st.Step].File
+	}
+	return sc.Sequence[st.Step].File
+}
+
+// ScenarioEntryByState returns the first entry of sc with the given state,
+// as if the scenario had just reached it. It does not touch runtime state.
+func ScenarioEntryByState(sc *Scenario, state string) (string, ScenarioState, error) {
+	var known []string
+	switch sc.Mode {
+	case "step":
+		for i, entry := range sc.Sequence {
+			if entry.State == state {
+				return entry.File, ScenarioState{Mode: sc.Mode, State: entry.State, Step: i}, nil
+			}
+			known = append(known, entry.State)
+		}
+	case "time":
+		total := sc.Timeline[len(sc.Timeline)-1].AfterSec
+		for i, entry := range sc.Timeline {
+			if entry.State == state {
+				return entry.File, ScenarioState{
+					Mode:    sc.Mode,
+					State:   entry.State,
+					Step:    i,
+					Elapsed: entry.AfterSec,
+					Total:   total,
+					Percent: progressPercent(float64(entry.AfterSec), total),
+				}, nil
+			}
+			known = append(known, entry.State)
+		}
+	}
+	return "", ScenarioState{}, &UnknownScenarioStateError{State: state, Known: known}
+}
 
-	return chosen.File, chosen.State, nil
+// progressPercent interpolates elapsed/total into 0..100. An empty timeline
+// (total 0) is always complete.
+func progressPercent(elapsed float64, total int64) int {
+	if total <= 0 {
+		return 100
+	}
+	pct := int(elapsed * 100 / float64(total))
+	return max(0, min(pct, 100))
 }
 
 func scenarioRuntimeKey(swaggerTpl, keyVal string) string {
 	return strings.ToUpper(strings.TrimSpace(swaggerTpl)) + "::" + keyVal
 }
 
+// clientScoped prefixes a runtime key with the client identity, if any.
+func clientScoped(client, key string) string {
+	if client == "" {
+		return key
+	}
+	return client + "@" + key
+}
+
 func matchesAny(rules []MatchRule, method string, actualPath string) bool {
-	method = strings.ToUpper(method)
 	for _, r := range rules {
-		if strings.ToUpper(strings.TrimSpace(r.Method)) != method {
+		if !matcher.MatchMethod(r.Method, method) {
 			continue
 		}
-		p := strings.TrimSpace(r.Path)
-		if p == "" {
-			return true
-		}
-		logger.GetLogger().Info("matching rule", "path", p, "method", method, "actualPath", actualPath)
-		if matchTemplatePathSuffix(p, actualPath) {
+		if strings.TrimSpace(r.Path) == "" || matcher.MatchPathSuffix(r.Path, actualPath) {
 			return true
 		}
 	}
 	return false
 }
 
-func matchTemplatePathSuffix(tpl, actual string) bool {
-	tplParts := strings.Split(strings.Trim(tpl, "/"), "/")
-	actParts := strings.Split(strings.Trim(actual, "/"), "/")
-	if len(actParts) < len(tplParts) {
-		return false
-	}
-
-	actParts = actParts[len(actParts)-len(tplParts):]
-
-	for i := range tplParts {
-		t := tplParts[i]
-		a := actParts[i]
-		if strings.HasPrefix(t, "{") && strings.HasSuffix(t, "}") {
-			continue
-		}
-		if t != a {
-			return false
+// keyFromParams looks the scenario key up in captured path params, trying
+// the key parameter name first and then its aliases.
+func keyFromParams(params map[string]string, keyParam string, aliases []string) (string, bool) {
+	for _, name := range append([]string{keyParam}, aliases...) {
+		if v, ok := params[strings.TrimSpace(name)]; ok {
+			return v, true
 		}
 	}
-	return true
+	return "", false
 }
 
-func extractPathParam(swaggerTpl, actualPath, want string) (string, bool) {
-	tplParts := strings.Split(strings.Trim(swaggerTpl, "/"), "/")
-	actParts := strings.Split(strings.Trim(actualPath, "/"), "/")
-	if len(tplParts) != len(actParts) {
-		return "", false
-	}
-	for i := range tplParts {
-		p := tplParts[i]
-		if strings.HasPrefix(p, "{") && strings.HasSuffix(p, "}") {
-			name := strings.TrimSuffix(strings.TrimPrefix(p, "{"), "}")
-			if name == want {
-				return actParts[i], true
-			}
+// extractKeyParam extracts the scenario key from actualPath, trying the key
+// parameter name first and then its aliases.
+func extractKeyParam(swaggerTpl, actualPath, keyParam string, aliases []string) (string, bool) {
+	for _, name := range append([]string{keyParam}, aliases...) {
+		if v, ok := extractPathParam(swaggerTpl, actualPath, strings.TrimSpace(name)); ok {
+			return v, true
 		}
 	}
 	return "", false
 }
 
+// extractPathParam returns the value of the path parameter want, matched
+// like the router does, so a greedy {want+} takes the rest of the path.
+func extractPathParam(swaggerTpl, actualPath, want string) (string, bool) {
+	v, ok := openapi.PathParams(swaggerTpl, actualPath)[want]
+	return v, ok
+}
+
 func ScenarioPathForSwagger(baseDir, swaggerPath, filename string) string {
 	pathDir := strings.TrimPrefix(swaggerPath, "/")
 	pathDir = filepath.FromSlash(pathDir)
-	return filepath.Join(baseDir, pathDir, filename)
+	p := filepath.Join(baseDir, pathDir, filename)
+	if encoded := filepath.Join(baseDir, EncodeParamSegments(pathDir), filename); encoded != p &&
+		!utils.FileExists(p) && utils.FileExists(encoded) {
+		return encoded
+	}
+	return p
 }
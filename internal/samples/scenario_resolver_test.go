@@ -5,9 +5,11 @@
 package samples
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -38,6 +40,13 @@ func TestExtractPathParam(t *testing.T) {
 	}
 }
 
+func TestExtractPathParam_Greedy(t *testing.T) {
+	val, ok := extractPathParam("/files/{path+}", "/files/reports/2026/q1.pdf", "path")
+	if !ok || val != "reports/2026/q1.pdf" {
+		t.Fatalf("expected the rest of the path, got ok=%v val=%q", ok, val)
+	}
+}
+
 func TestLoadScenario_ValidV1_Step(t *testing.T) {
 	dir := t.TempDir()
 	p := filepath.Join(dir, "scenario.json")
@@ -86,6 +95,31 @@ func TestLoadScenario_ValidV1_Time(t *testing.T) {
 	}
 }
 
+func TestLoadScenario_JSONC(t *testing.T) {
+	dir := t.TempDir()
+	p := filepath.Join(dir, "scenario.json")
+	if err := os.WriteFile(p, []byte(`{
+  "version": 1,
+  "mode": "step", // advance on every GET
+  "key": {"pathParam": "id"},
+  /* queued until the first poll */
+  "sequence": [
+    {"state": "queued", "file": "GET.queued.json"},
+    {"state": "done", "file": "GET.done.json"},
+  ],
+}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	sc, err := LoadScenario(p)
+	if err != nil {
+		t.Fatalf("LoadScenario: %v", err)
+	}
+	if len(sc.Sequence) != 2 || sc.Sequence[1].State != "done" {
+		t.Fatalf("unexpected sequence %+v", sc.Sequence)
+	}
+}
+
 func TestLoadScenario_InvalidVersion(t *testing.T) {
 	dir := t.TempDir()
 	p := filepath.Join(dir, "scenario.json")
@@ -119,6 +153,48 @@ func TestLoadScenario_MissingKeyPathParam(t *testing.T) {
 	}
 }
 
+func TestLoadScenario_SchemaViolations(t *testing.T) {
+	cases := map[string]string{
+		"unknown field": `{"version":1,"mode":"step","key":{"pathParam":"id"},
+			"sequence":[{"file":"a.json"}],"sequnce":[]}`,
+		"entry without file": `{"version":1,"mode":"step","key":{"pathParam":"id"},
+			"sequence":[{"state":"a"}]}`,
+		"negative afterSec": `{"version":1,"mode":"time","key":{"pathParam":"id"},
+			"timeline":[{"afterSec":-1,"file":"a.json"}]}`,
+		"rule without method": `{"version":1,"mode":"step","key":{"pathParam":"id"},
+			"sequence":[{"file":"a.json"}],"behavior":{"advanceOn":[{"path":"/x"}]}}`,
+	}
+
+	for name, content := range cases {
+		t.Run(name, func(t *testing.T) {
+			p := filepath.Join(t.TempDir(), "scenario.json")
+			writeF(t, p, content)
+
+			if _, err := LoadScenario(p); err == nil {
+				t.Fatalf("expected schema error")
+			}
+		})
+	}
+}
+
+func TestLoadScenario_ExampleFilesMatchSchema(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("..", "..", "examples", "*.json"))
+	if err != nil {
+		t.Fatalf("glob: %v", err)
+	}
+	status := filepath.Join("..", "..", "examples", "openvasd", "sample", "scans", "{id}", "status")
+	files = append(files, filepath.Join(status, "scenario.json"), filepath.Join(status, "scenario.time.json"))
+
+	for _, f := range files {
+		if !strings.Contains(filepath.Base(f), "scenario") {
+			continue
+		}
+		if _, err := LoadScenario(f); err != nil {
+			t.Fatalf("%s: %v", f, err)
+		}
+	}
+}
+
 func TestScenarioResolver_ResolveScenarioFile_Step_SelectsFirstThenAdvances(t *testing.T) {
 	e := NewScenarioResolver()
 
@@ -135,36 +211,36 @@ func TestScenarioResolver_ResolveScenarioFile_Step_SelectsFirstThenAdvances(t *t
 	sc.Behavior.AdvanceOn = []MatchRule{{Method: "GET"}}
 	sc.Behavior.RepeatLast = true
 
-	file1, state1, err := e.ResolveScenarioFile(sc, "get", "/api/v1/items/{id}", "/api/v1/items/1")
+	file1, state1, err := e.ResolveScenarioFile(sc, "get", "/api/v1/items/{id}", "/api/v1/items/1", nil, "")
 	if err != nil {
 		t.Fatalf("ResolveScenarioFile: %v", err)
 	}
-	if file1 != "a.json" || state1 != "requested" {
-		t.Fatalf("expected a.json/requested got %q/%q", file1, state1)
+	if file1 != "a.json" || state1.State != "requested" {
+		t.Fatalf("expected a.json/requested got %q/%q", file1, state1.State)
 	}
 
-	file2, state2, err := e.ResolveScenarioFile(sc, "GET", "/api/v1/items/{id}", "/api/v1/items/1")
+	file2, state2, err := e.ResolveScenarioFile(sc, "GET", "/api/v1/items/{id}", "/api/v1/items/1", nil, "")
 	if err != nil {
 		t.Fatalf("ResolveScenarioFile: %v", err)
 	}
-	if file2 != "b.json" || state2 != "running" {
-		t.Fatalf("expected b.json/running got %q/%q", file2, state2)
+	if file2 != "b.json" || state2.State != "running" {
+		t.Fatalf("expected b.json/running got %q/%q", file2, state2.State)
 	}
 
-	file3, state3, err := e.ResolveScenarioFile(sc, "GET", "/api/v1/items/{id}", "/api/v1/items/1")
+	file3, state3, err := e.ResolveScenarioFile(sc, "GET", "/api/v1/items/{id}", "/api/v1/items/1", nil, "")
 	if err != nil {
 		t.Fatalf("ResolveScenarioFile: %v", err)
 	}
-	if file3 != "c.json" || state3 != "done" {
-		t.Fatalf("expected c.json/done got %q/%q", file3, state3)
+	if file3 != "c.json" || state3.State != "done" {
+		t.Fatalf("expected c.json/done got %q/%q", file3, state3.State)
 	}
 
-	file4, state4, err := e.ResolveScenarioFile(sc, "GET", "/api/v1/items/{id}", "/api/v1/items/1")
+	file4, state4, err := e.ResolveScenarioFile(sc, "GET", "/api/v1/items/{id}", "/api/v1/items/1", nil, "")
 	if err != nil {
 		t.Fatalf("ResolveScenarioFile: %v", err)
 	}
-	if file4 != "c.json" || state4 != "done" {
-		t.Fatalf("expected c.json/done got %q/%q", file4, state4)
+	if file4 != "c.json" || state4.State != "done" {
+		t.Fatalf("expected c.json/done got %q/%q", file4, state4.State)
 	}
 }
 
@@ -181,11 +257,11 @@ func TestScenarioResolver_ResolveScenarioFile_Step_NoAdvanceRule_StaysOnFirst(t
 	sc.Behavior.AdvanceOn = nil
 	sc.Behavior.RepeatLast = true
 
-	file1, _, err := e.ResolveScenarioFile(sc, "GET", "/api/v1/items/{id}", "/api/v1/items/9")
+	file1, _, err := e.ResolveScenarioFile(sc, "GET", "/api/v1/items/{id}", "/api/v1/items/9", nil, "")
 	if err != nil {
 		t.Fatalf("ResolveScenarioFile: %v", err)
 	}
-	file2, _, err := e.ResolveScenarioFile(sc, "GET", "/api/v1/items/{id}", "/api/v1/items/9")
+	file2, _, err := e.ResolveScenarioFile(sc, "GET", "/api/v1/items/{id}", "/api/v1/items/9", nil, "")
 	if err != nil {
 		t.Fatalf("ResolveScenarioFile: %v", err)
 	}
@@ -201,7 +277,7 @@ func TestScenarioResolver_ResolveScenarioFile_Step_RequiresNonEmptySequence(t *t
 	sc.Key.PathParam = "id"
 	sc.Sequence = nil
 
-	_, _, err := e.ResolveScenarioFile(sc, "GET", "/api/v1/items/{id}", "/api/v1/items/1")
+	_, _, err := e.ResolveScenarioFile(sc, "GET", "/api/v1/items/{id}", "/api/v1/items/1", nil, "")
 	if err == nil {
 		t.Fatalf("expected error")
 	}
@@ -218,22 +294,22 @@ func TestScenarioResolver_ResolveScenarioFile_Time_ChoosesBasedOnElapsed(t *test
 	}
 	sc.Behavior.RepeatLast = true
 
-	file1, state1, err := e.ResolveScenarioFile(sc, "GET", "/api/v1/items/{id}", "/api/v1/items/5")
+	file1, state1, err := e.ResolveScenarioFile(sc, "GET", "/api/v1/items/{id}", "/api/v1/items/5", nil, "")
 	if err != nil {
 		t.Fatalf("ResolveScenarioFile: %v", err)
 	}
-	if file1 != "t0.json" || state1 != "t0" {
-		t.Fatalf("expected t0.json/t0 got %q/%q", file1, state1)
+	if file1 != "t0.json" || state1.State != "t0" {
+		t.Fatalf("expected t0.json/t0 got %q/%q", file1, state1.State)
 	}
 
 	time.Sleep(1100 * time.Millisecond)
 
-	file2, state2, err := e.ResolveScenarioFile(sc, "GET", "/api/v1/items/{id}", "/api/v1/items/5")
+	file2, state2, err := e.ResolveScenarioFile(sc, "GET", "/api/v1/items/{id}", "/api/v1/items/5", nil, "")
 	if err != nil {
 		t.Fatalf("ResolveScenarioFile: %v", err)
 	}
-	if file2 != "t20.json" || state2 != "t20" {
-		t.Fatalf("expected t20.json/t20 got %q/%q", file2, state2)
+	if file2 != "t20.json" || state2.State != "t20" {
+		t.Fatalf("expected t20.json/t20 got %q/%q", file2, state2.State)
 	}
 }
 
@@ -244,7 +320,7 @@ func TestScenarioResolver_ResolveScenarioFile_Time_RequiresNonEmptyTimeline(t *t
 	sc.Key.PathParam = "id"
 	sc.Timeline = nil
 
-	_, _, err := e.ResolveScenarioFile(sc, "GET", "/api/v1/items/{id}", "/api/v1/items/1")
+	_, _, err := e.ResolveScenarioFile(sc, "GET", "/api/v1/items/{id}", "/api/v1/items/1", nil, "")
 	if err == nil {
 		t.Fatalf("expected error")
 	}
@@ -263,18 +339,18 @@ func TestScenarioResolver_ResetOn_ClearsState_ViaTryResetByRequest(t *testing.T)
 	sc.Behavior.ResetOn = []MatchRule{{Method: "POST", Path: "/api/v1/items/{id}"}}
 	sc.Behavior.RepeatLast = true
 
-	_, _, _ = e.ResolveScenarioFile(sc, "GET", "/api/v1/items/{id}", "/api/v1/items/1")
-	f2, _, _ := e.ResolveScenarioFile(sc, "GET", "/api/v1/items/{id}", "/api/v1/items/1")
+	_, _, _ = e.ResolveScenarioFile(sc, "GET", "/api/v1/items/{id}", "/api/v1/items/1", nil, "")
+	f2, _, _ := e.ResolveScenarioFile(sc, "GET", "/api/v1/items/{id}", "/api/v1/items/1", nil, "")
 	if f2 != "b.json" {
 		t.Fatalf("expected b.json after advancing, got %q", f2)
 	}
 
-	reset := e.TryResetByRequest("POST", "/api/v1/items/1")
+	reset := e.TryResetByRequest("POST", "/api/v1/items/1", "")
 	if !reset {
 		t.Fatalf("expected reset=true")
 	}
 
-	fAfter, _, err := e.ResolveScenarioFile(sc, "GET", "/api/v1/items/{id}", "/api/v1/items/1")
+	fAfter, _, err := e.ResolveScenarioFile(sc, "GET", "/api/v1/items/{id}", "/api/v1/items/1", nil, "")
 	if err != nil {
 		t.Fatalf("ResolveScenarioFile(after reset): %v", err)
 	}
@@ -291,7 +367,7 @@ func TestScenarioResolver_ResolveScenarioFile_KeyExtractionError(t *testing.T) {
 	sc.Sequence = []ScenarioEntry{{State: "s1", File: "a.json"}}
 	sc.Behavior.RepeatLast = true
 
-	_, _, err := e.ResolveScenarioFile(sc, "GET", "/api/v1/items/{id}", "/api/v1/items")
+	_, _, err := e.ResolveScenarioFile(sc, "GET", "/api/v1/items/{id}", "/api/v1/items", nil, "")
 	if err == nil {
 		t.Fatalf("expected error")
 	}
@@ -310,9 +386,9 @@ func TestScenarioResolver_ResolveScenarioFile_Step_Loop_WrapsToFirst(t *testing.
 	sc.Behavior.Loop = true
 	sc.Behavior.RepeatLast = true
 
-	f1, _, _ := e.ResolveScenarioFile(sc, "GET", "/api/v1/items/{id}", "/api/v1/items/1")
-	f2, _, _ := e.ResolveScenarioFile(sc, "GET", "/api/v1/items/{id}", "/api/v1/items/1")
-	f3, _, _ := e.ResolveScenarioFile(sc, "GET", "/api/v1/items/{id}", "/api/v1/items/1")
+	f1, _, _ := e.ResolveScenarioFile(sc, "GET", "/api/v1/items/{id}", "/api/v1/items/1", nil, "")
+	f2, _, _ := e.ResolveScenarioFile(sc, "GET", "/api/v1/items/{id}", "/api/v1/items/1", nil, "")
+	f3, _, _ := e.ResolveScenarioFile(sc, "GET", "/api/v1/items/{id}", "/api/v1/items/1", nil, "")
 
 	if f1 != "a.json" || f2 != "b.json" || f3 != "a.json" {
 		t.Fatalf("expected a,b,a got %q,%q,%q", f1, f2, f3)
@@ -331,10 +407,10 @@ func TestScenarioResolver_StateIsolation_ByID(t *testing.T) {
 	sc.Behavior.AdvanceOn = []MatchRule{{Method: "GET"}}
 	sc.Behavior.RepeatLast = true
 
-	_, _, _ = e.ResolveScenarioFile(sc, "GET", "/api/v1/items/{id}", "/api/v1/items/1")
-	f1b, _, _ := e.ResolveScenarioFile(sc, "GET", "/api/v1/items/{id}", "/api/v1/items/1")
+	_, _, _ = e.ResolveScenarioFile(sc, "GET", "/api/v1/items/{id}", "/api/v1/items/1", nil, "")
+	f1b, _, _ := e.ResolveScenarioFile(sc, "GET", "/api/v1/items/{id}", "/api/v1/items/1", nil, "")
 
-	f2a, _, _ := e.ResolveScenarioFile(sc, "GET", "/api/v1/items/{id}", "/api/v1/items/2")
+	f2a, _, _ := e.ResolveScenarioFile(sc, "GET", "/api/v1/items/{id}", "/api/v1/items/2", nil, "")
 
 	if f1b != "b.json" {
 		t.Fatalf("expected id=1 to be b.json, got %q", f1b)
@@ -344,6 +420,33 @@ func TestScenarioResolver_StateIsolation_ByID(t *testing.T) {
 	}
 }
 
+func TestScenarioResolver_StateIsolation_GreedyKey(t *testing.T) {
+	e := NewScenarioResolver()
+
+	sc := &Scenario{Version: 1, Mode: "step"}
+	sc.Key.PathParam = "path"
+	sc.Sequence = []ScenarioEntry{
+		{State: "uploading", File: "a.json"},
+		{State: "stored", File: "b.json"},
+	}
+	sc.Behavior.AdvanceOn = []MatchRule{{Method: "GET"}}
+	sc.Behavior.RepeatLast = true
+
+	f1a, _, err := e.ResolveScenarioFile(sc, "GET", "/files/{path+}", "/files/a/one.txt", nil, "")
+	if err != nil {
+		t.Fatalf("ResolveScenarioFile: %v", err)
+	}
+	f1b, _, _ := e.ResolveScenarioFile(sc, "GET", "/files/{path+}", "/files/a/one.txt", nil, "")
+	f2a, _, _ := e.ResolveScenarioFile(sc, "GET", "/files/{path+}", "/files/a/two.txt", nil, "")
+
+	if f1a != "a.json" || f1b != "b.json" {
+		t.Fatalf("expected a/one.txt to advance, got %q then %q", f1a, f1b)
+	}
+	if f2a != "a.json" {
+		t.Fatalf("expected a/two.txt to start over, got %q", f2a)
+	}
+}
+
 func TestScenarioResolver_Time_RepeatLast_SticksToLast(t *testing.T) {
 	e := NewScenarioResolver()
 
@@ -356,18 +459,18 @@ func TestScenarioResolver_Time_RepeatLast_SticksToLast(t *testing.T) {
 	sc.Behavior.RepeatLast = true
 	sc.Behavior.Loop = false
 
-	_, _, _ = e.ResolveScenarioFile(sc, "GET", "/api/v1/items/{id}", "/api/v1/items/5")
+	_, _, _ = e.ResolveScenarioFile(sc, "GET", "/api/v1/items/{id}", "/api/v1/items/5", nil, "")
 	time.Sleep(1100 * time.Millisecond)
 
-	f2, s2, _ := e.ResolveScenarioFile(sc, "GET", "/api/v1/items/{id}", "/api/v1/items/5")
-	if f2 != "t1.json" || s2 != "t1" {
-		t.Fatalf("expected t1.json/t1 got %q/%q", f2, s2)
+	f2, s2, _ := e.ResolveScenarioFile(sc, "GET", "/api/v1/items/{id}", "/api/v1/items/5", nil, "")
+	if f2 != "t1.json" || s2.State != "t1" {
+		t.Fatalf("expected t1.json/t1 got %q/%q", f2, s2.State)
 	}
 
 	time.Sleep(1200 * time.Millisecond)
-	f3, s3, _ := e.ResolveScenarioFile(sc, "GET", "/api/v1/items/{id}", "/api/v1/items/5")
-	if f3 != "t1.json" || s3 != "t1" {
-		t.Fatalf("expected sticky t1.json/t1 got %q/%q", f3, s3)
+	f3, s3, _ := e.ResolveScenarioFile(sc, "GET", "/api/v1/items/{id}", "/api/v1/items/5", nil, "")
+	if f3 != "t1.json" || s3.State != "t1" {
+		t.Fatalf("expected sticky t1.json/t1 got %q/%q", f3, s3.State)
 	}
 }
 
@@ -392,6 +495,88 @@ func TestLoadScenario_Time_RejectsUnsortedTimeline(t *testing.T) {
 	}
 }
 
+func TestLoadScenario_Time_AbsoluteAt_FollowsWallClock(t *testing.T) {
+	now := time.Now().UTC()
+	p := filepath.Join(t.TempDir(), "scenario.json")
+	writeF(t, p, fmt.Sprintf(`{
+	  "version": 1,
+	  "mode": "time",
+	  "key": {"pathParam":"id"},
+	  "timeline": [
+		{"at": %q, "state":"normal", "file":"normal.json"},
+		{"at": %q, "state":"maintenance", "file":"maintenance.json"},
+		{"at": %q, "state":"back", "file":"back.json"}
+	  ]
+	}`, now.Add(-2*time.Hour).Format(time.RFC3339), now.Add(-time.Hour).Format(time.RFC3339),
+		now.Add(time.Hour).Format(time.RFC3339)))
+
+	sc, err := LoadScenario(p)
+	if err != nil {
+		t.Fatalf("LoadScenario: %v", err)
+	}
+	if sc.Timeline[1].AfterSec != 3600 || sc.Timeline[2].AfterSec != 3*3600 {
+		t.Fatalf("expected offsets from the first at, got %+v", sc.Timeline)
+	}
+
+	e := NewScenarioResolver()
+	file, st, err := e.ResolveScenarioFile(sc, "GET", "/items/{id}", "/items/1", nil, "")
+	if err != nil {
+		t.Fatalf("ResolveScenarioFile: %v", err)
+	}
+	if file != "maintenance.json" || st.State != "maintenance" || st.Percent != 66 {
+		t.Fatalf("expected maintenance at 66%%, got %q %+v", file, st)
+	}
+
+	if err := e.SetScenarioState(sc, "/items/{id}", "1", "back"); err == nil {
+		t.Fatalf("expected setting the state of an absolute timeline to fail")
+	}
+}
+
+func TestScenarioResolver_Time_AbsoluteAt_BeforeFirstServesFirst(t *testing.T) {
+	sc := &Scenario{Version: 1, Mode: "time"}
+	sc.Key.PathParam = "id"
+	sc.Timeline = []TimelineEntry{
+		{At: time.Now().Add(time.Hour).Format(time.RFC3339), State: "later", File: "later.json"},
+		{At: time.Now().Add(2 * time.Hour).Format(time.RFC3339), State: "latest", File: "latest.json"},
+	}
+	if err := anchorTimeline(sc); err != nil {
+		t.Fatalf("anchorTimeline: %v", err)
+	}
+
+	file, st, err := NewScenarioResolver().ResolveScenarioFile(sc, "GET", "/items/{id}", "/items/1", nil, "")
+	if err != nil {
+		t.Fatalf("ResolveScenarioFile: %v", err)
+	}
+	if file != "later.json" || st.Elapsed != 0 {
+		t.Fatalf("expected the first entry before its at, got %q %+v", file, st)
+	}
+}
+
+func TestLoadScenario_Time_AbsoluteAt_Rejects(t *testing.T) {
+	cases := map[string]string{
+		"mixed":     `[{"at":"2026-01-01T00:00:00Z","file":"a.json"},{"afterSec":5,"file":"b.json"}]`,
+		"both":      `[{"at":"2026-01-01T00:00:00Z","afterSec":0,"file":"a.json"}]`,
+		"malformed": `[{"at":"tomorrow","file":"a.json"}]`,
+		"unsorted":  `[{"at":"2026-01-02T00:00:00Z","file":"a.json"},{"at":"2026-01-01T00:00:00Z","file":"b.json"}]`,
+	}
+	for name, timeline := range cases {
+		t.Run(name, func(t *testing.T) {
+			p := filepath.Join(t.TempDir(), "scenario.json")
+			writeF(t, p, `{"version":1,"mode":"time","key":{"pathParam":"id"},"timeline":`+timeline+`}`)
+			if _, err := LoadScenario(p); err == nil {
+				t.Fatalf("expected error")
+			}
+		})
+	}
+
+	p := filepath.Join(t.TempDir(), "scenario.json")
+	writeF(t, p, `{"version":1,"mode":"time","key":{"pathParam":"id"},
+		"timeline":[{"at":"2026-01-01T00:00:00Z","file":"a.json"}],"behavior":{"loop":true}}`)
+	if _, err := LoadScenario(p); err == nil {
+		t.Fatalf("expected loop to be rejected")
+	}
+}
+
 func TestLoadScenario_Step_RequiresNonEmptySequence(t *testing.T) {
 	dir := t.TempDir()
 	p := filepath.Join(dir, "scenario.json")
@@ -445,17 +630,17 @@ func TestScenarioResolver_Step_WhenPastEnd_ClampsToLastEvenWithoutRepeatLast(t *
 	sc.Behavior.RepeatLast = false
 	sc.Behavior.Loop = false
 
-	f1, _, err := e.ResolveScenarioFile(sc, "GET", "/api/v1/items/{id}", "/api/v1/items/1")
+	f1, _, err := e.ResolveScenarioFile(sc, "GET", "/api/v1/items/{id}", "/api/v1/items/1", nil, "")
 	if err != nil {
 		t.Fatalf("unexpected err: %v", err)
 	}
 
-	f2, _, err := e.ResolveScenarioFile(sc, "GET", "/api/v1/items/{id}", "/api/v1/items/1")
+	f2, _, err := e.ResolveScenarioFile(sc, "GET", "/api/v1/items/{id}", "/api/v1/items/1", nil, "")
 	if err != nil {
 		t.Fatalf("unexpected err: %v", err)
 	}
 
-	f3, _, err := e.ResolveScenarioFile(sc, "GET", "/api/v1/items/{id}", "/api/v1/items/1")
+	f3, _, err := e.ResolveScenarioFile(sc, "GET", "/api/v1/items/{id}", "/api/v1/items/1", nil, "")
 	if err != nil {
 		t.Fatalf("unexpected err: %v", err)
 	}
@@ -477,8 +662,8 @@ func TestScenarioResolver_Step_AdvanceOn_MethodMatchIsCaseInsensitive(t *testing
 	sc.Behavior.AdvanceOn = []MatchRule{{Method: "get"}} // lowercase
 	sc.Behavior.RepeatLast = true
 
-	f1, _, _ := e.ResolveScenarioFile(sc, "GET", "/api/v1/items/{id}", "/api/v1/items/1")
-	f2, _, _ := e.ResolveScenarioFile(sc, "GET", "/api/v1/items/{id}", "/api/v1/items/1")
+	f1, _, _ := e.ResolveScenarioFile(sc, "GET", "/api/v1/items/{id}", "/api/v1/items/1", nil, "")
+	f2, _, _ := e.ResolveScenarioFile(sc, "GET", "/api/v1/items/{id}", "/api/v1/items/1", nil, "")
 
 	if f1 != "a.json" || f2 != "b.json" {
 		t.Fatalf("expected a then b, got %q then %q", f1, f2)
@@ -498,18 +683,18 @@ func TestScenarioResolver_ResetOn_PathMismatch_DoesNotReset(t *testing.T) {
 	sc.Behavior.ResetOn = []MatchRule{{Method: "POST", Path: "/api/v1/other/{id}"}}
 	sc.Behavior.RepeatLast = true
 
-	_, _, _ = e.ResolveScenarioFile(sc, "GET", "/api/v1/items/{id}", "/api/v1/items/1")
-	f2, _, _ := e.ResolveScenarioFile(sc, "GET", "/api/v1/items/{id}", "/api/v1/items/1")
+	_, _, _ = e.ResolveScenarioFile(sc, "GET", "/api/v1/items/{id}", "/api/v1/items/1", nil, "")
+	f2, _, _ := e.ResolveScenarioFile(sc, "GET", "/api/v1/items/{id}", "/api/v1/items/1", nil, "")
 	if f2 != "b.json" {
 		t.Fatalf("expected b.json after advancing, got %q", f2)
 	}
 
-	_, _, err := e.ResolveScenarioFile(sc, "POST", "/api/v1/items/{id}", "/api/v1/items/1")
+	_, _, err := e.ResolveScenarioFile(sc, "POST", "/api/v1/items/{id}", "/api/v1/items/1", nil, "")
 	if err != nil {
 		t.Fatalf("unexpected err: %v", err)
 	}
 
-	fAfter, _, _ := e.ResolveScenarioFile(sc, "GET", "/api/v1/items/{id}", "/api/v1/items/1")
+	fAfter, _, _ := e.ResolveScenarioFile(sc, "GET", "/api/v1/items/{id}", "/api/v1/items/1", nil, "")
 	if fAfter != "b.json" {
 		t.Fatalf("expected still b.json (no reset), got %q", fAfter)
 	}
@@ -527,24 +712,24 @@ func TestScenarioResolver_Time_Loop_Wraps(t *testing.T) {
 	sc.Behavior.Loop = true
 	sc.Behavior.RepeatLast = false
 
-	f1, s1, err := e.ResolveScenarioFile(sc, "GET", "/api/v1/items/{id}", "/api/v1/items/5")
+	f1, s1, err := e.ResolveScenarioFile(sc, "GET", "/api/v1/items/{id}", "/api/v1/items/5", nil, "")
 	if err != nil {
 		t.Fatalf("unexpected err: %v", err)
 	}
-	if f1 != "t0.json" || s1 != "t0" {
-		t.Fatalf("expected t0 first, got %q/%q", f1, s1)
+	if f1 != "t0.json" || s1.State != "t0" {
+		t.Fatalf("expected t0 first, got %q/%q", f1, s1.State)
 	}
 
 	time.Sleep(1100 * time.Millisecond)
-	f2, s2, _ := e.ResolveScenarioFile(sc, "GET", "/api/v1/items/{id}", "/api/v1/items/5")
-	if f2 != "t1.json" || s2 != "t1" {
-		t.Fatalf("expected t1 after ~1s, got %q/%q", f2, s2)
+	f2, s2, _ := e.ResolveScenarioFile(sc, "GET", "/api/v1/items/{id}", "/api/v1/items/5", nil, "")
+	if f2 != "t1.json" || s2.State != "t1" {
+		t.Fatalf("expected t1 after ~1s, got %q/%q", f2, s2.State)
 	}
 
 	time.Sleep(1200 * time.Millisecond)
-	f3, s3, _ := e.ResolveScenarioFile(sc, "GET", "/api/v1/items/{id}", "/api/v1/items/5")
-	if f3 != "t0.json" || s3 != "t0" {
-		t.Fatalf("expected wrap to t0, got %q/%q", f3, s3)
+	f3, s3, _ := e.ResolveScenarioFile(sc, "GET", "/api/v1/items/{id}", "/api/v1/items/5", nil, "")
+	if f3 != "t0.json" || s3.State != "t0" {
+		t.Fatalf("expected wrap to t0, got %q/%q", f3, s3.State)
 	}
 }
 
@@ -559,46 +744,20 @@ func TestScenarioResolver_Time_StateIsolation_ByID(t *testing.T) {
 	}
 	sc.Behavior.RepeatLast = true
 
-	_, _, _ = e.ResolveScenarioFile(sc, "GET", "/api/v1/items/{id}", "/api/v1/items/1")
+	_, _, _ = e.ResolveScenarioFile(sc, "GET", "/api/v1/items/{id}", "/api/v1/items/1", nil, "")
 	time.Sleep(1100 * time.Millisecond)
 
-	f1, _, _ := e.ResolveScenarioFile(sc, "GET", "/api/v1/items/{id}", "/api/v1/items/1")
+	f1, _, _ := e.ResolveScenarioFile(sc, "GET", "/api/v1/items/{id}", "/api/v1/items/1", nil, "")
 	if f1 != "t1.json" {
 		t.Fatalf("expected id=1 to be t1.json, got %q", f1)
 	}
 
-	f2, _, _ := e.ResolveScenarioFile(sc, "GET", "/api/v1/items/{id}", "/api/v1/items/2")
+	f2, _, _ := e.ResolveScenarioFile(sc, "GET", "/api/v1/items/{id}", "/api/v1/items/2", nil, "")
 	if f2 != "t0.json" {
 		t.Fatalf("expected id=2 to start at t0.json, got %q", f2)
 	}
 }
 
-func TestMatchTemplatePathSuffix(t *testing.T) {
-	cases := []struct {
-		tpl   string
-		act   string
-		match bool
-	}{
-		{"/scans/{id}", "/scans/123", true},
-		{"/scans/{id}", "/api/v1/scans/123", true},
-		{"/scans/{id}/status", "/scans/123/status", true},
-		{"/scans/{id}/status", "/x/y/scans/123/status", true},
-		{"/scans/{id}", "/scans", false},
-		{"/scans/{id}", "/scans/123/status", false},
-		{"/scans/{id}/status", "/scans/123/results", false},
-	}
-
-	for _, tc := range cases {
-		tc := tc
-		t.Run(tc.tpl+"->"+tc.act, func(t *testing.T) {
-			got := matchTemplatePathSuffix(tc.tpl, tc.act)
-			if got != tc.match {
-				t.Fatalf("expected %v, got %v", tc.match, got)
-			}
-		})
-	}
-}
-
 func TestScenarioResolver_RegistersResetRules_OnFirstResolve(t *testing.T) {
 	e := NewScenarioResolver()
 
@@ -609,7 +768,7 @@ func TestScenarioResolver_RegistersResetRules_OnFirstResolve(t *testing.T) {
 		{Method: "DELETE", Path: "/scans/{id}"},
 	}
 
-	_, _, err := e.ResolveScenarioFile(sc, "GET", "/scans/{id}", "/scans/1")
+	_, _, err := e.ResolveScenarioFile(sc, "GET", "/scans/{id}", "/scans/1", nil, "")
 	if err != nil {
 		t.Fatalf("unexpected err: %v", err)
 	}
@@ -641,23 +800,53 @@ func TestScenarioResolver_TryResetByRequest_ResetsRunningScenario(t *testing.T)
 	sc.Behavior.ResetOn = []MatchRule{{Method: "DELETE", Path: "/scans/{id}"}}
 	sc.Behavior.RepeatLast = true
 
-	_, _, _ = e.ResolveScenarioFile(sc, "GET", "/scans/{id}", "/scans/1")
-	f2, _, _ := e.ResolveScenarioFile(sc, "GET", "/scans/{id}", "/scans/1")
+	_, _, _ = e.ResolveScenarioFile(sc, "GET", "/scans/{id}", "/scans/1", nil, "")
+	f2, _, _ := e.ResolveScenarioFile(sc, "GET", "/scans/{id}", "/scans/1", nil, "")
 	if f2 != "b.json" {
 		t.Fatalf("expected b.json after advancing, got %q", f2)
 	}
 
-	reset := e.TryResetByRequest("DELETE", "/scans/1")
+	reset := e.TryResetByRequest("DELETE", "/scans/1", "")
 	if !reset {
 		t.Fatalf("expected reset=true")
 	}
 
-	fAfter, _, _ := e.ResolveScenarioFile(sc, "GET", "/scans/{id}", "/scans/1")
+	fAfter, _, _ := e.ResolveScenarioFile(sc, "GET", "/scans/{id}", "/scans/1", nil, "")
 	if fAfter != "a.json" {
 		t.Fatalf("expected a.json after reset, got %q", fAfter)
 	}
 }
 
+func TestScenarioResolver_ClientIsolation(t *testing.T) {
+	e := NewScenarioResolver()
+
+	sc := &Scenario{Version: 1, Mode: "step"}
+	sc.Key.PathParam = "id"
+	sc.Sequence = []ScenarioEntry{
+		{State: "s1", File: "a.json"},
+		{State: "s2", File: "b.json"},
+	}
+	sc.Behavior.AdvanceOn = []MatchRule{{Method: "GET"}}
+	sc.Behavior.ResetOn = []MatchRule{{Method: "DELETE", Path: "/scans/{id}"}}
+	sc.Behavior.RepeatLast = true
+
+	_, _, _ = e.ResolveScenarioFile(sc, "GET", "/scans/{id}", "/scans/1", nil, "ci-a")
+	fa, _, _ := e.ResolveScenarioFile(sc, "GET", "/scans/{id}", "/scans/1", nil, "ci-a")
+	fb, _, _ := e.ResolveScenarioFile(sc, "GET", "/scans/{id}", "/scans/1", nil, "ci-b")
+	if fa != "b.json" || fb != "a.json" {
+		t.Fatalf("expected clients to advance independently, got %q and %q", fa, fb)
+	}
+
+	_, _, _ = e.ResolveScenarioFile(sc, "GET", "/scans/{id}", "/scans/1", nil, "ci-b")
+	_ = e.TryResetByRequest("DELETE", "/scans/1", "ci-a")
+
+	fa, _, _ = e.ResolveScenarioFile(sc, "GET", "/scans/{id}", "/scans/1", nil, "ci-a")
+	fb, _, _ = e.ResolveScenarioFile(sc, "GET", "/scans/{id}", "/scans/1", nil, "ci-b")
+	if fa != "a.json" || fb != "b.json" {
+		t.Fatalf("expected reset to affect only ci-a, got %q and %q", fa, fb)
+	}
+}
+
 func TestScenarioResolver_TryResetByRequest_WrongMethod_DoesNotReset(t *testing.T) {
 	e := NewScenarioResolver()
 
@@ -671,15 +860,15 @@ func TestScenarioResolver_TryResetByRequest_WrongMethod_DoesNotReset(t *testing.
 	sc.Behavior.ResetOn = []MatchRule{{Method: "DELETE", Path: "/scans/{id}"}}
 	sc.Behavior.RepeatLast = true
 
-	_, _, _ = e.ResolveScenarioFile(sc, "GET", "/scans/{id}", "/scans/1")
-	_, _, _ = e.ResolveScenarioFile(sc, "GET", "/scans/{id}", "/scans/1") // now at b
+	_, _, _ = e.ResolveScenarioFile(sc, "GET", "/scans/{id}", "/scans/1", nil, "")
+	_, _, _ = e.ResolveScenarioFile(sc, "GET", "/scans/{id}", "/scans/1", nil, "") // now at b
 
-	reset := e.TryResetByRequest("POST", "/scans/1")
+	reset := e.TryResetByRequest("POST", "/scans/1", "")
 	if reset {
 		t.Fatalf("expected reset=false")
 	}
 
-	fAfter, _, _ := e.ResolveScenarioFile(sc, "GET", "/scans/{id}", "/scans/1")
+	fAfter, _, _ := e.ResolveScenarioFile(sc, "GET", "/scans/{id}", "/scans/1", nil, "")
 	if fAfter != "b.json" {
 		t.Fatalf("expected still b.json (no reset), got %q", fAfter)
 	}
@@ -698,15 +887,15 @@ func TestScenarioResolver_TryResetByRequest_PathMismatch_DoesNotReset(t *testing
 	sc.Behavior.ResetOn = []MatchRule{{Method: "DELETE", Path: "/scans/{id}"}}
 	sc.Behavior.RepeatLast = true
 
-	_, _, _ = e.ResolveScenarioFile(sc, "GET", "/scans/{id}", "/scans/1")
-	_, _, _ = e.ResolveScenarioFile(sc, "GET", "/scans/{id}", "/scans/1") // now at b
+	_, _, _ = e.ResolveScenarioFile(sc, "GET", "/scans/{id}", "/scans/1", nil, "")
+	_, _, _ = e.ResolveScenarioFile(sc, "GET", "/scans/{id}", "/scans/1", nil, "") // now at b
 
-	reset := e.TryResetByRequest("DELETE", "/other/1")
+	reset := e.TryResetByRequest("DELETE", "/other/1", "")
 	if reset {
 		t.Fatalf("expected reset=false")
 	}
 
-	fAfter, _, _ := e.ResolveScenarioFile(sc, "GET", "/scans/{id}", "/scans/1")
+	fAfter, _, _ := e.ResolveScenarioFile(sc, "GET", "/scans/{id}", "/scans/1", nil, "")
 	if fAfter != "b.json" {
 		t.Fatalf("expected still b.json (no reset), got %q", fAfter)
 	}
@@ -726,23 +915,52 @@ func TestScenarioResolver_TryResetByRequest_ResetsScenarioRegisteredFromDifferen
 	sc.Behavior.ResetOn = []MatchRule{{Method: "DELETE", Path: "/scans/{id}"}}
 	sc.Behavior.RepeatLast = true
 
-	_, _, _ = e.ResolveScenarioFile(sc, "GET", "/scans/{id}/status", "/scans/1/status")
-	f2, _, _ := e.ResolveScenarioFile(sc, "GET", "/scans/{id}/status", "/scans/1/status")
+	_, _, _ = e.ResolveScenarioFile(sc, "GET", "/scans/{id}/status", "/scans/1/status", nil, "")
+	f2, _, _ := e.ResolveScenarioFile(sc, "GET", "/scans/{id}/status", "/scans/1/status", nil, "")
 	if f2 != "b.json" {
 		t.Fatalf("expected b.json after advancing, got %q", f2)
 	}
 
-	reset := e.TryResetByRequest("DELETE", "/scans/1")
+	reset := e.TryResetByRequest("DELETE", "/scans/1", "")
 	if !reset {
 		t.Fatalf("expected reset=true")
 	}
 
-	fAfter, _, _ := e.ResolveScenarioFile(sc, "GET", "/scans/{id}/status", "/scans/1/status")
+	fAfter, _, _ := e.ResolveScenarioFile(sc, "GET", "/scans/{id}/status", "/scans/1/status", nil, "")
 	if fAfter != "a.json" {
 		t.Fatalf("expected a.json after reset, got %q", fAfter)
 	}
 }
 
+func TestScenarioResolver_TryResetByRequest_KeyAlias(t *testing.T) {
+	e := NewScenarioResolver()
+
+	sc := &Scenario{Version: 1, Mode: "step"}
+	sc.Key = ScenarioKey{PathParam: "id", Aliases: []string{"scanId"}}
+	sc.Sequence = []ScenarioEntry{
+		{State: "s1", File: "a.json"},
+		{State: "s2", File: "b.json"},
+	}
+	sc.Behavior.AdvanceOn = []MatchRule{{Method: "GET"}}
+	sc.Behavior.ResetOn = []MatchRule{{Method: "POST", Path: "/tasks/{scanId}/restart"}}
+
+	_, _, _ = e.ResolveScenarioFile(sc, "GET", "/scans/{id}/status", "/scans/1/status", nil, "")
+
+	_ = e.TryResetByRequest("POST", "/tasks/2/restart", "")
+	f, _, _ := e.ResolveScenarioFile(sc, "GET", "/scans/{id}/status", "/scans/1/status", nil, "")
+	if f != "b.json" {
+		t.Fatalf("reset of another key must not touch scan 1, got %q", f)
+	}
+
+	if !e.TryResetByRequest("POST", "/tasks/1/restart", "") {
+		t.Fatalf("expected reset via alias {scanId}")
+	}
+	f, _, _ = e.ResolveScenarioFile(sc, "GET", "/scans/{id}/status", "/scans/1/status", nil, "")
+	if f != "a.json" {
+		t.Fatalf("expected a.json after reset, got %q", f)
+	}
+}
+
 func writeF(t *testing.T, path string, content string) {
 	t.Helper()
 	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
@@ -752,3 +970,303 @@ func writeF(t *testing.T, path string, content string) {
 		t.Fatalf("write %s: %v", path, err)
 	}
 }
+
+func TestScenarioResolver_Time_ExposesProgress(t *testing.T) {
+	e := NewScenarioResolver().(*ScenarioResolver)
+	sc := &Scenario{Version: 1, Mode: "time"}
+	sc.Key.PathParam = "id"
+	sc.Timeline = []TimelineEntry{
+		{AfterSec: 0, State: "running", File: "progress.json"},
+		{AfterSec: 60, State: "done", File: "progress.json"},
+	}
+	sc.Behavior.RepeatLast = true
+
+	_ = e.store.SetStart(scenarioRuntimeKey("/jobs/{id}", "1"), time.Now().Add(-15*time.Second))
+
+	_, st, err := e.ResolveScenarioFile(sc, "GET", "/jobs/{id}", "/jobs/1", nil, "")
+	if err != nil {
+		t.Fatalf("ResolveScenarioFile: %v", err)
+	}
+	if st.Mode != "time" || st.State != "running" || st.Step != 0 {
+		t.Fatalf("unexpected state: %+v", st)
+	}
+	if st.Elapsed != 15 || st.Total != 60 || st.Percent != 25 {
+		t.Fatalf("expected 15/60 = 25%%, got %+v", st)
+	}
+
+	_ = e.store.SetStart(scenarioRuntimeKey("/jobs/{id}", "1"), time.Now().Add(-90*time.Second))
+
+	_, st, _ = e.ResolveScenarioFile(sc, "GET", "/jobs/{id}", "/jobs/1", nil, "")
+	if st.State != "done" || st.Step != 1 || st.Elapsed != 60 || st.Percent != 100 {
+		t.Fatalf("expected clamped completion, got %+v", st)
+	}
+}
+
+func TestProgressPercent(t *testing.T) {
+	cases := []struct {
+		elapsed float64
+		total   int64
+		want    int
+	}{
+		{0, 10, 0},
+		{2.5, 10, 25},
+		{10, 10, 100},
+		{12, 10, 100},
+		{0, 0, 100},
+	}
+	for _, tc := range cases {
+		if got := progressPercent(tc.elapsed, tc.total); got != tc.want {
+			t.Fatalf("progressPercent(%v, %d) = %d, want %d", tc.elapsed, tc.total, got, tc.want)
+		}
+	}
+}
+
+func TestScenarioEntryByState_Time(t *testing.T) {
+	sc := &Scenario{Version: 1, Mode: "time"}
+	sc.Timeline = []TimelineEntry{
+		{AfterSec: 0, State: "queued", File: "queued.json"},
+		{AfterSec: 30, State: "running", File: "running.json"},
+		{AfterSec: 120, State: "done", File: "done.json"},
+	}
+
+	file, st, err := ScenarioEntryByState(sc, "running")
+	if err != nil {
+		t.Fatalf("ScenarioEntryByState: %v", err)
+	}
+	if file != "running.json" || st.Step != 1 || st.Elapsed != 30 || st.Total != 120 || st.Percent != 25 {
+		t.Fatalf("unexpected entry: file=%s state=%+v", file, st)
+	}
+
+	if _, _, err := ScenarioEntryByState(sc, "failed"); err == nil {
+		t.Fatalf("expected error for unknown state")
+	}
+}
+
+func TestScenarioResolver_PeekScenarioState_DoesNotAdvance(t *testing.T) {
+	e := NewScenarioResolver()
+	sc := &Scenario{Version: 1, Mode: "step"}
+	sc.Key.PathParam = "id"
+	sc.Sequence = []ScenarioEntry{
+		{State: "s1", File: "a.json"},
+		{State: "s2", File: "b.json"},
+	}
+	sc.Behavior.AdvanceOn = []MatchRule{{Method: "GET"}}
+
+	for range 2 {
+		st, err := e.PeekScenarioState(sc, "GET", "/scans/{id}", "/scans/1", nil, "")
+		if err != nil || st.State != "s1" {
+			t.Fatalf("expected s1, got %+v (err=%v)", st, err)
+		}
+	}
+
+	_, _, _ = e.ResolveScenarioFile(sc, "GET", "/scans/{id}", "/scans/1", nil, "")
+	if st, _ := e.PeekScenarioState(sc, "GET", "/scans/{id}", "/scans/1", nil, ""); st.State != "s2" || st.Step != 1 {
+		t.Fatalf("expected s2 after advancing, got %+v", st)
+	}
+}
+
+func TestScenarioResolver_SetScenarioState_Time(t *testing.T) {
+	e := NewScenarioResolver()
+
+	sc := &Scenario{Version: 1, Mode: "time"}
+	sc.Key.PathParam = "id"
+	sc.Timeline = []TimelineEntry{
+		{AfterSec: 0, State: "queued", File: "queued.json"},
+		{AfterSec: 60, State: "done", File: "done.json"},
+	}
+
+	if err := e.SetScenarioState(sc, "/items/{id}", "5", "done"); err != nil {
+		t.Fatalf("SetScenarioState: %v", err)
+	}
+	file, state, err := e.ResolveScenarioFile(sc, "GET", "/items/{id}", "/items/5", nil, "")
+	if err != nil {
+		t.Fatalf("ResolveScenarioFile: %v", err)
+	}
+	if file != "done.json" || state.Elapsed != 60 {
+		t.Fatalf("expected done.json after 60s, got %q/%+v", file, state)
+	}
+
+	if err := e.SetScenarioState(sc, "/items/{id}", "5", "missing"); err == nil {
+		t.Fatalf("expected error for unknown state")
+	}
+}
+
+func TestScenarioResolver_Step_ConcurrentPollers_SharedStore_EachEntryOnce(t *testing.T) {
+	const n = 20
+	sc := &Scenario{Version: 1, Mode: "step"}
+	sc.Key.PathParam = "id"
+	for i := 0; i < n; i++ {
+		sc.Sequence = append(sc.Sequence, ScenarioEntry{State: fmt.Sprintf("s%d", i), File: "GET.json"})
+	}
+	sc.Behavior.AdvanceOn = []MatchRule{{Method: "GET"}}
+
+	// Two resolvers share one store, like replicas sharing a state server.
+	store := newMemoryScenarioStore()
+	resolvers := []IScenarioResolver{
+		NewScenarioResolver(WithScenarioStore(store)),
+		NewScenarioResolver(WithScenarioStore(store)),
+	}
+
+	var mu sync.Mutex
+	seen := map[string]int{}
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(r IScenarioResolver) {
+			defer wg.Done()
+			_, st, err := r.ResolveScenarioFile(sc, "GET", "/scans/{id}", "/scans/1", nil, "")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			mu.Lock()
+			seen[st.State]++
+			mu.Unlock()
+		}(resolvers[i%2])
+	}
+	wg.Wait()
+
+	if len(seen) != n {
+		t.Fatalf("expected each of %d entries once, got %v", n, seen)
+	}
+}
+
+// blockingStore holds Step calls for one key until release is closed, like a
+// slow round-trip to a shared store.
+type blockingStore struct {
+	*memoryScenarioStore
+	key     string
+	entered chan struct{}
+	release chan struct{}
+}
+
+func (b *blockingStore) Step(key string) (int, error) {
+	if key == b.key {
+		close(b.entered)
+		<-b.release
+	}
+	return b.memoryScenarioStore.Step(key)
+}
+
+func TestScenarioResolver_Step_SlowStoreDoesNotBlockOtherKeys(t *testing.T) {
+	sc := &Scenario{Version: 1, Mode: "step", Sequence: []ScenarioEntry{{State: "a", File: "a.json"}}}
+	sc.Key.PathParam = "id"
+
+	slow, fast := scenarioRuntimeKey("/scans/{id}", "1"), scenarioRuntimeKey("/scans/{id}", "2")
+	if stepLockStripe(slow) == stepLockStripe(fast) {
+		t.Fatalf("test keys share a lock stripe; pick other ids")
+	}
+	store := &blockingStore{
+		memoryScenarioStore: newMemoryScenarioStore(),
+		key:                 slow,
+		entered:             make(chan struct{}),
+		release:             make(chan struct{}),
+	}
+	e := NewScenarioResolver(WithScenarioStore(store))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _, _ = e.ResolveScenarioFile(sc, "GET", "/scans/{id}", "/scans/1", nil, "")
+	}()
+	<-store.entered
+
+	resolved := make(chan error, 1)
+	go func() {
+		_, _, err := e.ResolveScenarioFile(sc, "GET", "/scans/{id}", "/scans/2", nil, "")
+		resolved <- err
+	}()
+	select {
+	case err := <-resolved:
+		if err != nil {
+			t.Fatalf("resolve: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("a slow store call for one key blocked another key")
+	}
+
+	close(store.release)
+	<-done
+}
+
+func TestScenarioResolver_Step_AdvanceOnResponse(t *testing.T) {
+	e := NewScenarioResolver()
+
+	sc := &Scenario{Version: 1, Mode: "step"}
+	sc.Key.PathParam = "id"
+	sc.Sequence = []ScenarioEntry{
+		{State: "s1", File: "a.json"},
+		{State: "s2", File: "b.json"},
+		{State: "s3", File: "c.json"},
+	}
+	sc.Behavior.AdvanceOn = []MatchRule{{Method: "GET"}}
+	sc.Behavior.Advance = "response"
+
+	resolve := func() *Response {
+		_, st, err := e.ResolveScenarioFile(sc, "GET", "/scans/{id}", "/scans/1", nil, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &Response{Scenario: &st}
+	}
+
+	// Concurrent pollers read the same entry before any response is
+	// written; committing all of them advances the scenario once.
+	var polls []*Response
+	for i := 0; i < 3; i++ {
+		polls = append(polls, resolve())
+	}
+	for _, p := range polls {
+		if p.Scenario.State != "s1" {
+			t.Fatalf("expected s1 before any commit, got %q", p.Scenario.State)
+		}
+		if err := p.CommitScenario(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	next := resolve()
+	if next.Scenario.State != "s2" {
+		t.Fatalf("expected s2 after the commits, got %q", next.Scenario.State)
+	}
+
+	// An uncommitted response, e.g. a failed write, does not advance.
+	if again := resolve(); again.Scenario.State != "s2" {
+		t.Fatalf("expected s2 without commit, got %q", again.Scenario.State)
+	}
+}
+
+func TestScenarioResolver_ResetScenarios_StartsOver(t *testing.T) {
+	e := NewScenarioResolver()
+
+	sc := &Scenario{Version: 1, Mode: "step"}
+	sc.Key.PathParam = "id"
+	sc.Sequence = []ScenarioEntry{
+		{State: "s1", File: "a.json"},
+		{State: "s2", File: "b.json"},
+	}
+	sc.Behavior.AdvanceOn = []MatchRule{{Method: "GET"}}
+	sc.Behavior.ResetOn = []MatchRule{{Method: "DELETE", Path: "/api/v1/items/{id}"}}
+
+	for _, id := range []string{"1", "2"} {
+		if _, _, err := e.ResolveScenarioFile(sc, "GET", "/api/v1/items/{id}", "/api/v1/items/"+id, nil, ""); err != nil {
+			t.Fatalf("unexpected err: %v", err)
+		}
+	}
+
+	if n := e.ResetScenarios(); n != 2 {
+		t.Fatalf("expected 2 keys reset, got %d", n)
+	}
+	// resetOn rules are registered again on the next resolve only.
+	if e.TryResetByRequest("DELETE", "/api/v1/items/1", "") {
+		t.Fatalf("expected cached resetOn rules to be dropped")
+	}
+
+	f, _, err := e.ResolveScenarioFile(sc, "GET", "/api/v1/items/{id}", "/api/v1/items/1", nil, "")
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if f != "a.json" {
+		t.Fatalf("expected the first entry after reset, got %q", f)
+	}
+}
@@ -0,0 +1,101 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package samples
+
+import (
+	"sync"
+	"time"
+)
+
+// IScenarioStore keeps the runtime state of scenario keys: the step index of
+// step scenarios and the start time of time scenarios. Keys are runtime keys
+// as built by the resolver, already scoped to a client where needed.
+type IScenarioStore interface {
+	// Step returns the stored step index of key, 0 when none is stored.
+	Step(key string) (int, error)
+	SetStep(key string, idx int) error
+	// CompareAndSetStep stores idx only if the stored step index is still
+	// old, atomically also for replicas sharing the store. It reports
+	// whether idx was stored.
+	CompareAndSetStep(key string, old, idx int) (bool, error)
+	// Start returns the start time of key, storing now first when none is
+	// stored.
+	Start(key string, now time.Time) (time.Time, error)
+	SetStart(key string, t time.Time) error
+	// Delete drops the step index and start time of key.
+	Delete(key string) error
+}
+
+// ResolverOption customizes a ScenarioResolver created by NewScenarioResolver.
+type ResolverOption func(*ScenarioResolver)
+
+// WithScenarioStore keeps scenario state in store instead of process memory,
+// so replicas sharing the store present the same progress.
+func WithScenarioStore(store IScenarioStore) ResolverOption {
+	return func(e *ScenarioResolver) { e.store = store }
+}
+
+// memoryScenarioStore is the default, per-process store.
+type memoryScenarioStore struct {
+	mu        sync.Mutex
+	stepIndex map[string]int
+	startedAt map[string]time.Time
+}
+
+func newMemoryScenarioStore() *memoryScenarioStore {
+	return &memoryScenarioStore{
+		stepIndex: map[string]int{},
+		startedAt: map[string]time.Time{},
+	}
+}
+
+func (m *memoryScenarioStore) Step(key string) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.stepIndex[key], nil
+}
+
+func (m *memoryScenarioStore) SetStep(key string, idx int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.stepIndex[key] = idx
+	return nil
+}
+
+func (m *memoryScenarioStore) CompareAndSetStep(key string, old, idx int) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.stepIndex[key] != old {
+		return false, nil
+	}
+	m.stepIndex[key] = idx
+	return true, nil
+}
+
+func (m *memoryScenarioStore) Start(key string, now time.Time) (time.Time, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	t0, ok := m.startedAt[key]
+	if !ok {
+		t0 = now
+		m.startedAt[key] = t0
+	}
+	return t0, nil
+}
+
+func (m *memoryScenarioStore) SetStart(key string, t time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.startedAt[key] = t
+	return nil
+}
+
+func (m *memoryScenarioStore) Delete(key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.stepIndex, key)
+	delete(m.startedAt, key)
+	return nil
+}
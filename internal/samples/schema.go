@@ -0,0 +1,66 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package samples
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// EnvelopeVersion is the newest sample envelope version understood by the emulator.
+const EnvelopeVersion = 1
+
+//go:embed schema/*.json
+var schemaFS embed.FS
+
+var (
+	schemaOnce     sync.Once
+	envelopeSchema *openapi3.Schema
+	scenarioSchema *openapi3.Schema
+	schemaErr      error
+)
+
+func loadSchemas() error {
+	schemaOnce.Do(func() {
+		envelopeSchema, schemaErr = readSchema("schema/envelope.json")
+		if schemaErr != nil {
+			return
+		}
+		scenarioSchema, schemaErr = readSchema("schema/scenario.json")
+	})
+	return schemaErr
+}
+
+func readSchema(name string) (*openapi3.Schema, error) {
+	b, err := schemaFS.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", name, err)
+	}
+	var s openapi3.Schema
+	if err := json.Unmarshal(b, &s); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", name, err)
+	}
+	return &s, nil
+}
+
+// validateEnvelope checks a decoded versioned envelope against schema/envelope.json.
+func validateEnvelope(v any) error {
+	if err := loadSchemas(); err != nil {
+		return err
+	}
+	return envelopeSchema.VisitJSON(v, openapi3.MultiErrors())
+}
+
+// validateScenario checks a decoded scenario file against schema/scenario.json.
+func validateScenario(v any) error {
+	if err := loadSchemas(); err != nil {
+		return err
+	}
+	return scenarioSchema.VisitJSON(v, openapi3.MultiErrors())
+}
@@ -0,0 +1,65 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package samples
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// statusSuffix matches the status part of a sample file name: GET.404.json
+// is the 404 response of GET.json.
+var statusSuffix = regexp.MustCompile(`\.([1-5][0-9][0-9])(\.[^.]+)$`)
+
+// statusBase splits a status-suffixed sample name into the name of the
+// sample it belongs to and the status.
+func statusBase(name string) (string, int, bool) {
+	m := statusSuffix.FindStringSubmatchIndex(name)
+	if m == nil {
+		return "", 0, false
+	}
+	status, _ := strconv.Atoi(name[m[2]:m[3]])
+	return name[:m[0]] + name[m[4]:m[5]], status, true
+}
+
+// statusSamplePath returns the path of the status sample of the sample at
+// path, e.g. items/GET.404.json for items/GET.json.
+func statusSamplePath(path string, status int) string {
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "." + strconv.Itoa(status) + ext
+}
+
+// fileStatus is the status a sample file serves unless its envelope sets
+// one: the status of its name suffix, else 200.
+func fileStatus(path string) int {
+	if _, status, ok := statusBase(filepath.Base(path)); ok {
+		return status
+	}
+	return 200
+}
+
+// statusSamples lists the status samples of the sample at path, sorted by
+// name.
+func statusSamples(path string) []string {
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		return nil
+	}
+	want := filepath.Base(path)
+
+	var out []string
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		if base, _, ok := statusBase(e.Name()); ok && base == want {
+			out = append(out, filepath.Join(filepath.Dir(path), e.Name()))
+		}
+	}
+	return out
+}
@@ -0,0 +1,90 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package samples
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/ozgen/openapi-emulator/config"
+	"github.com/ozgen/openapi-emulator/logger"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatusBase(t *testing.T) {
+	base, status, ok := statusBase("GET.404.json")
+	require.True(t, ok)
+	require.Equal(t, "GET.json", base)
+	require.Equal(t, 404, status)
+
+	base, _, ok = statusBase("GET__items_{id}.500.json")
+	require.True(t, ok)
+	require.Equal(t, "GET__items_{id}.json", base)
+
+	for _, name := range []string{"GET.json", "GET.queued.json", "GET.600.json", "GET.40.json"} {
+		_, _, ok := statusBase(name)
+		require.False(t, ok, name)
+	}
+}
+
+func TestLoadFile_StatusFromFileName(t *testing.T) {
+	dir := t.TempDir()
+	plain := writeFile(t, dir, "GET.404.json", `{"error":"not found"}`)
+	env := writeFile(t, dir, "GET.500.json", `{"status":503,"body":{}}`)
+
+	resp, err := loadFile(plain, nil)
+	require.NoError(t, err)
+	require.Equal(t, 404, resp.Status)
+
+	resp, err = loadFile(env, nil)
+	require.NoError(t, err)
+	require.Equal(t, 503, resp.Status, "the envelope status wins")
+}
+
+func TestSampleProvider_ResolveAndLoad_PreferredStatus(t *testing.T) {
+	baseDir := t.TempDir()
+	dir := filepath.Join(baseDir, "scans", "{id}")
+	writeFile(t, dir, "GET.json", `{"id":"1"}`)
+	writeFile(t, dir, "GET.404.json", `{"error":"not found"}`)
+	writeFile(t, dir, "GET.404[header.X-Tenant=acme].json", `{"error":"no such scan for acme"}`)
+
+	p := NewSampleProvider(ProviderConfig{BaseDir: baseDir, Layout: config.LayoutFolders}, logger.GetLogger())
+
+	resp, err := p.ResolveAndLoad("GET", "/scans/{id}", "/scans/1", "", LoadOptions{Status: 404})
+	require.NoError(t, err)
+	require.Equal(t, 404, resp.Status)
+	require.JSONEq(t, `{"error":"not found"}`, string(resp.Body))
+
+	resp, err = p.ResolveAndLoad("GET", "/scans/{id}", "/scans/1", "", LoadOptions{
+		Status: 404,
+		Header: map[string]string{"X_Tenant": "acme"},
+	})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"error":"no such scan for acme"}`, string(resp.Body))
+
+	_, err = p.ResolveAndLoad("GET", "/scans/{id}", "/scans/1", "", LoadOptions{Status: 500})
+	require.ErrorContains(t, err, "no sample file for status 500")
+
+	resp, err = p.ResolveAndLoad("GET", "/scans/{id}", "/scans/1", "", LoadOptions{})
+	require.NoError(t, err)
+	require.Equal(t, 200, resp.Status)
+}
+
+func TestSampleProvider_SampleFiles_ListsStatusSamples(t *testing.T) {
+	baseDir := t.TempDir()
+	dir := filepath.Join(baseDir, "scans")
+	writeFile(t, dir, "GET.json", `{}`)
+	writeFile(t, dir, "GET.404.json", `{}`)
+	writeFile(t, dir, "POST.409.json", `{}`)
+
+	p := NewSampleProvider(ProviderConfig{BaseDir: baseDir, Layout: config.LayoutFolders}, logger.GetLogger())
+	files := p.SampleFiles("GET", "/scans", "", "")
+	require.Len(t, files, 2)
+	require.Equal(t, filepath.Join(dir, "GET.404.json"), files[1].Path)
+	require.Equal(t, 404, files[1].Response.Status)
+
+	files = p.SampleFiles("POST", "/scans", "", "")
+	require.Len(t, files, 1, "a status sample alone is listed")
+}
@@ -0,0 +1,38 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package samples
+
+import "time"
+
+// defaultStreamChunkSize is the chunk size of a stream that sets none.
+const defaultStreamChunkSize = 512
+
+// Stream is the envelope's "stream": the body is written in chunks of
+// ChunkSize bytes, flushed one by one with DelayMs between them, to emulate
+// slow or progressively rendered endpoints.
+type Stream struct {
+	ChunkSize int `json:"chunkSize,omitempty"`
+	DelayMs   int `json:"delayMs,omitempty"`
+}
+
+// Chunks splits body into the chunks written one by one. An empty body is
+// a single empty chunk.
+func (s *Stream) Chunks(body []byte) [][]byte {
+	size := s.ChunkSize
+	if size <= 0 {
+		size = defaultStreamChunkSize
+	}
+	out := make([][]byte, 0, len(body)/size+1)
+	for len(body) > size {
+		out = append(out, body[:size])
+		body = body[size:]
+	}
+	return append(out, body)
+}
+
+// Interval returns the pause between two chunks.
+func (s *Stream) Interval() time.Duration {
+	return time.Duration(max(s.DelayMs, 0)) * time.Millisecond
+}
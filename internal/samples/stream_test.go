@@ -0,0 +1,48 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package samples
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ozgen/openapi-emulator/config"
+	"github.com/ozgen/openapi-emulator/logger"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStream_Chunks(t *testing.T) {
+	s := &Stream{ChunkSize: 4, DelayMs: 20}
+	require.Equal(t, [][]byte{[]byte("abcd"), []byte("efgh"), []byte("ij")}, s.Chunks([]byte("abcdefghij")))
+	require.Equal(t, [][]byte{[]byte("abcd")}, s.Chunks([]byte("abcd")))
+	require.Equal(t, [][]byte{{}}, s.Chunks([]byte{}))
+	require.Equal(t, 20*time.Millisecond, s.Interval())
+
+	def := &Stream{}
+	require.Len(t, def.Chunks([]byte(strings.Repeat("x", 1025))), 3)
+	require.Zero(t, def.Interval())
+}
+
+func TestLoadSample_StreamFromEnvelope(t *testing.T) {
+	baseDir := t.TempDir()
+	writeFile(t, filepath.Join(baseDir, "logs"), "GET.json", `{
+	  "version": 1,
+	  "headers": {"Content-Type": "text/plain"},
+	  "stream": {"chunkSize": 16, "delayMs": 100},
+	  "body": "line 1"
+	}`)
+	p := NewSampleProvider(ProviderConfig{BaseDir: baseDir, Layout: config.LayoutFolders}, logger.GetLogger())
+
+	resp, err := p.ResolveAndLoad("GET", "/logs", "/logs", "", LoadOptions{})
+	require.NoError(t, err)
+	require.Equal(t, &Stream{ChunkSize: 16, DelayMs: 100}, resp.Stream)
+
+	writeFile(t, filepath.Join(baseDir, "logs"), "GET.json", `{"version": 1, "stream": {"chunkSize": 0}, "body": ""}`)
+	_, err = p.ResolveAndLoad("GET", "/logs", "/logs", "", LoadOptions{})
+	var envErr *SampleEnvelopeError
+	require.ErrorAs(t, err, &envErr)
+}
@@ -0,0 +1,66 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package samples
+
+import (
+	"bytes"
+	"text/template"
+)
+
+// TemplateData is the root object available to sample templates.
+type TemplateData struct {
+	// Scenario is nil for samples served outside a scenario.
+	Scenario *ScenarioState
+	Cookies  map[string]string
+	// Path holds the route's path parameters by name.
+	Path map[string]string
+	// Query holds the first value of every query parameter.
+	Query map[string]string
+	// Header holds the request headers by canonical name with "-" replaced
+	// by "_", so X-Tenant is reachable as .Header.X_Tenant.
+	Header map[string]string
+
+	body func() any
+}
+
+// Body is the decoded JSON request body, or nil. As a method it is only
+// evaluated, and the request body only read, when a template uses it.
+func (d *TemplateData) Body() any {
+	if d.body == nil {
+		return nil
+	}
+	return d.body()
+}
+
+// templateData builds the template root for one request.
+func templateData(state *ScenarioState, opts LoadOptions) *TemplateData {
+	return &TemplateData{
+		Scenario: state,
+		Cookies:  opts.Cookies,
+		Path:     opts.Path,
+		Query:    opts.Query,
+		Header:   opts.Header,
+		body:     opts.Body,
+	}
+}
+
+// renderTemplate executes raw as a text/template. Files without "{{" are
+// returned unchanged so plain samples never pay for parsing.
+func renderTemplate(path string, raw []byte, data *TemplateData) ([]byte, error) {
+	if !bytes.Contains(raw, []byte("{{")) {
+		return raw, nil
+	}
+
+	tpl, err := template.New(path).Funcs(templateFuncs).Option("missingkey=error").Parse(string(raw))
+	if err != nil {
+		return nil, &SampleTemplateError{Path: path, Err: err}
+	}
+
+	var out bytes.Buffer
+	if err := tpl.Execute(&out, data); err != nil {
+		return nil, &SampleTemplateError{Path: path, Err: err}
+	}
+	return out.Bytes(), nil
+}
@@ -0,0 +1,110 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package samples
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	randv2 "math/rand/v2"
+	"os"
+	"strconv"
+	"text/template"
+	"time"
+)
+
+// nowLayouts names the layouts accepted by the now helper besides plain Go
+// reference layouts.
+var nowLayouts = map[string]string{
+	"":            time.RFC3339,
+	"RFC3339":     time.RFC3339,
+	"RFC3339Nano": time.RFC3339Nano,
+	"RFC1123":     time.RFC1123,
+	"date":        time.DateOnly,
+}
+
+// templateFuncs are the helpers available to every sample template.
+var templateFuncs = template.FuncMap{
+	"uuid":         templateUUID,
+	"now":          templateNow,
+	"randomInt":    templateRandomInt,
+	"randomChoice": templateRandomChoice,
+	"env":          os.Getenv,
+	"base64":       templateBase64,
+	"json":         templateJSON,
+}
+
+// templateUUID returns a random version 4 UUID.
+func templateUUID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// templateNow formats the current UTC time. The optional layout is a Go
+// reference layout, one of the names in nowLayouts, or "unix" and
+// "unixMilli" for epoch numbers.
+func templateNow(layout ...string) (string, error) {
+	if len(layout) > 1 {
+		return "", fmt.Errorf("now: expected at most one layout, got %d", len(layout))
+	}
+	name := ""
+	if len(layout) == 1 {
+		name = layout[0]
+	}
+
+	t := time.Now().UTC()
+	switch name {
+	case "unix":
+		return strconv.FormatInt(t.Unix(), 10), nil
+	case "unixMilli":
+		return strconv.FormatInt(t.UnixMilli(), 10), nil
+	}
+	if l, ok := nowLayouts[name]; ok {
+		return t.Format(l), nil
+	}
+	return t.Format(name), nil
+}
+
+// templateRandomInt returns a random integer in [lo, hi].
+func templateRandomInt(lo, hi int) (int, error) {
+	if hi < lo {
+		return 0, fmt.Errorf("randomInt: max %d is below min %d", hi, lo)
+	}
+	return lo + randv2.IntN(hi-lo+1), nil //nolint:gosec
+}
+
+// templateRandomChoice returns one of its arguments at random.
+func templateRandomChoice(items ...any) (any, error) {
+	if len(items) == 0 {
+		return nil, fmt.Errorf("randomChoice: no values to choose from")
+	}
+	return items[randv2.IntN(len(items))], nil //nolint:gosec
+}
+
+// templateBase64 returns the standard base64 encoding of a string or byte
+// slice, or of the printed form of any other value.
+func templateBase64(v any) string {
+	switch x := v.(type) {
+	case string:
+		return base64.StdEncoding.EncodeToString([]byte(x))
+	case []byte:
+		return base64.StdEncoding.EncodeToString(x)
+	}
+	return base64.StdEncoding.EncodeToString([]byte(fmt.Sprint(v)))
+}
+
+// templateJSON returns v encoded as JSON, quotes included for strings, so
+// request values with quotes or backslashes keep a JSON sample valid.
+func templateJSON(v any) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("json: %w", err)
+	}
+	return string(b), nil
+}
@@ -0,0 +1,85 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package samples
+
+import (
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderTemplate_Helpers(t *testing.T) {
+	t.Setenv("EMULATOR_REGION", "eu-1")
+	raw := `{
+	  "id": "{{ uuid }}",
+	  "at": "{{ now }}",
+	  "day": "{{ now "2006-01-02" }}",
+	  "epoch": {{ now "unix" }},
+	  "n": {{ randomInt 3 5 }},
+	  "color": "{{ randomChoice "red" "green" }}",
+	  "region": "{{ env "EMULATOR_REGION" }}",
+	  "token": "{{ base64 "user:pw" }}"
+	}`
+
+	out, err := renderTemplate("t.json", []byte(raw), &TemplateData{})
+	require.NoError(t, err)
+
+	var m map[string]any
+	require.NoError(t, json.Unmarshal(out, &m))
+	require.Regexp(t, regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`), m["id"])
+	_, err = time.Parse(time.RFC3339, m["at"].(string))
+	require.NoError(t, err)
+	require.Equal(t, time.Now().UTC().Format("2006-01-02"), m["day"])
+	require.InDelta(t, float64(time.Now().Unix()), m["epoch"], 5)
+	require.Contains(t, []any{3.0, 4.0, 5.0}, m["n"])
+	require.Contains(t, []any{"red", "green"}, m["color"])
+	require.Equal(t, "eu-1", m["region"])
+	require.Equal(t, "dXNlcjpwdw==", m["token"])
+}
+
+func TestRenderTemplate_JSONEscapesRequestValues(t *testing.T) {
+	raw := `{"name": {{ json .Query.name }}, "tags": {{ json .Body.tags }}, "plain": "{{ .Query.name }}"}`
+	data := &TemplateData{
+		Query: map[string]string{"name": `say "hi" \ bye`},
+		body:  bodyOf(map[string]any{"tags": []any{"a", `b"c`}}),
+	}
+
+	out, err := renderTemplate("t.json", []byte(raw), data)
+	require.NoError(t, err)
+	require.False(t, json.Valid(out), "the unescaped plain field breaks the sample")
+
+	raw = `{"name": {{ json .Query.name }}, "tags": {{ json .Body.tags }}}`
+	out, err = renderTemplate("t.json", []byte(raw), data)
+	require.NoError(t, err)
+
+	var m map[string]any
+	require.NoError(t, json.Unmarshal(out, &m))
+	require.Equal(t, `say "hi" \ bye`, m["name"])
+	require.Equal(t, []any{"a", `b"c`}, m["tags"])
+}
+
+func TestRenderTemplate_HelperErrors(t *testing.T) {
+	for _, raw := range []string{
+		`{{ randomInt 5 3 }}`,
+		`{{ randomChoice }}`,
+		`{{ now "unix" "extra" }}`,
+	} {
+		_, err := renderTemplate("t.json", []byte(raw), &TemplateData{})
+		var tplErr *SampleTemplateError
+		require.ErrorAs(t, err, &tplErr, raw)
+	}
+}
+
+func TestTemplateNow_UnixMilli(t *testing.T) {
+	s, err := templateNow("unixMilli")
+	require.NoError(t, err)
+	ms, err := strconv.ParseInt(s, 10, 64)
+	require.NoError(t, err)
+	require.InDelta(t, float64(time.Now().UnixMilli()), float64(ms), 5000)
+}
@@ -0,0 +1,200 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package samples
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ozgen/openapi-emulator/internal/matcher"
+	"github.com/ozgen/openapi-emulator/utils"
+)
+
+// sampleVariant is a sibling of a sample file whose name carries request
+// conditions: GET[status=failed].json next to GET.json. Keys prefixed with
+// "header." match request headers, keys prefixed with "body." a dotted path
+// into the JSON request body, other keys query parameters. Richer conditions
+// go in the envelope's match block.
+type sampleVariant struct {
+	path string
+	rule matcher.Rule
+}
+
+// Prefixes marking header and body conditions in a variant name.
+const (
+	variantHeaderPrefix = "header."
+	variantBodyPrefix   = "body."
+)
+
+// variantBase splits "GET[status=failed].json" into "GET.json" and
+// "status=failed". It reports false for names without a condition block.
+func variantBase(name string) (base, conds string, ok bool) {
+	stem, ok := strings.CutSuffix(name, "].json")
+	if !ok {
+		return "", "", false
+	}
+	i := strings.LastIndexByte(stem, '[')
+	if i <= 0 {
+		return "", "", false
+	}
+	return stem[:i] + ".json", stem[i+1:], true
+}
+
+// parseVariantConditions parses "status=failed,type=full". Keys and values
+// are URL-unescaped; a "*" in a value stands for any run of characters, so
+// a value of "*" only requires the parameter to be present. An item without
+// "=" is a label that only names the variant.
+func parseVariantConditions(conds string) (matcher.Rule, error) {
+	rule := matcher.Rule{
+		Headers: map[string]matcher.Value{},
+		Query:   map[string]matcher.Value{},
+		Body:    map[string]matcher.Value{},
+	}
+	for _, c := range strings.Split(conds, ",") {
+		k, v, ok := strings.Cut(strings.TrimSpace(c), "=")
+		if !ok {
+			continue
+		}
+		if k == "" {
+			return matcher.Rule{}, fmt.Errorf("invalid condition %q (want key=value)", c)
+		}
+		var err error
+		if k, err = url.PathUnescape(k); err != nil {
+			return matcher.Rule{}, fmt.Errorf("invalid condition %q: %w", c, err)
+		}
+		if v, err = url.PathUnescape(v); err != nil {
+			return matcher.Rule{}, fmt.Errorf("invalid condition %q: %w", c, err)
+		}
+
+		value := matcher.Exact(v)
+		if strings.Contains(v, "*") {
+			value = matcher.Wildcard(v)
+		}
+		if name, ok := strings.CutPrefix(k, variantHeaderPrefix); ok {
+			rule.Headers[name] = value
+		} else if field, ok := strings.CutPrefix(k, variantBodyPrefix); ok {
+			rule.Body["$."+field] = value
+		} else {
+			rule.Query[k] = value
+		}
+	}
+	return rule, rule.Compile()
+}
+
+// sampleVariants lists the variants of the sample at path, sorted by name.
+// Names with malformed conditions are skipped.
+func sampleVariants(path string) []sampleVariant {
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		return nil
+	}
+	want := filepath.Base(path)
+
+	var out []sampleVariant
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		base, conds, ok := variantBase(e.Name())
+		if !ok || base != want {
+			continue
+		}
+		rule, err := parseVariantConditions(conds)
+		if err != nil {
+			continue
+		}
+		v := sampleVariant{path: filepath.Join(filepath.Dir(path), e.Name()), rule: rule}
+		out = append(out, v)
+	}
+	return out
+}
+
+// weightedDraw returns a random number in [0, n); tests replace it.
+var weightedDraw = rand.IntN
+
+// selectVariant returns the variant of the sample at path whose conditions,
+// from its name and its envelope's match block, all match the request. With
+// several matches the one with the most conditions wins, then the first by
+// name. Without a match, unconditional variants with a weight are drawn
+// from at random; see drawWeighted. A variant without condition or weight
+// is never selected. It returns "" when the plain sample applies, and an
+// error when a candidate variant fails to load.
+func (p *SampleProvider) selectVariant(path string, opts LoadOptions) (string, error) {
+	variants := sampleVariants(path)
+	if len(variants) == 0 {
+		return "", nil
+	}
+	best, bestConds := "", 0
+	var weighted []weightedFile
+	req := matchRequest(opts)
+	for _, v := range variants {
+		if !v.rule.Matches(req) {
+			continue
+		}
+		resp, err := p.load(v.path, templateData(nil, opts))
+		if err != nil {
+			return "", err
+		}
+		conds := v.rule.Conditions() + resp.Match.Conditions()
+		if conds == 0 {
+			if resp.Weight > 0 {
+				weighted = append(weighted, weightedFile{v.path, resp.Weight})
+			}
+			continue
+		}
+		if !resp.Match.Matches(req) {
+			continue
+		}
+		if conds > bestConds {
+			best, bestConds = v.path, conds
+		}
+	}
+	if best != "" || len(weighted) == 0 {
+		return best, nil
+	}
+	return p.drawWeighted(path, weighted, opts)
+}
+
+type weightedFile struct {
+	path   string
+	weight int
+}
+
+// drawWeighted picks one of the weighted variants or the plain sample at
+// path. Weights are relative; the plain sample takes its own weight, or
+// else what the variants leave of 100.
+func (p *SampleProvider) drawWeighted(path string, variants []weightedFile, opts LoadOptions) (string, error) {
+	total := 0
+	for _, v := range variants {
+		total += v.weight
+	}
+	if utils.FileExists(path) {
+		resp, err := p.load(path, templateData(nil, opts))
+		if err != nil {
+			return "", err
+		}
+		weight := resp.Weight
+		if weight == 0 {
+			weight = max(100-total, 0)
+		}
+		if weight > 0 {
+			variants = append(variants, weightedFile{path, weight})
+			total += weight
+		}
+	}
+
+	n := weightedDraw(total)
+	for _, v := range variants {
+		if n < v.weight {
+			return v.path, nil
+		}
+		n -= v.weight
+	}
+	return variants[len(variants)-1].path, nil
+}
@@ -0,0 +1,211 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package samples
+
+import (
+	"math/rand/v2"
+	"path/filepath"
+	"testing"
+
+	"github.com/ozgen/openapi-emulator/config"
+	"github.com/ozgen/openapi-emulator/internal/matcher"
+	"github.com/ozgen/openapi-emulator/logger"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVariantBase(t *testing.T) {
+	base, conds, ok := variantBase("GET[status=failed,type=full].json")
+	require.True(t, ok)
+	require.Equal(t, "GET.json", base)
+	require.Equal(t, "status=failed,type=full", conds)
+
+	for _, name := range []string{"GET.json", "[a=b].json", "GET[a=b].yaml"} {
+		_, _, ok := variantBase(name)
+		require.False(t, ok, name)
+	}
+}
+
+func TestParseVariantConditions(t *testing.T) {
+	rule, err := parseVariantConditions("status=failed, q=a%20b,owner=*")
+	require.NoError(t, err)
+	require.Equal(t, 3, rule.Conditions())
+	query := map[string]string{"status": "failed", "q": "a b", "owner": "me"}
+	require.True(t, rule.Matches(matcher.Request{Query: query}))
+	delete(query, "owner")
+	require.False(t, rule.Matches(matcher.Request{Query: query}), "* requires presence")
+
+	rule, err = parseVariantConditions("viewer,header.X-Role=viewer,header.Accept=text/*,body.order.kind=full")
+	require.NoError(t, err)
+	require.Equal(t, 3, rule.Conditions(), "items without = are labels")
+	require.True(t, rule.Matches(matcher.Request{
+		Header: map[string]string{"X-Role": "viewer", "Accept": "text/csv"},
+		Body:   map[string]any{"order": map[string]any{"kind": "full"}},
+	}))
+
+	_, err = parseVariantConditions("=failed")
+	require.Error(t, err)
+}
+
+func TestSampleProvider_QueryVariants(t *testing.T) {
+	baseDir := t.TempDir()
+	dir := filepath.Join(baseDir, "scans")
+	writeFile(t, dir, "GET.json", `{"items":["all"]}`)
+	writeFile(t, dir, "GET[status=failed].json", `{"items":["failed"]}`)
+	writeFile(t, dir, "GET[status=failed,owner=*].json", `{"items":["failed, owned"]}`)
+	writeFile(t, dir, "GET[=failed].json", `{"items":["malformed"]}`)
+	writeFile(t, dir, "POST[status=failed].json", `{"items":["post"]}`)
+
+	p := NewSampleProvider(ProviderConfig{BaseDir: baseDir, Layout: config.LayoutFolders}, logger.GetLogger())
+	load := func(query map[string]string) string {
+		t.Helper()
+		resp, err := p.ResolveAndLoad("GET", "/scans", "/scans", "", LoadOptions{Query: query})
+		require.NoError(t, err)
+		return string(resp.Body)
+	}
+
+	require.JSONEq(t, `{"items":["all"]}`, load(nil))
+	require.JSONEq(t, `{"items":["all"]}`, load(map[string]string{"status": "done"}))
+	require.JSONEq(t, `{"items":["failed"]}`, load(map[string]string{"status": "failed"}))
+	require.JSONEq(t, `{"items":["failed, owned"]}`, load(map[string]string{"status": "failed", "owner": "me"}))
+
+	files := p.SampleFiles("GET", "/scans", "", "")
+	require.Len(t, files, 3)
+	require.Equal(t, filepath.Join(dir, "GET.json"), files[0].Path)
+}
+
+func TestSampleProvider_QueryVariantWithoutPlainSample(t *testing.T) {
+	baseDir := t.TempDir()
+	writeFile(t, baseDir, "GET__scans[status=failed].json", `{"items":["failed"]}`)
+
+	p := NewSampleProvider(ProviderConfig{BaseDir: baseDir, Layout: config.LayoutAuto}, logger.GetLogger())
+
+	resp, err := p.ResolveAndLoad("GET", "/scans", "/scans", "GET__scans.json", LoadOptions{Query: map[string]string{"status": "failed"}})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"items":["failed"]}`, string(resp.Body))
+
+	_, err = p.ResolveAndLoad("GET", "/scans", "/scans", "GET__scans.json", LoadOptions{})
+	require.Error(t, err)
+
+	report, err := p.AuditFlatSamples([]RouteFiles{{Method: "GET", SwaggerPath: "/scans", FlatFile: "GET__scans.json"}})
+	require.NoError(t, err)
+	require.Empty(t, report.Orphaned)
+}
+
+func TestSampleProvider_HeaderVariants(t *testing.T) {
+	baseDir := t.TempDir()
+	dir := filepath.Join(baseDir, "items")
+	writeFile(t, dir, "GET.json", `{"greeting":"hello"}`)
+	writeFile(t, dir, "GET[de].json", `{"version":1,"match":{"headers":{"Accept-Language":{"prefix":"de"}}},"body":{"greeting":"hallo"}}`)
+	writeFile(t, dir, "GET[header.X-Role=viewer].json", `{"status":403,"body":{"error":"forbidden"}}`)
+	writeFile(t, dir, "GET[beta].json", `{"version":1,"match":{"headers":{"x-client":{"regex":"^beta-[0-9]+$"}}},"body":{"greeting":"hi beta"}}`)
+	writeFile(t, dir, "GET[unconditional].json", `{"greeting":"never"}`)
+
+	p := NewSampleProvider(ProviderConfig{BaseDir: baseDir, Layout: config.LayoutFolders}, logger.GetLogger())
+	load := func(header map[string]string) *Response {
+		t.Helper()
+		resp, err := p.ResolveAndLoad("GET", "/items", "/items", "", LoadOptions{Header: header})
+		require.NoError(t, err)
+		return resp
+	}
+
+	require.JSONEq(t, `{"greeting":"hello"}`, string(load(nil).Body))
+	require.JSONEq(t, `{"greeting":"hallo"}`, string(load(map[string]string{"Accept_Language": "de-CH,de;q=0.9"}).Body))
+	require.JSONEq(t, `{"greeting":"hello"}`, string(load(map[string]string{"Accept_Language": "en"}).Body))
+	require.Equal(t, 403, load(map[string]string{"X_Role": "viewer"}).Status)
+	require.JSONEq(t, `{"greeting":"hi beta"}`, string(load(map[string]string{"X_Client": "beta-42"}).Body))
+	require.JSONEq(t, `{"greeting":"hello"}`, string(load(map[string]string{"X_Client": "beta-x"}).Body))
+}
+
+func TestSampleProvider_HeaderVariant_InvalidMatcher(t *testing.T) {
+	baseDir := t.TempDir()
+	dir := filepath.Join(baseDir, "items")
+	writeFile(t, dir, "GET.json", `{"greeting":"hello"}`)
+	writeFile(t, dir, "GET[bad].json", `{"version":1,"match":{"headers":{"X-Client":{"regex":"("}}},"body":{}}`)
+
+	p := NewSampleProvider(ProviderConfig{BaseDir: baseDir, Layout: config.LayoutFolders}, logger.GetLogger())
+	_, err := p.ResolveAndLoad("GET", "/items", "/items", "", LoadOptions{})
+	var envErr *SampleEnvelopeError
+	require.ErrorAs(t, err, &envErr)
+}
+
+func TestSampleProvider_BodyVariants(t *testing.T) {
+	baseDir := t.TempDir()
+	dir := filepath.Join(baseDir, "orders")
+	writeFile(t, dir, "POST.json", `{"status":201,"body":{"state":"created"}}`)
+	writeFile(t, dir, "POST[body.action=cancel].json", `{"status":200,"body":{"state":"cancelled"}}`)
+	writeFile(t, dir, "POST[bulk].json", `{"version":1,"status":202,"match":{"body":{"$.items[1].sku":{"regex":"^SKU-"}}},"body":{"state":"queued"}}`)
+
+	p := NewSampleProvider(ProviderConfig{BaseDir: baseDir, Layout: config.LayoutFolders}, logger.GetLogger())
+	load := func(body any) *Response {
+		t.Helper()
+		resp, err := p.ResolveAndLoad("POST", "/orders", "/orders", "", LoadOptions{Body: bodyOf(body)})
+		require.NoError(t, err)
+		return resp
+	}
+
+	require.Equal(t, 201, load(nil).Status)
+	require.Equal(t, 201, load(map[string]any{"action": "approve"}).Status)
+	require.Equal(t, 200, load(map[string]any{"action": "cancel"}).Status)
+	require.Equal(t, 202, load(map[string]any{"items": []any{map[string]any{"sku": "x"}, map[string]any{"sku": "SKU-2"}}}).Status)
+}
+
+func TestSampleProvider_WeightedVariants(t *testing.T) {
+	baseDir := t.TempDir()
+	dir := filepath.Join(baseDir, "scans")
+	writeFile(t, dir, "GET.json", `{"items":[]}`)
+	writeFile(t, dir, "GET[throttled].json", `{"status":429,"weight":15,"body":{"error":"slow down"}}`)
+	writeFile(t, dir, "GET[error].json", `{"status":500,"weight":5,"body":{"error":"boom"}}`)
+	writeFile(t, dir, "GET[status=failed].json", `{"status":200,"body":{"items":["failed"]}}`)
+
+	var draws []int
+	t.Cleanup(func() { weightedDraw = rand.IntN })
+
+	p := NewSampleProvider(ProviderConfig{BaseDir: baseDir, Layout: config.LayoutFolders}, logger.GetLogger())
+	status := func(pick int, query map[string]string) int {
+		t.Helper()
+		weightedDraw = func(n int) int {
+			draws = append(draws, n)
+			return pick
+		}
+		resp, err := p.ResolveAndLoad("GET", "/scans", "/scans", "", LoadOptions{Query: query})
+		require.NoError(t, err)
+		return resp.Status
+	}
+
+	// Sorted by name: error (5), throttled (15), then the plain sample (80).
+	require.Equal(t, 500, status(0, nil))
+	require.Equal(t, 500, status(4, nil))
+	require.Equal(t, 429, status(5, nil))
+	require.Equal(t, 429, status(19, nil))
+	require.Equal(t, 200, status(20, nil))
+	require.Equal(t, 200, status(99, nil))
+	require.Equal(t, []int{100, 100, 100, 100, 100, 100}, draws)
+
+	// A matching conditional variant is not subject to the draw.
+	draws = nil
+	require.Equal(t, 200, status(0, map[string]string{"status": "failed"}))
+	require.Empty(t, draws)
+}
+
+func TestSampleProvider_WeightedVariants_PlainWeight(t *testing.T) {
+	baseDir := t.TempDir()
+	dir := filepath.Join(baseDir, "scans")
+	writeFile(t, dir, "GET.json", `{"weight":1,"body":{"items":[]}}`)
+	writeFile(t, dir, "GET[throttled].json", `{"status":429,"weight":3,"body":{}}`)
+	writeFile(t, dir, "GET[unweighted].json", `{"status":418,"body":{}}`)
+
+	var total int
+	weightedDraw = func(n int) int {
+		total = n
+		return n - 1
+	}
+	t.Cleanup(func() { weightedDraw = rand.IntN })
+
+	p := NewSampleProvider(ProviderConfig{BaseDir: baseDir, Layout: config.LayoutFolders}, logger.GetLogger())
+	resp, err := p.ResolveAndLoad("GET", "/scans", "/scans", "", LoadOptions{})
+	require.NoError(t, err)
+	require.Equal(t, 200, resp.Status)
+	require.Equal(t, 4, total)
+}
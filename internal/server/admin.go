@@ -0,0 +1,287 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	_ "embed"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ozgen/openapi-emulator/utils"
+)
+
+const (
+	adminPrefix  = "/__admin/"
+	maxFuzzCount = 100
+)
+
+//go:embed ui/index.html
+var adminUI []byte
+
+// handleAdmin serves emulator utilities under /__admin/. It returns false
+// when the request is not an admin request.
+func (s *Server) handleAdmin(w http.ResponseWriter, r *http.Request) bool {
+	if !strings.HasPrefix(r.URL.Path, adminPrefix) {
+		return false
+	}
+
+	sub := strings.TrimPrefix(r.URL.Path, adminPrefix)
+	if id, ok := strings.CutPrefix(sub, "examples/"); ok {
+		s.handleAdminExamples(w, r, id)
+		return true
+	}
+	if route, ok := strings.CutPrefix(sub, "routes/"); ok {
+		s.handleAdminRouteProvenance(w, r, route)
+		return true
+	}
+
+	switch sub {
+	case "fuzz":
+		s.handleAdminFuzz(w, r)
+	case "diff":
+		s.handleAdminDiff(w, r)
+	case "spec/reload":
+		s.handleAdminSpecReload(w, r)
+	case "ui", "ui/":
+		s.handleAdminUI(w, r)
+	case "routes":
+		s.handleAdminRoutes(w, r)
+	case "samples/flat":
+		s.handleAdminFlatSamples(w, r)
+	case "samples/coverage":
+		s.handleAdminSampleCoverage(w, r)
+	case "requests":
+		s.handleAdminRequests(w, r)
+	case "jobs":
+		s.handleAdminJobs(w, r)
+	case "scenarios":
+		s.handleAdminScenarios(w, r)
+	case "switches":
+		s.handleAdminSwitches(w, r)
+	default:
+		writeError(w, 404, CodeAdminNotFound, "Unknown admin endpoint", map[string]any{
+			"path": r.URL.Path,
+		})
+	}
+	return true
+}
+
+// handleAdminFuzz generates valid and invalid request bodies for an operation:
+// GET /__admin/fuzz?method=POST&path=/items&n=5
+func (s *Server) handleAdminFuzz(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+
+	q := r.URL.Query()
+	method := strings.ToUpper(strings.TrimSpace(q.Get("method")))
+	path := strings.TrimSpace(q.Get("path"))
+	if method == "" || path == "" {
+		writeError(w, 400, CodeInvalidParameter, "Bad Request", map[string]any{
+			"details": "query parameters 'method' and 'path' are required",
+		})
+		return
+	}
+
+	n := 5
+	if raw := q.Get("n"); raw != "" {
+		v, err := strconv.Atoi(raw)
+		if err != nil || v < 1 || v > maxFuzzCount {
+			writeError(w, 400, CodeInvalidParameter, "Bad Request", map[string]any{
+				"details": "query parameter 'n' must be between 1 and 100",
+			})
+			return
+		}
+		n = v
+	}
+
+	rt := s.router().FindRoute(method, path)
+	if rt == nil {
+		writeError(w, 404, CodeRouteNotFound, "No route", map[string]any{
+			"method": method,
+			"path":   path,
+		})
+		return
+	}
+
+	res, ok := s.specProvider.FuzzRequestBody(rt.Swagger, rt.Method, n)
+	if !ok {
+		writeError(w, 422, CodeNoRequestSchema, "Operation has no JSON request body schema", map[string]any{
+			"method":      rt.Method,
+			"swaggerPath": rt.Swagger,
+		})
+		return
+	}
+
+	utils.WriteJSON(w, 200, map[string]any{
+		"method":      rt.Method,
+		"swaggerPath": rt.Swagger,
+		"valid":       res.Valid,
+		"invalid":     res.Invalid,
+	})
+}
+
+// handleAdminExamples returns request and response examples for an
+// operation: GET /__admin/examples/{operationId}
+func (s *Server) handleAdminExamples(w http.ResponseWriter, r *http.Request, operationID string) {
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+
+	operationID = strings.Trim(operationID, "/")
+	if operationID == "" {
+		writeError(w, 400, CodeInvalidParameter, "Bad Request", map[string]any{
+			"details": "path must be /__admin/examples/{operationId}",
+		})
+		return
+	}
+
+	ex, ok := s.specProvider.OperationExamples(operationID)
+	if !ok {
+		writeError(w, 404, CodeOperationNotFound, "Unknown operationId", map[string]any{
+			"operationId": operationID,
+		})
+		return
+	}
+	utils.WriteJSON(w, 200, ex)
+}
+
+// handleAdminSpecReload re-reads the spec from SPEC_PATH: POST /__admin/spec/reload
+func (s *Server) handleAdminSpecReload(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodPost) {
+		return
+	}
+
+	if err := s.ReloadSpec(); err != nil {
+		s.log.WithError(err).Warn("spec reload failed")
+		writeError(w, 500, CodeSpecReloadFailed, "Spec reload failed", map[string]any{
+			"details": err.Error(),
+		})
+		return
+	}
+
+	utils.WriteJSON(w, 200, map[string]any{
+		"ok":     true,
+		"routes": len(s.router().GetRoutes()),
+	})
+}
+
+// handleAdminUI serves the dashboard page: GET /__admin/ui
+func (s *Server) handleAdminUI(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(200)
+	_, _ = w.Write(adminUI)
+}
+
+// handleAdminRoutes lists the spec routes: GET /__admin/routes
+func (s *Server) handleAdminRoutes(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+
+	type route struct {
+		Method      string `json:"method"`
+		SwaggerPath string `json:"swaggerPath"`
+		SampleFile  string `json:"sampleFile,omitempty"`
+		TagFile     string `json:"tagFile,omitempty"`
+	}
+	routes := []route{}
+	for _, rt := range s.router().GetRoutes() {
+		routes = append(routes, route{
+			Method:      rt.Method,
+			SwaggerPath: rt.Swagger,
+			SampleFile:  rt.SampleFile,
+			TagFile:     rt.TagFile,
+		})
+	}
+	utils.WriteJSON(w, 200, map[string]any{"routes": routes})
+}
+
+// handleAdminRequests returns the recent request log, optionally only the
+// entries after a sequence number: GET /__admin/requests?since=42. DELETE
+// purges the log.
+func (s *Server) handleAdminRequests(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet, http.MethodDelete) {
+		return
+	}
+	if r.Method == http.MethodDelete {
+		utils.WriteJSON(w, 200, map[string]any{"purged": s.requests.purge()})
+		return
+	}
+
+	var since int64
+	if raw := r.URL.Query().Get("since"); raw != "" {
+		v, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || v < 0 {
+			writeError(w, 400, CodeInvalidParameter, "Bad Request", map[string]any{
+				"details": "query parameter 'since' must be a non-negative integer",
+			})
+			return
+		}
+		since = v
+	}
+	utils.WriteJSON(w, 200, map[string]any{"requests": s.requests.since(since)})
+}
+
+// handleAdminJobs drops every x-emulator-job job: DELETE /__admin/jobs
+func (s *Server) handleAdminJobs(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodDelete) {
+		return
+	}
+	utils.WriteJSON(w, 200, map[string]any{"purged": s.jobs.purge()})
+}
+
+// handleAdminScenarios lists the last scenario state served per request
+// path: GET /__admin/scenarios
+func (s *Server) handleAdminScenarios(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+	utils.WriteJSON(w, 200, map[string]any{"scenarios": s.scenarios.list()})
+}
+
+// handleAdminSwitches reads or replaces the runtime switches:
+// GET /__admin/switches, PUT /__admin/switches. expiresAt and active are
+// computed; values sent for them are ignored.
+func (s *Server) handleAdminSwitches(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet, http.MethodPut) {
+		return
+	}
+
+	if r.Method == http.MethodPut {
+		var sw Switches
+		dec := json.NewDecoder(r.Body)
+		dec.DisallowUnknownFields()
+		err := dec.Decode(&sw)
+		if err == nil {
+			err = sw.validate()
+		}
+		if err != nil {
+			writeError(w, 400, CodeInvalidParameter, "Bad Request", map[string]any{"details": err.Error()})
+			return
+		}
+		s.switches.set(sw, time.Now())
+	}
+	utils.WriteJSON(w, 200, s.switches.get(time.Now()))
+}
+
+// allowMethods answers 405 with an Allow header unless the request uses one
+// of methods.
+func allowMethods(w http.ResponseWriter, r *http.Request, methods ...string) bool {
+	for _, m := range methods {
+		if r.Method == m {
+			return true
+		}
+	}
+	w.Header().Set("Allow", strings.Join(methods, ", "))
+	writeError(w, 405, CodeMethodNotAllowed, "Method Not Allowed", nil)
+	return false
+}
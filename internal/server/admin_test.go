@@ -0,0 +1,608 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ozgen/openapi-emulator/config"
+	"github.com/ozgen/openapi-emulator/internal/matcher"
+	"github.com/ozgen/openapi-emulator/internal/samples"
+)
+
+func TestAdminFuzz_ReturnsValidAndInvalidBodies(t *testing.T) {
+	s := newTestServer(t, config.ValidationRequired, config.FallbackOpenAPIExample)
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/__admin/fuzz?method=post&path=/items&n=3", nil)
+
+	s.handle(rr, req)
+
+	if rr.Code != 200 {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var m map[string]any
+	_ = json.Unmarshal(rr.Body.Bytes(), &m)
+	if m["swaggerPath"] != "/items" {
+		t.Fatalf("unexpected swaggerPath: %v", m)
+	}
+	if valid, _ := m["valid"].([]any); len(valid) != 3 {
+		t.Fatalf("expected 3 valid bodies, got %v", m["valid"])
+	}
+	if invalid, _ := m["invalid"].([]any); len(invalid) == 0 {
+		t.Fatalf("expected invalid bodies, got %v", m["invalid"])
+	}
+}
+
+func TestAdminFuzz_Errors(t *testing.T) {
+	s := newTestServer(t, config.ValidationRequired, config.FallbackOpenAPIExample)
+
+	cases := []struct {
+		url  string
+		want int
+	}{
+		{"/__admin/fuzz?path=/items", 400},
+		{"/__admin/fuzz?method=post&path=/items&n=0", 400},
+		{"/__admin/fuzz?method=post&path=/nope", 404},
+		{"/__admin/fuzz?method=get&path=/items/1", 422},
+		{"/__admin/unknown", 404},
+	}
+
+	for _, tc := range cases {
+		rr := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "http://example.com"+tc.url, nil)
+
+		s.handle(rr, req)
+
+		if rr.Code != tc.want {
+			t.Fatalf("%s: expected %d, got %d: %s", tc.url, tc.want, rr.Code, rr.Body.String())
+		}
+	}
+}
+
+func TestAdminExamples(t *testing.T) {
+	disableScenarioForTests()
+
+	dir := t.TempDir()
+	specPath := writeFile(t, dir, "spec.json", `{
+	  "openapi":"3.0.3",
+	  "info":{"title":"t","version":"1"},
+	  "paths":{"/items":{"post":{
+	    "operationId":"createItem",
+	    "requestBody":{"content":{"application/json":{"example":{"name":"widget"}}}},
+	    "responses":{"201":{"description":"created","content":{"application/json":{
+	      "schema":{"type":"object","properties":{"id":{"type":"string"}}}
+	    }}}}
+	  }}}
+	}`)
+
+	s, err := New(Config{
+		Port:           "0",
+		SpecPath:       specPath,
+		SamplesDir:     dir,
+		FallbackMode:   config.FallbackNone,
+		ValidationMode: config.ValidationNone,
+		Layout:         config.LayoutFolders,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	rr := httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com/__admin/examples/createItem", nil))
+	if rr.Code != 200 {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var m struct {
+		Method   string                        `json:"method"`
+		Request  struct{ Body map[string]any } `json:"request"`
+		Response struct{ Body map[string]any } `json:"response"`
+	}
+	_ = json.Unmarshal(rr.Body.Bytes(), &m)
+	if m.Method != "POST" || m.Request.Body["name"] != "widget" || m.Response.Body["id"] != "string" {
+		t.Fatalf("unexpected examples: %s", rr.Body.String())
+	}
+
+	rr = httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com/__admin/examples/nope", nil))
+	if rr.Code != 404 || !strings.Contains(rr.Body.String(), string(CodeOperationNotFound)) {
+		t.Fatalf("expected 404 OPERATION_NOT_FOUND, got %d: %s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestAdminSpecReload_RebuildsRoutes(t *testing.T) {
+	disableScenarioForTests()
+
+	dir := t.TempDir()
+	specPath := writeFile(t, dir, "spec.json", minimalSpec())
+
+	s, err := New(Config{
+		Port:           "0",
+		SpecPath:       specPath,
+		SamplesDir:     dir,
+		FallbackMode:   config.FallbackOpenAPIExample,
+		ValidationMode: config.ValidationNone,
+		Layout:         config.LayoutFolders,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	writeFile(t, dir, "spec.json", `{
+	  "openapi":"3.0.3",
+	  "info":{"title":"t","version":"1"},
+	  "paths":{"/fresh":{"get":{"responses":{"200":{"description":"ok"}}}}}
+	}`)
+
+	rr := httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com/__admin/spec/reload", nil))
+	if rr.Code != 405 {
+		t.Fatalf("expected 405 for GET, got %d", rr.Code)
+	}
+
+	rr = httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodPost, "http://example.com/__admin/spec/reload", nil))
+	if rr.Code != 200 {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	rr = httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com/fresh", nil))
+	if rr.Code != 200 {
+		t.Fatalf("expected new route to resolve, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	writeFile(t, dir, "spec.json", `{broken`)
+	rr = httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodPost, "http://example.com/__admin/spec/reload", nil))
+	if rr.Code != 500 {
+		t.Fatalf("expected 500 for broken spec, got %d", rr.Code)
+	}
+}
+
+func TestAdminUI_ServesDashboard(t *testing.T) {
+	s := newTestServer(t, config.ValidationRequired, config.FallbackOpenAPIExample)
+
+	rr := httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com/__admin/ui", nil))
+
+	if rr.Code != 200 || !strings.HasPrefix(rr.Header().Get("Content-Type"), "text/html") {
+		t.Fatalf("unexpected response: %d %q", rr.Code, rr.Header().Get("Content-Type"))
+	}
+	if !strings.Contains(rr.Body.String(), "openapi-emulator") {
+		t.Fatalf("unexpected body: %s", rr.Body.String())
+	}
+}
+
+func TestAdminRequests_LogsEmulatedRequests(t *testing.T) {
+	s := newTestServer(t, config.ValidationRequired, config.FallbackOpenAPIExample)
+
+	s.handle(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://example.com/items/1?x=1", nil))
+	s.handle(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://example.com/nope", nil))
+
+	rr := httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com/__admin/requests", nil))
+
+	var out struct {
+		Requests []requestLogEntry `json:"requests"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &out); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(out.Requests) != 2 {
+		t.Fatalf("expected 2 logged requests (admin excluded), got %+v", out.Requests)
+	}
+	first, second := out.Requests[0], out.Requests[1]
+	if first.Path != "/items/1" || first.Query != "x=1" || first.Status != 200 || second.Status != 404 {
+		t.Fatalf("unexpected entries: %+v", out.Requests)
+	}
+
+	rr = httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com/__admin/requests?since=1", nil))
+	_ = json.Unmarshal(rr.Body.Bytes(), &out)
+	if len(out.Requests) != 1 || out.Requests[0].Seq != 2 {
+		t.Fatalf("expected only the entry after seq 1, got %+v", out.Requests)
+	}
+}
+
+func TestRequestLog_KeepsMostRecent(t *testing.T) {
+	l := newRequestLog(0, 0)
+	for i := 0; i < defaultRequestLogSize+5; i++ {
+		l.add(requestLogEntry{Path: "/x"})
+	}
+
+	got := l.since(0)
+	if len(got) != defaultRequestLogSize || got[0].Seq != 6 {
+		t.Fatalf("expected the last %d entries starting at seq 6, got %d starting at %d", defaultRequestLogSize, len(got), got[0].Seq)
+	}
+
+	l = newRequestLog(3, 0)
+	for i := 0; i < 5; i++ {
+		l.add(requestLogEntry{Path: "/x"})
+	}
+	if got := l.since(0); len(got) != 3 || got[0].Seq != 3 || got[1].Seq != 4 || got[2].Seq != 5 {
+		t.Fatalf("expected the last 3 entries starting at seq 3, got %+v", got)
+	}
+	if got := l.since(4); len(got) != 1 || got[0].Seq != 5 {
+		t.Fatalf("expected only seq 5 after wrapping, got %+v", got)
+	}
+}
+
+func TestRequestLog_DropsEntriesOlderThanMaxAge(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	l := newRequestLog(10, time.Minute)
+	l.now = func() time.Time { return now }
+
+	l.add(requestLogEntry{Path: "/old", Time: now.Add(-2 * time.Minute)})
+	l.add(requestLogEntry{Path: "/new", Time: now.Add(-30 * time.Second)})
+	if got := l.since(0); len(got) != 1 || got[0].Path != "/new" {
+		t.Fatalf("expected only /new, got %+v", got)
+	}
+
+	now = now.Add(time.Minute)
+	if got := l.since(0); len(got) != 0 {
+		t.Fatalf("expected every entry to have expired, got %+v", got)
+	}
+}
+
+func TestRequestLog_DropsOldEntriesLoggedAfterNewerOnes(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	l := newRequestLog(10, time.Minute)
+	l.now = func() time.Time { return now }
+
+	// A fast request finishes first; a slow one started earlier is logged
+	// after it.
+	l.add(requestLogEntry{Path: "/fast", Time: now.Add(-10 * time.Second)})
+	l.add(requestLogEntry{Path: "/slow", Time: now.Add(-50 * time.Second)})
+
+	now = now.Add(30 * time.Second)
+	if got := l.since(0); len(got) != 1 || got[0].Path != "/fast" {
+		t.Fatalf("expected only /fast, got %+v", got)
+	}
+}
+
+func TestScenarioBoard_KeepsMostRecentViewsWithinMaxAge(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	b := newScenarioBoard(2, time.Minute)
+	b.now = func() time.Time { return now }
+
+	st := &samples.ScenarioState{Mode: "step", State: "queued"}
+	for _, p := range []string{"/scans/1", "/scans/2", "/scans/3"} {
+		b.observe(scenarioView{Method: "GET", Path: p}, st)
+		now = now.Add(time.Second)
+	}
+	views := b.list()
+	if len(views) != 2 || views[0].Path != "/scans/2" || views[1].Path != "/scans/3" {
+		t.Fatalf("expected the two most recent paths, got %+v", views)
+	}
+
+	now = now.Add(time.Minute)
+	if views := b.list(); len(views) != 0 {
+		t.Fatalf("expected every view to have expired, got %+v", views)
+	}
+}
+
+func TestAdminRequests_ExcludeAndPurge(t *testing.T) {
+	s := newTestServer(t, config.ValidationNone, config.FallbackOpenAPIExample)
+	var err error
+	if s.unloggedRoutes, err = matcher.ParseRoutes([]string{"POST /items"}); err != nil {
+		t.Fatalf("ParseRoutes: %v", err)
+	}
+
+	s.handle(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://example.com/items/1", nil))
+	s.handle(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "http://example.com/items", strings.NewReader(`{}`)))
+
+	list := func() []requestLogEntry {
+		rr := httptest.NewRecorder()
+		s.handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com/__admin/requests", nil))
+		var out struct {
+			Requests []requestLogEntry `json:"requests"`
+		}
+		_ = json.Unmarshal(rr.Body.Bytes(), &out)
+		return out.Requests
+	}
+	if got := list(); len(got) != 1 || got[0].Path != "/items/1" {
+		t.Fatalf("expected only GET /items/1 to be logged, got %+v", got)
+	}
+
+	rr := httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodDelete, "http://example.com/__admin/requests", nil))
+	if rr.Code != 200 || strings.TrimSpace(rr.Body.String()) != `{"purged":1}` {
+		t.Fatalf("unexpected purge response %d: %s", rr.Code, rr.Body.String())
+	}
+	if got := list(); len(got) != 0 {
+		t.Fatalf("expected an empty log after purge, got %+v", got)
+	}
+
+	s.handle(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://example.com/items/1", nil))
+	if got := list(); len(got) != 1 || got[0].Seq != 2 {
+		t.Fatalf("expected sequence numbers to keep counting, got %+v", got)
+	}
+}
+
+func TestAdminSwitches_Maintenance(t *testing.T) {
+	s := newTestServer(t, config.ValidationRequired, config.FallbackOpenAPIExample)
+
+	put := func(body string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		s.handle(rr, httptest.NewRequest(http.MethodPut, "http://example.com/__admin/switches", strings.NewReader(body)))
+		return rr
+	}
+
+	if rr := put(`{"chaosRate": 2}`); rr.Code != 400 {
+		t.Fatalf("expected 400 for chaosRate > 1, got %d", rr.Code)
+	}
+	if rr := put(`{"maintenance": true}`); rr.Code != 200 {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	rr := httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com/items/1", nil))
+	if rr.Code != 503 || !strings.Contains(rr.Body.String(), string(CodeMaintenance)) {
+		t.Fatalf("expected maintenance 503, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	// Admin endpoints keep working so the switch can be turned off again.
+	if rr := put(`{"maintenance": false}`); rr.Code != 200 {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	rr = httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com/items/1", nil))
+	if rr.Code != 200 {
+		t.Fatalf("expected 200 after maintenance, got %d", rr.Code)
+	}
+}
+
+func TestAdminSwitches_ChaosAlwaysFails(t *testing.T) {
+	s := newTestServer(t, config.ValidationRequired, config.FallbackOpenAPIExample)
+	s.switches.set(Switches{ChaosRate: 1, ChaosStatus: 502}, time.Now())
+
+	rr := httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com/items/1", nil))
+	if rr.Code != 502 || !strings.Contains(rr.Body.String(), string(CodeChaosFault)) {
+		t.Fatalf("expected chaos 502, got %d: %s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestSwitchBoard_DurationReverts(t *testing.T) {
+	var b switchBoard
+	t0 := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	b.set(Switches{ChaosRate: 0.5, Duration: "15m"}, t0)
+
+	sw := b.get(t0.Add(14 * time.Minute))
+	if !sw.Active || sw.ExpiresAt == nil || !sw.ExpiresAt.Equal(t0.Add(15*time.Minute)) {
+		t.Fatalf("expected active switches expiring at 10:15, got %+v", sw)
+	}
+	sw = b.get(t0.Add(15 * time.Minute))
+	if sw.Active || sw.ChaosRate != 0 || sw.ExpiresAt != nil || sw.Duration != "" {
+		t.Fatalf("expected switches reverted, got %+v", sw)
+	}
+}
+
+func TestSwitchBoard_Windows(t *testing.T) {
+	var b switchBoard
+	b.set(Switches{Maintenance: true, Windows: []string{"09:00-10:00", "22:00-02:00"}, Timezone: "UTC"}, time.Time{})
+
+	cases := map[string]bool{
+		"08:59": false,
+		"09:00": true,
+		"09:59": true,
+		"10:00": false,
+		"23:30": true,
+		"01:59": true,
+		"02:00": false,
+	}
+	for clock, want := range cases {
+		at, _ := time.Parse("15:04", clock)
+		now := time.Date(2026, 3, 2, at.Hour(), at.Minute(), 0, 0, time.UTC)
+		if got := b.get(now).Active; got != want {
+			t.Fatalf("%s: expected active=%v, got %v", clock, want, got)
+		}
+	}
+}
+
+func TestAdminSwitches_ScheduleValidation(t *testing.T) {
+	s := newTestServer(t, config.ValidationRequired, config.FallbackOpenAPIExample)
+
+	for _, body := range []string{
+		`{"chaosRate": 0.1, "duration": "-5m"}`,
+		`{"chaosRate": 0.1, "duration": "soon"}`,
+		`{"chaosRate": 0.1, "windows": ["9-17"]}`,
+		`{"chaosRate": 0.1, "windows": ["09:00-09:00"]}`,
+		`{"chaosRate": 0.1, "timezone": "Mars/Olympus"}`,
+	} {
+		rr := httptest.NewRecorder()
+		s.handle(rr, httptest.NewRequest(http.MethodPut, "http://example.com/__admin/switches", strings.NewReader(body)))
+		if rr.Code != 400 {
+			t.Fatalf("%s: expected 400, got %d: %s", body, rr.Code, rr.Body.String())
+		}
+	}
+
+	rr := httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodPut, "http://example.com/__admin/switches",
+		strings.NewReader(`{"maintenance": true, "duration": "1h"}`)))
+	var sw Switches
+	_ = json.Unmarshal(rr.Body.Bytes(), &sw)
+	if rr.Code != 200 || !sw.Active || sw.ExpiresAt == nil {
+		t.Fatalf("expected active switches with expiresAt, got %d: %s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestAdminSwitches_OutsideWindow_NotApplied(t *testing.T) {
+	s := newTestServer(t, config.ValidationRequired, config.FallbackOpenAPIExample)
+	now := time.Now().UTC()
+	// A one-minute window two hours from now.
+	from := now.Add(2 * time.Hour)
+	win := from.Format("15:04") + "-" + from.Add(time.Minute).Format("15:04")
+	s.switches.set(Switches{Maintenance: true, Windows: []string{win}}, now)
+
+	rr := httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com/items/1", nil))
+	if rr.Code != 200 {
+		t.Fatalf("expected 200 outside the window, got %d: %s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestAdminSwitches_WindowIgnoresClockSkew(t *testing.T) {
+	s := newTestServer(t, config.ValidationRequired, config.FallbackOpenAPIExample)
+	s.cfg.ClockSkew = 6 * time.Hour
+	now := time.Now().UTC()
+	win := now.Add(-time.Minute).Format("15:04") + "-" + now.Add(2*time.Minute).Format("15:04")
+
+	rr := httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodPut, "http://example.com/__admin/switches",
+		strings.NewReader(`{"maintenance": true, "windows": ["`+win+`"]}`)))
+	if rr.Code != 200 {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	rr = httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com/items/1", nil))
+	if rr.Code != 503 {
+		t.Fatalf("expected the window to open on wall-clock time despite CLOCK_SKEW, got %d: %s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestAdminRouteProvenance(t *testing.T) {
+	disableScenarioForTests()
+
+	dir := t.TempDir()
+	specPath := writeFile(t, dir, "spec.json", `{
+	  "openapi":"3.0.3",
+	  "info":{"title":"t","version":"2.1.0"},
+	  "paths":{"/items/{id}":{"get":{
+	    "parameters":[{"name":"id","in":"path","required":true,"schema":{"type":"string"}}],
+	    "responses":{"200":{"description":"ok","content":{"application/json":{
+	      "schema":{"type":"object","required":["id"],"properties":{"id":{"type":"string"}}}
+	    }}}}
+	  }}}
+	}`)
+	writeFileWithDirs(t, dir, "items/{id}/GET.json", `{"name":"no id"}`)
+
+	s, err := New(Config{
+		Port:           "0",
+		SpecPath:       specPath,
+		SamplesDir:     dir,
+		FallbackMode:   config.FallbackNone,
+		ValidationMode: config.ValidationNone,
+		Layout:         config.LayoutFolders,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	rr := httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com/__admin/routes/get/items/{id}", nil))
+	if rr.Code != 200 {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var m struct {
+		SwaggerPath      string `json:"swaggerPath"`
+		ValidatedAgainst struct {
+			SpecVersion string `json:"specVersion"`
+			SpecSha256  string `json:"specSha256"`
+		} `json:"validatedAgainst"`
+		Samples []struct {
+			File     string `json:"file"`
+			Modified string `json:"modified"`
+			Status   int    `json:"status"`
+			Drifted  bool   `json:"drifted"`
+		} `json:"samples"`
+	}
+	_ = json.Unmarshal(rr.Body.Bytes(), &m)
+	if m.SwaggerPath != "/items/{id}" || m.ValidatedAgainst.SpecVersion != "2.1.0" || len(m.ValidatedAgainst.SpecSha256) != 64 {
+		t.Fatalf("unexpected route provenance: %s", rr.Body.String())
+	}
+	if len(m.Samples) != 1 || m.Samples[0].File != "items/{id}/GET.json" || m.Samples[0].Modified == "" || !m.Samples[0].Drifted {
+		t.Fatalf("unexpected samples: %s", rr.Body.String())
+	}
+
+	rr = httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com/__admin/routes/DELETE/items/1", nil))
+	if rr.Code != 404 || !strings.Contains(rr.Body.String(), string(CodeRouteNotFound)) {
+		t.Fatalf("expected 404 %s, got %d: %s", CodeRouteNotFound, rr.Code, rr.Body.String())
+	}
+}
+
+func TestAdminFlatSamples(t *testing.T) {
+	disableScenarioForTests()
+
+	dir := t.TempDir()
+	specPath := writeFile(t, dir, "spec.json", minimalSpec())
+	writeFile(t, dir, "GET__items_{id}.json", `{"id":"flat"}`)
+	writeFileWithDirs(t, dir, "items/{id}/GET.json", `{"id":"folder"}`)
+	writeFile(t, dir, "GET__legacy.json", `{}`)
+
+	s, err := New(Config{
+		Port:           "0",
+		SpecPath:       specPath,
+		SamplesDir:     dir,
+		FallbackMode:   config.FallbackNone,
+		ValidationMode: config.ValidationNone,
+		Layout:         config.LayoutAuto,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	rr := httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com/__admin/samples/flat", nil))
+	if rr.Code != 200 {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	want := `{"shadowed":[{"file":"GET__items_{id}.json","shadowedBy":"items/{id}/GET.json"}],"orphaned":["GET__legacy.json"]}`
+	if got := strings.TrimSpace(rr.Body.String()); got != want {
+		t.Fatalf("unexpected report:\n got %s\nwant %s", got, want)
+	}
+}
+
+func TestAdminSampleCoverage(t *testing.T) {
+	s := newTestServer(t, config.ValidationNone, config.FallbackNone)
+	writeFile(t, s.cfg.SamplesDir, "GET__items_{id}.json", `{}`)
+	writeFileWithDirs(t, s.cfg.SamplesDir, "orders/GET.json", `{}`)
+	if err := os.Remove(filepath.Join(s.cfg.SamplesDir, "items", "POST.json")); err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com/__admin/samples/coverage", nil))
+	if rr.Code != 200 {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	want := `{"missing":["POST /items"],"unused":["orders/GET.json"]}`
+	if got := strings.TrimSpace(rr.Body.String()); got != want {
+		t.Fatalf("unexpected report:\n got %s\nwant %s", got, want)
+	}
+
+	out := s.DebugRoutes()
+	if !strings.Contains(out, "POST /items -> POST__items.json (no sample)") || !strings.Contains(out, "unused sample file: orders/GET.json") {
+		t.Fatalf("expected DebugRoutes to report coverage, got:\n%s", out)
+	}
+}
+
+func TestAdminSampleCoverage_UnreadableSamplesDir(t *testing.T) {
+	s := newTestServer(t, config.ValidationNone, config.FallbackNone)
+	if err := os.RemoveAll(s.cfg.SamplesDir); err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com/__admin/samples/coverage", nil))
+	if rr.Code != 500 {
+		t.Fatalf("expected 500, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var m map[string]any
+	_ = json.Unmarshal(rr.Body.Bytes(), &m)
+	if m["error"] != string(CodeSamplesDirUnreadable) {
+		t.Fatalf("unexpected body: %v", m)
+	}
+}
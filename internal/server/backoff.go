@@ -0,0 +1,58 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/ozgen/openapi-emulator/internal/openapi"
+)
+
+// backoffTracker counts attempts for x-emulator-backoff routes, keyed by
+// client, method and request path.
+type backoffTracker struct {
+	mu       sync.Mutex
+	attempts map[string]int
+}
+
+func newBackoffTracker() *backoffTracker {
+	return &backoffTracker{attempts: map[string]int{}}
+}
+
+// next records an attempt and returns its 1-based number and whether it must
+// fail. The attempt after the last failure succeeds and starts a new cycle,
+// so every retry loop sees the same sequence.
+func (b *backoffTracker) next(key string, cfg *openapi.BackoffConfig) (int, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.attempts[key]++
+	n := b.attempts[key]
+	if n > cfg.Failures {
+		delete(b.attempts, key)
+		return n, false
+	}
+	return n, true
+}
+
+// serveBackoff answers the request with the configured failure while the
+// route is still in its failure phase. It returns false once the request
+// should be served normally.
+func (s *Server) serveBackoff(w http.ResponseWriter, r *http.Request, cfg *openapi.BackoffConfig) bool {
+	attempt, fail := s.backoff.next(s.clientID(r)+"@"+r.Method+" "+r.URL.Path, cfg)
+	if !fail {
+		return false
+	}
+
+	w.Header().Set("Retry-After", strconv.Itoa(cfg.RetryAfterSec))
+	writeError(w, cfg.Status, CodeRetryLater, http.StatusText(cfg.Status), map[string]any{
+		"attempt":       attempt,
+		"failures":      cfg.Failures,
+		"retryAfterSec": cfg.RetryAfterSec,
+	})
+	return true
+}
@@ -0,0 +1,71 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/ozgen/openapi-emulator/config"
+)
+
+func TestBackoff_FailsThenSucceedsAndRestarts(t *testing.T) {
+	disableScenarioForTests()
+
+	dir := t.TempDir()
+	specPath := writeFile(t, dir, "spec.json", `{
+	  "openapi":"3.0.3",
+	  "info":{"title":"t","version":"1"},
+	  "paths":{"/items/{id}":{"get":{
+	    "x-emulator-backoff": {"failures": 2, "status": 429, "retryAfterSec": 3},
+	    "responses":{"200":{"description":"ok"}}
+	  }}}
+	}`)
+	writeFileWithDirs(t, dir, filepath.Join("items", "{id}", "GET.json"), `{"id":"1"}`)
+
+	s, err := New(Config{
+		Port:           "0",
+		SpecPath:       specPath,
+		SamplesDir:     dir,
+		FallbackMode:   config.FallbackNone,
+		ValidationMode: config.ValidationNone,
+		Layout:         config.LayoutFolders,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	get := func(path string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		s.handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com"+path, nil))
+		return rr
+	}
+
+	for i, want := range []int{429, 429, 200, 429} {
+		rr := get("/items/1")
+		if rr.Code != want {
+			t.Fatalf("attempt %d: expected %d, got %d: %s", i+1, want, rr.Code, rr.Body.String())
+		}
+		if want != 429 {
+			continue
+		}
+		if got := rr.Header().Get("Retry-After"); got != "3" {
+			t.Fatalf("attempt %d: unexpected Retry-After %q", i+1, got)
+		}
+		var m map[string]any
+		_ = json.Unmarshal(rr.Body.Bytes(), &m)
+		if m["error"] != string(CodeRetryLater) {
+			t.Fatalf("attempt %d: unexpected body: %v", i+1, m)
+		}
+	}
+
+	// Attempts are counted per request path.
+	if rr := get("/items/2"); rr.Code != 429 {
+		t.Fatalf("expected a fresh cycle for another path, got %d", rr.Code)
+	}
+}
@@ -0,0 +1,48 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+type basePathKey struct{}
+
+// stripBasePath removes SERVER_BASE_PATH from the request path so that
+// /gateway/v2/items/1 matches the spec path /items/1. Requests outside the
+// base path are returned unchanged. The stripped prefix is kept in the
+// context for Location headers, see requestBasePath.
+func (s *Server) stripBasePath(r *http.Request) *http.Request {
+	base := strings.TrimRight(strings.TrimSpace(s.cfg.BasePath), "/")
+	if base == "" {
+		return r
+	}
+	if !strings.HasPrefix(base, "/") {
+		base = "/" + base
+	}
+
+	rest, ok := strings.CutPrefix(r.URL.Path, base)
+	if !ok || (rest != "" && !strings.HasPrefix(rest, "/")) {
+		return r
+	}
+	if rest == "" {
+		rest = "/"
+	}
+
+	r = r.WithContext(context.WithValue(r.Context(), basePathKey{}, base))
+	u := *r.URL
+	u.Path = rest
+	u.RawPath = ""
+	r.URL = &u
+	return r
+}
+
+// requestBasePath is the prefix stripped from r, or "".
+func requestBasePath(r *http.Request) string {
+	base, _ := r.Context().Value(basePathKey{}).(string)
+	return base
+}
@@ -0,0 +1,47 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ozgen/openapi-emulator/config"
+)
+
+func TestHandle_BasePath(t *testing.T) {
+	cases := []struct {
+		path string
+		code int
+	}{
+		{"/gateway/v2/items/1", 200},
+		{"/items/1", 200},
+		{"/gateway/v2x/items/1", 404},
+		{"/gateway/v2/health/alive", 200},
+	}
+	for _, tc := range cases {
+		s := newTestServer(t, config.ValidationNone, config.FallbackOpenAPIExample)
+		s.cfg.BasePath = "/gateway/v2/"
+
+		rr := httptest.NewRecorder()
+		s.handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com"+tc.path, nil))
+		if rr.Code != tc.code {
+			t.Fatalf("%s: expected %d, got %d: %s", tc.path, tc.code, rr.Code, rr.Body.String())
+		}
+	}
+}
+
+func TestHandle_BasePath_RedirectKeepsPrefix(t *testing.T) {
+	s := newTestServer(t, config.ValidationNone, config.FallbackOpenAPIExample)
+	s.cfg.BasePath = "gateway"
+	s.cfg.TrailingSlash = config.TrailingSlashRedirect
+
+	rr := httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com/gateway/items/1/", nil))
+	if rr.Code != 301 || rr.Header().Get("Location") != "/gateway/items/1" {
+		t.Fatalf("unexpected response %d %q", rr.Code, rr.Header().Get("Location"))
+	}
+}
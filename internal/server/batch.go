@@ -0,0 +1,188 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/ozgen/openapi-emulator/utils"
+)
+
+const maxBatchSize = 100
+
+// batchRequest is one sub-request of a batch body.
+type batchRequest struct {
+	Method  string            `json:"method"`
+	Path    string            `json:"path"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    json.RawMessage   `json:"body,omitempty"`
+}
+
+// batchResponse is the outcome of one sub-request. A header sent once is a
+// string, a repeated one such as Set-Cookie an array of strings. JSON
+// bodies are embedded as-is, anything else as a string.
+type batchResponse struct {
+	Status  int            `json:"status"`
+	Headers map[string]any `json:"headers"`
+	Body    any            `json:"body,omitempty"`
+}
+
+type batchCtxKey struct{}
+
+// handleBatch fans the sub-requests of a batch body out through the emulator
+// and answers with their responses in request order.
+func (s *Server) handleBatch(w http.ResponseWriter, r *http.Request, status int) {
+	if r.Context().Value(batchCtxKey{}) != nil {
+		writeError(w, 400, CodeBatchInvalid, "Bad Request", map[string]any{
+			"details": "nested batch requests are not supported",
+		})
+		return
+	}
+
+	subs, err := decodeBatch(r.Body)
+	if err != nil {
+		writeError(w, 400, CodeBatchInvalid, "Bad Request", map[string]any{"details": err.Error()})
+		return
+	}
+
+	ctx := context.WithValue(r.Context(), batchCtxKey{}, true)
+	out := make([]batchResponse, 0, len(subs))
+	for _, sub := range subs {
+		out = append(out, s.serveBatchItem(ctx, r, sub))
+	}
+
+	if status == 0 {
+		status = 200
+	}
+	utils.WriteJSON(w, status, out)
+}
+
+func decodeBatch(body io.Reader) ([]batchRequest, error) {
+	if body == nil {
+		return nil, fmt.Errorf("request body must be a JSON array of sub-requests")
+	}
+	var subs []batchRequest
+	if err := json.NewDecoder(body).Decode(&subs); err != nil {
+		return nil, fmt.Errorf("request body must be a JSON array of sub-requests: %w", err)
+	}
+	if len(subs) > maxBatchSize {
+		return nil, fmt.Errorf("at most %d sub-requests are allowed, got %d", maxBatchSize, len(subs))
+	}
+	for i, sub := range subs {
+		if strings.TrimSpace(sub.Method) == "" || !strings.HasPrefix(sub.Path, "/") {
+			return nil, fmt.Errorf("sub-request %d needs a method and an absolute path", i)
+		}
+	}
+	return subs, nil
+}
+
+// serveBatchItem runs one sub-request. It inherits the headers of the batch
+// request; its own headers win. Admin endpoints are refused, so a batch
+// cannot reset or reconfigure the emulator behind the route it targets.
+func (s *Server) serveBatchItem(ctx context.Context, parent *http.Request, sub batchRequest) batchResponse {
+	var body io.Reader
+	if len(sub.Body) > 0 {
+		body = bytes.NewReader(sub.Body)
+	}
+	req, err := http.NewRequestWithContext(ctx, strings.ToUpper(sub.Method), sub.Path, body)
+	if err != nil {
+		return batchResponse{Status: 400, Headers: map[string]any{}, Body: map[string]any{
+			"error":   CodeBatchInvalid,
+			"message": "Bad Request",
+			"details": err.Error(),
+		}}
+	}
+	if strings.HasPrefix(s.stripBasePath(req).URL.Path, adminPrefix) {
+		return batchResponse{Status: 400, Headers: map[string]any{}, Body: map[string]any{
+			"error":   CodeBatchInvalid,
+			"message": "Bad Request",
+			"details": "sub-requests cannot call " + adminPrefix + " endpoints",
+		}}
+	}
+
+	req.Header = parent.Header.Clone()
+	req.RemoteAddr = parent.RemoteAddr
+	req.Header.Del("Content-Length")
+	req.Header.Del("Content-Type")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for k, v := range sub.Headers {
+		req.Header.Set(k, v)
+	}
+	req.Host = parent.Host
+
+	rec := newBatchRecorder()
+	s.handle(rec, req)
+	return rec.result()
+}
+
+// batchRecorder captures a sub-response in memory.
+type batchRecorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func newBatchRecorder() *batchRecorder {
+	return &batchRecorder{header: http.Header{}}
+}
+
+func (b *batchRecorder) Header() http.Header { return b.header }
+
+func (b *batchRecorder) WriteHeader(status int) {
+	if b.status == 0 {
+		b.status = status
+	}
+}
+
+func (b *batchRecorder) Write(p []byte) (int, error) {
+	if b.status == 0 {
+		b.status = 200
+	}
+	return b.body.Write(p)
+}
+
+func (b *batchRecorder) result() batchResponse {
+	status := b.status
+	if status == 0 {
+		status = 200
+	}
+
+	// With PRESERVE_HEADER_CASE a name may be stored twice, once as the
+	// sample spells it and once canonical without values; merge by the
+	// lowercase name.
+	values := map[string][]string{}
+	for k, vs := range b.header {
+		if len(vs) > 0 {
+			k = strings.ToLower(k)
+			values[k] = append(values[k], vs...)
+		}
+	}
+	headers := make(map[string]any, len(values))
+	for k, vs := range values {
+		if len(vs) == 1 {
+			headers[k] = vs[0]
+		} else {
+			headers[k] = vs
+		}
+	}
+
+	out := batchResponse{Status: status, Headers: headers}
+	switch raw := b.body.Bytes(); {
+	case len(raw) == 0:
+	case json.Valid(raw):
+		out.Body = json.RawMessage(raw)
+	default:
+		out.Body = string(raw)
+	}
+	return out
+}
@@ -0,0 +1,170 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ozgen/openapi-emulator/config"
+)
+
+func newBatchTestServer(t *testing.T) *Server {
+	t.Helper()
+	disableScenarioForTests()
+
+	dir := t.TempDir()
+	specPath := writeFile(t, dir, "spec.json", `{
+	  "openapi":"3.0.3",
+	  "info":{"title":"t","version":"1"},
+	  "paths":{
+	    "/batch":{"post":{
+	      "x-emulator-batch": true,
+	      "responses":{"200":{"description":"ok"}}
+	    }},
+	    "/items/{id}":{"get":{
+	      "responses":{"200":{"description":"ok","content":{"application/json":{"example":{"id":"example"}}}}}
+	    }}
+	  }
+	}`)
+	writeFileWithDirs(t, dir, filepath.Join("items", "{id}", "GET.json"),
+		`{"status":200,"headers":{"x-sample":"1","X-Trace-ID":"t1","Set-Cookie":["a=1","b=2"]},"body":{"id":"123"}}`)
+
+	s, err := New(Config{
+		Port:           "0",
+		SpecPath:       specPath,
+		SamplesDir:     dir,
+		FallbackMode:   config.FallbackOpenAPIExample,
+		ValidationMode: config.ValidationNone,
+		Layout:         config.LayoutFolders,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return s
+}
+
+func TestBatch_FansOutSubRequests(t *testing.T) {
+	s := newBatchTestServer(t)
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "http://example.com/batch", strings.NewReader(`[
+	  {"method":"GET","path":"/items/1"},
+	  {"method":"GET","path":"/nope"},
+	  {"method":"POST","path":"/batch","body":[]}
+	]`))
+
+	s.handle(rr, req)
+
+	if rr.Code != 200 {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var out []struct {
+		Status  int            `json:"status"`
+		Headers map[string]any `json:"headers"`
+		Body    map[string]any `json:"body"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &out); err != nil {
+		t.Fatalf("decode: %v: %s", err, rr.Body.String())
+	}
+	if len(out) != 3 {
+		t.Fatalf("expected 3 sub-responses, got %d", len(out))
+	}
+	if out[0].Status != 200 || out[0].Headers["x-sample"] != "1" || out[0].Body["id"] != "123" {
+		t.Fatalf("unexpected first sub-response: %+v", out[0])
+	}
+	if out[1].Status != 404 || out[1].Body["error"] != string(CodeRouteNotFound) {
+		t.Fatalf("unexpected second sub-response: %+v", out[1])
+	}
+	if out[2].Status != 400 || out[2].Body["error"] != string(CodeBatchInvalid) {
+		t.Fatalf("nested batch must be rejected, got %+v", out[2])
+	}
+}
+
+func TestBatch_RejectsAdminSubRequests(t *testing.T) {
+	s := newBatchTestServer(t)
+	s.requests.add(requestLogEntry{Path: "/items/1"})
+
+	rr := httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodPost, "http://example.com/batch", strings.NewReader(`[
+	  {"method":"DELETE","path":"/__admin/requests"},
+	  {"method":"GET","path":"/items/1"}
+	]`)))
+
+	var out []struct {
+		Status int            `json:"status"`
+		Body   map[string]any `json:"body"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &out); err != nil {
+		t.Fatalf("decode: %v: %s", err, rr.Body.String())
+	}
+	if len(out) != 2 || out[0].Status != 400 || out[0].Body["error"] != string(CodeBatchInvalid) {
+		t.Fatalf("expected the admin sub-request to be refused, got %+v", out)
+	}
+	if out[1].Status != 200 {
+		t.Fatalf("expected the other sub-request to run, got %+v", out[1])
+	}
+	if got := s.requests.since(0); len(got) == 0 || got[0].Path != "/items/1" {
+		t.Fatalf("the request log must not have been purged, got %+v", got)
+	}
+}
+
+func TestBatch_RepeatedAndCasePreservedHeaders(t *testing.T) {
+	for _, preserve := range []bool{false, true} {
+		s := newBatchTestServer(t)
+		s.cfg.PreserveHeaderCase = preserve
+
+		rr := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "http://example.com/batch",
+			strings.NewReader(`[{"method":"GET","path":"/items/1"}]`))
+
+		s.handle(rr, req)
+
+		if rr.Code != 200 {
+			t.Fatalf("preserve=%v: expected 200, got %d: %s", preserve, rr.Code, rr.Body.String())
+		}
+		var out []struct {
+			Headers map[string]any `json:"headers"`
+		}
+		if err := json.Unmarshal(rr.Body.Bytes(), &out); err != nil {
+			t.Fatalf("preserve=%v: decode: %v: %s", preserve, err, rr.Body.String())
+		}
+		h := out[0].Headers
+		if h["x-trace-id"] != "t1" {
+			t.Fatalf("preserve=%v: expected x-trace-id t1, got %#v", preserve, h)
+		}
+		cookies, ok := h["set-cookie"].([]any)
+		if !ok || len(cookies) != 2 || cookies[0] != "a=1" || cookies[1] != "b=2" {
+			t.Fatalf("preserve=%v: expected both cookies, got %#v", preserve, h["set-cookie"])
+		}
+		for k, v := range h {
+			if v == "" || v == nil {
+				t.Fatalf("preserve=%v: header %q has no value: %#v", preserve, k, h)
+			}
+		}
+	}
+}
+
+func TestBatch_InvalidBody_400(t *testing.T) {
+	s := newBatchTestServer(t)
+
+	for _, body := range []string{`{"method":"GET"}`, `[{"method":"GET","path":"items"}]`} {
+		rr := httptest.NewRecorder()
+		s.handle(rr, httptest.NewRequest(http.MethodPost, "http://example.com/batch", strings.NewReader(body)))
+
+		if rr.Code != 400 {
+			t.Fatalf("%s: expected 400, got %d: %s", body, rr.Code, rr.Body.String())
+		}
+		var m map[string]any
+		_ = json.Unmarshal(rr.Body.Bytes(), &m)
+		if m["error"] != string(CodeBatchInvalid) {
+			t.Fatalf("unexpected body: %v", m)
+		}
+	}
+}
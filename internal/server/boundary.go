@@ -0,0 +1,48 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/ozgen/openapi-emulator/internal/openapi"
+)
+
+// boundaryHeader replaces the sample with a body of schema boundary values:
+// "X-Mock-Boundary: min" or "max".
+const boundaryHeader = "X-Mock-Boundary"
+
+// serveBoundary answers the request with a boundary-value body when it
+// carries boundaryHeader. It returns false when the header is absent.
+func (s *Server) serveBoundary(w http.ResponseWriter, r *http.Request, rt *openapi.Route, status int, specialModes []string) bool {
+	raw := strings.ToLower(strings.TrimSpace(r.Header.Get(boundaryHeader)))
+	if raw == "" {
+		return false
+	}
+	kind := openapi.BoundaryKind(raw)
+	if kind != openapi.BoundaryMin && kind != openapi.BoundaryMax {
+		writeError(w, 400, CodeInvalidParameter, "Bad Request", map[string]any{
+			"details": boundaryHeader + " must be min or max",
+		})
+		return true
+	}
+
+	body, contentType, ok := s.specProvider.TryGetBoundaryBody(rt.Swagger, rt.Method, kind)
+	if !ok {
+		writeError(w, 422, CodeNoResponseSchema, "Operation has no response schema", map[string]any{
+			"method":      rt.Method,
+			"swaggerPath": rt.Swagger,
+		})
+		return true
+	}
+	if status == 0 {
+		status = 200
+	}
+	w.Header().Set("content-type", contentType)
+	w.WriteHeader(status)
+	_, _ = w.Write(injectSpecialStrings(body, specialModes))
+	return true
+}
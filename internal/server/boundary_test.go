@@ -0,0 +1,63 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ozgen/openapi-emulator/config"
+)
+
+func TestHandle_BoundaryHeader(t *testing.T) {
+	disableScenarioForTests()
+
+	dir := t.TempDir()
+	specPath := writeFile(t, dir, "spec.json", `{
+	  "openapi":"3.0.3",
+	  "info":{"title":"t","version":"1"},
+	  "paths":{
+	    "/items":{"get":{"responses":{"200":{"description":"ok","content":{"application/json":{
+	      "schema":{"type":"object","properties":{"count":{"type":"integer","format":"int32"}}}
+	    }}}}}},
+	    "/ping":{"get":{"responses":{"204":{"description":"no content"}}}}
+	  }
+	}`)
+	writeFileWithDirs(t, dir, "items/GET.json", `{"count":3}`)
+
+	s, err := New(Config{
+		Port:           "0",
+		SpecPath:       specPath,
+		SamplesDir:     dir,
+		FallbackMode:   config.FallbackNone,
+		ValidationMode: config.ValidationNone,
+		Layout:         config.LayoutFolders,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	cases := []struct {
+		path, value string
+		code        int
+		body        string
+	}{
+		{"/items", "max", 200, `"count":2147483647`},
+		{"/items", "MIN", 200, `"count":-2147483648`},
+		{"/items", "middle", 400, string(CodeInvalidParameter)},
+		{"/ping", "min", 422, string(CodeNoResponseSchema)},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(http.MethodGet, "http://example.com"+tc.path, nil)
+		req.Header.Set(boundaryHeader, tc.value)
+		rr := httptest.NewRecorder()
+		s.handle(rr, req)
+		if rr.Code != tc.code || !strings.Contains(rr.Body.String(), tc.body) {
+			t.Fatalf("%s %s: expected %d with %s, got %d: %s", tc.path, tc.value, tc.code, tc.body, rr.Code, rr.Body.String())
+		}
+	}
+}
@@ -0,0 +1,24 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"net/http"
+	"time"
+)
+
+// now is the emulator's clock: the wall clock shifted by CLOCK_SKEW. It is
+// used for everything a client sees, i.e. Date headers and token timestamps.
+func (s *Server) now() time.Time {
+	return time.Now().Add(s.cfg.ClockSkew)
+}
+
+// setDateHeader writes a skewed Date header. Without CLOCK_SKEW net/http
+// sets the real one.
+func (s *Server) setDateHeader(w http.ResponseWriter) {
+	if s.cfg.ClockSkew != 0 {
+		w.Header().Set("Date", s.now().UTC().Format(http.TimeFormat))
+	}
+}
@@ -0,0 +1,65 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ozgen/openapi-emulator/config"
+)
+
+func TestHandle_ClockSkew_DateHeader(t *testing.T) {
+	s := newTestServer(t, config.ValidationNone, config.FallbackOpenAPIExample)
+
+	rr := httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com/items/1", nil))
+	if got := rr.Header().Get("Date"); got != "" {
+		t.Fatalf("expected no Date header without CLOCK_SKEW, got %q", got)
+	}
+
+	s.cfg.ClockSkew = -10 * time.Minute
+	rr = httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com/items/1", nil))
+	date, err := http.ParseTime(rr.Header().Get("Date"))
+	if err != nil {
+		t.Fatalf("parse Date %q: %v", rr.Header().Get("Date"), err)
+	}
+	if d := time.Until(date); d > -9*time.Minute || d < -11*time.Minute {
+		t.Fatalf("expected Date about 10 minutes behind, got %v", d)
+	}
+}
+
+func TestOAuthToken_ClockSkew(t *testing.T) {
+	s := newOAuthTestServer(t)
+	s.cfg.ClockSkew = 2 * time.Hour
+
+	rr := postToken(s, "/oauth/token", "grant_type=client_credentials&client_id=sdk")
+	var tok map[string]any
+	_ = json.Unmarshal(rr.Body.Bytes(), &tok)
+	jwt, _ := tok["access_token"].(string)
+	payload, err := base64.RawURLEncoding.DecodeString(strings.Split(jwt, ".")[1])
+	if err != nil {
+		t.Fatalf("decode claims: %v", err)
+	}
+	var claims struct {
+		Iat int64 `json:"iat"`
+		Exp int64 `json:"exp"`
+	}
+	_ = json.Unmarshal(payload, &claims)
+
+	want := time.Now().Add(2 * time.Hour).Unix()
+	if claims.Iat < want-5 || claims.Iat > want+5 {
+		t.Fatalf("expected iat near %d, got %d", want, claims.Iat)
+	}
+	if claims.Exp-claims.Iat != int64(tokenTTL.Seconds()) {
+		t.Fatalf("unexpected exp %d for iat %d", claims.Exp, claims.Iat)
+	}
+}
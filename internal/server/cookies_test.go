@@ -0,0 +1,43 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/ozgen/openapi-emulator/config"
+)
+
+func TestHandle_SampleCookies_OneSetCookieEach(t *testing.T) {
+	s := newTestServer(t, config.ValidationNone, config.FallbackNone)
+	writeFile(t, filepath.Join(s.cfg.SamplesDir, "items"), "POST.json", `{
+	  "status": 201,
+	  "headers": {"X-Session": "new"},
+	  "cookies": [
+	    {"name": "session", "value": "{{ uuid }}", "path": "/", "httpOnly": true},
+	    {"name": "csrf", "value": "token-1", "sameSite": "Strict"}
+	  ],
+	  "body": {"ok": true}
+	}`)
+
+	rr := httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodPost, "http://example.com/items", nil))
+	if rr.Code != 201 {
+		t.Fatalf("expected 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+	cookies := rr.Result().Cookies()
+	if len(cookies) != 2 || cookies[0].Name != "session" || cookies[1].Name != "csrf" {
+		t.Fatalf("expected session and csrf cookies, got %v", rr.Header().Values("Set-Cookie"))
+	}
+	if len(cookies[0].Value) != 36 || !cookies[0].HttpOnly || cookies[1].SameSite != http.SameSiteStrictMode {
+		t.Fatalf("unexpected cookies %v", rr.Header().Values("Set-Cookie"))
+	}
+	if rr.Header().Get("X-Session") != "new" {
+		t.Fatalf("expected the sample headers as well")
+	}
+}
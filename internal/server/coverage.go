@@ -0,0 +1,125 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// RecordedRequest is one served request, as listed by GET /__admin/requests.
+type RecordedRequest struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+}
+
+// OperationCoverage counts the recorded requests that matched one operation.
+type OperationCoverage struct {
+	Method      string
+	SwaggerPath string
+	Hits        int
+}
+
+// CoverageReport lists every operation of the spec, except disabled ones,
+// with its hits. Unmatched counts recorded requests no operation matches.
+type CoverageReport struct {
+	Operations []OperationCoverage
+	Unmatched  int
+}
+
+// Covered returns the number of operations with at least one hit.
+func (r CoverageReport) Covered() int {
+	n := 0
+	for _, op := range r.Operations {
+		if op.Hits > 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// Percent returns the share of covered operations, 100 for a spec without
+// operations.
+func (r CoverageReport) Percent() float64 {
+	if len(r.Operations) == 0 {
+		return 100
+	}
+	return float64(r.Covered()) * 100 / float64(len(r.Operations))
+}
+
+// Coverage matches recorded requests against the spec's operations the
+// same way requests are routed, including HEAD served as GET.
+func (s *Server) Coverage(recorded []RecordedRequest) CoverageReport {
+	var report CoverageReport
+	index := map[string]int{}
+	for _, rt := range s.router().GetRoutes() {
+		if matchesAnyRoute(s.disabledRoutes, &rt) {
+			continue
+		}
+		index[rt.Method+" "+rt.Swagger] = len(report.Operations)
+		report.Operations = append(report.Operations, OperationCoverage{Method: rt.Method, SwaggerPath: rt.Swagger})
+	}
+
+	for _, req := range recorded {
+		method := strings.ToUpper(req.Method)
+		rt, _ := s.router().MatchRoute(method, req.Path)
+		if rt == nil && method == http.MethodHead {
+			rt, _ = s.router().MatchRoute(http.MethodGet, req.Path)
+		}
+		if rt == nil {
+			report.Unmatched++
+			continue
+		}
+		if i, ok := index[rt.Method+" "+rt.Swagger]; ok {
+			report.Operations[i].Hits++
+		}
+	}
+	return report
+}
+
+// LoadRecordings reads the .json files below dir. Each holds a saved
+// GET /__admin/requests response ({"requests": [...]}) or a plain array of
+// requests.
+func LoadRecordings(dir string) ([]RecordedRequest, error) {
+	var out []RecordedRequest
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.EqualFold(filepath.Ext(path), ".json") {
+			return nil
+		}
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		reqs, err := decodeRecording(b)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		out = append(out, reqs...)
+		return nil
+	})
+	return out, err
+}
+
+func decodeRecording(b []byte) ([]RecordedRequest, error) {
+	var list []RecordedRequest
+	if err := json.Unmarshal(b, &list); err == nil {
+		return list, nil
+	}
+	var dump struct {
+		Requests *[]RecordedRequest `json:"requests"`
+	}
+	if err := json.Unmarshal(b, &dump); err != nil || dump.Requests == nil {
+		return nil, fmt.Errorf(`expected {"requests": [...]} or an array of requests`)
+	}
+	return *dump.Requests, nil
+}
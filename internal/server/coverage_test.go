@@ -0,0 +1,67 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"testing"
+
+	"github.com/ozgen/openapi-emulator/config"
+	"github.com/ozgen/openapi-emulator/internal/matcher"
+)
+
+func TestCoverage_CountsMatchedOperations(t *testing.T) {
+	s := newTestServer(t, config.ValidationNone, config.FallbackNone)
+
+	report := s.Coverage([]RecordedRequest{
+		{Method: "GET", Path: "/items/1"},
+		{Method: "HEAD", Path: "/items/2"},
+		{Method: "GET", Path: "/unknown"},
+	})
+	if report.Covered() != 1 || len(report.Operations) != 2 || report.Percent() != 50 {
+		t.Fatalf("expected 1 of 2 operations covered, got %+v", report)
+	}
+	if report.Unmatched != 1 {
+		t.Fatalf("expected 1 unmatched request, got %d", report.Unmatched)
+	}
+	for _, op := range report.Operations {
+		if op.Method == "GET" && op.Hits != 2 {
+			t.Fatalf("expected 2 hits for GET /items/{id}, got %d", op.Hits)
+		}
+	}
+}
+
+func TestCoverage_SkipsDisabledOperations(t *testing.T) {
+	s := newTestServer(t, config.ValidationNone, config.FallbackNone)
+	s.disabledRoutes, _ = matcher.ParseRoutes([]string{"POST /items"})
+
+	report := s.Coverage([]RecordedRequest{{Method: "GET", Path: "/items/1"}})
+	if len(report.Operations) != 1 || report.Percent() != 100 {
+		t.Fatalf("expected the disabled operation to be left out, got %+v", report)
+	}
+}
+
+func TestLoadRecordings_AdminDumpAndArray(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "a.json", `{"requests":[{"seq":1,"method":"GET","path":"/items/1","status":200}]}`)
+	writeFileWithDirs(t, dir, "nested/b.json", `[{"method":"POST","path":"/items"}]`)
+	writeFile(t, dir, "notes.txt", `not a recording`)
+
+	got, err := LoadRecordings(dir)
+	if err != nil {
+		t.Fatalf("LoadRecordings: %v", err)
+	}
+	if len(got) != 2 || got[0].Path != "/items/1" || got[1].Method != "POST" {
+		t.Fatalf("unexpected recordings: %+v", got)
+	}
+}
+
+func TestLoadRecordings_RejectsOtherJSON(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "a.json", `{"ok":true}`)
+
+	if _, err := LoadRecordings(dir); err == nil {
+		t.Fatalf("expected an error")
+	}
+}
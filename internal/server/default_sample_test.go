@@ -0,0 +1,46 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/ozgen/openapi-emulator/config"
+)
+
+func TestHandle_DefaultSample(t *testing.T) {
+	s := newTestServer(t, config.ValidationNone, config.FallbackOpenAPIExample)
+
+	rr := httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com/telemetry", nil))
+	if rr.Code != 404 {
+		t.Fatalf("expected 404 without a catch-all sample, got %d", rr.Code)
+	}
+
+	writeFileWithDirs(t, s.cfg.SamplesDir, filepath.Join("_default", "ANY.json"), `{"status":204}`)
+	writeFileWithDirs(t, s.cfg.SamplesDir, filepath.Join("_default", "GET.json"), `{"status":200,"body":{"ok":true}}`)
+
+	rr = httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com/telemetry", nil))
+	if rr.Code != 200 || rr.Body.String() != `{"ok":true}` {
+		t.Fatalf("expected GET catch-all, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	rr = httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodPost, "http://example.com/telemetry/events", nil))
+	if rr.Code != 204 {
+		t.Fatalf("expected ANY catch-all, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	// routes of the spec still win
+	rr = httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com/items/1", nil))
+	if rr.Code != 200 || rr.Header().Get("x-sample") != "1" {
+		t.Fatalf("expected the route sample, got %d: %s", rr.Code, rr.Body.String())
+	}
+}
@@ -0,0 +1,295 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/ozgen/openapi-emulator/internal/samples"
+	"github.com/ozgen/openapi-emulator/utils"
+)
+
+// diffSide names one of the two responses compared by /__admin/diff: a
+// scenario state or a sample file relative to SAMPLES_DIR.
+type diffSide struct {
+	State  string `json:"state,omitempty"`
+	File   string `json:"file,omitempty"`
+	Status int    `json:"status"`
+}
+
+// diffChange is one difference between the two responses. Path is a JSON
+// pointer into {"status", "headers", "cookies", "body"}.
+type diffChange struct {
+	Op   string `json:"op"` // add, remove or replace
+	Path string `json:"path"`
+	From any    `json:"from,omitempty"`
+	To   any    `json:"to,omitempty"`
+}
+
+// handleAdminDiff renders a route's response twice and returns the
+// differences:
+// GET /__admin/diff?method=GET&path=/scans/1&fromState=queued&toFile=scans/{id}/GET.v2.json
+// Each side is either a scenario state (fromState, toState) or a sample file
+// (fromFile, toFile). Rendering never advances scenario state.
+func (s *Server) handleAdminDiff(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+
+	q := r.URL.Query()
+	method := strings.ToUpper(strings.TrimSpace(q.Get("method")))
+	reqPath := strings.TrimSpace(q.Get("path"))
+	if method == "" || reqPath == "" {
+		writeError(w, 400, CodeInvalidParameter, "Bad Request", map[string]any{
+			"details": "query parameters 'method' and 'path' are required",
+		})
+		return
+	}
+
+	from, err := parseDiffSide(q.Get("fromState"), q.Get("fromFile"), "from")
+	if err == nil {
+		var to diffSide
+		if to, err = parseDiffSide(q.Get("toState"), q.Get("toFile"), "to"); err == nil {
+			s.writeDiff(w, method, reqPath, from, to)
+			return
+		}
+	}
+	writeError(w, 400, CodeInvalidParameter, "Bad Request", map[string]any{
+		"details": err.Error(),
+	})
+}
+
+func parseDiffSide(state, file, name string) (diffSide, error) {
+	state, file = strings.TrimSpace(state), strings.TrimSpace(file)
+	switch {
+	case state != "" && file != "":
+		return diffSide{}, fmt.Errorf("set only one of '%sState' and '%sFile'", name, name)
+	case state == "" && file == "":
+		return diffSide{}, fmt.Errorf("one of '%sState' and '%sFile' is required", name, name)
+	case file != "":
+		clean := path.Clean("/" + file)
+		if clean != "/"+strings.TrimPrefix(file, "/") {
+			return diffSide{}, fmt.Errorf("'%sFile' must be a clean path inside SAMPLES_DIR", name)
+		}
+		return diffSide{File: strings.TrimPrefix(clean, "/")}, nil
+	}
+	return diffSide{State: state}, nil
+}
+
+func (s *Server) writeDiff(w http.ResponseWriter, method, reqPath string, from, to diffSide) {
+	rt, params := s.router().MatchRoute(method, reqPath)
+	if rt == nil {
+		writeError(w, 404, CodeRouteNotFound, "No route", map[string]any{
+			"method": method,
+			"path":   reqPath,
+		})
+		return
+	}
+
+	if (from.State != "" || to.State != "") && !s.hasScenario(rt.Method, rt.Swagger, rt.SampleFile, rt.TagFile) {
+		writeError(w, 400, CodeInvalidParameter, "Bad Request", map[string]any{
+			"details": fmt.Sprintf("%s %s has no scenario; compare sample files instead", rt.Method, rt.Swagger),
+		})
+		return
+	}
+
+	docs := make([]map[string]any, 2)
+	for i, side := range []*diffSide{&from, &to} {
+		opts := samples.LoadOptions{ForceState: side.State, TagFile: rt.TagFile, Path: params, IgnoreExpect: true}
+		var resp *samples.Response
+		var err error
+		if side.File != "" {
+			resp, err = s.sampleProvider.LoadSample(side.File, opts)
+		} else {
+			resp, err = s.sampleProvider.ResolveAndLoad(rt.Method, rt.Swagger, reqPath, rt.SampleFile, opts)
+		}
+		if err != nil {
+			status, code := diffLoadError(err)
+			writeError(w, status, code, "Cannot render response", map[string]any{
+				"method":      rt.Method,
+				"swaggerPath": rt.Swagger,
+				"state":       side.State,
+				"file":        side.File,
+				"details":     err.Error(),
+			})
+			return
+		}
+		side.Status = resp.Status
+		docs[i] = diffDocument(resp)
+	}
+
+	changes := []diffChange{}
+	diffValues("", docs[0], docs[1], &changes)
+	utils.WriteJSON(w, 200, map[string]any{
+		"method":      rt.Method,
+		"swaggerPath": rt.Swagger,
+		"from":        from,
+		"to":          to,
+		"changes":     changes,
+	})
+}
+
+// hasScenario reports whether the route's samples come from a scenario.
+func (s *Server) hasScenario(method, swaggerPath, sampleFile, tagFile string) bool {
+	for _, f := range s.sampleProvider.SampleFiles(method, swaggerPath, sampleFile, tagFile) {
+		if f.State != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func diffLoadError(err error) (int, ErrorCode) {
+	var stateErr *samples.UnknownScenarioStateError
+	var syntaxErr *samples.SampleSyntaxError
+	var envErr *samples.SampleEnvelopeError
+	var tplErr *samples.SampleTemplateError
+	var includeErr *samples.SampleIncludeError
+	switch {
+	case errors.As(err, &stateErr):
+		return 400, CodeScenarioStateUnknown
+	case errors.As(err, &syntaxErr):
+		return 500, CodeSampleInvalidJSON
+	case errors.As(err, &envErr):
+		return 500, CodeSampleInvalidEnvelope
+	case errors.As(err, &tplErr):
+		return 500, CodeSampleTemplateError
+	case errors.As(err, &includeErr):
+		return 500, CodeSampleIncludeFailed
+	}
+	return 404, CodeSampleNotFound
+}
+
+// diffDocument turns a response into the document that is diffed. Header
+// names are canonicalized and a header sent several times is an array of
+// its values; cookies are keyed by name; a body that is not JSON is
+// compared as a string.
+func diffDocument(resp *samples.Response) map[string]any {
+	headers := map[string]any{}
+	for k, v := range resp.Headers {
+		switch len(v) {
+		case 0:
+		case 1:
+			headers[http.CanonicalHeaderKey(k)] = v[0]
+		default:
+			values := make([]any, len(v))
+			for i, value := range v {
+				values[i] = value
+			}
+			headers[http.CanonicalHeaderKey(k)] = values
+		}
+	}
+	cookies := map[string]any{}
+	for _, c := range resp.Cookies {
+		cookies[c.Name] = diffCookie(c)
+	}
+	var body any
+	if err := json.Unmarshal(resp.Body, &body); err != nil {
+		body = string(resp.Body)
+	}
+	return map[string]any{"status": resp.Status, "headers": headers, "cookies": cookies, "body": body}
+}
+
+// diffCookie lists the value and the attributes set on c, so a change to
+// one attribute is reported on its own.
+func diffCookie(c *http.Cookie) map[string]any {
+	doc := map[string]any{"value": c.Value}
+	if c.Path != "" {
+		doc["path"] = c.Path
+	}
+	if c.Domain != "" {
+		doc["domain"] = c.Domain
+	}
+	if !c.Expires.IsZero() {
+		doc["expires"] = c.Expires.UTC().Format(http.TimeFormat)
+	}
+	if c.MaxAge != 0 {
+		doc["maxAge"] = c.MaxAge
+	}
+	if c.Secure {
+		doc["secure"] = true
+	}
+	if c.HttpOnly {
+		doc["httpOnly"] = true
+	}
+	switch c.SameSite {
+	case http.SameSiteLaxMode:
+		doc["sameSite"] = "Lax"
+	case http.SameSiteStrictMode:
+		doc["sameSite"] = "Strict"
+	case http.SameSiteNoneMode:
+		doc["sameSite"] = "None"
+	}
+	return doc
+}
+
+// diffValues appends the changes turning a into b. Objects are compared by
+// key and arrays by index; anything else is replaced as a whole.
+func diffValues(ptr string, a, b any, out *[]diffChange) {
+	switch av := a.(type) {
+	case map[string]any:
+		bv, ok := b.(map[string]any)
+		if !ok {
+			break
+		}
+		keys := make([]string, 0, len(av)+len(bv))
+		for k := range av {
+			keys = append(keys, k)
+		}
+		for k := range bv {
+			if _, ok := av[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		slices.Sort(keys)
+		for _, k := range keys {
+			p := ptr + "/" + escapePointer(k)
+			x, inA := av[k]
+			y, inB := bv[k]
+			switch {
+			case !inA:
+				*out = append(*out, diffChange{Op: "add", Path: p, To: y})
+			case !inB:
+				*out = append(*out, diffChange{Op: "remove", Path: p, From: x})
+			default:
+				diffValues(p, x, y, out)
+			}
+		}
+		return
+	case []any:
+		bv, ok := b.([]any)
+		if !ok {
+			break
+		}
+		for i := 0; i < max(len(av), len(bv)); i++ {
+			p := ptr + "/" + strconv.Itoa(i)
+			switch {
+			case i >= len(av):
+				*out = append(*out, diffChange{Op: "add", Path: p, To: bv[i]})
+			case i >= len(bv):
+				*out = append(*out, diffChange{Op: "remove", Path: p, From: av[i]})
+			default:
+				diffValues(p, av[i], bv[i], out)
+			}
+		}
+		return
+	}
+	if !reflect.DeepEqual(a, b) {
+		*out = append(*out, diffChange{Op: "replace", Path: ptr, From: a, To: b})
+	}
+}
+
+// escapePointer escapes a key for use in a JSON pointer (RFC 6901).
+func escapePointer(k string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(k, "~", "~0"), "/", "~1")
+}
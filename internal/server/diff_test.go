@@ -0,0 +1,126 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/ozgen/openapi-emulator/config"
+)
+
+type diffResponse struct {
+	From    diffSide     `json:"from"`
+	To      diffSide     `json:"to"`
+	Changes []diffChange `json:"changes"`
+}
+
+func getDiff(t *testing.T, s *Server, query string) (*httptest.ResponseRecorder, diffResponse) {
+	t.Helper()
+	rr := httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com/__admin/diff?"+query, nil))
+	var out diffResponse
+	_ = json.Unmarshal(rr.Body.Bytes(), &out)
+	return rr, out
+}
+
+func TestAdminDiff_ScenarioStates(t *testing.T) {
+	s := newIsolationTestServer(t, config.IsolationNone)
+
+	rr, out := getDiff(t, s, "method=GET&path=/scans/1&fromState=queued&toState=running")
+	if rr.Code != 200 {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	want := []diffChange{{Op: "replace", Path: "/body/state", From: "queued", To: "running"}}
+	if !reflect.DeepEqual(out.Changes, want) {
+		t.Fatalf("unexpected changes: %+v", out.Changes)
+	}
+	if out.From.State != "queued" || out.To.State != "running" || out.From.Status != 200 {
+		t.Fatalf("unexpected sides: %+v %+v", out.From, out.To)
+	}
+
+	// Rendering must not advance the scenario.
+	get := httptest.NewRecorder()
+	s.handle(get, httptest.NewRequest(http.MethodGet, "/scans/1", nil))
+	if got := get.Body.String(); got != `{"state":"queued"}` {
+		t.Fatalf("expected the scenario to start at queued, got %s", got)
+	}
+
+	if rr, _ := getDiff(t, s, "method=GET&path=/scans/1&fromState=queued&toState=nope"); rr.Code != 400 {
+		t.Fatalf("expected 400 for an unknown state, got %d", rr.Code)
+	}
+}
+
+func TestAdminDiff_SampleFiles(t *testing.T) {
+	s := newTestServer(t, config.ValidationNone, config.FallbackOpenAPIExample)
+	writeFileWithDirs(t, s.cfg.SamplesDir, filepath.Join("items", "{id}", "GET.v2.json"), `{
+	  "status": 200,
+	  "headers": {"content-type":"application/json","X-Version":"2"},
+	  "body": {"id":"123","tags":["a"],"owner":{"name":"x/y"}}
+	}`)
+
+	rr, out := getDiff(t, s, "method=GET&path=/items/1&fromFile=items/{id}/GET.json&toFile=items/{id}/GET.v2.json")
+	if rr.Code != 200 {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	want := []diffChange{
+		{Op: "add", Path: "/body/owner", To: map[string]any{"name": "x/y"}},
+		{Op: "add", Path: "/body/tags", To: []any{"a"}},
+		{Op: "remove", Path: "/headers/X-Sample", From: "1"},
+		{Op: "add", Path: "/headers/X-Version", To: "2"},
+	}
+	if !reflect.DeepEqual(out.Changes, want) {
+		t.Fatalf("unexpected changes: %+v", out.Changes)
+	}
+}
+
+func TestAdminDiff_RepeatedHeadersAndCookies(t *testing.T) {
+	s := newTestServer(t, config.ValidationNone, config.FallbackOpenAPIExample)
+	writeFileWithDirs(t, s.cfg.SamplesDir, filepath.Join("items", "{id}", "GET.a.json"), `{
+	  "headers": {"Link": ["</items?page=2>; rel=\"next\"", "</items?page=9>; rel=\"last\""]},
+	  "cookies": [{"name": "session", "value": "abc", "path": "/"}],
+	  "body": {}
+	}`)
+	writeFileWithDirs(t, s.cfg.SamplesDir, filepath.Join("items", "{id}", "GET.b.json"), `{
+	  "headers": {"Link": ["</items?page=2>; rel=\"next\"", "</items?page=8>; rel=\"last\""]},
+	  "cookies": [{"name": "session", "value": "abc", "path": "/", "httpOnly": true}, {"name": "theme", "value": "dark"}],
+	  "body": {}
+	}`)
+
+	rr, out := getDiff(t, s, "method=GET&path=/items/1&fromFile=items/{id}/GET.a.json&toFile=items/{id}/GET.b.json")
+	if rr.Code != 200 {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	want := []diffChange{
+		{Op: "add", Path: "/cookies/session/httpOnly", To: true},
+		{Op: "add", Path: "/cookies/theme", To: map[string]any{"value": "dark"}},
+		{Op: "replace", Path: "/headers/Link/1", From: `</items?page=9>; rel="last"`, To: `</items?page=8>; rel="last"`},
+	}
+	if !reflect.DeepEqual(out.Changes, want) {
+		t.Fatalf("unexpected changes: %+v", out.Changes)
+	}
+}
+
+func TestAdminDiff_BadRequests(t *testing.T) {
+	s := newTestServer(t, config.ValidationNone, config.FallbackOpenAPIExample)
+
+	for query, status := range map[string]int{
+		"method=GET&path=/items/1&fromFile=items/{id}/GET.json":                                400,
+		"method=GET&path=/items/1&fromFile=a.json&fromState=x&toFile=b.json":                   400,
+		"method=GET&path=/items/1&fromFile=../spec.json&toFile=items/{id}/GET.json":            400,
+		"method=GET&path=/items/1&fromState=queued&toFile=items/{id}/GET.json":                 400,
+		"method=GET&path=/items/1&fromFile=items/{id}/GET.json&toFile=items/{id}/missing.json": 404,
+		"method=GET&path=/nothing&fromFile=items/{id}/GET.json&toFile=items/{id}/GET.json":     404,
+		"path=/items/1&fromFile=items/{id}/GET.json&toFile=items/{id}/GET.json":                400,
+	} {
+		if rr, _ := getDiff(t, s, query); rr.Code != status {
+			t.Fatalf("%s: expected %d, got %d: %s", query, status, rr.Code, rr.Body.String())
+		}
+	}
+}
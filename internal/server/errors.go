@@ -0,0 +1,62 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"net/http"
+
+	"github.com/ozgen/openapi-emulator/utils"
+)
+
+// ErrorCode is the stable, machine-readable identifier of an emulator error.
+// Clients should branch on it rather than on the human-readable message.
+type ErrorCode string
+
+const (
+	CodeRouteNotFound           ErrorCode = "ROUTE_NOT_FOUND"
+	CodeRequestBodyRequired     ErrorCode = "REQUEST_BODY_REQUIRED"
+	CodeRequestBodyUnreadable   ErrorCode = "REQUEST_BODY_UNREADABLE"
+	CodeRequestBodyInvalid      ErrorCode = "REQUEST_BODY_INVALID"
+	CodeRequestParameterInvalid ErrorCode = "REQUEST_PARAMETER_INVALID"
+	CodeSampleNotFound          ErrorCode = "SAMPLE_NOT_FOUND"
+	CodeSampleInvalidJSON       ErrorCode = "SAMPLE_INVALID_JSON"
+	CodeSampleInvalidEnvelope   ErrorCode = "SAMPLE_INVALID_ENVELOPE"
+	CodeSampleTemplateError     ErrorCode = "SAMPLE_TEMPLATE_ERROR"
+	CodeSampleIncludeFailed     ErrorCode = "SAMPLE_INCLUDE_FAILED"
+	CodeAdminNotFound           ErrorCode = "ADMIN_ENDPOINT_NOT_FOUND"
+	CodeMethodNotAllowed        ErrorCode = "METHOD_NOT_ALLOWED"
+	CodeInvalidParameter        ErrorCode = "INVALID_PARAMETER"
+	CodeNoRequestSchema         ErrorCode = "NO_REQUEST_SCHEMA"
+	CodeSpecReloadFailed        ErrorCode = "SPEC_RELOAD_FAILED"
+	CodeScenarioStateUnknown    ErrorCode = "SCENARIO_STATE_UNKNOWN"
+	CodeBatchInvalid            ErrorCode = "BATCH_INVALID"
+	CodeUnauthorized            ErrorCode = "UNAUTHORIZED"
+	CodeJobNotFound             ErrorCode = "JOB_NOT_FOUND"
+	CodeJobNotFinished          ErrorCode = "JOB_NOT_FINISHED"
+	CodeUnsupportedMediaType    ErrorCode = "UNSUPPORTED_MEDIA_TYPE"
+	CodeRetryLater              ErrorCode = "RETRY_LATER"
+	CodeMaintenance             ErrorCode = "MAINTENANCE"
+	CodeChaosFault              ErrorCode = "CHAOS_FAULT"
+	CodeSampleSchemaMismatch    ErrorCode = "SAMPLE_SCHEMA_MISMATCH"
+	CodeRouteDisabled           ErrorCode = "ROUTE_DISABLED"
+	CodeOperationNotFound       ErrorCode = "OPERATION_NOT_FOUND"
+	CodeNoResponseSchema        ErrorCode = "NO_RESPONSE_SCHEMA"
+	CodeScenarioStoreFailed     ErrorCode = "SCENARIO_STORE_FAILED"
+	CodeExpectationFailed       ErrorCode = "EXPECTATION_FAILED"
+	CodeRequestUnexpected       ErrorCode = "REQUEST_UNEXPECTED"
+	CodeUpstreamUnavailable     ErrorCode = "UPSTREAM_UNAVAILABLE"
+	CodeSamplesDirUnreadable    ErrorCode = "SAMPLES_DIR_UNREADABLE"
+)
+
+// writeError writes {"error": code, "message": message, ...fields}.
+func writeError(w http.ResponseWriter, status int, code ErrorCode, message string, fields map[string]any) {
+	out := map[string]any{}
+	for k, v := range fields {
+		out[k] = v
+	}
+	out["error"] = code
+	out["message"] = message
+	utils.WriteJSON(w, status, out)
+}
@@ -0,0 +1,33 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWriteError_CodeMessageAndFields(t *testing.T) {
+	rr := httptest.NewRecorder()
+
+	writeError(rr, 404, CodeRouteNotFound, "No route", map[string]any{
+		"path":  "/x",
+		"error": "must not override the code",
+	})
+
+	if rr.Code != 404 {
+		t.Fatalf("expected 404, got %d", rr.Code)
+	}
+	if ct := rr.Header().Get("content-type"); ct != "application/json" {
+		t.Fatalf("expected application/json, got %q", ct)
+	}
+
+	var m map[string]any
+	_ = json.Unmarshal(rr.Body.Bytes(), &m)
+	if m["error"] != "ROUTE_NOT_FOUND" || m["message"] != "No route" || m["path"] != "/x" {
+		t.Fatalf("unexpected body: %v", m)
+	}
+}
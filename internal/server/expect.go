@@ -0,0 +1,46 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/ozgen/openapi-emulator/internal/openapi"
+)
+
+// rejectExpectation answers requests carrying an Expect header the emulator
+// will not honour, with 417 and before the body is read. "100-continue" is
+// honoured unless the operation is listed in EXPECT_CONTINUE_REJECT; the
+// 100 Continue interim response itself is sent by net/http once the handler
+// starts reading the body, so a request whose body no validator or sample
+// needs gets its final response straight away. It returns true when a
+// response was written.
+func (s *Server) rejectExpectation(w http.ResponseWriter, r *http.Request, rt *openapi.Route) bool {
+	expect := strings.TrimSpace(r.Header.Get("Expect"))
+	if expect == "" {
+		return false
+	}
+
+	if !strings.EqualFold(expect, "100-continue") {
+		writeError(w, 417, CodeExpectationFailed, "Expectation Failed", map[string]any{
+			"details": "unsupported expectation " + expect,
+		})
+		return true
+	}
+	if !matchesAnyRoute(s.noContinueRoutes, rt) {
+		return false
+	}
+
+	// The client has not sent the body; close the connection rather than
+	// wait for it.
+	w.Header().Set("Connection", "close")
+	writeError(w, 417, CodeExpectationFailed, "Expectation Failed", map[string]any{
+		"method":      rt.Method,
+		"swaggerPath": rt.Swagger,
+		"details":     "the operation is listed in EXPECT_CONTINUE_REJECT",
+	})
+	return true
+}
@@ -0,0 +1,114 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ozgen/openapi-emulator/config"
+	"github.com/ozgen/openapi-emulator/internal/matcher"
+)
+
+// expectContinue sends the headers of a POST /items with
+// "Expect: 100-continue" and returns the first status line the server sends
+// back. On 100 Continue the body is sent and the final status line returned
+// as well.
+func expectContinue(t *testing.T, addr string) (first, final string) {
+	t.Helper()
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	body := `{"name":"a"}`
+	_, err = conn.Write([]byte("POST /items HTTP/1.1\r\nHost: example.com\r\nContent-Type: application/json\r\n" +
+		"Content-Length: 12\r\nExpect: 100-continue\r\n\r\n"))
+	if err != nil {
+		t.Fatalf("write headers: %v", err)
+	}
+
+	rd := bufio.NewReader(conn)
+	first, err = rd.ReadString('\n')
+	if err != nil {
+		t.Fatalf("read status: %v", err)
+	}
+	if !strings.Contains(first, " 100 ") {
+		return strings.TrimSpace(first), ""
+	}
+	// Skip the blank line ending the interim response.
+	if _, err := rd.ReadString('\n'); err != nil {
+		t.Fatalf("read interim response: %v", err)
+	}
+	if _, err := conn.Write([]byte(body)); err != nil {
+		t.Fatalf("write body: %v", err)
+	}
+	final, err = rd.ReadString('\n')
+	if err != nil {
+		t.Fatalf("read final status: %v", err)
+	}
+	return strings.TrimSpace(first), strings.TrimSpace(final)
+}
+
+func TestHandle_ExpectContinue_SendsContinueThenResponse(t *testing.T) {
+	s := newTestServer(t, config.ValidationNone, config.FallbackOpenAPIExample)
+	// The body is only read, and 100 Continue only sent, when the sample
+	// uses it.
+	writeFileWithDirs(t, s.cfg.SamplesDir, filepath.Join("items", "POST.json"),
+		`{"status":201,"body":{"name":"{{ .Body.name }}"}}`)
+	ts := httptest.NewServer(http.HandlerFunc(s.handle))
+	defer ts.Close()
+
+	first, final := expectContinue(t, ts.Listener.Addr().String())
+	if first != "HTTP/1.1 100 Continue" {
+		t.Fatalf("expected 100 Continue, got %q", first)
+	}
+	if !strings.HasPrefix(final, "HTTP/1.1 201") {
+		t.Fatalf("expected 201 after the body, got %q", final)
+	}
+}
+
+func TestHandle_ExpectContinue_RejectedBeforeBody(t *testing.T) {
+	s := newTestServer(t, config.ValidationNone, config.FallbackOpenAPIExample)
+	s.noContinueRoutes, _ = matcher.ParseRoutes([]string{"POST /items"})
+	ts := httptest.NewServer(http.HandlerFunc(s.handle))
+	defer ts.Close()
+
+	first, _ := expectContinue(t, ts.Listener.Addr().String())
+	if !strings.HasPrefix(first, "HTTP/1.1 417") {
+		t.Fatalf("expected 417 without 100 Continue, got %q", first)
+	}
+
+	// Other operations still get 100 Continue.
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/items/123", nil)
+	req.Header.Set("Expect", "100-continue")
+	s.handle(rr, req)
+	if rr.Code != 200 {
+		t.Fatalf("expected 200 for an operation not listed, got %d: %s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestHandle_Expect_UnsupportedExpectation(t *testing.T) {
+	s := newTestServer(t, config.ValidationNone, config.FallbackOpenAPIExample)
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "http://example.com/items", strings.NewReader(`{"name":"a"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Expect", "fast-path")
+	s.handle(rr, req)
+
+	if rr.Code != 417 || !strings.Contains(rr.Body.String(), string(CodeExpectationFailed)) {
+		t.Fatalf("expected 417 EXPECTATION_FAILED, got %d: %s", rr.Code, rr.Body.String())
+	}
+}
@@ -0,0 +1,64 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"net/http"
+
+	"github.com/ozgen/openapi-emulator/internal/samples"
+	"github.com/ozgen/openapi-emulator/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// routeFiles lists the spec's routes with the sample files they name.
+func (s *Server) routeFiles() []samples.RouteFiles {
+	var routes []samples.RouteFiles
+	for _, rt := range s.router().GetRoutes() {
+		routes = append(routes, samples.RouteFiles{
+			Method:      rt.Method,
+			SwaggerPath: rt.Swagger,
+			FlatFile:    rt.SampleFile,
+			TagFile:     rt.TagFile,
+			Sample:      s.specProvider.GetEmulatorExtensions(rt.Swagger, rt.Method).Sample,
+		})
+	}
+	return routes
+}
+
+func (s *Server) auditFlatSamples() (*samples.FlatSampleReport, error) {
+	return s.sampleProvider.AuditFlatSamples(s.routeFiles())
+}
+
+// warnFlatSamples logs the legacy flat samples auto layout never serves, so
+// stale fixtures show up at startup.
+func (s *Server) warnFlatSamples() {
+	report, err := s.auditFlatSamples()
+	if err != nil {
+		s.log.WithError(err).Debug("flat sample audit skipped")
+		return
+	}
+	for _, f := range report.Shadowed {
+		s.log.WithFields(logrus.Fields{"file": f.File, "shadowedBy": f.ShadowedBy}).Warn("flat sample is shadowed by a folder sample")
+	}
+	for _, f := range report.Orphaned {
+		s.log.WithField("file", f).Warn("flat sample matches no route")
+	}
+}
+
+// handleAdminFlatSamples lists shadowed and orphaned legacy flat samples:
+// GET /__admin/samples/flat
+func (s *Server) handleAdminFlatSamples(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+	report, err := s.auditFlatSamples()
+	if err != nil {
+		writeError(w, 500, CodeSampleNotFound, "Samples directory not readable", map[string]any{
+			"details": err.Error(),
+		})
+		return
+	}
+	utils.WriteJSON(w, 200, report)
+}
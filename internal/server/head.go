@@ -0,0 +1,53 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/ozgen/openapi-emulator/internal/openapi"
+)
+
+// headAsGetKey marks a HEAD request that matchHeadAsGet rewrote to GET.
+type headAsGetKey struct{}
+
+// matchHeadAsGet routes a HEAD request for a path that declares GET but no
+// HEAD to the GET operation. The returned request carries method GET so the
+// GET sample and validation apply; the returned writer drops the body so
+// only status and headers reach the client. The request stays read-only:
+// see isHeadAsGet.
+func (s *Server) matchHeadAsGet(w http.ResponseWriter, r *http.Request) (*openapi.Route, map[string]string, http.ResponseWriter, *http.Request) {
+	rt, params := s.router().MatchRoute(http.MethodGet, r.URL.Path)
+	if rt == nil {
+		return nil, nil, w, r
+	}
+	get := r.WithContext(context.WithValue(r.Context(), headAsGetKey{}, true))
+	get.Method = http.MethodGet
+	return rt, params, headWriter{w}, get
+}
+
+// isHeadAsGet reports whether r is a HEAD request answered from the GET
+// operation. Such a request must not change what the next GET sees: it
+// peeks at the scenario state instead of advancing it and does not count as
+// a backoff attempt.
+func isHeadAsGet(r *http.Request) bool {
+	head, _ := r.Context().Value(headAsGetKey{}).(bool)
+	return head
+}
+
+// headWriter discards the response body of a HEAD request.
+type headWriter struct {
+	http.ResponseWriter
+}
+
+func (w headWriter) Write(b []byte) (int, error) {
+	return len(b), nil
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (w headWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,129 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ozgen/openapi-emulator/config"
+)
+
+func TestHandle_HeadDerivedFromGet(t *testing.T) {
+	s := newTestServer(t, config.ValidationNone, config.FallbackNone)
+
+	rr := httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodHead, "http://example.com/items/123", nil))
+
+	if rr.Code != 200 {
+		t.Fatalf("expected GET sample status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if rr.Header().Get("x-sample") != "1" {
+		t.Fatalf("expected GET sample headers, got %v", rr.Header())
+	}
+	if rr.Body.Len() != 0 {
+		t.Fatalf("expected empty body, got %q", rr.Body.String())
+	}
+}
+
+func TestHandle_HeadWithoutGet(t *testing.T) {
+	s := newTestServer(t, config.ValidationNone, config.FallbackNone)
+
+	rr := httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodHead, "http://example.com/items", nil))
+
+	if rr.Code != 405 {
+		t.Fatalf("expected 405, got %d", rr.Code)
+	}
+	if got := rr.Header().Get("Allow"); got != "POST" {
+		t.Fatalf("expected Allow: POST, got %q", got)
+	}
+}
+
+func TestHandle_HeadDoesNotAdvanceScenario(t *testing.T) {
+	s := newTestServer(t, config.ValidationNone, config.FallbackNone)
+	config.Envs.Scenario.Enabled = true
+	t.Cleanup(disableScenarioForTests)
+
+	s2, err := New(s.cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	writeFileWithDirs(t, s.cfg.SamplesDir, filepath.Join("items", "{id}", "scenario.json"), `{
+	  "version": 1,
+	  "mode": "step",
+	  "key": {"pathParam": "id"},
+	  "sequence": [
+	    {"state": "pending", "file": "pending.json"},
+	    {"state": "done", "file": "done.json"}
+	  ],
+	  "behavior": {"advanceOn": [{"method": "GET"}]}
+	}`)
+	writeFileWithDirs(t, s.cfg.SamplesDir, filepath.Join("items", "{id}", "pending.json"), `{"state":"pending"}`)
+	writeFileWithDirs(t, s.cfg.SamplesDir, filepath.Join("items", "{id}", "done.json"), `{"state":"done"}`)
+
+	for i := 0; i < 2; i++ {
+		rr := httptest.NewRecorder()
+		s2.handle(rr, httptest.NewRequest(http.MethodHead, "http://example.com/items/1", nil))
+		if rr.Code != 200 || rr.Body.Len() != 0 {
+			t.Fatalf("HEAD %d: expected 200 without body, got %d: %q", i+1, rr.Code, rr.Body.String())
+		}
+	}
+
+	rr := httptest.NewRecorder()
+	s2.handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com/items/1", nil))
+	if !strings.Contains(rr.Body.String(), `"pending"`) {
+		t.Fatalf("HEAD must not advance the scenario, got %s", rr.Body.String())
+	}
+
+	var methods []string
+	for _, e := range s2.requests.since(0) {
+		methods = append(methods, e.Method)
+	}
+	if strings.Join(methods, ",") != "HEAD,HEAD,GET" {
+		t.Fatalf("expected HEAD requests to be logged as HEAD, got %v", methods)
+	}
+}
+
+func TestHandle_HeadIsNotABackoffAttempt(t *testing.T) {
+	disableScenarioForTests()
+
+	dir := t.TempDir()
+	specPath := writeFile(t, dir, "spec.json", `{
+	  "openapi":"3.0.3",
+	  "info":{"title":"t","version":"1"},
+	  "paths":{"/items/{id}":{"get":{
+	    "x-emulator-backoff": {"failures": 1, "status": 429, "retryAfterSec": 1},
+	    "responses":{"200":{"description":"ok"}}
+	  }}}
+	}`)
+	writeFileWithDirs(t, dir, filepath.Join("items", "{id}", "GET.json"), `{"id":"1"}`)
+
+	s, err := New(Config{
+		Port:           "0",
+		SpecPath:       specPath,
+		SamplesDir:     dir,
+		FallbackMode:   config.FallbackNone,
+		ValidationMode: config.ValidationNone,
+		Layout:         config.LayoutFolders,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	rr := httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodHead, "http://example.com/items/1", nil))
+	if rr.Code != 200 {
+		t.Fatalf("expected HEAD to skip the backoff, got %d", rr.Code)
+	}
+	rr = httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com/items/1", nil))
+	if rr.Code != 429 {
+		t.Fatalf("expected the first GET to be the first attempt, got %d", rr.Code)
+	}
+}
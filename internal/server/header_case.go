@@ -0,0 +1,41 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// setSampleHeaders copies the headers of a sample to w. net/http
+// canonicalizes names set through Header().Set; with PRESERVE_HEADER_CASE
+// the names are stored as the sample spells them, which HTTP/1.1 writes to
+// the wire verbatim. HTTP/2 lowercases every name regardless. A header with
+// several values is written once per value.
+func (s *Server) setSampleHeaders(w http.ResponseWriter, headers http.Header) {
+	h := w.Header()
+	for k, values := range headers {
+		if !s.cfg.PreserveHeaderCase {
+			h.Del(k)
+			for _, v := range values {
+				h.Add(k, v)
+			}
+			continue
+		}
+		for existing := range h {
+			if strings.EqualFold(existing, k) {
+				delete(h, existing)
+			}
+		}
+		h[k] = slices.Clone(values)
+		// net/http looks up Content-Type, Content-Length and Date by their
+		// canonical names and adds its own when they are absent; a nil
+		// value suppresses that without writing a second header.
+		if canonical := http.CanonicalHeaderKey(k); canonical != k {
+			h[canonical] = nil
+		}
+	}
+}
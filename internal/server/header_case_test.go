@@ -0,0 +1,109 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ozgen/openapi-emulator/config"
+)
+
+// rawResponseHeaders sends GET path over a plain connection and returns the
+// header lines exactly as written to the wire.
+func rawResponseHeaders(t *testing.T, addr, path string) []string {
+	t.Helper()
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	if _, err := conn.Write([]byte("GET " + path + " HTTP/1.1\r\nHost: example.com\r\nConnection: close\r\n\r\n")); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	rd := bufio.NewReader(conn)
+	if _, err := rd.ReadString('\n'); err != nil {
+		t.Fatalf("read status: %v", err)
+	}
+	var out []string
+	for {
+		line, err := rd.ReadString('\n')
+		if err != nil {
+			t.Fatalf("read header: %v", err)
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			return out
+		}
+		out = append(out, line)
+	}
+}
+
+func headerLine(lines []string, prefix string) bool {
+	for _, l := range lines {
+		if strings.HasPrefix(l, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestHandle_PreserveHeaderCase(t *testing.T) {
+	s := newTestServer(t, config.ValidationNone, config.FallbackOpenAPIExample)
+	writeFileWithDirs(t, s.cfg.SamplesDir, filepath.Join("items", "{id}", "GET.json"), `{
+	  "headers": {"x-request-ID": "abc", "content-type": "application/json", "ETAG": "\"v1\""},
+	  "body": {"id": "123"}
+	}`)
+	ts := httptest.NewServer(http.HandlerFunc(s.handle))
+	defer ts.Close()
+
+	lines := rawResponseHeaders(t, ts.Listener.Addr().String(), "/items/123")
+	if !headerLine(lines, "X-Request-Id: abc") || !headerLine(lines, "Content-Type: application/json") {
+		t.Fatalf("expected canonical names by default, got %q", lines)
+	}
+
+	s.cfg.PreserveHeaderCase = true
+	lines = rawResponseHeaders(t, ts.Listener.Addr().String(), "/items/123")
+	for _, want := range []string{"x-request-ID: abc", "content-type: application/json", `ETAG: "v1"`} {
+		if !headerLine(lines, want) {
+			t.Fatalf("expected %q, got %q", want, lines)
+		}
+	}
+	if headerLine(lines, "Content-Type:") || headerLine(lines, "X-Request-Id:") {
+		t.Fatalf("expected no canonical duplicates, got %q", lines)
+	}
+}
+
+func TestHandle_RepeatedHeaders(t *testing.T) {
+	s := newTestServer(t, config.ValidationNone, config.FallbackOpenAPIExample)
+	writeFileWithDirs(t, s.cfg.SamplesDir, filepath.Join("items", "{id}", "GET.json"), `{
+	  "headers": {"warning": ["199 - \"stale\"", "299 - \"deprecated\""], "X-Id": "abc"},
+	  "body": {"id": "123"}
+	}`)
+	ts := httptest.NewServer(http.HandlerFunc(s.handle))
+	defer ts.Close()
+
+	for _, preserve := range []bool{false, true} {
+		s.cfg.PreserveHeaderCase = preserve
+		name := "Warning"
+		if preserve {
+			name = "warning"
+		}
+		lines := rawResponseHeaders(t, ts.Listener.Addr().String(), "/items/123")
+		for _, want := range []string{name + `: 199 - "stale"`, name + `: 299 - "deprecated"`, "X-Id: abc"} {
+			if !headerLine(lines, want) {
+				t.Fatalf("preserve=%v: expected %q, got %q", preserve, want, lines)
+			}
+		}
+	}
+}
@@ -0,0 +1,34 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/ozgen/openapi-emulator/config"
+	"github.com/ozgen/openapi-emulator/internal/openapi"
+)
+
+// clientID identifies the caller for STATE_ISOLATION. Requests with the same
+// id share scenario and backoff state; "" (isolation off, or no identity in
+// the request) shares state with everyone else without one.
+func (s *Server) clientID(r *http.Request) string {
+	switch s.cfg.StateIsolation {
+	case config.IsolationIP:
+		host, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			return r.RemoteAddr
+		}
+		return host
+	case config.IsolationAPIKey:
+		return openapi.RequestCredential(r, s.specProvider.GetSpec())
+	case config.IsolationHeader:
+		return strings.TrimSpace(r.Header.Get(s.cfg.StateIsolationHeader))
+	default:
+		return ""
+	}
+}
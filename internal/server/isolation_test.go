@@ -0,0 +1,139 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ozgen/openapi-emulator/config"
+)
+
+func newIsolationTestServer(t *testing.T, mode config.IsolationMode) *Server {
+	t.Helper()
+	config.Envs.Scenario.Enabled = true
+	config.Envs.Scenario.Filename = "scenario.json"
+	t.Cleanup(disableScenarioForTests)
+
+	dir := t.TempDir()
+	specPath := writeFile(t, dir, "spec.json", `{
+	  "openapi":"3.0.3",
+	  "info":{"title":"t","version":"1"},
+	  "paths":{"/scans/{id}":{"get":{"responses":{"200":{"description":"ok"}}}}}
+	}`)
+	scDir := filepath.Join("scans", "{id}")
+	writeFileWithDirs(t, dir, filepath.Join(scDir, "scenario.json"), `{
+	  "version": 1,
+	  "mode": "step",
+	  "key": {"pathParam": "id"},
+	  "sequence": [
+	    {"state": "queued", "file": "queued.json"},
+	    {"state": "running", "file": "running.json"}
+	  ],
+	  "behavior": {"advanceOn": [{"method": "GET"}], "repeatLast": true}
+	}`)
+	writeFileWithDirs(t, dir, filepath.Join(scDir, "queued.json"), `{"state":"queued"}`)
+	writeFileWithDirs(t, dir, filepath.Join(scDir, "running.json"), `{"state":"running"}`)
+
+	s, err := New(Config{
+		Port:                 "0",
+		SpecPath:             specPath,
+		SamplesDir:           dir,
+		FallbackMode:         config.FallbackNone,
+		ValidationMode:       config.ValidationNone,
+		StateIsolation:       mode,
+		StateIsolationHeader: "X-Client-Id",
+		Layout:               config.LayoutFolders,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return s
+}
+
+func TestStateIsolation_Header(t *testing.T) {
+	s := newIsolationTestServer(t, config.IsolationHeader)
+
+	get := func(client string) string {
+		req := httptest.NewRequest(http.MethodGet, "http://example.com/scans/1", nil)
+		req.Header.Set("X-Client-Id", client)
+		rr := httptest.NewRecorder()
+		s.handle(rr, req)
+		return rr.Body.String()
+	}
+
+	_ = get("job-a")
+	if body := get("job-a"); !strings.Contains(body, "running") {
+		t.Fatalf("expected job-a to advance, got %s", body)
+	}
+	if body := get("job-b"); !strings.Contains(body, "queued") {
+		t.Fatalf("expected job-b to start fresh, got %s", body)
+	}
+
+	views := s.scenarios.list()
+	if len(views) != 2 || views[0].Client != "job-a" || views[0].State != "running" || views[1].State != "queued" {
+		t.Fatalf("unexpected scenario board: %+v", views)
+	}
+}
+
+func TestStateIsolation_IP(t *testing.T) {
+	s := newIsolationTestServer(t, config.IsolationIP)
+
+	get := func(remoteAddr string) string {
+		req := httptest.NewRequest(http.MethodGet, "http://example.com/scans/1", nil)
+		req.RemoteAddr = remoteAddr
+		rr := httptest.NewRecorder()
+		s.handle(rr, req)
+		return rr.Body.String()
+	}
+
+	_ = get("10.0.0.1:1111")
+	if body := get("10.0.0.1:2222"); !strings.Contains(body, "running") {
+		t.Fatalf("expected the same IP to share state across ports, got %s", body)
+	}
+	if body := get("10.0.0.2:1111"); !strings.Contains(body, "queued") {
+		t.Fatalf("expected another IP to start fresh, got %s", body)
+	}
+}
+
+func TestScenarioStateURL_Unreachable_503(t *testing.T) {
+	config.Envs.Scenario.StateURL = "redis://127.0.0.1:1"
+	t.Cleanup(func() { config.Envs.Scenario.StateURL = "" })
+	s := newIsolationTestServer(t, config.IsolationNone)
+
+	rr := httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodGet, "/scans/1", nil))
+
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var m map[string]any
+	_ = json.Unmarshal(rr.Body.Bytes(), &m)
+	if m["error"] != string(CodeScenarioStoreFailed) {
+		t.Fatalf("unexpected body: %v", m)
+	}
+}
+
+func TestNew_InvalidScenarioStateURL(t *testing.T) {
+	config.Envs.Scenario.Enabled = true
+	config.Envs.Scenario.StateURL = "memcached://cache:11211"
+	t.Cleanup(func() { config.Envs.Scenario.StateURL = "" })
+	t.Cleanup(disableScenarioForTests)
+
+	dir := t.TempDir()
+	_, err := New(Config{
+		Port:       "0",
+		SpecPath:   writeFile(t, dir, "spec.json", minimalSpec()),
+		SamplesDir: dir,
+		Layout:     config.LayoutFolders,
+	})
+	if err == nil || !strings.Contains(err.Error(), "SCENARIO_STATE_URL") {
+		t.Fatalf("expected SCENARIO_STATE_URL error, got %v", err)
+	}
+}
@@ -0,0 +1,226 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"crypto/rand"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ozgen/openapi-emulator/internal/openapi"
+	"github.com/ozgen/openapi-emulator/utils"
+)
+
+// jobRetention is how long a finished job can still be polled before it is
+// dropped, so a long-running emulator does not keep every job forever.
+const jobRetention = time.Hour
+
+// jobRegistry backs x-emulator-job: it knows the status and result routes of
+// every job-creating operation and the jobs created so far (in-memory).
+type jobRegistry struct {
+	mu       sync.Mutex
+	byCreate map[string]*openapi.JobConfig
+	byStatus map[string]*openapi.JobConfig
+	byResult map[string]*openapi.JobConfig
+	jobs     map[string]jobRun
+
+	now func() time.Time
+}
+
+type jobRun struct {
+	cfg       *openapi.JobConfig
+	startedAt time.Time
+}
+
+// expired reports whether the run finished more than jobRetention ago.
+func (run jobRun) expired(now time.Time) bool {
+	return now.Sub(run.startedAt) > time.Duration(run.cfg.DurationSec)*time.Second+jobRetention
+}
+
+func newJobRegistry() *jobRegistry {
+	return &jobRegistry{
+		byCreate: map[string]*openapi.JobConfig{},
+		byStatus: map[string]*openapi.JobConfig{},
+		byResult: map[string]*openapi.JobConfig{},
+		jobs:     map[string]jobRun{},
+		now:      time.Now,
+	}
+}
+
+// index rebuilds the job route lookup from the POST operations carrying
+// x-emulator-job. Jobs already created are kept.
+func (j *jobRegistry) index(routes []openapi.Route, spec openapi.ISpecProvider) {
+	byCreate := map[string]*openapi.JobConfig{}
+	byStatus := map[string]*openapi.JobConfig{}
+	byResult := map[string]*openapi.JobConfig{}
+	for _, rt := range routes {
+		if rt.Method != http.MethodPost {
+			continue
+		}
+		cfg := spec.GetEmulatorExtensions(rt.Swagger, rt.Method).Job
+		if cfg == nil {
+			continue
+		}
+		if strings.TrimSpace(cfg.StatusPath) == "" {
+			cfg.StatusPath = itemRoute(routes, rt.Swagger)
+		}
+		cfg.Normalize(rt.Swagger)
+		byCreate[rt.Swagger] = cfg
+		byStatus[cfg.StatusPath] = cfg
+		byResult[cfg.ResultPath] = cfg
+	}
+
+	j.mu.Lock()
+	j.byCreate, j.byStatus, j.byResult = byCreate, byStatus, byResult
+	j.mu.Unlock()
+}
+
+// itemRoute finds the GET route addressing a single item of a collection,
+// e.g. /jobs/{jobId} for /jobs.
+func itemRoute(routes []openapi.Route, collection string) string {
+	prefix := strings.TrimSuffix(collection, "/") + "/"
+	for _, rt := range routes {
+		rest, ok := strings.CutPrefix(rt.Swagger, prefix)
+		if ok && rt.Method == http.MethodGet && !strings.Contains(rest, "/") &&
+			strings.HasPrefix(rest, "{") && strings.HasSuffix(rest, "}") {
+			return rt.Swagger
+		}
+	}
+	return ""
+}
+
+// serve answers job requests. It returns false when the request is not a job
+// request or when a finished job's result should be served from its sample.
+func (j *jobRegistry) serve(w http.ResponseWriter, r *http.Request, rt *openapi.Route) bool {
+	j.mu.Lock()
+	createCfg := j.byCreate[rt.Swagger]
+	statusCfg := j.byStatus[rt.Swagger]
+	resultCfg := j.byResult[rt.Swagger]
+	j.mu.Unlock()
+
+	if rt.Method == http.MethodPost && createCfg != nil {
+		j.create(w, r, createCfg)
+		return true
+	}
+	if rt.Method != http.MethodGet {
+		return false
+	}
+
+	switch {
+	case statusCfg != nil:
+		id := jobIDFromPath(r, statusCfg.StatusPath)
+		run, ok := j.lookup(id)
+		if !ok {
+			writeJobNotFound(w, id)
+			return true
+		}
+		utils.WriteJSON(w, 200, j.status(id, run))
+		return true
+	case resultCfg != nil:
+		id := jobIDFromPath(r, resultCfg.ResultPath)
+		run, ok := j.lookup(id)
+		if !ok {
+			writeJobNotFound(w, id)
+			return true
+		}
+		if st := j.status(id, run); st.Progress < 100 {
+			writeError(w, 409, CodeJobNotFinished, "Job not finished", map[string]any{
+				"id":     id,
+				"status": st.Status,
+			})
+			return true
+		}
+		return false
+	}
+	return false
+}
+
+type jobStatus struct {
+	ID       string `json:"id"`
+	Status   string `json:"status"`
+	Progress int    `json:"progress"`
+}
+
+func (j *jobRegistry) create(w http.ResponseWriter, r *http.Request, cfg *openapi.JobConfig) {
+	id := newUUID()
+
+	j.mu.Lock()
+	now := j.now()
+	for old, run := range j.jobs {
+		if run.expired(now) {
+			delete(j.jobs, old)
+		}
+	}
+	j.jobs[id] = jobRun{cfg: cfg, startedAt: now}
+	j.mu.Unlock()
+
+	w.Header().Set("Location", requestBasePath(r)+strings.Replace(cfg.StatusPath, "{"+jobIDParam(cfg.StatusPath)+"}", id, 1))
+	utils.WriteJSON(w, 202, jobStatus{ID: id, Status: cfg.States[0]})
+}
+
+// lookup returns the job id unless it is unknown or expired. Expired jobs are
+// also dropped whenever a job is created.
+func (j *jobRegistry) lookup(id string) (jobRun, bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	run, ok := j.jobs[id]
+	if ok && run.expired(j.now()) {
+		delete(j.jobs, id)
+		return jobRun{}, false
+	}
+	return run, ok
+}
+
+// purge drops every job and returns how many there were.
+func (j *jobRegistry) purge() int {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	n := len(j.jobs)
+	clear(j.jobs)
+	return n
+}
+
+// status spreads the states evenly over the duration: the first state at 0,
+// the last one once the duration has elapsed.
+func (j *jobRegistry) status(id string, run jobRun) jobStatus {
+	elapsed := j.now().Sub(run.startedAt)
+	total := time.Duration(run.cfg.DurationSec) * time.Second
+
+	progress := min(int(elapsed*100/total), 100)
+	last := len(run.cfg.States) - 1
+	idx := min(int(elapsed*time.Duration(last)/total), last)
+
+	return jobStatus{ID: id, Status: run.cfg.States[idx], Progress: progress}
+}
+
+func writeJobNotFound(w http.ResponseWriter, id string) {
+	writeError(w, 404, CodeJobNotFound, "Unknown job", map[string]any{"id": id})
+}
+
+// jobIDParam is the name of the last path parameter of a status path.
+func jobIDParam(statusPath string) string {
+	parts := strings.Split(strings.Trim(statusPath, "/"), "/")
+	for i := len(parts) - 1; i >= 0; i-- {
+		if strings.HasPrefix(parts[i], "{") && strings.HasSuffix(parts[i], "}") {
+			return strings.TrimSuffix(strings.TrimPrefix(parts[i], "{"), "}")
+		}
+	}
+	return ""
+}
+
+func jobIDFromPath(r *http.Request, tpl string) string {
+	return openapi.ContextPathParams(r.Context(), tpl, r.URL.Path)[jobIDParam(tpl)]
+}
+
+func newUUID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
@@ -0,0 +1,149 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ozgen/openapi-emulator/config"
+)
+
+func newJobsTestServer(t *testing.T) *Server {
+	t.Helper()
+	disableScenarioForTests()
+
+	dir := t.TempDir()
+	specPath := writeFile(t, dir, "spec.json", `{
+	  "openapi":"3.0.3",
+	  "info":{"title":"t","version":"1"},
+	  "paths":{
+	    "/jobs":{"post":{
+	      "x-emulator-job": {"durationSec": 20, "states": ["queued", "running", "done"]},
+	      "responses":{"202":{"description":"accepted"}}
+	    }},
+	    "/jobs/{jobId}":{"get":{"responses":{"200":{"description":"ok"}}}},
+	    "/jobs/{jobId}/result":{"get":{"responses":{"200":{"description":"ok"}}}}
+	  }
+	}`)
+	writeFileWithDirs(t, dir, filepath.Join("jobs", "{jobId}", "result", "GET.json"), `{"findings": 3}`)
+
+	s, err := New(Config{
+		Port:           "0",
+		SpecPath:       specPath,
+		SamplesDir:     dir,
+		FallbackMode:   config.FallbackNone,
+		ValidationMode: config.ValidationNone,
+		Layout:         config.LayoutFolders,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return s
+}
+
+func TestJobs_CreatePollAndResult(t *testing.T) {
+	s := newJobsTestServer(t)
+
+	start := time.Now()
+	s.jobs.now = func() time.Time { return start }
+
+	rr := httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodPost, "http://example.com/jobs", nil))
+	if rr.Code != 202 {
+		t.Fatalf("expected 202, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var created jobStatus
+	_ = json.Unmarshal(rr.Body.Bytes(), &created)
+	if created.ID == "" || created.Status != "queued" {
+		t.Fatalf("unexpected create body: %s", rr.Body.String())
+	}
+	if loc := rr.Header().Get("Location"); loc != "/jobs/"+created.ID {
+		t.Fatalf("unexpected Location: %q", loc)
+	}
+
+	get := func(path string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		s.handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com"+path, nil))
+		return rr
+	}
+
+	s.jobs.now = func() time.Time { return start.Add(10 * time.Second) }
+	rr = get("/jobs/" + created.ID)
+	var st jobStatus
+	_ = json.Unmarshal(rr.Body.Bytes(), &st)
+	if st.Status != "running" || st.Progress != 50 {
+		t.Fatalf("expected running at 50%%, got %s", rr.Body.String())
+	}
+	if rr = get("/jobs/" + created.ID + "/result"); rr.Code != 409 {
+		t.Fatalf("expected 409 before completion, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	s.jobs.now = func() time.Time { return start.Add(25 * time.Second) }
+	rr = get("/jobs/" + created.ID)
+	_ = json.Unmarshal(rr.Body.Bytes(), &st)
+	if st.Status != "done" || st.Progress != 100 {
+		t.Fatalf("expected done at 100%%, got %s", rr.Body.String())
+	}
+	if rr = get("/jobs/" + created.ID + "/result"); rr.Code != 200 || rr.Body.String() != `{"findings": 3}` {
+		t.Fatalf("expected result sample, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	if rr = get("/jobs/unknown"); rr.Code != 404 {
+		t.Fatalf("expected 404 for unknown job, got %d", rr.Code)
+	}
+	var m map[string]any
+	_ = json.Unmarshal(rr.Body.Bytes(), &m)
+	if m["error"] != string(CodeJobNotFound) {
+		t.Fatalf("unexpected body: %v", m)
+	}
+}
+
+func TestJobs_ExpireAfterRetentionAndPurge(t *testing.T) {
+	s := newJobsTestServer(t)
+	start := time.Now()
+	s.jobs.now = func() time.Time { return start }
+
+	create := func() string {
+		rr := httptest.NewRecorder()
+		s.handle(rr, httptest.NewRequest(http.MethodPost, "http://example.com/jobs", nil))
+		var created jobStatus
+		_ = json.Unmarshal(rr.Body.Bytes(), &created)
+		return created.ID
+	}
+	get := func(id string) int {
+		rr := httptest.NewRecorder()
+		s.handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com/jobs/"+id, nil))
+		return rr.Code
+	}
+
+	old := create()
+	s.jobs.now = func() time.Time { return start.Add(20*time.Second + jobRetention) }
+	if code := get(old); code != 200 {
+		t.Fatalf("expected a job to be kept for the retention after finishing, got %d", code)
+	}
+
+	s.jobs.now = func() time.Time { return start.Add(21*time.Second + jobRetention) }
+	fresh := create()
+	if n := len(s.jobs.jobs); n != 1 {
+		t.Fatalf("expected the expired job to be dropped on create, %d jobs left", n)
+	}
+	if code := get(old); code != 404 {
+		t.Fatalf("expected 404 for an expired job, got %d", code)
+	}
+
+	rr := httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodDelete, "http://example.com/__admin/jobs", nil))
+	if rr.Code != 200 || rr.Body.String() != `{"purged":1}` {
+		t.Fatalf("expected one job purged, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if code := get(fresh); code != 404 {
+		t.Fatalf("expected 404 after purge, got %d", code)
+	}
+}
@@ -0,0 +1,48 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/ozgen/openapi-emulator/internal/openapi"
+)
+
+// longPollInterval is how often a held request re-checks its scenario state.
+const longPollInterval = 100 * time.Millisecond
+
+// holdLongPoll blocks until the route's scenario state differs from the one
+// seen on arrival or timeout elapses. Routes without a scenario are held for
+// the full timeout. It returns false if the client went away.
+func (s *Server) holdLongPoll(w http.ResponseWriter, r *http.Request, swaggerPath string, timeout time.Duration) bool {
+	extendWriteDeadline(w, timeout)
+
+	client := s.clientID(r)
+	params := openapi.ContextPathParams(r.Context(), swaggerPath, r.URL.Path)
+	initial, err := s.sampleProvider.PeekScenarioState(r.Method, swaggerPath, r.URL.Path, params, client)
+	if err != nil || initial == nil {
+		return sleepCtx(r.Context(), timeout)
+	}
+
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	tick := time.NewTicker(longPollInterval)
+	defer tick.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return false
+		case <-deadline.C:
+			return true
+		case <-tick.C:
+			cur, err := s.sampleProvider.PeekScenarioState(r.Method, swaggerPath, r.URL.Path, params, client)
+			if err != nil || cur == nil || cur.State != initial.State || cur.Step != initial.Step {
+				return true
+			}
+		}
+	}
+}
@@ -0,0 +1,98 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ozgen/openapi-emulator/config"
+)
+
+func newLongPollTestServer(t *testing.T, holdMs int) *Server {
+	t.Helper()
+	config.Envs.Scenario.Enabled = true
+	config.Envs.Scenario.Filename = "scenario.json"
+	t.Cleanup(disableScenarioForTests)
+
+	dir := t.TempDir()
+	specPath := writeFile(t, dir, "spec.json", `{
+	  "openapi":"3.0.3",
+	  "info":{"title":"t","version":"1"},
+	  "paths":{"/scans/{id}":{
+	    "get":{"x-emulator-long-poll-ms": `+strconv.Itoa(holdMs)+`, "responses":{"200":{"description":"ok"}}},
+	    "put":{"responses":{"200":{"description":"ok"}}}
+	  }}
+	}`)
+	scDir := filepath.Join("scans", "{id}")
+	writeFileWithDirs(t, dir, filepath.Join(scDir, "scenario.json"), `{
+	  "version": 1,
+	  "mode": "step",
+	  "key": {"pathParam": "id"},
+	  "sequence": [
+	    {"state": "queued", "file": "queued.json"},
+	    {"state": "running", "file": "running.json"}
+	  ],
+	  "behavior": {"advanceOn": [{"method": "PUT"}], "repeatLast": true}
+	}`)
+	writeFileWithDirs(t, dir, filepath.Join(scDir, "queued.json"), `{"state":"queued"}`)
+	writeFileWithDirs(t, dir, filepath.Join(scDir, "running.json"), `{"state":"running"}`)
+
+	s, err := New(Config{
+		Port:           "0",
+		SpecPath:       specPath,
+		SamplesDir:     dir,
+		FallbackMode:   config.FallbackNone,
+		ValidationMode: config.ValidationNone,
+		Layout:         config.LayoutFolders,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return s
+}
+
+func TestLongPoll_RespondsWhenStateChanges(t *testing.T) {
+	s := newLongPollTestServer(t, 5000)
+
+	done := make(chan *httptest.ResponseRecorder)
+	start := time.Now()
+	go func() {
+		rr := httptest.NewRecorder()
+		s.handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com/scans/1", nil))
+		done <- rr
+	}()
+
+	time.Sleep(3 * longPollInterval)
+	s.handle(httptest.NewRecorder(), httptest.NewRequest(http.MethodPut, "http://example.com/scans/1", nil))
+
+	rr := <-done
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("expected release on state change, held for %v", elapsed)
+	}
+	if !strings.Contains(rr.Body.String(), "running") {
+		t.Fatalf("expected new state, got %d: %s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestLongPoll_TimesOutWithCurrentState(t *testing.T) {
+	s := newLongPollTestServer(t, 300)
+
+	start := time.Now()
+	rr := httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com/scans/1", nil))
+
+	if elapsed := time.Since(start); elapsed < 300*time.Millisecond {
+		t.Fatalf("expected the request to be held, returned after %v", elapsed)
+	}
+	if !strings.Contains(rr.Body.String(), "queued") {
+		t.Fatalf("expected current state, got %d: %s", rr.Code, rr.Body.String())
+	}
+}
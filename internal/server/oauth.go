@@ -0,0 +1,208 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ozgen/openapi-emulator/internal/openapi"
+	"github.com/ozgen/openapi-emulator/utils"
+)
+
+const (
+	defaultTokenPath = "/oauth/token"
+	tokenIssuer      = "openapi-emulator"
+	tokenTTL         = time.Hour
+
+	grantClientCredentials = "client_credentials"
+	grantPassword          = "password"
+)
+
+// oauthIssuer emulates the token endpoints of the spec's OAuth2 flows. It
+// hands out HS256-signed JWTs, which SECURITY_MODE=enforce accepts until
+// they expire.
+type oauthIssuer struct {
+	key []byte
+
+	mu     sync.RWMutex
+	grants map[string][]string // token path -> supported grant types
+
+	now func() time.Time
+}
+
+func newOAuthIssuer(signingKey string) *oauthIssuer {
+	return &oauthIssuer{
+		key:    []byte(signingKey),
+		grants: map[string][]string{},
+		now:    time.Now,
+	}
+}
+
+// index collects the token endpoints of the spec's clientCredentials and
+// password flows. Each flow's tokenUrl path is served, and so is
+// /oauth/token for all of them.
+func (o *oauthIssuer) index(spec *openapi.Spec) {
+	grants := map[string][]string{}
+	add := func(tokenURL, grant string) {
+		for _, p := range []string{tokenPath(tokenURL), defaultTokenPath} {
+			if p != "" && !slices.Contains(grants[p], grant) {
+				grants[p] = append(grants[p], grant)
+			}
+		}
+	}
+
+	if spec != nil && spec.Doc3 != nil && spec.Doc3.Components != nil {
+		for _, ref := range spec.Doc3.Components.SecuritySchemes {
+			if ref == nil || ref.Value == nil || ref.Value.Flows == nil || !strings.EqualFold(ref.Value.Type, "oauth2") {
+				continue
+			}
+			if f := ref.Value.Flows.ClientCredentials; f != nil {
+				add(f.TokenURL, grantClientCredentials)
+			}
+			if f := ref.Value.Flows.Password; f != nil {
+				add(f.TokenURL, grantPassword)
+			}
+		}
+	}
+
+	o.mu.Lock()
+	o.grants = grants
+	o.mu.Unlock()
+}
+
+func tokenPath(tokenURL string) string {
+	u, err := url.Parse(strings.TrimSpace(tokenURL))
+	if err != nil || u.Path == "" {
+		return ""
+	}
+	return "/" + strings.TrimPrefix(u.Path, "/")
+}
+
+// isIssued reports whether token is an unexpired JWT signed by this issuer.
+// Tokens are verified rather than stored, so a long-running emulator does
+// not accumulate every token it ever handed out.
+func (o *oauthIssuer) isIssued(token string) bool {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return false
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !hmac.Equal(sig, o.mac(parts[0]+"."+parts[1])) {
+		return false
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return false
+	}
+	var claims struct {
+		Iss string `json:"iss"`
+		Exp int64  `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return false
+	}
+	return claims.Iss == tokenIssuer && o.now().Unix() < claims.Exp
+}
+
+// handleOAuthToken serves POST requests to a token endpoint. It returns
+// false when the request is not for one.
+func (s *Server) handleOAuthToken(w http.ResponseWriter, r *http.Request) bool {
+	o := s.oauth
+	o.mu.RLock()
+	grants, ok := o.grants[r.URL.Path]
+	o.mu.RUnlock()
+	if !ok || r.Method != http.MethodPost {
+		return false
+	}
+
+	if err := r.ParseForm(); err != nil {
+		writeOAuthError(w, 400, "invalid_request", err.Error())
+		return true
+	}
+
+	grant := r.PostForm.Get("grant_type")
+	if !slices.Contains(grants, grant) {
+		writeOAuthError(w, 400, "unsupported_grant_type", "supported: "+strings.Join(grants, ", "))
+		return true
+	}
+
+	var subject string
+	switch grant {
+	case grantClientCredentials:
+		subject = r.PostForm.Get("client_id")
+		if user, _, ok := r.BasicAuth(); ok {
+			subject = user
+		}
+		if subject == "" {
+			writeOAuthError(w, 401, "invalid_client", "client_id is required")
+			return true
+		}
+	case grantPassword:
+		subject = r.PostForm.Get("username")
+		if subject == "" || r.PostForm.Get("password") == "" {
+			writeOAuthError(w, 400, "invalid_request", "username and password are required")
+			return true
+		}
+	}
+
+	scope := r.PostForm.Get("scope")
+	token := o.sign(subject, scope)
+
+	w.Header().Set("Cache-Control", "no-store")
+	body := map[string]any{
+		"access_token": token,
+		"token_type":   "Bearer",
+		"expires_in":   int(tokenTTL.Seconds()),
+	}
+	if scope != "" {
+		body["scope"] = scope
+	}
+	utils.WriteJSON(w, 200, body)
+	return true
+}
+
+func (o *oauthIssuer) sign(subject, scope string) string {
+	now := o.now()
+	header, _ := json.Marshal(map[string]string{"alg": "HS256", "typ": "JWT"})
+	claims := map[string]any{
+		"iss": tokenIssuer,
+		"sub": subject,
+		"iat": now.Unix(),
+		"exp": now.Add(tokenTTL).Unix(),
+		"jti": newUUID(),
+	}
+	if scope != "" {
+		claims["scope"] = scope
+	}
+	payload, _ := json.Marshal(claims)
+
+	enc := base64.RawURLEncoding
+	unsigned := enc.EncodeToString(header) + "." + enc.EncodeToString(payload)
+	return unsigned + "." + enc.EncodeToString(o.mac(unsigned))
+}
+
+func (o *oauthIssuer) mac(unsigned string) []byte {
+	mac := hmac.New(sha256.New, o.key)
+	mac.Write([]byte(unsigned))
+	return mac.Sum(nil)
+}
+
+// writeOAuthError answers in the RFC 6749 error format client SDKs expect.
+func writeOAuthError(w http.ResponseWriter, status int, code, description string) {
+	w.Header().Set("Cache-Control", "no-store")
+	utils.WriteJSON(w, status, map[string]string{
+		"error":             code,
+		"error_description": description,
+	})
+}
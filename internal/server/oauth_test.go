@@ -0,0 +1,162 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ozgen/openapi-emulator/config"
+)
+
+func newOAuthTestServer(t *testing.T) *Server {
+	t.Helper()
+	disableScenarioForTests()
+
+	dir := t.TempDir()
+	specPath := writeFile(t, dir, "spec.json", `{
+	  "openapi":"3.0.3",
+	  "info":{"title":"t","version":"1"},
+	  "components":{"securitySchemes":{"oauth":{"type":"oauth2","flows":{
+	    "clientCredentials":{"tokenUrl":"https://auth.example.com/connect/token","scopes":{}}
+	  }}}},
+	  "security":[{"oauth":[]}],
+	  "paths":{"/scans":{"get":{
+	    "responses":{"200":{"description":"ok","content":{"application/json":{"example":[]}}}}
+	  }}}
+	}`)
+
+	s, err := New(Config{
+		Port:            "0",
+		SpecPath:        specPath,
+		SamplesDir:      dir,
+		FallbackMode:    config.FallbackOpenAPIExample,
+		ValidationMode:  config.ValidationNone,
+		SecurityMode:    config.SecurityEnforce,
+		SecurityTokens:  []string{"static"},
+		OAuthSigningKey: "k",
+		Layout:          config.LayoutFolders,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return s
+}
+
+func postToken(s *Server, path, form string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "http://example.com"+path, strings.NewReader(form))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rr := httptest.NewRecorder()
+	s.handle(rr, req)
+	return rr
+}
+
+func TestOAuthToken_ClientCredentials_IssuesAcceptedJWT(t *testing.T) {
+	s := newOAuthTestServer(t)
+
+	for _, path := range []string{"/connect/token", "/oauth/token"} {
+		rr := postToken(s, path, "grant_type=client_credentials&client_id=sdk&scope=read")
+		if rr.Code != 200 {
+			t.Fatalf("%s: expected 200, got %d: %s", path, rr.Code, rr.Body.String())
+		}
+		var tok map[string]any
+		_ = json.Unmarshal(rr.Body.Bytes(), &tok)
+		if tok["token_type"] != "Bearer" || tok["scope"] != "read" {
+			t.Fatalf("unexpected token response: %v", tok)
+		}
+
+		jwt, _ := tok["access_token"].(string)
+		parts := strings.Split(jwt, ".")
+		if len(parts) != 3 {
+			t.Fatalf("expected a JWT, got %q", jwt)
+		}
+		mac := hmac.New(sha256.New, []byte("k"))
+		mac.Write([]byte(parts[0] + "." + parts[1]))
+		if base64.RawURLEncoding.EncodeToString(mac.Sum(nil)) != parts[2] {
+			t.Fatalf("JWT signature does not verify")
+		}
+		payload, _ := base64.RawURLEncoding.DecodeString(parts[1])
+		if !strings.Contains(string(payload), `"sub":"sdk"`) {
+			t.Fatalf("unexpected claims: %s", payload)
+		}
+
+		req := httptest.NewRequest(http.MethodGet, "http://example.com/scans", nil)
+		req.Header.Set("Authorization", "Bearer "+jwt)
+		rr = httptest.NewRecorder()
+		s.handle(rr, req)
+		if rr.Code != 200 {
+			t.Fatalf("issued token must be accepted, got %d: %s", rr.Code, rr.Body.String())
+		}
+	}
+}
+
+func TestOAuthToken_Errors(t *testing.T) {
+	s := newOAuthTestServer(t)
+
+	cases := []struct {
+		form string
+		want int
+		code string
+	}{
+		{"grant_type=password&username=u&password=p", 400, "unsupported_grant_type"},
+		{"grant_type=client_credentials", 401, "invalid_client"},
+	}
+	for _, tc := range cases {
+		rr := postToken(s, "/oauth/token", tc.form)
+		if rr.Code != tc.want {
+			t.Fatalf("%s: expected %d, got %d: %s", tc.form, tc.want, rr.Code, rr.Body.String())
+		}
+		var m map[string]string
+		_ = json.Unmarshal(rr.Body.Bytes(), &m)
+		if m["error"] != tc.code {
+			t.Fatalf("%s: unexpected body: %v", tc.form, m)
+		}
+	}
+}
+
+func TestOAuthToken_NoOAuthFlow_NotServed(t *testing.T) {
+	s := newTestServer(t, config.ValidationNone, config.FallbackOpenAPIExample)
+
+	if rr := postToken(s, "/oauth/token", "grant_type=client_credentials&client_id=x"); rr.Code != 404 {
+		t.Fatalf("expected 404 without an OAuth2 flow in the spec, got %d", rr.Code)
+	}
+}
+
+func TestOAuthIssuer_IsIssued_VerifiesSignatureAndExpiry(t *testing.T) {
+	o := newOAuthIssuer("k")
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	o.now = func() time.Time { return now }
+
+	token := o.sign("sdk", "read")
+	if !o.isIssued(token) {
+		t.Fatalf("expected a fresh token to be accepted")
+	}
+
+	parts := strings.Split(token, ".")
+	forged := parts[0] + "." + base64.RawURLEncoding.EncodeToString([]byte(`{"iss":"openapi-emulator","exp":9999999999}`)) + "." + parts[2]
+	other := newOAuthIssuer("other")
+	other.now = o.now
+	for name, tok := range map[string]string{
+		"forged claims": forged,
+		"other key":     other.sign("sdk", ""),
+		"not a JWT":     "abc",
+	} {
+		if o.isIssued(tok) {
+			t.Fatalf("%s: expected the token to be rejected", name)
+		}
+	}
+
+	now = now.Add(tokenTTL)
+	if o.isIssued(token) {
+		t.Fatalf("expected an expired token to be rejected")
+	}
+}
@@ -0,0 +1,44 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// paddingField is the filler field added by x-emulator-pad-bytes.
+const paddingField = "_padding"
+
+// padBody grows a JSON object body to exactly size bytes by adding a
+// paddingField string. Other bodies, bodies already at least size bytes and
+// bodies too small to take the field are returned unchanged.
+func padBody(body []byte, size int) []byte {
+	if size <= len(body) || !json.Valid(body) {
+		return body
+	}
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) < 2 || trimmed[0] != '{' {
+		return body
+	}
+
+	head := trimmed[:len(trimmed)-1] // without the closing brace
+	sep := ","
+	if len(bytes.TrimSpace(head[1:])) == 0 {
+		head, sep = head[:1], ""
+	}
+	field := `"` + paddingField + `":"`
+	fill := size - len(head) - len(sep) - len(field) - len(`"}`)
+	if fill < 0 {
+		return body
+	}
+
+	out := make([]byte, 0, size)
+	out = append(out, head...)
+	out = append(out, sep...)
+	out = append(out, field...)
+	out = append(out, bytes.Repeat([]byte("x"), fill)...)
+	return append(out, `"}`...)
+}
@@ -0,0 +1,88 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/ozgen/openapi-emulator/config"
+)
+
+func TestPadBody(t *testing.T) {
+	cases := []struct {
+		name string
+		body string
+		size int
+		pad  bool
+	}{
+		{"object", `{"id":"1"}`, 100, true},
+		{"empty object", `{ }`, 64, true},
+		{"trailing whitespace", "{\"id\":1}\n", 50, true},
+		{"already large", `{"id":"1"}`, 5, false},
+		{"no room for the field", `{"id":"1"}`, 12, false},
+		{"array", `[1,2,3]`, 100, false},
+		{"not json", `hello`, 100, false},
+	}
+	for _, tc := range cases {
+		got := padBody([]byte(tc.body), tc.size)
+		if !tc.pad {
+			if string(got) != tc.body {
+				t.Fatalf("%s: expected body unchanged, got %q", tc.name, got)
+			}
+			continue
+		}
+		if len(got) != tc.size {
+			t.Fatalf("%s: expected %d bytes, got %d: %s", tc.name, tc.size, len(got), got)
+		}
+		var m map[string]any
+		if err := json.Unmarshal(got, &m); err != nil {
+			t.Fatalf("%s: padded body is not JSON: %v: %s", tc.name, err, got)
+		}
+		if _, ok := m[paddingField]; !ok {
+			t.Fatalf("%s: missing %s: %s", tc.name, paddingField, got)
+		}
+	}
+}
+
+func TestHandle_PadBytes(t *testing.T) {
+	disableScenarioForTests()
+
+	dir := t.TempDir()
+	specPath := writeFile(t, dir, "spec.json", `{
+	  "openapi":"3.0.3",
+	  "info":{"title":"t","version":"1"},
+	  "paths":{"/items/{id}":{"get":{
+	    "x-emulator-pad-bytes": 65536,
+	    "responses":{"200":{"description":"ok"}}
+	  }}}
+	}`)
+	writeFileWithDirs(t, dir, filepath.Join("items", "{id}", "GET.json"), `{"status":200,"body":{"id":"1"}}`)
+
+	s, err := New(Config{
+		Port:           "0",
+		SpecPath:       specPath,
+		SamplesDir:     dir,
+		FallbackMode:   config.FallbackNone,
+		ValidationMode: config.ValidationNone,
+		Layout:         config.LayoutFolders,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	rr := httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com/items/1", nil))
+	if rr.Code != 200 || rr.Body.Len() != 65536 {
+		t.Fatalf("expected 200 with 65536 bytes, got %d with %d", rr.Code, rr.Body.Len())
+	}
+	var m map[string]any
+	if err := json.Unmarshal(rr.Body.Bytes(), &m); err != nil || m["id"] != "1" {
+		t.Fatalf("unexpected body: %v %v", err, m["id"])
+	}
+}
@@ -0,0 +1,63 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ozgen/openapi-emulator/internal/samples"
+)
+
+// preferredStatus returns the status asked for with "Prefer: code=404", or 0
+// when the request has no such preference. Other preferences are ignored.
+func preferredStatus(r *http.Request) (int, error) {
+	for _, line := range r.Header.Values("Prefer") {
+		for _, pref := range strings.Split(line, ",") {
+			// Preference parameters after ";" do not apply to code.
+			pref, _, _ = strings.Cut(pref, ";")
+			name, value, ok := strings.Cut(strings.TrimSpace(pref), "=")
+			if !ok || !strings.EqualFold(strings.TrimSpace(name), "code") {
+				continue
+			}
+			value = strings.Trim(strings.TrimSpace(value), `"`)
+			status, err := strconv.Atoi(value)
+			if err != nil || status < 100 || status > 599 {
+				return 0, fmt.Errorf("invalid Prefer code %q: not an HTTP status", value)
+			}
+			return status, nil
+		}
+	}
+	return 0, nil
+}
+
+// serveStatusSample answers with the route's status sample for status, e.g.
+// GET.500.json for an injected 500. It returns false when the request
+// matches no route or the route has no such sample.
+func (s *Server) serveStatusSample(w http.ResponseWriter, r *http.Request, status int) bool {
+	rt, params := s.router().MatchRoute(r.Method, r.URL.Path)
+	if rt == nil {
+		return false
+	}
+	resp, err := s.sampleProvider.ResolveAndLoad(r.Method, rt.Swagger, r.URL.Path, rt.SampleFile, samples.LoadOptions{
+		Status:  status,
+		Client:  s.clientID(r),
+		TagFile: rt.TagFile,
+		Cookies: requestCookies(r),
+		Path:    params,
+		Query:   requestQuery(r),
+		Header:  requestHeaders(r),
+	})
+	if err != nil {
+		return false
+	}
+	s.setSampleHeaders(w, resp.Headers)
+	setSampleCookies(w, resp.Cookies)
+	w.WriteHeader(resp.Status)
+	_, _ = w.Write(resp.Body)
+	return true
+}
@@ -0,0 +1,90 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ozgen/openapi-emulator/config"
+)
+
+func TestPreferredStatus(t *testing.T) {
+	cases := map[string]int{
+		"":                            0,
+		"return=minimal":              0,
+		"code=404":                    404,
+		`return=minimal, Code="503"`:  503,
+		"code=429; reason=throttling": 429,
+	}
+	for header, want := range cases {
+		req := httptest.NewRequest(http.MethodGet, "http://example.com/items/1", nil)
+		if header != "" {
+			req.Header.Set("Prefer", header)
+		}
+		got, err := preferredStatus(req)
+		if err != nil || got != want {
+			t.Fatalf("%q: expected %d, got %d, %v", header, want, got, err)
+		}
+	}
+
+	for _, header := range []string{"code=abc", "code=42", "code=600"} {
+		req := httptest.NewRequest(http.MethodGet, "http://example.com/items/1", nil)
+		req.Header.Set("Prefer", header)
+		if _, err := preferredStatus(req); err == nil {
+			t.Fatalf("%q: expected an error", header)
+		}
+	}
+}
+
+func TestHandle_PreferCode_ServesStatusSample(t *testing.T) {
+	s := newTestServer(t, config.ValidationNone, config.FallbackOpenAPIExample)
+	writeFileWithDirs(t, s.cfg.SamplesDir, filepath.Join("items", "{id}", "GET.404.json"), `{"error":"not found"}`)
+
+	get := func(prefer string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "http://example.com/items/123", nil)
+		req.Header.Set("Prefer", prefer)
+		s.handle(rr, req)
+		return rr
+	}
+
+	if rr := get("code=404"); rr.Code != 404 || !strings.Contains(rr.Body.String(), "not found") {
+		t.Fatalf("expected the 404 sample, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if rr := get("code=500"); rr.Code != 501 || !strings.Contains(rr.Body.String(), string(CodeSampleNotFound)) {
+		t.Fatalf("expected SAMPLE_NOT_FOUND without falling back, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if rr := get("code=nope"); rr.Code != 400 {
+		t.Fatalf("expected 400 for an invalid code, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if rr := get("return=minimal"); rr.Code != 200 {
+		t.Fatalf("expected the plain sample, got %d: %s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestAdminSwitches_ChaosServesStatusSample(t *testing.T) {
+	s := newTestServer(t, config.ValidationNone, config.FallbackOpenAPIExample)
+	writeFileWithDirs(t, s.cfg.SamplesDir, filepath.Join("items", "{id}", "GET.503.json"),
+		`{"headers":{"Retry-After":"5"},"body":{"error":"busy"}}`)
+	s.switches.set(Switches{ChaosRate: 1, ChaosStatus: 503}, time.Now())
+
+	rr := httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com/items/1", nil))
+	if rr.Code != 503 || !strings.Contains(rr.Body.String(), "busy") || rr.Header().Get("Retry-After") != "5" {
+		t.Fatalf("expected the 503 sample, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	// Routes without a sample for the status get the generic fault.
+	rr = httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodPost, "http://example.com/items", strings.NewReader(`{}`)))
+	if rr.Code != 503 || !strings.Contains(rr.Body.String(), string(CodeChaosFault)) {
+		t.Fatalf("expected CHAOS_FAULT, got %d: %s", rr.Code, rr.Body.String())
+	}
+}
@@ -0,0 +1,107 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ozgen/openapi-emulator/internal/openapi"
+	"github.com/ozgen/openapi-emulator/internal/samples"
+	"github.com/ozgen/openapi-emulator/utils"
+)
+
+// specInfo identifies the spec samples are validated against.
+type specInfo struct {
+	Version  string    `json:"specVersion,omitempty"`
+	SHA256   string    `json:"specSha256,omitempty"`
+	LoadedAt time.Time `json:"loadedAt"`
+}
+
+// recordSpec remembers the version and content hash of the loaded spec.
+func (s *Server) recordSpec() {
+	info := specInfo{LoadedAt: time.Now().UTC()}
+	if spec := s.specProvider.GetSpec(); spec != nil && spec.Doc3 != nil && spec.Doc3.Info != nil {
+		info.Version = spec.Doc3.Info.Version
+	}
+	if b, err := os.ReadFile(s.cfg.SpecPath); err == nil {
+		sum := sha256.Sum256(b)
+		info.SHA256 = hex.EncodeToString(sum[:])
+	}
+
+	s.routerMu.Lock()
+	s.spec = info
+	s.routerMu.Unlock()
+}
+
+type sampleProvenance struct {
+	File     string               `json:"file"`
+	State    string               `json:"state,omitempty"`
+	Modified *time.Time           `json:"modified,omitempty"`
+	Status   int                  `json:"status,omitempty"`
+	Drifted  bool                 `json:"drifted"`
+	Errors   []openapi.FieldError `json:"errors,omitempty"`
+	Error    string               `json:"error,omitempty"`
+}
+
+// handleAdminRouteProvenance reports, for one route, when each sample file
+// was last modified and whether it still matches the loaded spec:
+// GET /__admin/routes/{method}/{path}
+func (s *Server) handleAdminRouteProvenance(w http.ResponseWriter, r *http.Request, sub string) {
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+
+	method, path, _ := strings.Cut(sub, "/")
+	method = strings.ToUpper(method)
+	path = "/" + path
+	rt := s.router().FindRoute(method, path)
+	if rt == nil {
+		writeError(w, 404, CodeRouteNotFound, "No route", map[string]any{
+			"method": method,
+			"path":   path,
+		})
+		return
+	}
+
+	out := []sampleProvenance{}
+	for _, f := range s.sampleProvider.SampleFiles(rt.Method, rt.Swagger, rt.SampleFile, rt.TagFile) {
+		p := sampleProvenance{File: f.Path, State: f.State}
+		if rel, err := filepath.Rel(s.cfg.SamplesDir, f.Path); err == nil && !strings.HasPrefix(rel, "..") {
+			p.File = filepath.ToSlash(rel)
+		}
+		if !f.Modified.IsZero() {
+			mod := f.Modified.UTC()
+			p.Modified = &mod
+		}
+		if f.Err != nil {
+			p.Error = f.Err.Error()
+			out = append(out, p)
+			continue
+		}
+
+		p.Status = f.Response.Status
+		contentType, _ := samples.HeaderGet(f.Response.Headers, "Content-Type")
+		p.Errors = s.validator.ValidateResponseBody(rt.Swagger, rt.Method, f.Response.Status, contentType, f.Response.Body)
+		p.Drifted = len(p.Errors) > 0
+		out = append(out, p)
+	}
+
+	s.routerMu.RLock()
+	spec := s.spec
+	s.routerMu.RUnlock()
+
+	utils.WriteJSON(w, 200, map[string]any{
+		"method":           rt.Method,
+		"swaggerPath":      rt.Swagger,
+		"validatedAgainst": spec,
+		"samples":          out,
+	})
+}
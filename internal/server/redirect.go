@@ -0,0 +1,35 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"net/http"
+	"strings"
+)
+
+// prefixLocation prepends SERVER_BASE_PATH to an absolute-path Location
+// header of a sample, so a redirect to /items/1/status written against the
+// spec paths reaches the client as /gateway/v2/items/1/status. Full URLs,
+// relative references and locations already under the base path are kept.
+func prefixLocation(r *http.Request, headers http.Header) {
+	base := requestBasePath(r)
+	if base == "" {
+		return
+	}
+	for k, values := range headers {
+		if !strings.EqualFold(k, "Location") {
+			continue
+		}
+		for i, v := range values {
+			if !strings.HasPrefix(v, "/") || strings.HasPrefix(v, "//") {
+				continue
+			}
+			if v == base || strings.HasPrefix(v, base+"/") || strings.HasPrefix(v, base+"?") {
+				continue
+			}
+			values[i] = base + v
+		}
+	}
+}
@@ -0,0 +1,54 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/ozgen/openapi-emulator/config"
+)
+
+const redirectSample = `{"status": 303, "headers": {"Location": "/items/{{ .Path.id }}/status?from={{ index .Query "from" }}"}}`
+
+func TestHandle_RedirectSample_TemplatedLocation(t *testing.T) {
+	s := newTestServer(t, config.ValidationNone, config.FallbackNone)
+	writeFile(t, filepath.Join(s.cfg.SamplesDir, "items", "{id}"), "GET.json", redirectSample)
+
+	rr := httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com/items/42?from=list", nil))
+	if rr.Code != 303 {
+		t.Fatalf("expected 303, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if loc := rr.Header().Get("Location"); loc != "/items/42/status?from=list" {
+		t.Fatalf("unexpected Location %q", loc)
+	}
+	if rr.Body.Len() != 0 || rr.Header().Get("Content-Type") != "" {
+		t.Fatalf("expected an empty body without Content-Type, got %q %q", rr.Header().Get("Content-Type"), rr.Body.String())
+	}
+}
+
+func TestHandle_RedirectSample_KeepsBasePath(t *testing.T) {
+	cases := map[string]string{
+		"/items/{{ .Path.id }}/status":         "/gateway/items/7/status",
+		"/gateway/items/{{ .Path.id }}/status": "/gateway/items/7/status",
+		"https://other.example/items":          "https://other.example/items",
+		"status":                               "status",
+	}
+	for location, want := range cases {
+		s := newTestServer(t, config.ValidationNone, config.FallbackNone)
+		s.cfg.BasePath = "/gateway"
+		writeFile(t, filepath.Join(s.cfg.SamplesDir, "items", "{id}"), "GET.json",
+			`{"status": 302, "headers": {"Location": "`+location+`"}}`)
+
+		rr := httptest.NewRecorder()
+		s.handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com/gateway/items/7", nil))
+		if rr.Code != 302 || rr.Header().Get("Location") != want {
+			t.Fatalf("%s: expected 302 %q, got %d %q", location, want, rr.Code, rr.Header().Get("Location"))
+		}
+	}
+}
@@ -0,0 +1,162 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// defaultRequestLogSize is how many recent requests the admin request log
+// keeps unless REQUEST_LOG_SIZE says otherwise.
+const defaultRequestLogSize = 200
+
+type requestLogEntry struct {
+	Seq        int64     `json:"seq"`
+	Time       time.Time `json:"time"`
+	Method     string    `json:"method"`
+	Path       string    `json:"path"`
+	Query      string    `json:"query,omitempty"`
+	Status     int       `json:"status"`
+	DurationMs int64     `json:"durationMs"`
+}
+
+// requestLog is a bounded ring of the most recent emulated requests: once
+// full, each entry overwrites the oldest one. Entries older than maxAge are
+// left out of reads; a maxAge of 0 keeps them until they are overwritten.
+type requestLog struct {
+	mu sync.Mutex
+	// entries grows up to size; from then on head is the index of the
+	// oldest entry and the next one to be overwritten.
+	entries []requestLogEntry
+	head    int
+	seq     int64
+	size    int
+	maxAge  time.Duration
+	now     func() time.Time
+}
+
+// newRequestLog keeps up to size entries; a size below 1 means
+// defaultRequestLogSize.
+func newRequestLog(size int, maxAge time.Duration) *requestLog {
+	if size < 1 {
+		size = defaultRequestLogSize
+	}
+	return &requestLog{
+		entries: make([]requestLogEntry, 0, min(size, defaultRequestLogSize)),
+		size:    size,
+		maxAge:  maxAge,
+		now:     time.Now,
+	}
+}
+
+func (l *requestLog) add(e requestLogEntry) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.seq++
+	e.Seq = l.seq
+	if len(l.entries) < l.size {
+		l.entries = append(l.entries, e)
+		return
+	}
+	l.entries[l.head] = e
+	l.head = (l.head + 1) % l.size
+}
+
+// liveLocked calls fn for the entries not older than maxAge, oldest first.
+// Entries are added when a request completes but stamped with its start, so
+// a slow request can sit behind newer ones; every entry is checked, not only
+// a prefix.
+func (l *requestLog) liveLocked(fn func(requestLogEntry)) {
+	var cutoff time.Time
+	if l.maxAge > 0 {
+		cutoff = l.now().Add(-l.maxAge)
+	}
+	for i := range l.entries {
+		e := l.entries[(l.head+i)%len(l.entries)]
+		if l.maxAge > 0 && e.Time.Before(cutoff) {
+			continue
+		}
+		fn(e)
+	}
+}
+
+// purge drops every entry and returns how many had not expired yet.
+// Sequence numbers keep counting, so pollers using since are not confused.
+func (l *requestLog) purge() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	n := 0
+	l.liveLocked(func(requestLogEntry) { n++ })
+	l.entries = l.entries[:0]
+	l.head = 0
+	return n
+}
+
+// since returns the entries with a sequence number above seq, oldest first.
+func (l *requestLog) since(seq int64) []requestLogEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	out := []requestLogEntry{}
+	l.liveLocked(func(e requestLogEntry) {
+		if e.Seq > seq {
+			out = append(out, e)
+		}
+	})
+	return out
+}
+
+// track wraps w so the request is logged with its status once done is called.
+func (l *requestLog) track(w http.ResponseWriter, r *http.Request) *loggingWriter {
+	return &loggingWriter{
+		ResponseWriter: w,
+		log:            l,
+		entry: requestLogEntry{
+			Time:   time.Now(),
+			Method: r.Method,
+			Path:   r.URL.Path,
+			Query:  r.URL.RawQuery,
+		},
+	}
+}
+
+type loggingWriter struct {
+	http.ResponseWriter
+	log   *requestLog
+	entry requestLogEntry
+	// skip keeps the request out of the log, for routes in REQUEST_LOG_EXCLUDE.
+	skip bool
+}
+
+func (w *loggingWriter) WriteHeader(status int) {
+	if w.entry.Status == 0 {
+		w.entry.Status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *loggingWriter) Write(b []byte) (int, error) {
+	if w.entry.Status == 0 {
+		w.entry.Status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (w *loggingWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *loggingWriter) done() {
+	if w.skip {
+		return
+	}
+	w.entry.DurationMs = time.Since(w.entry.Time).Milliseconds()
+	w.log.add(w.entry)
+}
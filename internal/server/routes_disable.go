@@ -0,0 +1,41 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"net/http"
+
+	"github.com/ozgen/openapi-emulator/internal/matcher"
+	"github.com/ozgen/openapi-emulator/internal/openapi"
+)
+
+// matchesAnyRoute reports whether rt is listed in a "METHOD /path" route
+// list such as ROUTES_DISABLE, matched against its spec path template.
+func matchesAnyRoute(routes []matcher.Route, rt *openapi.Route) bool {
+	return matcher.MatchAnyRoute(routes, rt.Method, rt.Swagger)
+}
+
+// rejectDisabledRoute answers requests to operations listed in
+// ROUTES_DISABLE: with 404 as if the route was not deployed, or with 503
+// when ROUTES_DISABLE_STATUS=503. It returns true when a response was written.
+func (s *Server) rejectDisabledRoute(w http.ResponseWriter, rt *openapi.Route, path string) bool {
+	if !matchesAnyRoute(s.disabledRoutes, rt) {
+		return false
+	}
+
+	if s.cfg.RoutesDisableStatus == http.StatusServiceUnavailable {
+		writeError(w, 503, CodeRouteDisabled, "Route disabled", map[string]any{
+			"method":      rt.Method,
+			"path":        path,
+			"swaggerPath": rt.Swagger,
+		})
+		return true
+	}
+	writeError(w, 404, CodeRouteNotFound, "No route", map[string]any{
+		"method": rt.Method,
+		"path":   path,
+	})
+	return true
+}
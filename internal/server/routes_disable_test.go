@@ -0,0 +1,72 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ozgen/openapi-emulator/config"
+	"github.com/ozgen/openapi-emulator/internal/matcher"
+	"github.com/ozgen/openapi-emulator/internal/openapi"
+)
+
+func TestMatchesAnyRoute(t *testing.T) {
+	pats, err := matcher.ParseRoutes([]string{"delete /items/{id}", "* /admin/*"})
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	cases := []struct {
+		method, path string
+		want         bool
+	}{
+		{"DELETE", "/items/{id}", true},
+		{"GET", "/items/{id}", false},
+		{"POST", "/admin", true},
+		{"GET", "/admin/users/{id}", true},
+		{"GET", "/administrators", false},
+	}
+	for _, tc := range cases {
+		rt := &openapi.Route{Method: tc.method, Swagger: tc.path}
+		got := matchesAnyRoute(pats, rt)
+		if got != tc.want {
+			t.Fatalf("%s %s: expected %v, got %v", tc.method, tc.path, tc.want, got)
+		}
+	}
+
+	if _, err := matcher.ParseRoutes([]string{"/items"}); err == nil {
+		t.Fatalf("expected an error for an entry without method")
+	}
+}
+
+func TestHandle_RoutesDisable(t *testing.T) {
+	s := newTestServer(t, config.ValidationNone, config.FallbackOpenAPIExample)
+	s.disabledRoutes, _ = matcher.ParseRoutes([]string{"POST /items"})
+
+	post := func() *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "http://example.com/items", strings.NewReader(`{"name":"a"}`))
+		req.Header.Set("Content-Type", "application/json")
+		s.handle(rr, req)
+		return rr
+	}
+
+	if rr := post(); rr.Code != 404 || !strings.Contains(rr.Body.String(), string(CodeRouteNotFound)) {
+		t.Fatalf("expected 404 ROUTE_NOT_FOUND, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	s.cfg.RoutesDisableStatus = 503
+	if rr := post(); rr.Code != 503 || !strings.Contains(rr.Body.String(), string(CodeRouteDisabled)) {
+		t.Fatalf("expected 503 ROUTE_DISABLED, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	rr := httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com/items/1", nil))
+	if rr.Code != 200 {
+		t.Fatalf("other operations stay enabled, got %d", rr.Code)
+	}
+}
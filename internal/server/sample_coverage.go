@@ -0,0 +1,68 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"net/http"
+	"path/filepath"
+	"slices"
+
+	"github.com/ozgen/openapi-emulator/config"
+	"github.com/ozgen/openapi-emulator/internal/samples"
+	"github.com/ozgen/openapi-emulator/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// auditSampleCoverage reports routes without samples and sample files that
+// match no route. The spec file is not reported when it lives among the
+// samples.
+func (s *Server) auditSampleCoverage() (*samples.SampleCoverageReport, error) {
+	report, err := s.sampleProvider.AuditSampleCoverage(s.routeFiles())
+	if err != nil {
+		return nil, err
+	}
+	if rel, err := filepath.Rel(s.cfg.SamplesDir, s.cfg.SpecPath); err == nil {
+		report.Unused = slices.DeleteFunc(report.Unused, func(f string) bool { return f == filepath.ToSlash(rel) })
+	}
+	return report, nil
+}
+
+// warnSampleCoverage logs the result of auditSampleCoverage at startup, so
+// misnamed fixtures such as GET__items_{id}.json in folder layout show up
+// before a request misses them. Routes without samples are only warned
+// about with FALLBACK_MODE=none, where they cannot be served.
+func (s *Server) warnSampleCoverage() {
+	report, err := s.auditSampleCoverage()
+	if err != nil {
+		s.log.WithError(err).Debug("sample coverage audit skipped")
+		return
+	}
+	level := logrus.InfoLevel
+	if s.cfg.FallbackMode == config.FallbackNone {
+		level = logrus.WarnLevel
+	}
+	for _, route := range report.Missing {
+		s.log.WithField("route", route).Log(level, "route has no sample")
+	}
+	for _, f := range report.Unused {
+		s.log.WithField("file", f).Warn("sample file matches no route")
+	}
+}
+
+// handleAdminSampleCoverage lists routes without samples and unused sample
+// files: GET /__admin/samples/coverage
+func (s *Server) handleAdminSampleCoverage(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+	report, err := s.auditSampleCoverage()
+	if err != nil {
+		writeError(w, 500, CodeSamplesDirUnreadable, "Samples directory not readable", map[string]any{
+			"details": err.Error(),
+		})
+		return
+	}
+	utils.WriteJSON(w, 200, report)
+}
@@ -0,0 +1,48 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"net/http"
+
+	"github.com/ozgen/openapi-emulator/config"
+	"github.com/ozgen/openapi-emulator/internal/openapi"
+	"github.com/ozgen/openapi-emulator/internal/samples"
+	"github.com/sirupsen/logrus"
+)
+
+// checkSample validates a sample response against the spec when
+// SAMPLE_VALIDATION is set. In warn mode mismatches are only logged; in
+// strict mode they are answered with 500 and checkSample returns false.
+func (s *Server) checkSample(w http.ResponseWriter, rt *openapi.Route, resp *samples.Response) bool {
+	mode := s.cfg.SampleValidation
+	if mode != config.SampleValidationWarn && mode != config.SampleValidationStrict {
+		return true
+	}
+
+	contentType, _ := samples.HeaderGet(resp.Headers, "Content-Type")
+	fieldErrs := s.validator.ValidateResponseBody(rt.Swagger, rt.Method, resp.Status, contentType, resp.Body)
+	if len(fieldErrs) == 0 {
+		return true
+	}
+
+	s.log.WithFields(logrus.Fields{
+		"method":      rt.Method,
+		"swaggerPath": rt.Swagger,
+		"status":      resp.Status,
+		"errors":      fieldErrs,
+	}).Warn("sample does not match the response schema")
+	if mode != config.SampleValidationStrict {
+		return true
+	}
+
+	writeError(w, 500, CodeSampleSchemaMismatch, "Sample does not match the API spec", map[string]any{
+		"method":      rt.Method,
+		"swaggerPath": rt.Swagger,
+		"status":      resp.Status,
+		"errors":      fieldErrs,
+	})
+	return false
+}
@@ -0,0 +1,70 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ozgen/openapi-emulator/config"
+	"github.com/ozgen/openapi-emulator/utils"
+)
+
+// checkSamplesDir detects a SAMPLES_DIR that does not exist at startup.
+// Every route would then fail with SAMPLE_NOT_FOUND, so FALLBACK_MODE=none is
+// raised to openapi_examples, and the condition is logged and reported by
+// /health/ready.
+func (s *Server) checkSamplesDir() {
+	if st, err := os.Stat(s.cfg.SamplesDir); err == nil && st.IsDir() {
+		return
+	}
+
+	if s.cfg.FallbackMode == config.FallbackNone {
+		s.cfg.FallbackMode = config.FallbackOpenAPIExample
+	}
+	msg := fmt.Sprintf("SAMPLES_DIR %s does not exist; every route is served by FALLBACK_MODE=%s", s.cfg.SamplesDir, s.cfg.FallbackMode)
+	s.log.Warn(msg)
+	s.warnings = append(s.warnings, msg)
+}
+
+// reportReadOnlySamples logs at startup that SAMPLES_DIR is mounted
+// read-only. Serving only reads it; the one command that writes to it,
+// migrate-samples, refuses to run there, and runtime state goes to memory
+// or SCENARIO_STATE_URL.
+func (s *Server) reportReadOnlySamples() {
+	if st, err := os.Stat(s.cfg.SamplesDir); err != nil || !st.IsDir() || utils.DirWritable(s.cfg.SamplesDir) {
+		return
+	}
+	state := "in memory"
+	if url := strings.TrimSpace(config.Envs.Scenario.StateURL); config.Envs.Scenario.Enabled && url != "" {
+		state = "in SCENARIO_STATE_URL"
+	}
+	s.log.Infof("SAMPLES_DIR %s is read-only: samples are served as mounted, migrate-samples is unavailable and scenario state is kept %s", s.cfg.SamplesDir, state)
+}
+
+// checkStateDir makes sure STATE_DIR, when set, exists and is writable, so
+// a misconfigured volume fails at startup instead of on the first write.
+func (s *Server) checkStateDir() error {
+	if s.cfg.StateDir == "" {
+		return nil
+	}
+	if err := checkWritableDir(s.cfg.StateDir); err != nil {
+		return fmt.Errorf("STATE_DIR: %w", err)
+	}
+	return nil
+}
+
+// checkWritableDir creates dir when missing and reports an error unless
+// files can be created in it.
+func checkWritableDir(dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	if !utils.DirWritable(dir) {
+		return fmt.Errorf("%s is not writable (read-only mount?)", dir)
+	}
+	return nil
+}
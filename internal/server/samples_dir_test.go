@@ -0,0 +1,103 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ozgen/openapi-emulator/config"
+)
+
+func TestNew_MissingSamplesDir_ForcesFallbackAndReportsReadiness(t *testing.T) {
+	disableScenarioForTests()
+	dir := t.TempDir()
+	missing := filepath.Join(dir, "samples")
+
+	s, err := New(Config{
+		Port:           "0",
+		SpecPath:       writeFile(t, dir, "spec.json", minimalSpec()),
+		SamplesDir:     missing,
+		FallbackMode:   config.FallbackNone,
+		ValidationMode: config.ValidationNone,
+		Layout:         config.LayoutFolders,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if s.cfg.FallbackMode != config.FallbackOpenAPIExample {
+		t.Fatalf("expected FALLBACK_MODE to be forced to openapi_examples, got %q", s.cfg.FallbackMode)
+	}
+
+	rr := httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com/items/123", nil))
+	if rr.Code != 200 {
+		t.Fatalf("expected the spec example, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	rr = httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com/health/ready", nil))
+	var ready struct {
+		OK       bool     `json:"ok"`
+		Warnings []string `json:"warnings"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &ready); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if rr.Code != 200 || !ready.OK || len(ready.Warnings) != 1 || !strings.Contains(ready.Warnings[0], missing) {
+		t.Fatalf("expected a readiness warning naming %s, got %d: %s", missing, rr.Code, rr.Body.String())
+	}
+}
+
+func TestNew_ExistingSamplesDir_NoReadinessWarning(t *testing.T) {
+	s := newTestServer(t, config.ValidationNone, config.FallbackNone)
+	if s.cfg.FallbackMode != config.FallbackNone {
+		t.Fatalf("expected FALLBACK_MODE to be kept, got %q", s.cfg.FallbackMode)
+	}
+
+	rr := httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com/health/ready", nil))
+	if strings.Contains(rr.Body.String(), "warnings") {
+		t.Fatalf("expected no warnings, got %s", rr.Body.String())
+	}
+}
+
+func TestNew_StateDir_HoldsRelativeScenarioState(t *testing.T) {
+	s := newTestServer(t, config.ValidationNone, config.FallbackNone)
+	config.Envs.Scenario.Enabled = true
+	config.Envs.Scenario.StateURL = "file:scenarios/state.json"
+	t.Cleanup(func() {
+		config.Envs.Scenario.StateURL = ""
+		disableScenarioForTests()
+	})
+
+	cfg := s.cfg
+	cfg.StateDir = filepath.Join(t.TempDir(), "state")
+	if _, err := New(cfg); err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if st, err := os.Stat(filepath.Join(cfg.StateDir, "scenarios")); err != nil || !st.IsDir() {
+		t.Fatalf("expected the snapshot directory to be created in STATE_DIR, got %v", err)
+	}
+
+	cfg.StateDir = ""
+	if _, err := New(cfg); err == nil || !strings.Contains(err.Error(), "SCENARIO_STATE_URL") {
+		t.Fatalf("expected a relative file: URL without STATE_DIR to fail, got %v", err)
+	}
+}
+
+func TestNew_StateDirNotADirectory_Fails(t *testing.T) {
+	s := newTestServer(t, config.ValidationNone, config.FallbackNone)
+	cfg := s.cfg
+	cfg.StateDir = writeFile(t, t.TempDir(), "state", "")
+	if _, err := New(cfg); err == nil || !strings.Contains(err.Error(), "STATE_DIR") {
+		t.Fatalf("expected STATE_DIR to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,103 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ozgen/openapi-emulator/config"
+)
+
+// fileStamp identifies one version of a watched file.
+type fileStamp struct {
+	size    int64
+	modTime int64
+}
+
+// WatchSamples polls SAMPLES_DIR and SCENARIOS_DIR every interval and logs
+// the files that were added, changed or removed. Samples are checked per
+// request, so edits are served right away; a changed scenario file also
+// resets the runtime scenario state, which may point at entries the edited
+// file no longer has.
+func (s *Server) WatchSamples(every time.Duration) {
+	seen := s.sampleStamps()
+	for range time.Tick(every) {
+		seen = s.reloadSamples(seen)
+	}
+}
+
+// reloadSamples compares the watched files with seen, applies the changes
+// and returns the new stamps.
+func (s *Server) reloadSamples(seen map[string]fileStamp) map[string]fileStamp {
+	now := s.sampleStamps()
+	changed := changedFiles(seen, now)
+	if len(changed) == 0 {
+		return now
+	}
+
+	scenarioChanged := false
+	for _, path := range changed {
+		s.log.WithField("file", path).Info("sample file changed")
+		if filepath.Base(path) == config.Envs.Scenario.Filename {
+			scenarioChanged = true
+		}
+	}
+	if scenarioChanged && s.scenario != nil {
+		n := s.scenario.ResetScenarios()
+		s.log.WithField("keys", n).Info("scenario files changed; scenario state reset")
+	}
+	return now
+}
+
+// sampleStamps walks the watched directories. Hidden entries, such as the
+// ..data links of a ConfigMap volume, are skipped.
+func (s *Server) sampleStamps() map[string]fileStamp {
+	out := map[string]fileStamp{}
+	for _, dir := range []string{s.cfg.SamplesDir, config.Envs.Scenario.Dir} {
+		if strings.TrimSpace(dir) == "" {
+			continue
+		}
+		_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return nil
+			}
+			if path != dir && strings.HasPrefix(d.Name(), ".") {
+				if d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if d.IsDir() {
+				return nil
+			}
+			if info, err := d.Info(); err == nil {
+				out[path] = fileStamp{size: info.Size(), modTime: info.ModTime().UnixNano()}
+			}
+			return nil
+		})
+	}
+	return out
+}
+
+// changedFiles returns the sorted paths that differ between two walks.
+func changedFiles(before, after map[string]fileStamp) []string {
+	var out []string
+	for path, st := range after {
+		if prev, ok := before[path]; !ok || prev != st {
+			out = append(out, path)
+		}
+	}
+	for path := range before {
+		if _, ok := after[path]; !ok {
+			out = append(out, path)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,62 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ozgen/openapi-emulator/config"
+)
+
+func TestReloadSamples_ScenarioChangeResetsState(t *testing.T) {
+	s := newIsolationTestServer(t, config.IsolationNone)
+	seen := s.sampleStamps()
+
+	get := func() string {
+		rr := httptest.NewRecorder()
+		s.handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com/scans/1", nil))
+		return rr.Body.String()
+	}
+	_ = get()
+	if body := get(); !strings.Contains(body, "running") {
+		t.Fatalf("expected the scenario to advance, got %s", body)
+	}
+
+	// A sample edit is served as is and keeps the scenario position.
+	writeFileWithDirs(t, s.cfg.SamplesDir, filepath.Join("scans", "{id}", "running.json"), `{"state":"running","v":2}`)
+	seen = s.reloadSamples(seen)
+	if body := get(); !strings.Contains(body, `"v":2`) {
+		t.Fatalf("expected the edited sample, got %s", body)
+	}
+
+	writeFileWithDirs(t, s.cfg.SamplesDir, filepath.Join("scans", "{id}", "scenario.json"), `{
+	  "version": 1,
+	  "mode": "step",
+	  "key": {"pathParam": "id"},
+	  "sequence": [
+	    {"state": "queued", "file": "queued.json"},
+	    {"state": "running", "file": "running.json"}
+	  ],
+	  "behavior": {"advanceOn": [{"method": "GET"}], "repeatLast": false}
+	}`)
+	s.reloadSamples(seen)
+	if body := get(); !strings.Contains(body, "queued") {
+		t.Fatalf("expected the scenario to start over, got %s", body)
+	}
+}
+
+func TestChangedFiles(t *testing.T) {
+	before := map[string]fileStamp{"a": {1, 1}, "b": {1, 1}, "c": {1, 1}}
+	after := map[string]fileStamp{"a": {1, 1}, "b": {2, 2}, "d": {1, 1}}
+
+	got := changedFiles(before, after)
+	if strings.Join(got, ",") != "b,c,d" {
+		t.Fatalf("expected b,c,d, got %v", got)
+	}
+}
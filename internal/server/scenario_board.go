@@ -0,0 +1,100 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ozgen/openapi-emulator/internal/samples"
+)
+
+// scenarioView is the last scenario state served for one request path.
+type scenarioView struct {
+	Method      string    `json:"method"`
+	Path        string    `json:"path"`
+	SwaggerPath string    `json:"swaggerPath"`
+	Client      string    `json:"client,omitempty"`
+	Mode        string    `json:"mode"`
+	State       string    `json:"state"`
+	Step        int       `json:"step"`
+	Percent     int       `json:"percent,omitempty"`
+	SeenAt      time.Time `json:"seenAt"`
+}
+
+// scenarioBoard remembers the scenario states served, for the admin UI. It
+// is bounded like the request log: it keeps the size most recently seen
+// views, and views older than maxAge are dropped (0 keeps them).
+type scenarioBoard struct {
+	mu     sync.Mutex
+	views  map[string]scenarioView
+	size   int
+	maxAge time.Duration
+	now    func() time.Time
+}
+
+// newScenarioBoard keeps up to size views; a size below 1 means
+// defaultRequestLogSize.
+func newScenarioBoard(size int, maxAge time.Duration) *scenarioBoard {
+	if size < 1 {
+		size = defaultRequestLogSize
+	}
+	return &scenarioBoard{views: map[string]scenarioView{}, size: size, maxAge: maxAge, now: time.Now}
+}
+
+func (b *scenarioBoard) observe(v scenarioView, st *samples.ScenarioState) {
+	v.Mode, v.State, v.Step, v.Percent = st.Mode, st.State, st.Step, st.Percent
+	v.SeenAt = b.now()
+	key := v.Client + "@" + v.Method + " " + v.Path
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.pruneLocked()
+	if _, ok := b.views[key]; !ok && len(b.views) >= b.size {
+		oldest := ""
+		for k, old := range b.views {
+			if oldest == "" || old.SeenAt.Before(b.views[oldest].SeenAt) {
+				oldest = k
+			}
+		}
+		delete(b.views, oldest)
+	}
+	b.views[key] = v
+}
+
+// pruneLocked drops the views older than maxAge.
+func (b *scenarioBoard) pruneLocked() {
+	if b.maxAge <= 0 {
+		return
+	}
+	cutoff := b.now().Add(-b.maxAge)
+	for k, v := range b.views {
+		if v.SeenAt.Before(cutoff) {
+			delete(b.views, k)
+		}
+	}
+}
+
+func (b *scenarioBoard) list() []scenarioView {
+	b.mu.Lock()
+	b.pruneLocked()
+	out := make([]scenarioView, 0, len(b.views))
+	for _, v := range b.views {
+		out = append(out, v)
+	}
+	b.mu.Unlock()
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Path != out[j].Path {
+			return out[i].Path < out[j].Path
+		}
+		if out[i].Method != out[j].Method {
+			return out[i].Method < out[j].Method
+		}
+		return out[i].Client < out[j].Client
+	})
+	return out
+}
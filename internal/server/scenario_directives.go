@@ -0,0 +1,97 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// scenarioDirective is one line of a directive file:
+//
+//	set /scans/{id} key 42 to failed
+//	reset /scans/{id} key 42
+type scenarioDirective struct {
+	SwaggerPath string
+	Key         string
+	// State is empty for reset.
+	State string
+}
+
+func parseScenarioDirective(line string) (scenarioDirective, error) {
+	f := strings.Fields(line)
+	switch {
+	case len(f) == 6 && f[0] == "set" && f[2] == "key" && f[4] == "to":
+		return scenarioDirective{SwaggerPath: f[1], Key: f[3], State: f[5]}, nil
+	case len(f) == 4 && f[0] == "reset" && f[2] == "key":
+		return scenarioDirective{SwaggerPath: f[1], Key: f[3]}, nil
+	}
+	return scenarioDirective{}, fmt.Errorf(`expected "set <path> key <key> to <state>" or "reset <path> key <key>"`)
+}
+
+// WatchScenarioDirectives polls dir every interval and applies the
+// directives of each file whose content changed since the last poll. It
+// suits a mounted ConfigMap, whose files are swapped atomically on update.
+func (s *Server) WatchScenarioDirectives(dir string, every time.Duration) {
+	seen := map[string][32]byte{}
+	s.applyScenarioDirectives(dir, seen)
+	for range time.Tick(every) {
+		s.applyScenarioDirectives(dir, seen)
+	}
+}
+
+// applyScenarioDirectives applies new or changed directive files in dir and
+// records their digests in seen. Hidden entries, such as the ..data links of
+// a ConfigMap volume, are skipped.
+func (s *Server) applyScenarioDirectives(dir string, seen map[string][32]byte) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		s.log.WithError(err).Warn("read scenario directives")
+		return
+	}
+
+	for _, e := range entries {
+		if strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		path := filepath.Join(dir, e.Name())
+		b, err := os.ReadFile(path)
+		if err != nil {
+			// directories, or a link that vanished during a ConfigMap update
+			continue
+		}
+		sum := sha256.Sum256(b)
+		if prev, ok := seen[e.Name()]; ok && prev == sum {
+			continue
+		}
+		seen[e.Name()] = sum
+
+		sc := bufio.NewScanner(bytes.NewReader(b))
+		for n := 1; sc.Scan(); n++ {
+			line := strings.TrimSpace(sc.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			fields := logrus.Fields{"file": path, "line": n}
+			d, err := parseScenarioDirective(line)
+			if err == nil {
+				err = s.sampleProvider.SetScenarioState(d.SwaggerPath, d.Key, d.State)
+			}
+			if err != nil {
+				s.log.WithFields(fields).WithError(err).Warn("scenario directive rejected")
+				continue
+			}
+			s.log.WithFields(fields).Infof("scenario directive applied: %s", line)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ozgen/openapi-emulator/config"
+)
+
+func TestParseScenarioDirective(t *testing.T) {
+	d, err := parseScenarioDirective("set /scans/{id} key 42 to failed")
+	if err != nil || d.SwaggerPath != "/scans/{id}" || d.Key != "42" || d.State != "failed" {
+		t.Fatalf("unexpected directive %+v, err=%v", d, err)
+	}
+	d, err = parseScenarioDirective("reset /scans/{id} key 42")
+	if err != nil || d.Key != "42" || d.State != "" {
+		t.Fatalf("unexpected directive %+v, err=%v", d, err)
+	}
+	if _, err := parseScenarioDirective("set /scans/{id} to failed"); err == nil {
+		t.Fatalf("expected error for malformed directive")
+	}
+}
+
+func TestApplyScenarioDirectives(t *testing.T) {
+	s := newIsolationTestServer(t, config.IsolationNone)
+	get := func() string {
+		rr := httptest.NewRecorder()
+		s.handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com/scans/42", nil))
+		return rr.Body.String()
+	}
+
+	dir := t.TempDir()
+	writeFile(t, dir, "scans", "# driven by GitOps\nset /scans/{id} key 42 to running\nset /scans/{id} key 42 to nope\n")
+	writeFile(t, dir, "..data", "set /scans/{id} key 42 to queued\n")
+
+	seen := map[string][32]byte{}
+	s.applyScenarioDirectives(dir, seen)
+	if body := get(); !strings.Contains(body, "running") {
+		t.Fatalf("expected directive to move key 42 to running, got %s", body)
+	}
+
+	// unchanged files are not applied again
+	writeFile(t, dir, "other", "reset /scans/{id} key 7\n")
+	s.applyScenarioDirectives(dir, seen)
+	if body := get(); !strings.Contains(body, "running") {
+		t.Fatalf("expected state to stay running, got %s", body)
+	}
+
+	writeFile(t, dir, "scans", "reset /scans/{id} key 42\n")
+	s.applyScenarioDirectives(dir, seen)
+	if body := get(); !strings.Contains(body, "queued") {
+		t.Fatalf("expected reset to queued, got %s", body)
+	}
+}
@@ -0,0 +1,127 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"errors"
+	"regexp"
+
+	"github.com/ozgen/openapi-emulator/internal/matcher"
+	"github.com/ozgen/openapi-emulator/internal/openapi"
+	"github.com/ozgen/openapi-emulator/internal/samples"
+)
+
+// SelfTestResult is the outcome of producing one response during SelfTest.
+// Source is "sample", "scenario", "fallback" or "batch"; Err is set when no
+// response could be produced.
+type SelfTestResult struct {
+	Method      string
+	SwaggerPath string
+	State       string
+	Source      string
+	Err         error
+}
+
+// selfTestParamValue stands in for every path parameter.
+const selfTestParamValue = "1"
+
+var selfTestParam = regexp.MustCompile(`\{([^}/]+?)\+?\}`)
+
+// SelfTest produces the response of every operation once, or of those
+// matching patterns ("METHOD /path", as in ROUTES_DISABLE), without serving
+// it. Scenario routes are checked for every state without reading or
+// advancing the stored scenario state. Disabled operations are skipped.
+func (s *Server) SelfTest(patterns []string) ([]SelfTestResult, error) {
+	only, err := matcher.ParseRoutes(patterns)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []SelfTestResult
+	for _, rt := range s.router().GetRoutes() {
+		if len(only) > 0 && !matchesAnyRoute(only, &rt) {
+			continue
+		}
+		if matchesAnyRoute(s.disabledRoutes, &rt) {
+			continue
+		}
+		out = append(out, s.selfTestRoute(&rt)...)
+	}
+	return out, nil
+}
+
+func (s *Server) selfTestRoute(rt *openapi.Route) []SelfTestResult {
+	result := SelfTestResult{Method: rt.Method, SwaggerPath: rt.Swagger, Source: "sample"}
+
+	ext := s.specProvider.GetEmulatorExtensions(rt.Swagger, rt.Method)
+	if ext.Batch {
+		result.Source = "batch"
+		return []SelfTestResult{result}
+	}
+
+	params := map[string]string{}
+	path := selfTestParam.ReplaceAllStringFunc(rt.Swagger, func(m string) string {
+		params[selfTestParam.FindStringSubmatch(m)[1]] = selfTestParamValue
+		return selfTestParamValue
+	})
+	opts := samples.LoadOptions{
+		TagFile:      rt.TagFile,
+		Path:         params,
+		Query:        map[string]string{},
+		Header:       map[string]string{},
+		Cookies:      map[string]string{},
+		IgnoreExpect: true,
+	}
+
+	if ext.Sample != "" {
+		_, result.Err = s.sampleProvider.LoadSample(ext.Sample, opts)
+		return []SelfTestResult{result}
+	}
+
+	files := s.sampleProvider.SampleFiles(rt.Method, rt.Swagger, rt.SampleFile, rt.TagFile)
+	var states []SelfTestResult
+	for _, f := range files {
+		if f.State == "" {
+			continue
+		}
+		st := result
+		st.Source, st.State = "scenario", f.State
+		o := opts
+		o.ForceState = f.State
+		_, st.Err = s.sampleProvider.ResolveAndLoad(rt.Method, rt.Swagger, path, rt.SampleFile, o)
+		states = append(states, st)
+	}
+	if len(states) > 0 {
+		return states
+	}
+
+	_, err := s.sampleProvider.ResolveAndLoad(rt.Method, rt.Swagger, path, rt.SampleFile, opts)
+	if err == nil {
+		return []SelfTestResult{result}
+	}
+	// Only a missing sample falls back; a broken one is a failure.
+	if len(files) == 0 && !isSampleFault(err) {
+		if _, _, ok := s.tryFallbackBody(s.fallbackFor(rt).Mode, rt, ""); ok {
+			result.Source = "fallback"
+			return []SelfTestResult{result}
+		}
+	}
+	result.Err = err
+	return []SelfTestResult{result}
+}
+
+// isSampleFault reports whether err is a sample or scenario failure rather
+// than a missing sample.
+func isSampleFault(err error) bool {
+	var (
+		syntaxErr  *samples.SampleSyntaxError
+		envErr     *samples.SampleEnvelopeError
+		tplErr     *samples.SampleTemplateError
+		includeErr *samples.SampleIncludeError
+		storeErr   *samples.ScenarioStoreError
+	)
+	return errors.As(err, &syntaxErr) || errors.As(err, &envErr) ||
+		errors.As(err, &tplErr) || errors.As(err, &includeErr) || errors.As(err, &storeErr)
+}
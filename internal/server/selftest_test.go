@@ -0,0 +1,116 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ozgen/openapi-emulator/config"
+)
+
+func selfTestByRoute(t *testing.T, s *Server, patterns ...string) map[string]SelfTestResult {
+	t.Helper()
+	results, err := s.SelfTest(patterns)
+	if err != nil {
+		t.Fatalf("SelfTest: %v", err)
+	}
+	out := map[string]SelfTestResult{}
+	for _, r := range results {
+		key := r.Method + " " + r.SwaggerPath
+		if r.State != "" {
+			key += " " + r.State
+		}
+		out[key] = r
+	}
+	return out
+}
+
+func TestSelfTest_AllSamplesProduced(t *testing.T) {
+	s := newTestServer(t, config.ValidationRequired, config.FallbackNone)
+
+	got := selfTestByRoute(t, s)
+	if len(got) != 2 {
+		t.Fatalf("expected both operations, got %v", got)
+	}
+	for name, r := range got {
+		if r.Err != nil || r.Source != "sample" {
+			t.Fatalf("%s: expected a sample, got %+v", name, r)
+		}
+	}
+}
+
+func TestSelfTest_FallbackAndFailures(t *testing.T) {
+	s := newTestServer(t, config.ValidationNone, config.FallbackOpenAPIExample)
+	if err := os.Remove(filepath.Join(s.cfg.SamplesDir, "items", "{id}", "GET.json")); err != nil {
+		t.Fatal(err)
+	}
+	writeFileWithDirs(t, s.cfg.SamplesDir, filepath.Join("items", "POST.json"), `{"created": tru`)
+
+	got := selfTestByRoute(t, s)
+	if r := got["GET /items/{id}"]; r.Err != nil || r.Source != "fallback" {
+		t.Fatalf("expected the spec example, got %+v", r)
+	}
+	if r := got["POST /items"]; r.Err == nil {
+		t.Fatalf("expected a malformed sample to fail, got %+v", r)
+	}
+
+	s.cfg.FallbackMode = config.FallbackNone
+	if r := selfTestByRoute(t, s)["GET /items/{id}"]; r.Err == nil {
+		t.Fatalf("expected a missing sample without fallback to fail, got %+v", r)
+	}
+}
+
+func TestSelfTest_RoutePatterns(t *testing.T) {
+	s := newTestServer(t, config.ValidationNone, config.FallbackNone)
+
+	got := selfTestByRoute(t, s, "POST /items")
+	if _, ok := got["POST /items"]; !ok || len(got) != 1 {
+		t.Fatalf("expected only POST /items, got %v", got)
+	}
+	if _, err := s.SelfTest([]string{"/items"}); err == nil {
+		t.Fatalf("expected an error for an invalid pattern")
+	}
+}
+
+func TestSelfTest_ScenarioStates_DoNotAdvance(t *testing.T) {
+	s := newTestServer(t, config.ValidationNone, config.FallbackOpenAPIExample)
+	config.Envs.Scenario.Enabled = true
+	t.Cleanup(disableScenarioForTests)
+
+	s2, err := New(s.cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	writeFileWithDirs(t, s.cfg.SamplesDir, filepath.Join("items", "{id}", "scenario.json"), `{
+	  "version": 1,
+	  "mode": "step",
+	  "key": {"pathParam": "id"},
+	  "sequence": [
+	    {"state": "pending", "file": "pending.json"},
+	    {"state": "done", "file": "done.json"}
+	  ],
+	  "behavior": {"advanceOn": [{"method": "GET"}]}
+	}`)
+	writeFileWithDirs(t, s.cfg.SamplesDir, filepath.Join("items", "{id}", "pending.json"), `{"id":"{{ .Path.id }}"}`)
+
+	got := selfTestByRoute(t, s2, "GET /items/{id}")
+	if r := got["GET /items/{id} pending"]; r.Err != nil || r.Source != "scenario" {
+		t.Fatalf("expected the pending state to be produced, got %+v", r)
+	}
+	if r := got["GET /items/{id} done"]; r.Err == nil {
+		t.Fatalf("expected the missing done file to fail, got %+v", r)
+	}
+
+	rr := httptest.NewRecorder()
+	s2.handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com/items/1", nil))
+	if !strings.Contains(rr.Body.String(), `"1"`) {
+		t.Fatalf("self-test must not advance the scenario, got %s", rr.Body.String())
+	}
+}
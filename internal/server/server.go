@@ -5,12 +5,25 @@
 package server
 
 import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/ozgen/openapi-emulator/config"
+	"github.com/ozgen/openapi-emulator/internal/matcher"
 	"github.com/ozgen/openapi-emulator/internal/openapi"
 	"github.com/ozgen/openapi-emulator/internal/samples"
 	"github.com/ozgen/openapi-emulator/logger"
@@ -18,30 +31,110 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+// scenarioStateHeader forces a scenario state for a single request without
+// changing the stored scenario progress.
+const scenarioStateHeader = "X-Mock-Scenario-State"
+
 type Config struct {
-	Port           string
-	SpecPath       string
-	SamplesDir     string
-	FallbackMode   config.FallbackMode
-	ValidationMode config.ValidationMode
-	Layout         config.LayoutMode
+	Port                  string
+	TLSCertFile           string
+	TLSKeyFile            string
+	SpecPath              string
+	SamplesDir            string
+	FallbackMode          config.FallbackMode
+	FallbackOverridesPath string
+	RoutePriorityPath     string
+	ValidationMode        config.ValidationMode
+	ValidateHeaders       bool
+	ValidateCookies       bool
+	ValidateContentType   bool
+	StrictQueryParams     bool
+	SampleValidation      config.SampleValidationMode
+	SecurityMode          config.SecurityMode
+	SecurityTokens        []string
+	OAuthSigningKey       string
+	StateIsolation        config.IsolationMode
+	StateIsolationHeader  string
+	Layout                config.LayoutMode
+	TrailingSlash         config.TrailingSlashMode
+	BasePath              string
+	ClockSkew             time.Duration
+	RoutesDisable         []string
+	RoutesDisableStatus   int
+	// RequestLogSize bounds the admin request log and scenario board (0
+	// means 200) and RequestLogMaxAge drops older entries (0 keeps them).
+	// Requests to routes matching RequestLogExclude ("METHOD /path"
+	// patterns) are not logged.
+	RequestLogSize    int
+	RequestLogMaxAge  time.Duration
+	RequestLogExclude []string
+	// ExpectContinueReject lists "METHOD /path" patterns whose
+	// "Expect: 100-continue" requests get 417 instead of 100 Continue.
+	ExpectContinueReject []string
+	// UnmatchedStatus (0 means 404) and UnmatchedBodyFile replace the
+	// ROUTE_NOT_FOUND answer for requests no operation matches;
+	// UnmatchedUpstream forwards them to another server instead.
+	UnmatchedStatus   int
+	UnmatchedBodyFile string
+	UnmatchedUpstream string
+	// SampleCacheSize bounds the parsed samples kept in memory; 0 disables
+	// the cache.
+	SampleCacheSize int
+	// TemplateBodyMaxBytes caps the request body decoded for samples (0
+	// means 1 MiB); a larger body is seen as no body.
+	TemplateBodyMaxBytes int
+	// StateDir is the writable directory relative file: scenario state is
+	// kept in; see config.Config.StateDir.
+	StateDir string
+	// PreserveHeaderCase writes sample header names as spelled in the
+	// sample instead of canonicalizing them.
+	PreserveHeaderCase bool
+	// ExampleGenerator and RandomGenerator replace the built-in schema
+	// generators of the openapi_examples and random fallback modes.
+	ExampleGenerator openapi.IExampleGenerator
+	RandomGenerator  openapi.IExampleGenerator
 }
 
 type Server struct {
 	cfg            Config
 	specProvider   openapi.ISpecProvider
+	routerMu       sync.RWMutex
 	routerProvider openapi.IRouterProvider
+	spec           specInfo
 	validator      openapi.IValidator
 	sampleProvider samples.ISampleProvider
 	log            *logrus.Logger
 
-	scenario samples.IScenarioResolver
+	scenario          samples.IScenarioResolver
+	fallbackOverrides map[string]config.FallbackOverride
+	routePriorities   map[string]int
+	disabledRoutes    []matcher.Route
+	unloggedRoutes    []matcher.Route
+	noContinueRoutes  []matcher.Route
+	unmatched         *unmatchedResponse
+	jobs              *jobRegistry
+	oauth             *oauthIssuer
+	backoff           *backoffTracker
+	requests          *requestLog
+	scenarios         *scenarioBoard
+	switches          switchBoard
+	// cert is the TLS certificate loaded from TLSCertFile and TLSKeyFile.
+	cert *tls.Certificate
+	// warnings are startup misconfigurations reported by /health/ready.
+	warnings []string
 }
 
 func New(cfg Config) (*Server, error) {
 	log := logger.GetLogger()
 
-	specProvider, err := openapi.NewSpecProvider(cfg.SpecPath, log)
+	var specOpts []openapi.SpecOption
+	if cfg.ExampleGenerator != nil {
+		specOpts = append(specOpts, openapi.WithExampleGenerator(cfg.ExampleGenerator))
+	}
+	if cfg.RandomGenerator != nil {
+		specOpts = append(specOpts, openapi.WithRandomGenerator(cfg.RandomGenerator))
+	}
+	specProvider, err := openapi.NewSpecProvider(cfg.SpecPath, log, specOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -51,19 +144,44 @@ func New(cfg Config) (*Server, error) {
 		return nil, fmt.Errorf("unexpected spec provider type: %T", specProvider)
 	}
 
-	routeProvider := openapi.NewRouterProvider(sp.GetSpec())
+	var priorities map[string]int
+	if strings.TrimSpace(cfg.RoutePriorityPath) != "" {
+		if priorities, err = config.LoadRoutePriorities(cfg.RoutePriorityPath); err != nil {
+			return nil, err
+		}
+	}
+
+	routeProvider := openapi.NewRouterProvider(sp.GetSpec(), openapi.WithRoutePriorities(priorities))
 	validator := openapi.NewValidator(specProvider)
 
 	if strings.TrimSpace(string(cfg.Layout)) == "" {
 		cfg.Layout = config.LayoutAuto
 	}
 
+	cert, err := loadCertificate(cfg.TLSCertFile, cfg.TLSKeyFile)
+	if err != nil {
+		return nil, err
+	}
+
 	s := &Server{
-		cfg:            cfg,
-		specProvider:   specProvider,
-		routerProvider: routeProvider,
-		validator:      validator,
-		log:            log,
+		cfg:             cfg,
+		cert:            cert,
+		specProvider:    specProvider,
+		routerProvider:  routeProvider,
+		routePriorities: priorities,
+		validator:       validator,
+		log:             log,
+		jobs:            newJobRegistry(),
+		oauth:           newOAuthIssuer(cfg.OAuthSigningKey),
+		backoff:         newBackoffTracker(),
+		requests:        newRequestLog(cfg.RequestLogSize, cfg.RequestLogMaxAge),
+		scenarios:       newScenarioBoard(cfg.RequestLogSize, cfg.RequestLogMaxAge),
+	}
+	s.oauth.now = s.now
+	s.recordSpec()
+	s.oauth.index(sp.GetSpec())
+	if routeProvider != nil {
+		s.jobs.index(routeProvider.GetRoutes(), specProvider)
 	}
 
 	providerCfg := samples.ProviderConfig{
@@ -71,21 +189,74 @@ func New(cfg Config) (*Server, error) {
 		Layout:           cfg.Layout,
 		ScenarioEnabled:  config.Envs.Scenario.Enabled,
 		ScenarioFilename: config.Envs.Scenario.Filename,
+		ScenariosDir:     config.Envs.Scenario.Dir,
+		CacheSize:        cfg.SampleCacheSize,
 	}
 
+	if err := s.checkStateDir(); err != nil {
+		return nil, err
+	}
 	if config.Envs.Scenario.Enabled {
-		s.scenario = samples.NewScenarioResolver()
+		var opts []samples.ResolverOption
+		if url := strings.TrimSpace(config.Envs.Scenario.StateURL); url != "" {
+			store, err := samples.NewScenarioStore(url, cfg.StateDir)
+			if err != nil {
+				return nil, fmt.Errorf("SCENARIO_STATE_URL: %w", err)
+			}
+			if fs, ok := store.(*samples.FileScenarioStore); ok {
+				if err := checkWritableDir(filepath.Dir(fs.Path())); err != nil {
+					return nil, fmt.Errorf("SCENARIO_STATE_URL: %w; keep the snapshot under STATE_DIR", err)
+				}
+			}
+			opts = append(opts, samples.WithScenarioStore(store))
+		}
+		s.scenario = samples.NewScenarioResolver(opts...)
 		providerCfg.ScenarioResolver = s.scenario
 	}
 
 	s.sampleProvider = samples.NewSampleProvider(providerCfg, log)
 
+	s.disabledRoutes, err = matcher.ParseRoutes(cfg.RoutesDisable)
+	if err != nil {
+		return nil, fmt.Errorf("ROUTES_DISABLE: %w", err)
+	}
+	s.unloggedRoutes, err = matcher.ParseRoutes(cfg.RequestLogExclude)
+	if err != nil {
+		return nil, fmt.Errorf("REQUEST_LOG_EXCLUDE: %w", err)
+	}
+	s.noContinueRoutes, err = matcher.ParseRoutes(cfg.ExpectContinueReject)
+	if err != nil {
+		return nil, fmt.Errorf("EXPECT_CONTINUE_REJECT: %w", err)
+	}
+	s.unmatched, err = newUnmatchedResponse(cfg.UnmatchedStatus, cfg.UnmatchedBodyFile, cfg.UnmatchedUpstream)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSpace(cfg.FallbackOverridesPath) != "" {
+		overrides, err := config.LoadFallbackOverrides(cfg.FallbackOverridesPath)
+		if err != nil {
+			return nil, err
+		}
+		s.fallbackOverrides = overrides
+	}
+
+	s.checkSamplesDir()
+	s.reportReadOnlySamples()
+	s.warnFlatSamples()
+	s.warnSampleCoverage()
 	return s, nil
 }
 
 func (s *Server) ListenAndServe() error {
+	return s.listen(http.HandlerFunc(s.handle), nil)
+}
+
+// listen serves h on the configured port, over TLS when s or any host in
+// sni has a certificate.
+func (s *Server) listen(h http.Handler, sni map[string]*tls.Certificate) error {
 	mux := http.NewServeMux()
-	mux.HandleFunc("/", s.handle)
+	mux.Handle("/", h)
 
 	addr := "0.0.0.0:" + s.cfg.Port
 
@@ -105,39 +276,154 @@ func (s *Server) ListenAndServe() error {
 		IdleTimeout:       60 * time.Second,
 	}
 
+	if tlsCfg := newTLSConfig(s.cert, sni); tlsCfg != nil {
+		server.TLSConfig = tlsCfg
+		s.log.Printf("serving HTTPS")
+		return server.ListenAndServeTLS("", "")
+	}
 	return server.ListenAndServe()
 }
 
 func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	r = s.stripBasePath(r)
+	s.setDateHeader(w)
 	method := r.Method
 	path := r.URL.Path
 
 	// Health endpoints
 	if method == http.MethodGet && (path == "/health/alive" || path == "/health/ready" || path == "/health/started") {
-		utils.WriteJSON(w, 200, map[string]any{"ok": true})
+		body := map[string]any{"ok": true}
+		if path == "/health/ready" && len(s.warnings) > 0 {
+			body["warnings"] = s.warnings
+		}
+		utils.WriteJSON(w, 200, body)
+		return
+	}
+
+	if s.handleAdmin(w, r) {
+		return
+	}
+
+	lw := s.requests.track(w, r)
+	defer lw.done()
+	w = lw
+
+	if !s.applySwitches(w, r) {
+		return
+	}
+
+	if s.handleOAuthToken(w, r) {
 		return
 	}
 
-	rt := s.routerProvider.FindRoute(method, path)
+	rt, params := s.router().MatchRoute(method, path)
+	if rt == nil && method == http.MethodHead {
+		rt, params, w, r = s.matchHeadAsGet(w, r)
+		method = r.Method
+	}
+	if rt != nil && matchesAnyRoute(s.unloggedRoutes, rt) {
+		lw.skip = true
+	}
 	if rt == nil {
-		utils.WriteJSON(w, 404, map[string]any{
-			"error":  "No route",
-			"method": method,
-			"path":   path,
-		})
+		if allowed := s.router().AllowedMethods(path); len(allowed) > 0 {
+			w.Header().Set("Allow", strings.Join(allowed, ", "))
+			// OPTIONS without an explicit spec operation is answered with
+			// the Allow header alone.
+			if method == http.MethodOptions {
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			writeError(w, 405, CodeMethodNotAllowed, "Method Not Allowed", map[string]any{
+				"method":  method,
+				"path":    path,
+				"allowed": allowed,
+			})
+			return
+		}
+		if s.serveDefaultSample(w, method) {
+			return
+		}
+		s.unmatched.write(w, r)
+		return
+	}
+	if !s.checkTrailingSlash(w, r, rt) {
+		return
+	}
+	if s.rejectDisabledRoute(w, rt, path) {
+		return
+	}
+	if s.rejectExpectation(w, r, rt) {
 		return
 	}
+	r = r.WithContext(openapi.WithPathParams(r.Context(), params))
+
+	if s.cfg.SecurityMode == config.SecurityEnforce {
+		if failure := s.validator.ValidateSecurity(r, rt.Swagger, rt.Method, s.acceptCredential); failure != nil {
+			for _, c := range failure.Challenges {
+				w.Header().Add("WWW-Authenticate", c)
+			}
+			writeError(w, 401, CodeUnauthorized, "Unauthorized", map[string]any{
+				"details": "Missing or invalid credentials for security schemes: " + strings.Join(failure.Schemes, ", "),
+				"schemes": failure.Schemes,
+			})
+			return
+		}
+	}
+
+	report := s.newValidationReport()
+	schemaChecks := s.cfg.ValidationMode == config.ValidationSchema || s.cfg.ValidationMode == config.ValidationWarn
+
+	var paramsIn []string
+	if schemaChecks {
+		paramsIn = append(paramsIn, openapi3.ParameterInPath, openapi3.ParameterInQuery)
+	}
+	if s.cfg.ValidateHeaders {
+		paramsIn = append(paramsIn, openapi3.ParameterInHeader)
+	}
+	if s.cfg.ValidateCookies {
+		paramsIn = append(paramsIn, openapi3.ParameterInCookie)
+	}
+	for _, in := range paramsIn {
+		report.check(in)
+		if fieldErrs := s.validator.ValidateParameters(r, rt.Swagger, rt.Method, in); len(fieldErrs) > 0 && !report.soften(fieldErrs...) {
+			writeParameterErrors(w, fieldErrs)
+			return
+		}
+	}
+	if s.cfg.StrictQueryParams {
+		report.check("undeclaredQuery")
+		if fieldErrs := s.validator.UndeclaredQueryParams(r, rt.Swagger, rt.Method); len(fieldErrs) > 0 && !report.soften(fieldErrs...) {
+			writeParameterErrors(w, fieldErrs)
+			return
+		}
+	}
 
-	if s.cfg.ValidationMode == config.ValidationRequired {
+	if s.cfg.ValidateContentType {
+		report.check("contentType")
+		if supported, ok := s.validator.ValidateContentType(r, rt.Swagger, rt.Method); !ok && !report.soften(openapi.FieldError{
+			In:     openapi3.ParameterInHeader,
+			Field:  "Content-Type",
+			Reason: "media type is not accepted by the API spec",
+		}) {
+			writeError(w, 415, CodeUnsupportedMediaType, "Unsupported Media Type", map[string]any{
+				"details":     "Content-Type " + r.Header.Get("Content-Type") + " is not accepted by the API spec",
+				"contentType": r.Header.Get("Content-Type"),
+				"supported":   supported,
+			})
+			return
+		}
+	}
+
+	if s.cfg.ValidationMode == config.ValidationRequired || schemaChecks {
 		if s.validator.HasRequiredBodyParam(rt.Swagger, rt.Method) {
+			report.check("bodyRequired")
 			empty, err := s.validator.IsEmptyBody(r)
 			if err != nil {
-				utils.WriteJSON(w, 400, map[string]any{"error": "Bad Request", "details": err.Error()})
+				writeError(w, 400, CodeRequestBodyUnreadable, "Bad Request", map[string]any{"details": err.Error()})
 				return
 			}
-			if empty {
-				utils.WriteJSON(w, 400, map[string]any{
-					"error":   "Bad Request",
+			if empty && !report.soften(openapi.FieldError{In: "body", Reason: "request body is required by the API spec"}) {
+				writeError(w, 400, CodeRequestBodyRequired, "Bad Request", map[string]any{
 					"details": "Request body is required by the API spec",
 				})
 				return
@@ -145,46 +431,513 @@ func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	resp, err := s.sampleProvider.ResolveAndLoad(
-		method,
-		rt.Swagger,
-		path,
-		rt.SampleFile,
-	)
+	if schemaChecks {
+		report.check("body")
+		fieldErrs, err := s.validator.ValidateRequestBody(r, rt.Swagger, rt.Method)
+		if err != nil {
+			writeError(w, 400, CodeRequestBodyUnreadable, "Bad Request", map[string]any{"details": err.Error()})
+			return
+		}
+		if len(fieldErrs) > 0 && !report.soften(inBody(fieldErrs)...) {
+			writeError(w, 400, CodeRequestBodyInvalid, "Bad Request", map[string]any{
+				"details": "Request body does not match the API spec",
+				"errors":  fieldErrs,
+			})
+			return
+		}
+	}
+	report.write(w)
+
+	ext := s.specProvider.GetEmulatorExtensions(rt.Swagger, rt.Method)
+	if ext.DelayMs > 0 && !delayResponse(w, r, time.Duration(ext.DelayMs)*time.Millisecond) {
+		return
+	}
+
+	if ext.Backoff != nil && !isHeadAsGet(r) && s.serveBackoff(w, r, ext.Backoff) {
+		return
+	}
+
+	if ext.LongPollMs > 0 && ext.Sample == "" &&
+		!s.holdLongPoll(w, r, rt.Swagger, time.Duration(ext.LongPollMs)*time.Millisecond) {
+		return
+	}
+
+	if ext.Batch {
+		s.handleBatch(w, r, ext.Status)
+		return
+	}
+	if s.jobs.serve(w, r, rt) {
+		return
+	}
+
+	specialModes, err := parseSpecialStrings(r)
 	if err != nil {
-		if s.cfg.FallbackMode == config.FallbackOpenAPIExample {
-			if body, ok := s.specProvider.TryGetExampleBody(rt.Swagger, rt.Method); ok {
-				w.Header().Set("content-type", "application/json")
-				w.WriteHeader(200)
-				_, _ = w.Write(body)
-				return
+		writeError(w, 400, CodeInvalidParameter, "Bad Request", map[string]any{
+			"details": err.Error(),
+		})
+		return
+	}
+	if s.serveBoundary(w, r, rt, ext.Status, specialModes) {
+		return
+	}
+	preferred, err := preferredStatus(r)
+	if err != nil {
+		writeError(w, 400, CodeInvalidParameter, "Bad Request", map[string]any{
+			"details": err.Error(),
+		})
+		return
+	}
+
+	opts := samples.LoadOptions{
+		ForceState: strings.TrimSpace(r.Header.Get(scenarioStateHeader)),
+		ReadOnly:   isHeadAsGet(r),
+		Status:     preferred,
+		Client:     s.clientID(r),
+		TagFile:    rt.TagFile,
+		Cookies:    requestCookies(r),
+		Path:       params,
+		Query:      requestQuery(r),
+		Header:     requestHeaders(r),
+		Body:       sync.OnceValue(func() any { return s.requestJSONBody(r) }),
+	}
+	var resp *samples.Response
+	if ext.Sample != "" && preferred == 0 {
+		resp, err = s.sampleProvider.LoadSample(ext.Sample, opts)
+	} else {
+		resp, err = s.sampleProvider.ResolveAndLoad(method, rt.Swagger, path, rt.SampleFile, opts)
+	}
+	var stateErr *samples.UnknownScenarioStateError
+	if errors.As(err, &stateErr) {
+		writeError(w, 400, CodeScenarioStateUnknown, "Unknown scenario state", map[string]any{
+			"state": stateErr.State,
+			"known": stateErr.Known,
+		})
+		return
+	}
+	var storeErr *samples.ScenarioStoreError
+	if errors.As(err, &storeErr) {
+		s.log.WithError(storeErr.Err).Error("scenario store failed")
+		writeError(w, 503, CodeScenarioStoreFailed, "Scenario state is unavailable", map[string]any{
+			"details": storeErr.Err.Error(),
+		})
+		return
+	}
+	var syntaxErr *samples.SampleSyntaxError
+	if errors.As(err, &syntaxErr) {
+		s.log.WithFields(logrus.Fields{
+			"file":   syntaxErr.Path,
+			"line":   syntaxErr.Line,
+			"column": syntaxErr.Column,
+		}).WithError(syntaxErr.Err).Warn("sample file is not valid JSON")
+		writeError(w, 500, CodeSampleInvalidJSON, "Sample file is not valid JSON", map[string]any{
+			"method":      method,
+			"path":        path,
+			"swaggerPath": rt.Swagger,
+			"file":        syntaxErr.Path,
+			"line":        syntaxErr.Line,
+			"column":      syntaxErr.Column,
+			"details":     syntaxErr.Err.Error(),
+		})
+		return
+	}
+	var envErr *samples.SampleEnvelopeError
+	if errors.As(err, &envErr) {
+		s.log.WithField("file", envErr.Path).WithError(envErr.Err).Warn("sample envelope does not match schema")
+		writeError(w, 500, CodeSampleInvalidEnvelope, "Sample envelope is invalid", map[string]any{
+			"method":      method,
+			"path":        path,
+			"swaggerPath": rt.Swagger,
+			"file":        envErr.Path,
+			"details":     envErr.Err.Error(),
+		})
+		return
+	}
+	var expectErr *samples.RequestExpectationError
+	if errors.As(err, &expectErr) {
+		writeError(w, expectErr.Status, CodeRequestUnexpected, "Request does not meet the sample's expectations", map[string]any{
+			"method":      method,
+			"path":        path,
+			"swaggerPath": rt.Swagger,
+			"file":        expectErr.Path,
+			"failures":    expectErr.Failures,
+		})
+		return
+	}
+	var includeErr *samples.SampleIncludeError
+	if errors.As(err, &includeErr) {
+		s.log.WithField("file", includeErr.Path).WithError(includeErr.Err).Warn("sample include failed")
+		writeError(w, 500, CodeSampleIncludeFailed, "Sample include failed", map[string]any{
+			"method":      method,
+			"path":        path,
+			"swaggerPath": rt.Swagger,
+			"file":        includeErr.Path,
+			"include":     includeErr.Include,
+			"details":     includeErr.Err.Error(),
+		})
+		return
+	}
+	var tplErr *samples.SampleTemplateError
+	if errors.As(err, &tplErr) {
+		s.log.WithField("file", tplErr.Path).WithError(tplErr.Err).Warn("sample template failed")
+		writeError(w, 500, CodeSampleTemplateError, "Sample template failed", map[string]any{
+			"method":      method,
+			"path":        path,
+			"swaggerPath": rt.Swagger,
+			"file":        tplErr.Path,
+			"details":     tplErr.Err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		fb := s.fallbackFor(rt)
+		// A preferred status is only served from its sample; falling back
+		// to the spec example would answer a different status.
+		if body, contentType, ok := s.tryFallbackBody(fb.Mode, rt, r.Header.Get("Accept")); ok && preferred == 0 {
+			status := 200
+			switch {
+			case fb.Status > 0:
+				status = fb.Status
+			case ext.Status > 0:
+				status = ext.Status
 			}
+			w.Header().Set("content-type", contentType)
+			w.WriteHeader(status)
+			_, _ = w.Write(padBody(injectSpecialStrings(body, specialModes), ext.PadBytes))
+			return
 		}
 
-		utils.WriteJSON(w, 501, map[string]any{
-			"error":              "No sample file for route",
+		status := 501
+		if fb.Status > 0 {
+			status = fb.Status
+		}
+		writeError(w, status, CodeSampleNotFound, "No sample file for route", map[string]any{
 			"method":             method,
 			"path":               path,
 			"swaggerPath":        rt.Swagger,
 			"legacyFlatFilename": rt.SampleFile,
 			"layout":             s.cfg.Layout,
+			"fallbackMode":       fb.Mode,
 			"details":            err.Error(),
-			"hint":               "Create the sample file under SAMPLES_DIR/<path>/<METHOD>[.<state>].json (or legacy flat), or set FALLBACK_MODE=openapi_examples and add examples to swagger.json",
+			"hint":               "Create the sample file under SAMPLES_DIR/<path>/<METHOD>[.<state>|.<status>].json (or legacy flat), or set FALLBACK_MODE=openapi_examples and add examples to swagger.json",
 		})
 		return
 	}
 
-	for k, v := range resp.Headers {
-		w.Header().Set(k, v)
+	if resp.Scenario != nil {
+		s.scenarios.observe(scenarioView{
+			Method:      method,
+			Path:        path,
+			SwaggerPath: rt.Swagger,
+			Client:      s.clientID(r),
+		}, resp.Scenario)
+	}
+
+	if ext.Status > 0 && preferred == 0 {
+		resp.Status = ext.Status
+	}
+
+	if !s.checkSample(w, rt, resp) {
+		return
+	}
+
+	if d := resp.Delay.Duration(); d > 0 && !delayResponse(w, r, d) {
+		return
+	}
+
+	prefixLocation(r, resp.Headers)
+	s.setSampleHeaders(w, resp.Headers)
+	setSampleCookies(w, resp.Cookies)
+	body := padBody(injectSpecialStrings(resp.Body, specialModes), ext.PadBytes)
+	if len(body) != len(resp.Body) || resp.Stream != nil {
+		w.Header().Del("Content-Length")
+	}
+	w.WriteHeader(resp.Status)
+	if resp.Stream != nil {
+		if err := writeStream(r.Context(), w, body, resp.Stream); err != nil {
+			return
+		}
+	} else if _, err := w.Write(body); err != nil {
+		return
+	}
+	if err := resp.CommitScenario(); err != nil {
+		s.log.WithError(err).Warn("failed to advance scenario")
+	}
+}
+
+// serveDefaultSample answers a request that matches no route from the
+// catch-all samples under SAMPLES_DIR/_default. It returns false when there
+// is none, so the caller can answer 404.
+func (s *Server) serveDefaultSample(w http.ResponseWriter, method string) bool {
+	resp, ok, err := s.sampleProvider.LoadDefault(method)
+	if !ok {
+		return false
 	}
+	if err != nil {
+		s.log.WithError(err).Warn("catch-all sample failed")
+		code, msg := CodeSampleInvalidJSON, "Sample file is not valid JSON"
+		var envErr *samples.SampleEnvelopeError
+		if errors.As(err, &envErr) {
+			code, msg = CodeSampleInvalidEnvelope, "Sample envelope is invalid"
+		}
+		writeError(w, 500, code, msg, map[string]any{
+			"method":  method,
+			"file":    samples.DefaultDir,
+			"details": err.Error(),
+		})
+		return true
+	}
+	s.setSampleHeaders(w, resp.Headers)
+	setSampleCookies(w, resp.Cookies)
 	w.WriteHeader(resp.Status)
 	_, _ = w.Write(resp.Body)
+	return true
+}
+
+// acceptCredential reports whether SECURITY_MODE=enforce accepts a credential:
+// any value when SECURITY_TOKENS is empty, otherwise a listed one or a token
+// issued by the emulated OAuth2 endpoint.
+func (s *Server) acceptCredential(cred string) bool {
+	if len(s.cfg.SecurityTokens) == 0 {
+		return true
+	}
+	return slices.Contains(s.cfg.SecurityTokens, cred) || s.oauth.isIssued(cred)
+}
+
+// writeParameterErrors rejects a request whose parameters do not match the spec.
+func writeParameterErrors(w http.ResponseWriter, fieldErrs []openapi.FieldError) {
+	names := make([]string, 0, len(fieldErrs))
+	for _, fe := range fieldErrs {
+		names = append(names, fe.Field)
+	}
+	writeError(w, 400, CodeRequestParameterInvalid, "Bad Request", map[string]any{
+		"details":    "Request parameters do not match the API spec: " + strings.Join(names, ", "),
+		"parameters": names,
+		"errors":     fieldErrs,
+	})
+}
+
+// requestCookies returns the request's cookies by name; the first of several
+// cookies with the same name wins.
+func requestCookies(r *http.Request) map[string]string {
+	out := map[string]string{}
+	for _, c := range r.Cookies() {
+		if _, ok := out[c.Name]; !ok {
+			out[c.Name] = c.Value
+		}
+	}
+	return out
+}
+
+// setSampleCookies adds one Set-Cookie header per cookie of a sample.
+func setSampleCookies(w http.ResponseWriter, cookies []*http.Cookie) {
+	for _, c := range cookies {
+		http.SetCookie(w, c)
+	}
+}
+
+// requestQuery returns the first value of every query parameter.
+func requestQuery(r *http.Request) map[string]string {
+	out := map[string]string{}
+	for k, v := range r.URL.Query() {
+		if len(v) > 0 {
+			out[k] = v[0]
+		}
+	}
+	return out
+}
+
+// requestHeaders returns the first value of every request header, keyed by
+// its canonical name with "-" replaced by "_" so templates can use
+// .Header.X_Tenant.
+func requestHeaders(r *http.Request) map[string]string {
+	out := map[string]string{}
+	for k, v := range r.Header {
+		if len(v) > 0 {
+			out[strings.ReplaceAll(k, "-", "_")] = v[0]
+		}
+	}
+	return out
+}
+
+// defaultTemplateBodyMaxBytes is used when Config.TemplateBodyMaxBytes is 0.
+const defaultTemplateBodyMaxBytes = 1 << 20
+
+// requestJSONBody decodes the request body for samples, or returns nil when
+// it is empty, not JSON or larger than TemplateBodyMaxBytes. Whatever was
+// read is put back for later readers.
+func (s *Server) requestJSONBody(r *http.Request) any {
+	if r.Body == nil {
+		return nil
+	}
+	limit := int64(s.cfg.TemplateBodyMaxBytes)
+	if limit <= 0 {
+		limit = defaultTemplateBodyMaxBytes
+	}
+	var read bytes.Buffer
+	body := r.Body
+	b, err := io.ReadAll(http.MaxBytesReader(nil, io.NopCloser(io.TeeReader(body, &read)), limit))
+	r.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(&read, body), body}
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		s.log.WithField("limit", limit).Warn("request body too large for sample templates; .Body is empty")
+		return nil
+	}
+	if err != nil {
+		return nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var v any
+	if dec.Decode(&v) != nil {
+		return nil
+	}
+	return v
+}
+
+func (s *Server) router() openapi.IRouterProvider {
+	s.routerMu.RLock()
+	defer s.routerMu.RUnlock()
+	return s.routerProvider
+}
+
+// ReloadSpec re-reads the spec (dropping cached fallback bodies) and rebuilds the route table.
+func (s *Server) ReloadSpec() error {
+	if err := s.specProvider.Reload(); err != nil {
+		return err
+	}
+	rp := openapi.NewRouterProvider(s.specProvider.GetSpec(), openapi.WithRoutePriorities(s.routePriorities))
+	if rp == nil {
+		return fmt.Errorf("reloaded spec has no paths")
+	}
+
+	s.routerMu.Lock()
+	s.routerProvider = rp
+	s.routerMu.Unlock()
+	s.jobs.index(rp.GetRoutes(), s.specProvider)
+	s.oauth.index(s.specProvider.GetSpec())
+	s.recordSpec()
+	return nil
+}
+
+// fallbackFor returns the fallback for a route: the per-route override if one
+// is configured, otherwise the global FALLBACK_MODE.
+func (s *Server) fallbackFor(rt *openapi.Route) config.FallbackOverride {
+	fb := config.FallbackOverride{Mode: s.cfg.FallbackMode}
+	if o, ok := s.fallbackOverrides[config.RouteKey(rt.Method, rt.Swagger)]; ok {
+		if o.Mode != "" {
+			fb.Mode = o.Mode
+		}
+		fb.Status = o.Status
+	}
+	return fb
+}
+
+// tryFallbackBody builds the fallback body of a route. Spec examples are
+// served in the first media type of accept the response declares, if any.
+func (s *Server) tryFallbackBody(mode config.FallbackMode, rt *openapi.Route, accept string) ([]byte, string, bool) {
+	switch mode {
+	case config.FallbackOpenAPIExample:
+		for _, mt := range acceptedMediaTypes(accept) {
+			if b, ct, ok := s.specProvider.TryGetExampleBodyFor(rt.Swagger, rt.Method, mt); ok {
+				return b, ct, true
+			}
+		}
+		return s.specProvider.TryGetExampleBody(rt.Swagger, rt.Method)
+	case config.FallbackRandom:
+		return s.specProvider.TryGetRandomBody(rt.Swagger, rt.Method)
+	default:
+		return nil, "", false
+	}
+}
+
+// writeDeadlineSlack is the time left to write a response once a hold ends.
+const writeDeadlineSlack = 10 * time.Second
+
+// extendWriteDeadline lets a response that is held back or written slowly
+// for d outlive the server-wide write timeout, which would otherwise drop
+// the connection.
+func extendWriteDeadline(w http.ResponseWriter, d time.Duration) {
+	_ = http.NewResponseController(w).SetWriteDeadline(time.Now().Add(d + writeDeadlineSlack))
+}
+
+// delayResponse holds the response back for d, extending the write deadline
+// first so that a long delay reaches the client as a slow response rather
+// than a dropped connection. It returns false if the client went away.
+func delayResponse(w http.ResponseWriter, r *http.Request, d time.Duration) bool {
+	extendWriteDeadline(w, d)
+	return sleepCtx(r.Context(), d)
+}
+
+// acceptedMediaTypes lists the concrete media types of an Accept header,
+// highest q value first. Wildcards and q=0 are left out.
+func acceptedMediaTypes(accept string) []string {
+	type ranked struct {
+		mediaType string
+		q         float64
+	}
+	var out []ranked
+	for _, part := range strings.Split(accept, ",") {
+		rng, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		rng = strings.ToLower(strings.TrimSpace(rng))
+		if rng == "" || strings.Contains(rng, "*") {
+			continue
+		}
+		q := 1.0
+		for _, p := range strings.Split(params, ";") {
+			if v, ok := strings.CutPrefix(strings.TrimSpace(p), "q="); ok {
+				if f, err := strconv.ParseFloat(v, 64); err == nil {
+					q = f
+				}
+			}
+		}
+		if q > 0 {
+			out = append(out, ranked{rng, q})
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].q > out[j].q })
+	types := make([]string, len(out))
+	for i, r := range out {
+		types[i] = r.mediaType
+	}
+	return types
+}
+
+// sleepCtx waits for d unless the request is cancelled first.
+func sleepCtx(ctx context.Context, d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
 }
 
 func (s *Server) DebugRoutes() string {
+	var missing, unused []string
+	if report, err := s.auditSampleCoverage(); err == nil {
+		missing, unused = report.Missing, report.Unused
+	}
+
 	out := ""
-	for _, r := range s.routerProvider.GetRoutes() {
-		out += fmt.Sprintf("%s %s -> %s\n", r.Method, r.Swagger, r.SampleFile)
+	for _, r := range s.router().GetRoutes() {
+		file := r.SampleFile
+		if s.cfg.Layout == config.LayoutTags {
+			file = r.TagFile
+		}
+		note := ""
+		if slices.Contains(missing, r.Method+" "+r.Swagger) {
+			note = " (no sample)"
+		}
+		out += fmt.Sprintf("%s %s -> %s%s\n", r.Method, r.Swagger, file, note)
+	}
+	for _, f := range unused {
+		out += fmt.Sprintf("unused sample file: %s\n", f)
 	}
 	return out
 }
@@ -5,13 +5,17 @@
 package server
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/ozgen/openapi-emulator/config"
 )
@@ -88,68 +92,1069 @@ func TestHandle_NoRoute_404(t *testing.T) {
 	}
 	var m map[string]any
 	_ = json.Unmarshal(rr.Body.Bytes(), &m)
-	if m["error"] != "No route" {
+	if m["error"] != string(CodeRouteNotFound) || m["message"] != "No route" {
 		t.Fatalf("unexpected body: %v", m)
 	}
 }
 
+func TestHandle_KnownPathWrongMethod_405WithAllow(t *testing.T) {
+	s := newTestServer(t, config.ValidationRequired, config.FallbackOpenAPIExample)
+
+	rr := httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodDelete, "http://example.com/items/1", nil))
+
+	if rr.Code != 405 {
+		t.Fatalf("expected 405, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if got := rr.Header().Get("Allow"); got != "GET" {
+		t.Fatalf("unexpected Allow header: %q", got)
+	}
+	var m map[string]any
+	_ = json.Unmarshal(rr.Body.Bytes(), &m)
+	if m["error"] != string(CodeMethodNotAllowed) {
+		t.Fatalf("unexpected body: %v", m)
+	}
+}
+
+func TestHandle_OptionsAnsweredFromSpec(t *testing.T) {
+	s := newTestServer(t, config.ValidationNone, config.FallbackNone)
+
+	rr := httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodOptions, "http://example.com/items/123", nil))
+
+	if rr.Code != 204 {
+		t.Fatalf("expected 204, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if got := rr.Header().Get("Allow"); got != "GET" {
+		t.Fatalf("expected Allow: GET, got %q", got)
+	}
+	if rr.Body.Len() != 0 {
+		t.Fatalf("expected empty body, got %q", rr.Body.String())
+	}
+
+	rr = httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodOptions, "http://example.com/unknown", nil))
+	if rr.Code != 404 {
+		t.Fatalf("expected 404 for unknown path, got %d", rr.Code)
+	}
+}
+
+func TestHandle_SampleValidation(t *testing.T) {
+	disableScenarioForTests()
+
+	dir := t.TempDir()
+	specPath := writeFile(t, dir, "spec.json", `{
+	  "openapi":"3.0.3",
+	  "info":{"title":"t","version":"1"},
+	  "paths":{"/items/{id}":{"get":{"responses":{"200":{
+	    "description":"ok",
+	    "content":{"application/json":{"schema":{
+	      "type":"object","required":["id"],"properties":{"id":{"type":"string"}}
+	    }}}
+	  }}}}}
+	}`)
+	writeFileWithDirs(t, dir, filepath.Join("items", "{id}", "GET.json"), `{"id": 42}`)
+
+	for _, tc := range []struct {
+		mode config.SampleValidationMode
+		want int
+	}{
+		{config.SampleValidationOff, 200},
+		{config.SampleValidationWarn, 200},
+		{config.SampleValidationStrict, 500},
+	} {
+		s, err := New(Config{
+			Port:             "0",
+			SpecPath:         specPath,
+			SamplesDir:       dir,
+			FallbackMode:     config.FallbackNone,
+			ValidationMode:   config.ValidationNone,
+			SampleValidation: tc.mode,
+			Layout:           config.LayoutFolders,
+		})
+		if err != nil {
+			t.Fatalf("New: %v", err)
+		}
+
+		rr := httptest.NewRecorder()
+		s.handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com/items/1", nil))
+		if rr.Code != tc.want {
+			t.Fatalf("%s: expected %d, got %d: %s", tc.mode, tc.want, rr.Code, rr.Body.String())
+		}
+		if tc.want == 500 && !strings.Contains(rr.Body.String(), string(CodeSampleSchemaMismatch)) {
+			t.Fatalf("unexpected body: %s", rr.Body.String())
+		}
+	}
+}
+
 func TestHandle_ValidationRequired_EmptyBody_400(t *testing.T) {
 	s := newTestServer(t, config.ValidationRequired, config.FallbackOpenAPIExample)
 
 	rr := httptest.NewRecorder()
-	req := httptest.NewRequest(http.MethodPost, "http://example.com/items", strings.NewReader("   "))
+	req := httptest.NewRequest(http.MethodPost, "http://example.com/items", strings.NewReader("   "))
+
+	s.handle(rr, req)
+
+	if rr.Code != 400 {
+		t.Fatalf("expected 400, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var m map[string]any
+	_ = json.Unmarshal(rr.Body.Bytes(), &m)
+	if m["error"] != string(CodeRequestBodyRequired) {
+		t.Fatalf("unexpected: %v", m)
+	}
+}
+
+func TestHandle_ValidationRequired_NonEmptyBody_AllowsSample(t *testing.T) {
+	s := newTestServer(t, config.ValidationRequired, config.FallbackOpenAPIExample)
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "http://example.com/items", strings.NewReader(`{"x":1}`))
+
+	s.handle(rr, req)
+
+	if rr.Code != 201 {
+		t.Fatalf("expected 201 from sample envelope, got %d", rr.Code)
+	}
+	if ct := rr.Header().Get("content-type"); ct != "application/json" {
+		t.Fatalf("expected content-type application/json, got %q", ct)
+	}
+	if strings.TrimSpace(rr.Body.String()) != `{"created":true}` {
+		t.Fatalf("unexpected body: %q", rr.Body.String())
+	}
+}
+
+func TestHandle_ValidateContentType_415(t *testing.T) {
+	s := newTestServer(t, config.ValidationNone, config.FallbackOpenAPIExample)
+
+	post := func(contentType string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "http://example.com/items", strings.NewReader(`<item/>`))
+		req.Header.Set("Content-Type", contentType)
+		s.handle(rr, req)
+		return rr
+	}
+
+	if rr := post("text/xml"); rr.Code != 201 {
+		t.Fatalf("content type must not be checked by default, got %d", rr.Code)
+	}
+
+	s.cfg.ValidateContentType = true
+	rr := post("text/xml")
+	if rr.Code != 415 {
+		t.Fatalf("expected 415, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var m map[string]any
+	_ = json.Unmarshal(rr.Body.Bytes(), &m)
+	if m["error"] != string(CodeUnsupportedMediaType) {
+		t.Fatalf("unexpected body: %v", m)
+	}
+
+	if rr := post("application/json"); rr.Code != 201 {
+		t.Fatalf("expected 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestHandle_ValidationSchema_InvalidBody_400WithFieldErrors(t *testing.T) {
+	s := newTestServer(t, config.ValidationSchema, config.FallbackOpenAPIExample)
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "http://example.com/items", strings.NewReader(`[1]`))
+	req.Header.Set("Content-Type", "application/json")
+	s.handle(rr, req)
+
+	if rr.Code != 400 {
+		t.Fatalf("expected 400, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var m map[string]any
+	_ = json.Unmarshal(rr.Body.Bytes(), &m)
+	if m["error"] != string(CodeRequestBodyInvalid) {
+		t.Fatalf("unexpected body: %v", m)
+	}
+	if errs, _ := m["errors"].([]any); len(errs) == 0 {
+		t.Fatalf("expected field errors, got %v", m)
+	}
+
+	rr = httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodPost, "http://example.com/items", nil))
+	if rr.Code != 400 || !strings.Contains(rr.Body.String(), string(CodeRequestBodyRequired)) {
+		t.Fatalf("expected REQUEST_BODY_REQUIRED, got %d: %s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestHandle_ValidationSchema_ValidBody_AllowsSample(t *testing.T) {
+	s := newTestServer(t, config.ValidationSchema, config.FallbackOpenAPIExample)
+
+	rr := httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodPost, "http://example.com/items", strings.NewReader(`{"a":1}`)))
+
+	if rr.Code != 201 {
+		t.Fatalf("expected 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestHandle_ValidationSchema_InvalidQuery_400WithParameterNames(t *testing.T) {
+	disableScenarioForTests()
+
+	dir := t.TempDir()
+	specPath := writeFile(t, dir, "spec.json", `{
+	  "openapi":"3.0.3",
+	  "info":{"title":"t","version":"1"},
+	  "paths":{"/scans":{"get":{
+	    "parameters":[
+	      {"name":"limit","in":"query","required":true,"schema":{"type":"integer"}},
+	      {"name":"state","in":"query","schema":{"type":"string","enum":["running","done"]}}
+	    ],
+	    "responses":{"200":{"description":"ok","content":{"application/json":{"example":[]}}}}
+	  }}}
+	}`)
+
+	s, err := New(Config{
+		Port:           "0",
+		SpecPath:       specPath,
+		SamplesDir:     dir,
+		FallbackMode:   config.FallbackOpenAPIExample,
+		ValidationMode: config.ValidationSchema,
+		Layout:         config.LayoutFolders,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	rr := httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com/scans?state=paused", nil))
+
+	if rr.Code != 400 {
+		t.Fatalf("expected 400, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var m map[string]any
+	_ = json.Unmarshal(rr.Body.Bytes(), &m)
+	if m["error"] != string(CodeRequestParameterInvalid) {
+		t.Fatalf("unexpected body: %v", m)
+	}
+	if names, _ := m["parameters"].([]any); len(names) != 2 {
+		t.Fatalf("expected limit and state reported, got %v", m["parameters"])
+	}
+
+	rr = httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com/scans?limit=5&state=done", nil))
+	if rr.Code != 200 {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestHandle_LayoutTags_ServesTagOperationSample(t *testing.T) {
+	disableScenarioForTests()
+
+	dir := t.TempDir()
+	specPath := writeFile(t, dir, "spec.json", `{
+	  "openapi":"3.0.3",
+	  "info":{"title":"t","version":"1"},
+	  "paths":{"/api/v1/scans/{id}":{"get":{
+	    "tags":["scans"],
+	    "operationId":"getScan",
+	    "responses":{"200":{"description":"ok"}}
+	  }}}
+	}`)
+	writeFileWithDirs(t, dir, filepath.Join("scans", "getScan.json"), `{"id":"s1"}`)
+
+	s, err := New(Config{
+		Port:           "0",
+		SpecPath:       specPath,
+		SamplesDir:     dir,
+		FallbackMode:   config.FallbackNone,
+		ValidationMode: config.ValidationNone,
+		Layout:         config.LayoutTags,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	rr := httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com/api/v1/scans/1", nil))
+	if rr.Code != 200 || !strings.Contains(rr.Body.String(), "s1") {
+		t.Fatalf("expected tag sample, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if got := s.DebugRoutes(); !strings.Contains(got, "-> scans/getScan.json") {
+		t.Fatalf("unexpected debug routes: %q", got)
+	}
+}
+
+func TestHandle_StrictQueryParams_400(t *testing.T) {
+	disableScenarioForTests()
+
+	dir := t.TempDir()
+	specPath := writeFile(t, dir, "spec.json", `{
+	  "openapi":"3.0.3",
+	  "info":{"title":"t","version":"1"},
+	  "paths":{"/scans":{"get":{
+	    "parameters":[{"name":"limit","in":"query","schema":{"type":"integer"}}],
+	    "responses":{"200":{"description":"ok","content":{"application/json":{"example":[]}}}}
+	  }}}
+	}`)
+
+	s, err := New(Config{
+		Port:              "0",
+		SpecPath:          specPath,
+		SamplesDir:        dir,
+		FallbackMode:      config.FallbackOpenAPIExample,
+		ValidationMode:    config.ValidationNone,
+		StrictQueryParams: true,
+		Layout:            config.LayoutFolders,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	rr := httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com/scans?limit=5", nil))
+	if rr.Code != 200 {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	rr = httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com/scans?limit=5&offset=10", nil))
+	if rr.Code != 400 {
+		t.Fatalf("expected 400, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var m map[string]any
+	_ = json.Unmarshal(rr.Body.Bytes(), &m)
+	if m["error"] != string(CodeRequestParameterInvalid) {
+		t.Fatalf("unexpected body: %v", m)
+	}
+	if params, _ := m["parameters"].([]any); len(params) != 1 || params[0] != "offset" {
+		t.Fatalf("unexpected parameters: %v", m["parameters"])
+	}
+}
+
+func TestHandle_ValidateHeaders_MissingRequiredHeader_400(t *testing.T) {
+	disableScenarioForTests()
+
+	dir := t.TempDir()
+	specPath := writeFile(t, dir, "spec.json", `{
+	  "openapi":"3.0.3",
+	  "info":{"title":"t","version":"1"},
+	  "paths":{"/scans":{"get":{
+	    "parameters":[{"name":"X-Tenant","in":"header","required":true,"schema":{"type":"string"}}],
+	    "responses":{"200":{"description":"ok","content":{"application/json":{"example":[]}}}}
+	  }}}
+	}`)
+
+	newServer := func(validateHeaders bool) *Server {
+		s, err := New(Config{
+			Port:            "0",
+			SpecPath:        specPath,
+			SamplesDir:      dir,
+			FallbackMode:    config.FallbackOpenAPIExample,
+			ValidationMode:  config.ValidationRequired,
+			ValidateHeaders: validateHeaders,
+			Layout:          config.LayoutFolders,
+		})
+		if err != nil {
+			t.Fatalf("New: %v", err)
+		}
+		return s
+	}
+
+	rr := httptest.NewRecorder()
+	newServer(false).handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com/scans", nil))
+	if rr.Code != 200 {
+		t.Fatalf("headers must not be checked by default, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	s := newServer(true)
+	rr = httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com/scans", nil))
+	if rr.Code != 400 {
+		t.Fatalf("expected 400, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var m map[string]any
+	_ = json.Unmarshal(rr.Body.Bytes(), &m)
+	if m["error"] != string(CodeRequestParameterInvalid) {
+		t.Fatalf("unexpected body: %v", m)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/scans", nil)
+	req.Header.Set("X-Tenant", "acme")
+	rr = httptest.NewRecorder()
+	s.handle(rr, req)
+	if rr.Code != 200 {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestHandle_ValidateCookies_MissingRequiredCookie_400(t *testing.T) {
+	disableScenarioForTests()
+
+	dir := t.TempDir()
+	specPath := writeFile(t, dir, "spec.json", `{
+	  "openapi":"3.0.3",
+	  "info":{"title":"t","version":"1"},
+	  "paths":{"/me":{"get":{
+	    "parameters":[{"name":"session","in":"cookie","required":true,"schema":{"type":"string","minLength":4}}],
+	    "responses":{"200":{"description":"ok","content":{"application/json":{"example":{}}}}}
+	  }}}
+	}`)
+
+	newServer := func(validateCookies bool) *Server {
+		s, err := New(Config{
+			Port:            "0",
+			SpecPath:        specPath,
+			SamplesDir:      dir,
+			FallbackMode:    config.FallbackOpenAPIExample,
+			ValidationMode:  config.ValidationRequired,
+			ValidateCookies: validateCookies,
+			Layout:          config.LayoutFolders,
+		})
+		if err != nil {
+			t.Fatalf("New: %v", err)
+		}
+		return s
+	}
+
+	rr := httptest.NewRecorder()
+	newServer(false).handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com/me", nil))
+	if rr.Code != 200 {
+		t.Fatalf("cookies must not be checked by default, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	s := newServer(true)
+	for _, value := range []string{"", "abc"} {
+		req := httptest.NewRequest(http.MethodGet, "http://example.com/me", nil)
+		if value != "" {
+			req.AddCookie(&http.Cookie{Name: "session", Value: value})
+		}
+		rr = httptest.NewRecorder()
+		s.handle(rr, req)
+		if rr.Code != 400 {
+			t.Fatalf("cookie %q: expected 400, got %d: %s", value, rr.Code, rr.Body.String())
+		}
+		var m map[string]any
+		_ = json.Unmarshal(rr.Body.Bytes(), &m)
+		fields, _ := m["errors"].([]any)
+		if m["error"] != string(CodeRequestParameterInvalid) || len(fields) != 1 {
+			t.Fatalf("unexpected body: %v", m)
+		}
+		if f, _ := fields[0].(map[string]any); f["in"] != "cookie" || f["field"] != "session" {
+			t.Fatalf("unexpected field error: %v", fields[0])
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/me", nil)
+	req.AddCookie(&http.Cookie{Name: "session", Value: "abcd"})
+	rr = httptest.NewRecorder()
+	s.handle(rr, req)
+	if rr.Code != 200 {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestHandle_SecurityEnforce_MissingToken_401(t *testing.T) {
+	disableScenarioForTests()
+
+	dir := t.TempDir()
+	specPath := writeFile(t, dir, "spec.json", `{
+	  "openapi":"3.0.3",
+	  "info":{"title":"t","version":"1"},
+	  "components":{"securitySchemes":{"bearerAuth":{"type":"http","scheme":"bearer"}}},
+	  "security":[{"bearerAuth":[]}],
+	  "paths":{"/scans":{"get":{
+	    "responses":{"200":{"description":"ok","content":{"application/json":{"example":[]}}}}
+	  }}}
+	}`)
+
+	s, err := New(Config{
+		Port:           "0",
+		SpecPath:       specPath,
+		SamplesDir:     dir,
+		FallbackMode:   config.FallbackOpenAPIExample,
+		ValidationMode: config.ValidationNone,
+		SecurityMode:   config.SecurityEnforce,
+		SecurityTokens: []string{"t0ken"},
+		Layout:         config.LayoutFolders,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	for _, auth := range []string{"", "Bearer wrong"} {
+		req := httptest.NewRequest(http.MethodGet, "http://example.com/scans", nil)
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+		rr := httptest.NewRecorder()
+		s.handle(rr, req)
+
+		if rr.Code != 401 {
+			t.Fatalf("%q: expected 401, got %d: %s", auth, rr.Code, rr.Body.String())
+		}
+		if got := rr.Header().Get("WWW-Authenticate"); got != "Bearer" {
+			t.Fatalf("unexpected WWW-Authenticate: %q", got)
+		}
+		var m map[string]any
+		_ = json.Unmarshal(rr.Body.Bytes(), &m)
+		if m["error"] != string(CodeUnauthorized) {
+			t.Fatalf("unexpected body: %v", m)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/scans", nil)
+	req.Header.Set("Authorization", "Bearer t0ken")
+	rr := httptest.NewRecorder()
+	s.handle(rr, req)
+	if rr.Code != 200 {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestHandle_ValidationSchema_InvalidPathParam_400(t *testing.T) {
+	disableScenarioForTests()
+
+	dir := t.TempDir()
+	specPath := writeFile(t, dir, "spec.json", `{
+	  "openapi":"3.0.3",
+	  "info":{"title":"t","version":"1"},
+	  "paths":{"/items/{id}":{
+	    "parameters":[{"name":"id","in":"path","required":true,"schema":{"type":"integer"}}],
+	    "get":{"responses":{"200":{"description":"ok","content":{"application/json":{"example":{"id":1}}}}}}
+	  }}
+	}`)
+
+	s, err := New(Config{
+		Port:           "0",
+		SpecPath:       specPath,
+		SamplesDir:     dir,
+		FallbackMode:   config.FallbackOpenAPIExample,
+		ValidationMode: config.ValidationSchema,
+		Layout:         config.LayoutFolders,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	rr := httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com/items/abc", nil))
+	if rr.Code != 400 || !strings.Contains(rr.Body.String(), string(CodeRequestParameterInvalid)) {
+		t.Fatalf("expected 400 REQUEST_PARAMETER_INVALID, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	rr = httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com/items/7", nil))
+	if rr.Code != 200 {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestHandle_SampleFound_WritesHeadersStatusBody(t *testing.T) {
+	s := newTestServer(t, config.ValidationRequired, config.FallbackOpenAPIExample)
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/items/123", nil)
+
+	s.handle(rr, req)
+
+	if rr.Code != 200 {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	if rr.Header().Get("x-sample") != "1" {
+		t.Fatalf("expected x-sample header, got %q", rr.Header().Get("x-sample"))
+	}
+	if strings.TrimSpace(rr.Body.String()) != `{"id":"123"}` {
+		t.Fatalf("unexpected body: %q", rr.Body.String())
+	}
+}
+
+func TestHandle_SampleMissing_FallbackOpenAPIExample_200(t *testing.T) {
+	disableScenarioForTests()
+
+	dir := t.TempDir()
+	specPath := writeFile(t, dir, "spec.json", minimalSpec())
+
+	s, err := New(Config{
+		Port:           "0",
+		SpecPath:       specPath,
+		SamplesDir:     dir,
+		FallbackMode:   config.FallbackOpenAPIExample,
+		ValidationMode: config.ValidationRequired,
+		Layout:         config.LayoutFolders,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/items/999", nil)
+
+	s.handle(rr, req)
+
+	if rr.Code != 200 {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if rr.Header().Get("content-type") != "application/json" {
+		t.Fatalf("expected application/json")
+	}
+	if strings.TrimSpace(rr.Body.String()) != `{"id":"example"}` {
+		t.Fatalf("unexpected body: %q", rr.Body.String())
+	}
+}
+
+func TestHandle_SampleMissing_NoFallback_501(t *testing.T) {
+	disableScenarioForTests()
+
+	dir := t.TempDir()
+	specPath := writeFile(t, dir, "spec.json", minimalSpec())
+
+	s, err := New(Config{
+		Port:           "0",
+		SpecPath:       specPath,
+		SamplesDir:     dir,
+		FallbackMode:   config.FallbackNone,
+		ValidationMode: config.ValidationRequired,
+		Layout:         config.LayoutFolders,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/items/1", nil)
+
+	s.handle(rr, req)
+
+	if rr.Code != 501 {
+		t.Fatalf("expected 501, got %d", rr.Code)
+	}
+	var m map[string]any
+	_ = json.Unmarshal(rr.Body.Bytes(), &m)
+
+	if m["error"] != string(CodeSampleNotFound) || m["message"] != "No sample file for route" {
+		t.Fatalf("unexpected: %v", m)
+	}
+	if m["swaggerPath"] != "/items/{id}" {
+		t.Fatalf("expected swaggerPath=/items/{id}, got %v", m["swaggerPath"])
+	}
+	if _, ok := m["legacyFlatFilename"]; !ok {
+		t.Fatalf("legacyFlatFilename missing: %v", m)
+	}
+	if m["layout"] != string(config.LayoutFolders) {
+		t.Fatalf("expected layout=%q, got %v", string(config.LayoutFolders), m["layout"])
+	}
+}
+
+func TestHandle_SampleInvalidJSON_500WithPosition(t *testing.T) {
+	s := newTestServer(t, config.ValidationNone, config.FallbackOpenAPIExample)
+	writeFileWithDirs(t, s.cfg.SamplesDir, filepath.Join("items", "{id}", "GET.json"), "{\n  \"body\": {,}\n}")
+
+	rr := httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com/items/1", nil))
+
+	if rr.Code != 500 {
+		t.Fatalf("expected 500, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var m map[string]any
+	_ = json.Unmarshal(rr.Body.Bytes(), &m)
+	if m["error"] != string(CodeSampleInvalidJSON) {
+		t.Fatalf("unexpected body: %v", m)
+	}
+	if m["line"] != float64(2) {
+		t.Fatalf("expected line 2, got %v", m["line"])
+	}
+}
+
+func TestHandle_SampleTemplate_RequestContext(t *testing.T) {
+	s := newTestServer(t, config.ValidationNone, config.FallbackOpenAPIExample)
+	writeFileWithDirs(t, s.cfg.SamplesDir, filepath.Join("items", "{id}", "GET.json"),
+		`{"headers":{"X-Tenant":"{{ .Header.X_Tenant }}"},"body":{"id":"{{ .Path.id }}","page":"{{ .Query.page }}"}}`)
+	writeFileWithDirs(t, s.cfg.SamplesDir, filepath.Join("items", "POST.json"),
+		`{"status":201,"body":{"name":"{{ .Body.name }}","count":{{ .Body.count }}}}`)
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/items/42?page=3", nil)
+	req.Header.Set("X-Tenant", "acme")
+	rr := httptest.NewRecorder()
+	s.handle(rr, req)
+
+	if rr.Code != 200 {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if got := rr.Header().Get("X-Tenant"); got != "acme" {
+		t.Fatalf("expected X-Tenant acme, got %q", got)
+	}
+	if got := strings.TrimSpace(rr.Body.String()); got != `{"id":"42","page":"3"}` {
+		t.Fatalf("unexpected body: %s", got)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "http://example.com/items", strings.NewReader(`{"name":"widget","count":12345678901}`))
+	req.Header.Set("Content-Type", "application/json")
+	rr = httptest.NewRecorder()
+	s.handle(rr, req)
+
+	if rr.Code != 201 {
+		t.Fatalf("expected 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if got := strings.TrimSpace(rr.Body.String()); got != `{"count":12345678901,"name":"widget"}` {
+		t.Fatalf("unexpected body: %s", got)
+	}
+}
+
+func TestRequestJSONBody_OverLimitIsNilAndBodyIsKept(t *testing.T) {
+	s := newTestServer(t, config.ValidationNone, config.FallbackOpenAPIExample)
+	s.cfg.TemplateBodyMaxBytes = 16
+
+	const small = `{"name":"w"}`
+	req := httptest.NewRequest(http.MethodPost, "http://example.com/items", strings.NewReader(small))
+	if got, ok := s.requestJSONBody(req).(map[string]any); !ok || got["name"] != "w" {
+		t.Fatalf("expected the decoded body, got %v", got)
+	}
+
+	const large = `{"name":"a much longer widget name"}`
+	req = httptest.NewRequest(http.MethodPost, "http://example.com/items", strings.NewReader(large))
+	if got := s.requestJSONBody(req); got != nil {
+		t.Fatalf("expected nil for a body over the limit, got %v", got)
+	}
+	b, err := io.ReadAll(req.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != large {
+		t.Fatalf("expected the body to be left intact, got %q", b)
+	}
+}
+
+func TestHandle_HeaderVariant_ServedForMatchingHeader(t *testing.T) {
+	s := newTestServer(t, config.ValidationNone, config.FallbackOpenAPIExample)
+	writeFileWithDirs(t, s.cfg.SamplesDir, filepath.Join("items", "{id}", "GET[german].json"),
+		`{"version":1,"match":{"headers":{"Accept-Language":{"prefix":"de"}}},"body":{"id":"123","name":"Artikel"}}`)
+	writeFileWithDirs(t, s.cfg.SamplesDir, filepath.Join("items", "{id}", "GET[header.X-Role=viewer].json"),
+		`{"status":403,"body":{"error":"forbidden"}}`)
+
+	for _, tc := range []struct {
+		header, value string
+		status        int
+		body          string
+	}{
+		{"Accept-Language", "de-DE", 200, `{"id":"123","name":"Artikel"}`},
+		{"Accept-Language", "en-US", 200, `{"id":"123"}`},
+		{"X-Role", "viewer", 403, `{"error":"forbidden"}`},
+	} {
+		req := httptest.NewRequest(http.MethodGet, "http://example.com/items/123", nil)
+		req.Header.Set(tc.header, tc.value)
+		rr := httptest.NewRecorder()
+		s.handle(rr, req)
+
+		if rr.Code != tc.status {
+			t.Fatalf("%s: %s: expected %d, got %d: %s", tc.header, tc.value, tc.status, rr.Code, rr.Body.String())
+		}
+		if got := strings.TrimSpace(rr.Body.String()); got != tc.body {
+			t.Fatalf("%s: %s: unexpected body: %s", tc.header, tc.value, got)
+		}
+	}
+}
+
+func TestHandle_BodyVariant_ServedForMatchingBody(t *testing.T) {
+	s := newTestServer(t, config.ValidationNone, config.FallbackOpenAPIExample)
+	writeFileWithDirs(t, s.cfg.SamplesDir, filepath.Join("items", "POST[cancel].json"),
+		`{"version":1,"status":200,"match":{"body":{"$.action":"cancel"}},"body":{"cancelled":true}}`)
+
+	for body, want := range map[string]int{`{"action":"cancel"}`: 200, `{"action":"create"}`: 201} {
+		req := httptest.NewRequest(http.MethodPost, "http://example.com/items", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+		s.handle(rr, req)
+
+		if rr.Code != want {
+			t.Fatalf("%s: expected %d, got %d: %s", body, want, rr.Code, rr.Body.String())
+		}
+	}
+}
+
+func TestHandle_SampleDelay(t *testing.T) {
+	s := newTestServer(t, config.ValidationNone, config.FallbackOpenAPIExample)
+	writeFileWithDirs(t, s.cfg.SamplesDir, filepath.Join("items", "{id}", "GET.json"),
+		`{"status":200,"delayMs":50,"body":{"id":"123"}}`)
+
+	start := time.Now()
+	rr := httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com/items/123", nil))
+	if rr.Code != 200 || time.Since(start) < 50*time.Millisecond {
+		t.Fatalf("expected a delayed 200, got %d after %s", rr.Code, time.Since(start))
+	}
+
+	// A cancelled client does not wait for the delay, and gets no response.
+	writeFileWithDirs(t, s.cfg.SamplesDir, filepath.Join("items", "{id}", "GET.json"),
+		`{"status":200,"delayMs":10000,"body":{"id":"123"}}`)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start = time.Now()
+	rr = httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com/items/123", nil).WithContext(ctx))
+	if time.Since(start) > 5*time.Second || rr.Body.Len() != 0 {
+		t.Fatalf("expected the handler to return on cancellation, took %s: %q", time.Since(start), rr.Body.String())
+	}
+}
+
+func TestHandle_SampleDelay_OutlastsWriteTimeout(t *testing.T) {
+	s := newTestServer(t, config.ValidationNone, config.FallbackOpenAPIExample)
+	writeFileWithDirs(t, s.cfg.SamplesDir, filepath.Join("items", "{id}", "GET.json"),
+		`{"status":200,"delayMs":400,"body":{"id":"123"}}`)
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(s.handle))
+	// Scaled down from the 10s of Server.listen.
+	ts.Config.WriteTimeout = 200 * time.Millisecond
+	ts.Start()
+	defer ts.Close()
+
+	res, err := http.Get(ts.URL + "/items/123")
+	if err != nil {
+		t.Fatalf("expected a slow response, got %v", err)
+	}
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil || res.StatusCode != 200 || string(body) != `{"id":"123"}` {
+		t.Fatalf("unexpected response %d %q: %v", res.StatusCode, body, err)
+	}
+}
+
+func TestHandle_EchoFields_CopiesRequestBody(t *testing.T) {
+	s := newTestServer(t, config.ValidationNone, config.FallbackOpenAPIExample)
+	writeFileWithDirs(t, s.cfg.SamplesDir, filepath.Join("items", "POST.json"),
+		`{"status":201,"echoFields":["name","tags"],"body":{"id":"i1","name":"x"}}`)
 
+	req := httptest.NewRequest(http.MethodPost, "http://example.com/items", strings.NewReader(`{"name":"widget","tags":["new"]}`))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
 	s.handle(rr, req)
 
-	if rr.Code != 400 {
-		t.Fatalf("expected 400, got %d: %s", rr.Code, rr.Body.String())
+	if rr.Code != 201 {
+		t.Fatalf("expected 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if got := strings.TrimSpace(rr.Body.String()); got != `{"id":"i1","name":"widget","tags":["new"]}` {
+		t.Fatalf("unexpected body: %s", got)
+	}
+}
+
+func TestHandle_SampleInvalidEnvelope_500(t *testing.T) {
+	s := newTestServer(t, config.ValidationNone, config.FallbackOpenAPIExample)
+	writeFileWithDirs(t, s.cfg.SamplesDir, filepath.Join("items", "{id}", "GET.json"), `{"version":1,"status":"ok"}`)
+
+	rr := httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com/items/1", nil))
+
+	if rr.Code != 500 {
+		t.Fatalf("expected 500, got %d: %s", rr.Code, rr.Body.String())
 	}
 	var m map[string]any
 	_ = json.Unmarshal(rr.Body.Bytes(), &m)
-	if m["error"] != "Bad Request" {
-		t.Fatalf("unexpected: %v", m)
+	if m["error"] != string(CodeSampleInvalidEnvelope) {
+		t.Fatalf("unexpected body: %v", m)
 	}
 }
 
-func TestHandle_ValidationRequired_NonEmptyBody_AllowsSample(t *testing.T) {
-	s := newTestServer(t, config.ValidationRequired, config.FallbackOpenAPIExample)
+func TestHandle_SampleMissing_FallbackNonJSONMediaType(t *testing.T) {
+	disableScenarioForTests()
 
-	rr := httptest.NewRecorder()
-	req := httptest.NewRequest(http.MethodPost, "http://example.com/items", strings.NewReader(`{"x":1}`))
+	dir := t.TempDir()
+	specPath := writeFile(t, dir, "spec.json", `{
+	  "openapi":"3.0.3",
+	  "info":{"title":"t","version":"1"},
+	  "paths":{"/report":{"get":{"responses":{"200":{
+	    "description":"ok",
+	    "content":{"text/csv":{"example":"id,name\n1,a\n"}}
+	  }}}}}
+	}`)
 
-	s.handle(rr, req)
+	s, err := New(Config{
+		Port:           "0",
+		SpecPath:       specPath,
+		SamplesDir:     dir,
+		FallbackMode:   config.FallbackOpenAPIExample,
+		ValidationMode: config.ValidationNone,
+		Layout:         config.LayoutFolders,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
 
-	if rr.Code != 201 {
-		t.Fatalf("expected 201 from sample envelope, got %d", rr.Code)
+	rr := httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com/report", nil))
+
+	if rr.Code != 200 {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
 	}
-	if ct := rr.Header().Get("content-type"); ct != "application/json" {
-		t.Fatalf("expected content-type application/json, got %q", ct)
+	if ct := rr.Header().Get("content-type"); ct != "text/csv" {
+		t.Fatalf("expected text/csv, got %q", ct)
 	}
-	if strings.TrimSpace(rr.Body.String()) != `{"created":true}` {
+	if rr.Body.String() != "id,name\n1,a\n" {
 		t.Fatalf("unexpected body: %q", rr.Body.String())
 	}
 }
 
-func TestHandle_SampleFound_WritesHeadersStatusBody(t *testing.T) {
-	s := newTestServer(t, config.ValidationRequired, config.FallbackOpenAPIExample)
+func TestHandle_Base64Sample_ServedByteExact(t *testing.T) {
+	s := newTestServer(t, config.ValidationNone, config.FallbackOpenAPIExample)
+	writeFileWithDirs(t, s.cfg.SamplesDir, filepath.Join("items", "{id}", "GET.json"), `{
+	  "version": 1,
+	  "headers": {"Content-Type": "image/png"},
+	  "bodyEncoding": "base64",
+	  "body": "iVBORw0KGgoAAAANSUhEUg=="
+	}`)
 
 	rr := httptest.NewRecorder()
-	req := httptest.NewRequest(http.MethodGet, "http://example.com/items/123", nil)
+	s.handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com/items/123", nil))
+
+	want := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+	if rr.Code != 200 || !bytes.Equal(rr.Body.Bytes(), want) {
+		t.Fatalf("expected the decoded PNG header, got %d: %q", rr.Code, rr.Body.Bytes())
+	}
+	if ct := rr.Header().Get("Content-Type"); ct != "image/png" {
+		t.Fatalf("expected image/png, got %q", ct)
+	}
+}
+
+func TestHandle_RawSampleFile_ServedWithInferredContentType(t *testing.T) {
+	s := newTestServer(t, config.ValidationNone, config.FallbackOpenAPIExample)
+	if err := os.Remove(filepath.Join(s.cfg.SamplesDir, "items", "{id}", "GET.json")); err != nil {
+		t.Fatal(err)
+	}
+	pdf := "%PDF-1.7\n\xe2\xe3\xcf\xd3\n"
+	writeFileWithDirs(t, s.cfg.SamplesDir, filepath.Join("items", "{id}", "GET.pdf"), pdf)
+
+	rr := httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com/items/123", nil))
+	if rr.Code != 200 || rr.Body.String() != pdf {
+		t.Fatalf("expected the PDF as is, got %d: %q", rr.Code, rr.Body.String())
+	}
+	if ct := rr.Header().Get("Content-Type"); ct != "application/pdf" {
+		t.Fatalf("expected application/pdf, got %q", ct)
+	}
+}
+
+func TestHandle_SampleExpect_UnmetAnsweredWithDiagnostics(t *testing.T) {
+	s := newTestServer(t, config.ValidationNone, config.FallbackOpenAPIExample)
+	writeFileWithDirs(t, s.cfg.SamplesDir, filepath.Join("items", "POST.json"), `{
+	  "version": 1,
+	  "status": 201,
+	  "expect": {"status": 422, "headers": {"X-Tenant": true}, "body": {"$.name": true}},
+	  "body": {"ok": true}
+	}`)
+
+	req := httptest.NewRequest(http.MethodPost, "http://example.com/items", strings.NewReader(`{"name":"a"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+	s.handle(rr, req)
+	if rr.Code != 422 {
+		t.Fatalf("expected 422, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var out map[string]any
+	if err := json.Unmarshal(rr.Body.Bytes(), &out); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	failures, _ := out["failures"].([]any)
+	if out["error"] != string(CodeRequestUnexpected) || len(failures) != 1 || failures[0] != "header X-Tenant is missing" {
+		t.Fatalf("unexpected diagnostics: %s", rr.Body.String())
+	}
 
+	req = httptest.NewRequest(http.MethodPost, "http://example.com/items", strings.NewReader(`{"name":"a"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Tenant", "acme")
+	rr = httptest.NewRecorder()
 	s.handle(rr, req)
+	if rr.Code != 201 {
+		t.Fatalf("expected the sample once expectations are met, got %d: %s", rr.Code, rr.Body.String())
+	}
+}
 
-	if rr.Code != 200 {
-		t.Fatalf("expected 200, got %d", rr.Code)
+func TestHandle_SampleIncludeMissing_Returns500(t *testing.T) {
+	s := newTestServer(t, config.ValidationNone, config.FallbackOpenAPIExample)
+	writeFileWithDirs(t, s.cfg.SamplesDir, filepath.Join("items", "{id}", "GET.json"),
+		`{"status": 200, "body": {"$include": "../../_shared/item.json"}}`)
+
+	rr := httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com/items/123", nil))
+	if rr.Code != 500 || !strings.Contains(rr.Body.String(), string(CodeSampleIncludeFailed)) {
+		t.Fatalf("expected 500 %s, got %d: %s", CodeSampleIncludeFailed, rr.Code, rr.Body.String())
 	}
-	if rr.Header().Get("x-sample") != "1" {
-		t.Fatalf("expected x-sample header, got %q", rr.Header().Get("x-sample"))
+}
+
+func TestHandle_ScenarioStateHeader_ForcesStateWithoutAdvancing(t *testing.T) {
+	s := newTestServer(t, config.ValidationNone, config.FallbackOpenAPIExample)
+	config.Envs.Scenario.Enabled = true
+	t.Cleanup(disableScenarioForTests)
+
+	s2, err := New(s.cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
 	}
-	if strings.TrimSpace(rr.Body.String()) != `{"id":"123"}` {
-		t.Fatalf("unexpected body: %q", rr.Body.String())
+	writeFileWithDirs(t, s.cfg.SamplesDir, filepath.Join("items", "{id}", "scenario.json"), `{
+	  "version": 1,
+	  "mode": "step",
+	  "key": {"pathParam": "id"},
+	  "sequence": [
+	    {"state": "pending", "file": "pending.json"},
+	    {"state": "done", "file": "done.json"}
+	  ],
+	  "behavior": {"advanceOn": [{"method": "GET"}]}
+	}`)
+	writeFileWithDirs(t, s.cfg.SamplesDir, filepath.Join("items", "{id}", "pending.json"), `{"state":"pending"}`)
+	writeFileWithDirs(t, s.cfg.SamplesDir, filepath.Join("items", "{id}", "done.json"), `{"state":"done"}`)
+
+	get := func(state string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "http://example.com/items/1", nil)
+		if state != "" {
+			req.Header.Set(scenarioStateHeader, state)
+		}
+		s2.handle(rr, req)
+		return rr
+	}
+
+	if rr := get("done"); rr.Code != 200 || !strings.Contains(rr.Body.String(), `"done"`) {
+		t.Fatalf("expected forced done state, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if rr := get(""); !strings.Contains(rr.Body.String(), `"pending"`) {
+		t.Fatalf("forced state must not advance the scenario, got %s", rr.Body.String())
+	}
+
+	rr := get("unknown")
+	if rr.Code != 400 {
+		t.Fatalf("expected 400, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var m map[string]any
+	_ = json.Unmarshal(rr.Body.Bytes(), &m)
+	if m["error"] != string(CodeScenarioStateUnknown) {
+		t.Fatalf("unexpected body: %v", m)
 	}
 }
 
-func TestHandle_SampleMissing_FallbackOpenAPIExample_200(t *testing.T) {
+func TestHandle_ScenarioAdvanceOnResponse_AdvancesAfterWrite(t *testing.T) {
+	s := newTestServer(t, config.ValidationNone, config.FallbackOpenAPIExample)
+	config.Envs.Scenario.Enabled = true
+	t.Cleanup(disableScenarioForTests)
+
+	s2, err := New(s.cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	writeFileWithDirs(t, s.cfg.SamplesDir, filepath.Join("items", "{id}", "scenario.json"), `{
+	  "version": 1,
+	  "mode": "step",
+	  "key": {"pathParam": "id"},
+	  "sequence": [
+	    {"state": "pending", "file": "pending.json"},
+	    {"state": "done", "file": "done.json"}
+	  ],
+	  "behavior": {"advanceOn": [{"method": "GET"}], "advance": "response"}
+	}`)
+	writeFileWithDirs(t, s.cfg.SamplesDir, filepath.Join("items", "{id}", "pending.json"), `{"state":"pending"}`)
+	writeFileWithDirs(t, s.cfg.SamplesDir, filepath.Join("items", "{id}", "done.json"), `{"state":"done"}`)
+
+	for _, want := range []string{`"pending"`, `"done"`, `"done"`} {
+		rr := httptest.NewRecorder()
+		s2.handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com/items/1", nil))
+		if rr.Code != 200 || !strings.Contains(rr.Body.String(), want) {
+			t.Fatalf("expected %s, got %d: %s", want, rr.Code, rr.Body.String())
+		}
+	}
+}
+
+func TestHandle_SampleMissing_FallbackRandom_200(t *testing.T) {
 	disableScenarioForTests()
 
 	dir := t.TempDir()
@@ -159,7 +1164,7 @@ func TestHandle_SampleMissing_FallbackOpenAPIExample_200(t *testing.T) {
 		Port:           "0",
 		SpecPath:       specPath,
 		SamplesDir:     dir,
-		FallbackMode:   config.FallbackOpenAPIExample,
+		FallbackMode:   config.FallbackRandom,
 		ValidationMode: config.ValidationRequired,
 		Layout:         config.LayoutFolders,
 	})
@@ -175,20 +1180,92 @@ func TestHandle_SampleMissing_FallbackOpenAPIExample_200(t *testing.T) {
 	if rr.Code != 200 {
 		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
 	}
-	if rr.Header().Get("content-type") != "application/json" {
-		t.Fatalf("expected application/json")
+	if !json.Valid(rr.Body.Bytes()) {
+		t.Fatalf("expected JSON body, got %q", rr.Body.String())
 	}
-	if strings.TrimSpace(rr.Body.String()) != `{"id":"example"}` {
-		t.Fatalf("unexpected body: %q", rr.Body.String())
+}
+
+func TestHandle_SampleMissing_FallbackOverridePerRoute(t *testing.T) {
+	disableScenarioForTests()
+
+	dir := t.TempDir()
+	specPath := writeFile(t, dir, "spec.json", minimalSpec())
+	overridesPath := writeFile(t, dir, "fallback.yaml", `
+"GET /items/{id}":
+  mode: none
+  status: 404
+`)
+
+	s, err := New(Config{
+		Port:                  "0",
+		SpecPath:              specPath,
+		SamplesDir:            dir,
+		FallbackMode:          config.FallbackOpenAPIExample,
+		FallbackOverridesPath: overridesPath,
+		ValidationMode:        config.ValidationNone,
+		Layout:                config.LayoutFolders,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	rr := httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com/items/1", nil))
+
+	if rr.Code != 404 {
+		t.Fatalf("expected 404 from override, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var m map[string]any
+	_ = json.Unmarshal(rr.Body.Bytes(), &m)
+	if m["error"] != string(CodeSampleNotFound) || m["fallbackMode"] != string(config.FallbackNone) {
+		t.Fatalf("unexpected body: %v", m)
+	}
+
+	// POST /items has no override and keeps the global openapi_examples fallback.
+	rr = httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodPost, "http://example.com/items", strings.NewReader(`{}`)))
+	if rr.Code != 200 {
+		t.Fatalf("expected 200 from global fallback, got %d: %s", rr.Code, rr.Body.String())
 	}
 }
 
-func TestHandle_SampleMissing_NoFallback_501(t *testing.T) {
+func TestNew_InvalidFallbackOverrides_Error(t *testing.T) {
 	disableScenarioForTests()
 
 	dir := t.TempDir()
 	specPath := writeFile(t, dir, "spec.json", minimalSpec())
 
+	_, err := New(Config{
+		Port:                  "0",
+		SpecPath:              specPath,
+		SamplesDir:            dir,
+		FallbackOverridesPath: filepath.Join(dir, "missing.yaml"),
+	})
+	if err == nil {
+		t.Fatalf("expected error for missing overrides file")
+	}
+}
+
+func TestHandle_EmulatorExtensions_StatusAndSampleOverride(t *testing.T) {
+	disableScenarioForTests()
+
+	dir := t.TempDir()
+	specPath := writeFile(t, dir, "spec.json", `{
+	  "openapi":"3.0.3",
+	  "info":{"title":"t","version":"1"},
+	  "paths":{
+		"/jobs/{id}":{
+		  "get":{
+			"x-emulator-status": 202,
+			"x-emulator-sample": "shared/pending.json",
+			"x-emulator-delay-ms": 1,
+			"responses":{"200":{"description":"ok"}}
+		  }
+		}
+	  }
+	}`)
+	writeFileWithDirs(t, dir, filepath.Join("shared", "pending.json"), `{"state":"pending"}`)
+
 	s, err := New(Config{
 		Port:           "0",
 		SpecPath:       specPath,
@@ -202,27 +1279,15 @@ func TestHandle_SampleMissing_NoFallback_501(t *testing.T) {
 	}
 
 	rr := httptest.NewRecorder()
-	req := httptest.NewRequest(http.MethodGet, "http://example.com/items/1", nil)
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/jobs/1", nil)
 
 	s.handle(rr, req)
 
-	if rr.Code != 501 {
-		t.Fatalf("expected 501, got %d", rr.Code)
-	}
-	var m map[string]any
-	_ = json.Unmarshal(rr.Body.Bytes(), &m)
-
-	if m["error"] != "No sample file for route" {
-		t.Fatalf("unexpected: %v", m)
-	}
-	if m["swaggerPath"] != "/items/{id}" {
-		t.Fatalf("expected swaggerPath=/items/{id}, got %v", m["swaggerPath"])
-	}
-	if _, ok := m["legacyFlatFilename"]; !ok {
-		t.Fatalf("legacyFlatFilename missing: %v", m)
+	if rr.Code != 202 {
+		t.Fatalf("expected 202, got %d: %s", rr.Code, rr.Body.String())
 	}
-	if m["layout"] != string(config.LayoutFolders) {
-		t.Fatalf("expected layout=%q, got %v", string(config.LayoutFolders), m["layout"])
+	if strings.TrimSpace(rr.Body.String()) != `{"state":"pending"}` {
+		t.Fatalf("unexpected body: %q", rr.Body.String())
 	}
 }
 
@@ -346,3 +1411,99 @@ func minimalSpec() string {
 	  }
 	}`
 }
+
+func TestHandle_GreedyPathParam(t *testing.T) {
+	disableScenarioForTests()
+
+	dir := t.TempDir()
+	specPath := writeFile(t, dir, "spec.json", `{
+	  "openapi":"3.0.3",
+	  "info":{"title":"t","version":"1"},
+	  "paths":{"/files/{path+}":{"get":{
+	    "parameters":[{"name":"path","in":"path","required":true,"schema":{"type":"string"}}],
+	    "responses":{"200":{"description":"ok"}}
+	  }}}
+	}`)
+	writeFileWithDirs(t, dir, filepath.Join("files", "{path+}", "GET.json"), `{"file":true}`)
+
+	s, err := New(Config{
+		Port:           "0",
+		SpecPath:       specPath,
+		SamplesDir:     dir,
+		FallbackMode:   config.FallbackNone,
+		ValidationMode: config.ValidationSchema,
+		Layout:         config.LayoutFolders,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	rr := httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com/files/docs/2026/report.pdf", nil))
+	if rr.Code != 200 || !strings.Contains(rr.Body.String(), `"file":true`) {
+		t.Fatalf("expected sample for greedy route, got %d: %s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestHandle_GreedyExtensionScenario(t *testing.T) {
+	disableScenarioForTests()
+	config.Envs.Scenario.Enabled = true
+	t.Cleanup(disableScenarioForTests)
+
+	dir := t.TempDir()
+	specPath := writeFile(t, dir, "spec.json", `{
+	  "openapi":"3.0.3",
+	  "info":{"title":"t","version":"1"},
+	  "paths":{"/files/{path}":{"get":{
+	    "parameters":[{"name":"path","in":"path","required":true,"x-emulator-greedy":true,"schema":{"type":"string"}}],
+	    "responses":{"200":{"description":"ok"}}
+	  }}}
+	}`)
+	writeFileWithDirs(t, dir, filepath.Join("files", "{path}", "scenario.json"), `{
+	  "version": 1,
+	  "mode": "step",
+	  "key": {"pathParam": "path"},
+	  "sequence": [
+	    {"state": "pending", "file": "pending.json"},
+	    {"state": "done", "file": "done.json"}
+	  ],
+	  "behavior": {"advanceOn": [{"method": "GET"}]}
+	}`)
+	writeFileWithDirs(t, dir, filepath.Join("files", "{path}", "pending.json"), `{"state":"pending"}`)
+	writeFileWithDirs(t, dir, filepath.Join("files", "{path}", "done.json"), `{"state":"done"}`)
+
+	s, err := New(Config{
+		Port:           "0",
+		SpecPath:       specPath,
+		SamplesDir:     dir,
+		FallbackMode:   config.FallbackNone,
+		ValidationMode: config.ValidationNone,
+		Layout:         config.LayoutFolders,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	for _, want := range []string{`"pending"`, `"done"`} {
+		rr := httptest.NewRecorder()
+		s.handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com/files/docs/2026/report.pdf", nil))
+		if rr.Code != 200 || !strings.Contains(rr.Body.String(), want) {
+			t.Fatalf("expected %s, got %d: %s", want, rr.Code, rr.Body.String())
+		}
+	}
+	rr := httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com/files/docs/other.pdf", nil))
+	if !strings.Contains(rr.Body.String(), `"pending"`) {
+		t.Fatalf("expected a separate scenario key per path, got %s", rr.Body.String())
+	}
+}
+
+func TestAcceptedMediaTypes(t *testing.T) {
+	got := acceptedMediaTypes("text/*, application/xml;q=0.5, Text/CSV, application/json;q=0, */*;q=0.1")
+	if strings.Join(got, ",") != "text/csv,application/xml" {
+		t.Fatalf("unexpected media types %v", got)
+	}
+	if got := acceptedMediaTypes(""); len(got) != 0 {
+		t.Fatalf("expected none, got %v", got)
+	}
+}
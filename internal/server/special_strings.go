@@ -0,0 +1,121 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+// specialStringsHeader asks for the string values of a JSON response body to
+// be extended with hard-to-handle text, e.g. "X-Mock-Strings: emoji,long".
+const specialStringsHeader = "X-Mock-Strings"
+
+// longStringRunes is the length, in characters, of strings in "long" mode.
+const longStringRunes = 10000
+
+// specialStrings maps each X-Mock-Strings mode to the text it appends.
+var specialStrings = map[string]string{
+	"unicode": " ÀÉÎõü ß é 中文 日本語 한국어 Ωμέγα",
+	"emoji":   " 😀 👩‍👩‍👧 🏳️‍🌈 🇩🇪",
+	"rtl":     " \u202bمرحبا بالعالم שלום\u202c",
+	"long":    "",
+}
+
+// parseSpecialStrings reads the X-Mock-Strings modes of r in a fixed order;
+// "all" selects every mode. It returns nil when the header is absent.
+func parseSpecialStrings(r *http.Request) ([]string, error) {
+	raw := strings.TrimSpace(r.Header.Get(specialStringsHeader))
+	if raw == "" {
+		return nil, nil
+	}
+	want := map[string]bool{}
+	for _, m := range strings.Split(raw, ",") {
+		m = strings.ToLower(strings.TrimSpace(m))
+		if m == "all" {
+			for k := range specialStrings {
+				want[k] = true
+			}
+			continue
+		}
+		if _, ok := specialStrings[m]; !ok {
+			return nil, fmt.Errorf("unknown %s mode %q (want unicode, emoji, rtl, long or all)", specialStringsHeader, m)
+		}
+		want[m] = true
+	}
+
+	var out []string
+	for _, m := range []string{"unicode", "emoji", "rtl", "long"} {
+		if want[m] {
+			out = append(out, m)
+		}
+	}
+	return out, nil
+}
+
+// injectSpecialStrings extends every string value of a JSON body, keys
+// excluded, with the text of modes; "long" then repeats the result up to
+// longStringRunes characters. Non-JSON bodies are returned unchanged.
+func injectSpecialStrings(body []byte, modes []string) []byte {
+	if len(modes) == 0 {
+		return body
+	}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return body
+	}
+
+	var out bytes.Buffer
+	enc := json.NewEncoder(&out)
+	enc.SetEscapeHTML(false)
+	err := enc.Encode(mapStrings(v, func(s string) string {
+		for _, m := range modes {
+			if m == "long" {
+				s = lengthen(s)
+				continue
+			}
+			s += specialStrings[m]
+		}
+		return s
+	}))
+	if err != nil {
+		return body
+	}
+	return bytes.TrimSuffix(out.Bytes(), []byte("\n"))
+}
+
+func mapStrings(v any, f func(string) string) any {
+	switch t := v.(type) {
+	case string:
+		return f(t)
+	case []any:
+		for i := range t {
+			t[i] = mapStrings(t[i], f)
+		}
+	case map[string]any:
+		for k := range t {
+			t[k] = mapStrings(t[k], f)
+		}
+	}
+	return v
+}
+
+func lengthen(s string) string {
+	if s == "" {
+		s = "x"
+	}
+	n := utf8.RuneCountInString(s)
+	if n >= longStringRunes {
+		return s
+	}
+	rs := []rune(strings.Repeat(s, longStringRunes/n+1))
+	return string(rs[:longStringRunes])
+}
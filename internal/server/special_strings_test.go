@@ -0,0 +1,66 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/ozgen/openapi-emulator/config"
+)
+
+func TestInjectSpecialStrings(t *testing.T) {
+	body := []byte(`{"name":"a<b","tags":["x"],"count":12345678901234567890,"ok":true}`)
+
+	got := injectSpecialStrings(body, []string{"emoji", "rtl"})
+	var m map[string]any
+	if err := json.Unmarshal(got, &m); err != nil {
+		t.Fatalf("invalid JSON %s: %v", got, err)
+	}
+	name, _ := m["name"].(string)
+	if !strings.HasPrefix(name, "a<b") || !strings.Contains(name, "😀") || !strings.Contains(name, "\u202b") {
+		t.Fatalf("unexpected name %q", name)
+	}
+	if tags, _ := m["tags"].([]any); len(tags) != 1 || !strings.Contains(tags[0].(string), "😀") {
+		t.Fatalf("unexpected tags %v", m["tags"])
+	}
+	if !strings.Contains(string(got), "12345678901234567890") || m["ok"] != true {
+		t.Fatalf("non-string values changed: %s", got)
+	}
+
+	got = injectSpecialStrings([]byte(`{"id":"é"}`), []string{"long"})
+	_ = json.Unmarshal(got, &m)
+	if n := utf8.RuneCountInString(m["id"].(string)); n != longStringRunes {
+		t.Fatalf("expected %d characters, got %d", longStringRunes, n)
+	}
+
+	if got := injectSpecialStrings([]byte("plain"), []string{"emoji"}); string(got) != "plain" {
+		t.Fatalf("expected non-JSON body unchanged, got %q", got)
+	}
+}
+
+func TestHandle_SpecialStringsHeader(t *testing.T) {
+	s := newTestServer(t, config.ValidationNone, config.FallbackNone)
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/items/1", nil)
+	req.Header.Set(specialStringsHeader, "Unicode, emoji")
+	rr := httptest.NewRecorder()
+	s.handle(rr, req)
+	if rr.Code != 200 || !strings.Contains(rr.Body.String(), "123 ÀÉÎõü") || !strings.Contains(rr.Body.String(), "😀") {
+		t.Fatalf("expected mangled id, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "http://example.com/items/1", nil)
+	req.Header.Set(specialStringsHeader, "klingon")
+	rr = httptest.NewRecorder()
+	s.handle(rr, req)
+	if rr.Code != 400 || !strings.Contains(rr.Body.String(), string(CodeInvalidParameter)) {
+		t.Fatalf("expected 400 %s, got %d: %s", CodeInvalidParameter, rr.Code, rr.Body.String())
+	}
+}
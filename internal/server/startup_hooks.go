@@ -0,0 +1,104 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/ozgen/openapi-emulator/config"
+)
+
+// RunStartupHooks runs the STARTUP_HOOKS_PATH steps in order: requests are
+// served in-process like any other, so POSTs create jobs and advance
+// scenarios, and directives set scenario state. It stops at the first
+// request answered with a 4xx or 5xx status or the first rejected directive.
+func (s *Server) RunStartupHooks(hooks []config.StartupHook) error {
+	return s.runStartupHooks(hooks, nil)
+}
+
+// RunStartupHooks runs the startup hooks on the default server and every
+// virtual host, like RunStartupHooks on a single Server. A step with a host
+// runs only on the server that host is routed to; a step without one runs
+// on all of them.
+func (v *VirtualHosts) RunStartupHooks(hooks []config.StartupHook) error {
+	run := func(s *Server) error {
+		return s.runStartupHooks(hooks, func(h config.StartupHook) bool {
+			return h.Host == "" || v.serverFor(h.Host) == s
+		})
+	}
+	if err := run(v.def); err != nil {
+		return fmt.Errorf("default host: %w", err)
+	}
+	for _, host := range v.Hosts() {
+		if err := run(v.hosts[host]); err != nil {
+			return fmt.Errorf("virtual host %s: %w", host, err)
+		}
+	}
+	return nil
+}
+
+// runStartupHooks runs the hooks accepted by include, or all when include is
+// nil, keeping their position in the file in error messages.
+func (s *Server) runStartupHooks(hooks []config.StartupHook, include func(config.StartupHook) bool) error {
+	for i, h := range hooks {
+		if include != nil && !include(h) {
+			continue
+		}
+		if h.Directive != "" {
+			d, err := parseScenarioDirective(h.Directive)
+			if err == nil {
+				err = s.sampleProvider.SetScenarioState(d.SwaggerPath, d.Key, d.State)
+			}
+			if err != nil {
+				return fmt.Errorf("startup hook %d: %w", i+1, err)
+			}
+			s.log.Infof("startup hook %d: %s", i+1, h.Directive)
+			continue
+		}
+
+		for n := 0; n < max(h.Repeat, 1); n++ {
+			status, err := s.serveStartupHook(h)
+			if err != nil {
+				return fmt.Errorf("startup hook %d: %w", i+1, err)
+			}
+			if status >= 400 {
+				return fmt.Errorf("startup hook %d: %s %s answered %d", i+1, strings.ToUpper(h.Method), h.Path, status)
+			}
+			s.log.Infof("startup hook %d: %s %s answered %d", i+1, strings.ToUpper(h.Method), h.Path, status)
+		}
+	}
+	return nil
+}
+
+func (s *Server) serveStartupHook(h config.StartupHook) (int, error) {
+	var body io.Reader
+	if h.Body != nil {
+		b, err := json.Marshal(h.Body)
+		if err != nil {
+			return 0, fmt.Errorf("encode body: %w", err)
+		}
+		body = bytes.NewReader(b)
+	}
+	req, err := http.NewRequest(strings.ToUpper(h.Method), h.Path, body)
+	if err != nil {
+		return 0, err
+	}
+	req.Host = h.Host
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for k, v := range h.Headers {
+		req.Header.Set(k, v)
+	}
+
+	rec := newBatchRecorder()
+	s.handle(rec, req)
+	return rec.result().Status, nil
+}
@@ -0,0 +1,92 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ozgen/openapi-emulator/config"
+)
+
+func TestRunStartupHooks_SeedsScenarioState(t *testing.T) {
+	s := newIsolationTestServer(t, config.IsolationNone)
+
+	err := s.RunStartupHooks([]config.StartupHook{
+		{Method: "get", Path: "/scans/1", Repeat: 2},
+		{Directive: "set /scans/{id} key 7 to running"},
+	})
+	if err != nil {
+		t.Fatalf("RunStartupHooks: %v", err)
+	}
+
+	for _, id := range []string{"1", "7"} {
+		rr := httptest.NewRecorder()
+		s.handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com/scans/"+id, nil))
+		if !strings.Contains(rr.Body.String(), "running") {
+			t.Fatalf("expected scan %s to be running, got %s", id, rr.Body.String())
+		}
+	}
+}
+
+func TestRunStartupHooks_SendsBody(t *testing.T) {
+	s := newTestServer(t, config.ValidationRequired, config.FallbackNone)
+
+	err := s.RunStartupHooks([]config.StartupHook{
+		{Method: "POST", Path: "/items", Body: map[string]any{"name": "demo"}},
+	})
+	if err != nil {
+		t.Fatalf("RunStartupHooks: %v", err)
+	}
+}
+
+func TestRunStartupHooks_StopsAtFailure(t *testing.T) {
+	s := newTestServer(t, config.ValidationRequired, config.FallbackNone)
+
+	err := s.RunStartupHooks([]config.StartupHook{
+		{Method: "POST", Path: "/items"},
+		{Method: "GET", Path: "/items/1"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "startup hook 1: POST /items answered 400") {
+		t.Fatalf("expected the missing body to fail hook 1, got %v", err)
+	}
+
+	err = s.RunStartupHooks([]config.StartupHook{{Directive: "set /items/{id} key 1 to done"}})
+	if err == nil || !strings.Contains(err.Error(), "startup hook 1") {
+		t.Fatalf("expected the directive to be rejected, got %v", err)
+	}
+}
+
+func TestVirtualHosts_RunStartupHooks_SeedsEveryHost(t *testing.T) {
+	def := newIsolationTestServer(t, config.IsolationNone)
+	other := newIsolationTestServer(t, config.IsolationNone)
+	vh := NewVirtualHosts(def, map[string]*Server{"other.local": other})
+
+	err := vh.RunStartupHooks([]config.StartupHook{
+		{Method: "GET", Path: "/scans/1"},
+		{Host: "other.local", Directive: "set /scans/{id} key 7 to running"},
+	})
+	if err != nil {
+		t.Fatalf("RunStartupHooks: %v", err)
+	}
+
+	cases := []struct {
+		host, id, want string
+	}{
+		{"example.com", "1", "running"},
+		{"other.local", "1", "running"},
+		{"example.com", "7", "queued"},
+		{"other.local", "7", "running"},
+	}
+	for _, tc := range cases {
+		rr := httptest.NewRecorder()
+		vh.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "http://"+tc.host+"/scans/"+tc.id, nil))
+		if !strings.Contains(rr.Body.String(), tc.want) {
+			t.Fatalf("%s scan %s: expected %s, got %s", tc.host, tc.id, tc.want, rr.Body.String())
+		}
+	}
+}
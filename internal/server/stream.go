@@ -0,0 +1,34 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/ozgen/openapi-emulator/internal/samples"
+)
+
+// writeStream writes body in the chunks of stream, flushing each one and
+// pausing between them. It stops when the client goes away.
+func writeStream(ctx context.Context, w http.ResponseWriter, body []byte, stream *samples.Stream) error {
+	chunks := stream.Chunks(body)
+	extendWriteDeadline(w, time.Duration(len(chunks)-1)*stream.Interval())
+	rc := http.NewResponseController(w)
+	for i, chunk := range chunks {
+		if i > 0 && !sleepCtx(ctx, stream.Interval()) {
+			return ctx.Err()
+		}
+		if _, err := w.Write(chunk); err != nil {
+			return err
+		}
+		if err := rc.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ozgen/openapi-emulator/config"
+)
+
+func TestHandle_StreamedSample_WritesDelayedChunks(t *testing.T) {
+	s := newTestServer(t, config.ValidationNone, config.FallbackOpenAPIExample)
+	writeFileWithDirs(t, s.cfg.SamplesDir, filepath.Join("items", "{id}", "GET.json"), `{
+	  "version": 1,
+	  "stream": {"chunkSize": 10, "delayMs": 150},
+	  "body": {"log": "0123456789012345678"}
+	}`)
+	ts := httptest.NewServer(http.HandlerFunc(s.handle))
+	defer ts.Close()
+
+	start := time.Now()
+	res, err := http.Get(ts.URL + "/items/1")
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	defer res.Body.Close()
+	if len(res.TransferEncoding) == 0 || res.TransferEncoding[0] != "chunked" {
+		t.Fatalf("expected a chunked response, got %v (Content-Length %d)", res.TransferEncoding, res.ContentLength)
+	}
+
+	var body []byte
+	var arrivals []time.Duration
+	buf := make([]byte, 64)
+	for {
+		n, err := res.Body.Read(buf)
+		if n > 0 {
+			body = append(body, buf[:n]...)
+			arrivals = append(arrivals, time.Since(start))
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("read: %v", err)
+		}
+	}
+	if string(body) != `{"log":"0123456789012345678"}` || len(arrivals) != 3 {
+		t.Fatalf("expected the body in 3 reads, got %q in %d", body, len(arrivals))
+	}
+	if arrivals[0] > 100*time.Millisecond {
+		t.Fatalf("expected the first chunk right away, got it after %v", arrivals[0])
+	}
+	if arrivals[2] < 300*time.Millisecond {
+		t.Fatalf("expected two pauses before the last chunk, got it after %v", arrivals[2])
+	}
+}
+
+func TestHandle_StreamedSample_OutlastsWriteTimeout(t *testing.T) {
+	s := newTestServer(t, config.ValidationNone, config.FallbackOpenAPIExample)
+	writeFileWithDirs(t, s.cfg.SamplesDir, filepath.Join("items", "{id}", "GET.json"), `{
+	  "version": 1,
+	  "stream": {"chunkSize": 5, "delayMs": 150},
+	  "body": {"log": "01234567890123456789"}
+	}`)
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(s.handle))
+	// Scaled down from the 10s of Server.listen; the stream takes 0.9s.
+	ts.Config.WriteTimeout = 300 * time.Millisecond
+	ts.Start()
+	defer ts.Close()
+
+	res, err := http.Get(ts.URL + "/items/1")
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatalf("stream cut short after %d bytes: %v", len(body), err)
+	}
+	if string(body) != `{"log":"01234567890123456789"}` {
+		t.Fatalf("unexpected body %q", body)
+	}
+}
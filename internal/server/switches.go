@@ -0,0 +1,166 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Switches are runtime toggles flipped from the admin UI (or
+// PUT /__admin/switches). They apply to every emulated route.
+type Switches struct {
+	// Maintenance answers every request with 503.
+	Maintenance bool `json:"maintenance"`
+	// LatencyMs is added to every response.
+	LatencyMs int `json:"latencyMs"`
+	// ChaosRate is the fraction (0..1) of requests failed with ChaosStatus.
+	ChaosRate   float64 `json:"chaosRate"`
+	ChaosStatus int     `json:"chaosStatus"`
+
+	// Duration turns the switches off again this long after they are set
+	// (e.g. "15m"); ExpiresAt is when that happens.
+	Duration  string     `json:"duration,omitempty"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+	// Windows limits the switches to daily "HH:MM-HH:MM" ranges in
+	// Timezone (an IANA name, default UTC). A range may wrap midnight.
+	Windows  []string `json:"windows,omitempty"`
+	Timezone string   `json:"timezone,omitempty"`
+	// Active reports whether the switches are in effect right now.
+	Active bool `json:"active"`
+}
+
+func (sw Switches) validate() error {
+	if sw.LatencyMs < 0 || sw.ChaosRate < 0 || sw.ChaosRate > 1 ||
+		(sw.ChaosStatus != 0 && (sw.ChaosStatus < 400 || sw.ChaosStatus > 599)) {
+		return errors.New("latencyMs must be >= 0, chaosRate within 0..1 and chaosStatus a 4xx/5xx status")
+	}
+	if sw.Duration != "" {
+		if d, err := time.ParseDuration(sw.Duration); err != nil || d <= 0 {
+			return fmt.Errorf("duration %q must be a positive duration such as 15m", sw.Duration)
+		}
+	}
+	if _, err := time.LoadLocation(sw.Timezone); err != nil {
+		return fmt.Errorf("unknown timezone %q", sw.Timezone)
+	}
+	for _, win := range sw.Windows {
+		if _, _, err := parseSwitchWindow(win); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseSwitchWindow parses "09:00-17:30" into minutes since midnight.
+func parseSwitchWindow(win string) (from, to int, err error) {
+	a, b, ok := strings.Cut(win, "-")
+	if ok {
+		from, err = parseClockMinutes(a)
+	}
+	if ok && err == nil {
+		to, err = parseClockMinutes(b)
+	}
+	if !ok || err != nil || from == to {
+		return 0, 0, fmt.Errorf("window %q must look like 09:00-17:30", win)
+	}
+	return from, to, nil
+}
+
+func parseClockMinutes(s string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, err
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// inWindows reports whether now falls in one of the switches' windows, or
+// true when there are none.
+func (sw Switches) inWindows(now time.Time) bool {
+	if len(sw.Windows) == 0 {
+		return true
+	}
+	loc, err := time.LoadLocation(sw.Timezone)
+	if err != nil {
+		return false
+	}
+	now = now.In(loc)
+	m := now.Hour()*60 + now.Minute()
+	for _, win := range sw.Windows {
+		from, to, err := parseSwitchWindow(win)
+		if err != nil {
+			continue
+		}
+		if (from < to && m >= from && m < to) || (from > to && (m >= from || m < to)) {
+			return true
+		}
+	}
+	return false
+}
+
+type switchBoard struct {
+	mu  sync.RWMutex
+	cur Switches
+}
+
+// get returns the switches as of now, turning them off once they expired.
+func (b *switchBoard) get(now time.Time) Switches {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.cur.ExpiresAt != nil && !now.Before(*b.cur.ExpiresAt) {
+		b.cur = Switches{ChaosStatus: 500}
+	}
+	sw := b.cur
+	sw.Active = (sw.Maintenance || sw.LatencyMs > 0 || sw.ChaosRate > 0) && sw.inWindows(now)
+	return sw
+}
+
+func (b *switchBoard) set(sw Switches, now time.Time) {
+	if sw.ChaosStatus == 0 {
+		sw.ChaosStatus = 500
+	}
+	sw.ExpiresAt = nil
+	if d, err := time.ParseDuration(sw.Duration); err == nil && d > 0 {
+		at := now.Add(d).UTC()
+		sw.ExpiresAt = &at
+	}
+	b.mu.Lock()
+	b.cur = sw
+	b.mu.Unlock()
+}
+
+// applySwitches enforces the runtime switches. It returns false when the
+// request has been answered (or the client went away). Windows and expiry
+// follow the wall clock, not the CLOCK_SKEW one clients see.
+func (s *Server) applySwitches(w http.ResponseWriter, r *http.Request) bool {
+	sw := s.switches.get(time.Now())
+	if !sw.Active {
+		return true
+	}
+
+	if sw.LatencyMs > 0 && !delayResponse(w, r, time.Duration(sw.LatencyMs)*time.Millisecond) {
+		return false
+	}
+	if sw.Maintenance {
+		w.Header().Set("Retry-After", "60")
+		writeError(w, 503, CodeMaintenance, "Service in maintenance", nil)
+		return false
+	}
+	if sw.ChaosRate > 0 && rand.Float64() < sw.ChaosRate {
+		if s.serveStatusSample(w, r, sw.ChaosStatus) {
+			return false
+		}
+		writeError(w, sw.ChaosStatus, CodeChaosFault, "Injected fault", map[string]any{
+			"chaosRate": sw.ChaosRate,
+		})
+		return false
+	}
+	return true
+}
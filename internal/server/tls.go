@@ -0,0 +1,50 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"crypto/tls"
+	"fmt"
+	"strings"
+)
+
+// loadCertificate loads a PEM certificate and key pair. It returns nil when
+// both paths are empty.
+func loadCertificate(certFile, keyFile string) (*tls.Certificate, error) {
+	certFile, keyFile = strings.TrimSpace(certFile), strings.TrimSpace(keyFile)
+	if certFile == "" && keyFile == "" {
+		return nil, nil
+	}
+	if certFile == "" || keyFile == "" {
+		return nil, fmt.Errorf("TLS needs both a certificate and a key file")
+	}
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("load TLS certificate: %w", err)
+	}
+	return &cert, nil
+}
+
+// newTLSConfig picks the certificate by the client's SNI host name: the one
+// in sni for that host, otherwise def. It returns nil when there is no
+// certificate at all. Without def, a client asking for an unlisted host
+// fails the handshake.
+func newTLSConfig(def *tls.Certificate, sni map[string]*tls.Certificate) *tls.Config {
+	if def == nil && len(sni) == 0 {
+		return nil
+	}
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetCertificate: func(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+			if cert, ok := lookupHost(sni, hello.ServerName); ok {
+				return cert, nil
+			}
+			if def == nil {
+				return nil, fmt.Errorf("no certificate for %q", hello.ServerName)
+			}
+			return def, nil
+		},
+	}
+}
@@ -0,0 +1,141 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ozgen/openapi-emulator/config"
+)
+
+// writeTestCertificate writes a self-signed certificate for host and returns
+// the certificate and key paths.
+func writeTestCertificate(t *testing.T, host string) (string, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: host},
+		DNSNames:     []string{host},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	certPath, keyPath := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	if err := os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return certPath, keyPath
+}
+
+func TestLoadCertificate(t *testing.T) {
+	if cert, err := loadCertificate("", ""); cert != nil || err != nil {
+		t.Fatalf("expected no certificate, got %v, %v", cert, err)
+	}
+	certPath, keyPath := writeTestCertificate(t, "api.foo.test")
+	if _, err := loadCertificate(certPath, ""); err == nil {
+		t.Fatalf("expected an error without a key file")
+	}
+	if _, err := loadCertificate(certPath, certPath); err == nil {
+		t.Fatalf("expected an error for a certificate used as key")
+	}
+	if cert, err := loadCertificate(certPath, keyPath); err != nil || cert == nil {
+		t.Fatalf("load: %v", err)
+	}
+}
+
+func TestNewTLSConfig_SelectsCertificateBySNI(t *testing.T) {
+	if newTLSConfig(nil, nil) != nil {
+		t.Fatalf("expected no TLS config without certificates")
+	}
+
+	apiCert, apiKey := writeTestCertificate(t, "api.foo.test")
+	authCert, authKey := writeTestCertificate(t, "auth.foo.test")
+	def, _ := loadCertificate(apiCert, apiKey)
+	auth, _ := loadCertificate(authCert, authKey)
+
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	ts.TLS = newTLSConfig(def, map[string]*tls.Certificate{"auth.foo.test": auth})
+	ts.StartTLS()
+	defer ts.Close()
+
+	for _, host := range []string{"api.foo.test", "Auth.Foo.Test", "other.foo.test"} {
+		conn, err := tls.Dial("tcp", ts.Listener.Addr().String(), &tls.Config{ServerName: host, InsecureSkipVerify: true}) //nolint:gosec // inspecting the served certificate
+		if err != nil {
+			t.Fatalf("%s: dial: %v", host, err)
+		}
+		got := conn.ConnectionState().PeerCertificates[0].Subject.CommonName
+		_ = conn.Close()
+
+		want := "api.foo.test"
+		if host == "Auth.Foo.Test" {
+			want = "auth.foo.test"
+		}
+		if got != want {
+			t.Fatalf("%s: expected certificate for %s, got %s", host, want, got)
+		}
+	}
+}
+
+func TestNewTLSConfig_NoDefault_RejectsUnknownHost(t *testing.T) {
+	authCert, authKey := writeTestCertificate(t, "auth.foo.test")
+	auth, _ := loadCertificate(authCert, authKey)
+
+	cfg := newTLSConfig(nil, map[string]*tls.Certificate{"auth.foo.test": auth})
+	if _, err := cfg.GetCertificate(&tls.ClientHelloInfo{ServerName: "api.foo.test"}); err == nil {
+		t.Fatalf("expected an error for a host without certificate")
+	}
+	if cert, err := cfg.GetCertificate(&tls.ClientHelloInfo{ServerName: "auth.foo.test."}); err != nil || cert != auth {
+		t.Fatalf("expected the auth certificate, got %v, %v", cert, err)
+	}
+}
+
+func TestNew_InvalidTLSFiles(t *testing.T) {
+	disableScenarioForTests()
+	dir := t.TempDir()
+	specPath := writeFile(t, dir, "spec.json", minimalSpec())
+
+	_, err := New(Config{
+		Port:         "0",
+		SpecPath:     specPath,
+		SamplesDir:   dir,
+		FallbackMode: config.FallbackNone,
+		TLSCertFile:  filepath.Join(dir, "missing.crt"),
+		TLSKeyFile:   filepath.Join(dir, "missing.key"),
+	})
+	if err == nil {
+		t.Fatalf("expected an error for missing certificate files")
+	}
+}
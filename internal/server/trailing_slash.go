@@ -0,0 +1,54 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/ozgen/openapi-emulator/config"
+	"github.com/ozgen/openapi-emulator/internal/openapi"
+)
+
+// checkTrailingSlash applies TRAILING_SLASH to a request that matched rt.
+// Routes match with or without a trailing slash; in strict mode a mismatch
+// with the spec path is answered with 404, in redirect mode with a 301 to
+// the spec form. It returns false when a response was written.
+func (s *Server) checkTrailingSlash(w http.ResponseWriter, r *http.Request, rt *openapi.Route) bool {
+	mode := s.cfg.TrailingSlash
+	if mode != config.TrailingSlashStrict && mode != config.TrailingSlashRedirect {
+		return true
+	}
+
+	path := r.URL.Path
+	want := hasTrailingSlash(rt.Swagger)
+	if hasTrailingSlash(path) == want {
+		return true
+	}
+
+	if mode == config.TrailingSlashStrict {
+		writeError(w, 404, CodeRouteNotFound, "No route", map[string]any{
+			"method": r.Method,
+			"path":   path,
+			"hint":   "trailing slash does not match the API spec path " + rt.Swagger,
+		})
+		return false
+	}
+
+	target := requestBasePath(r) + strings.TrimSuffix(path, "/")
+	if want {
+		target = requestBasePath(r) + path + "/"
+	}
+	if r.URL.RawQuery != "" {
+		target += "?" + r.URL.RawQuery
+	}
+	w.Header().Set("Location", target)
+	w.WriteHeader(http.StatusMovedPermanently)
+	return false
+}
+
+func hasTrailingSlash(p string) bool {
+	return len(p) > 1 && strings.HasSuffix(p, "/")
+}
@@ -0,0 +1,56 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ozgen/openapi-emulator/config"
+	"github.com/ozgen/openapi-emulator/internal/openapi"
+)
+
+func TestHandle_TrailingSlash(t *testing.T) {
+	cases := []struct {
+		mode     config.TrailingSlashMode
+		path     string
+		code     int
+		location string
+	}{
+		{config.TrailingSlashIgnore, "/items/1/", 200, ""},
+		{config.TrailingSlashStrict, "/items/1", 200, ""},
+		{config.TrailingSlashStrict, "/items/1/", 404, ""},
+		{config.TrailingSlashRedirect, "/items/1/?x=1", 301, "/items/1?x=1"},
+		{config.TrailingSlashRedirect, "/items/1", 200, ""},
+	}
+	for _, tc := range cases {
+		s := newTestServer(t, config.ValidationNone, config.FallbackOpenAPIExample)
+		s.cfg.TrailingSlash = tc.mode
+
+		rr := httptest.NewRecorder()
+		s.handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com"+tc.path, nil))
+		if rr.Code != tc.code {
+			t.Fatalf("%s %s: expected %d, got %d: %s", tc.mode, tc.path, tc.code, rr.Code, rr.Body.String())
+		}
+		if got := rr.Header().Get("Location"); got != tc.location {
+			t.Fatalf("%s %s: expected Location %q, got %q", tc.mode, tc.path, tc.location, got)
+		}
+	}
+}
+
+func TestCheckTrailingSlash_SpecPathWithSlash(t *testing.T) {
+	s := newTestServer(t, config.ValidationNone, config.FallbackOpenAPIExample)
+	s.cfg.TrailingSlash = config.TrailingSlashRedirect
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/reports", nil)
+	if s.checkTrailingSlash(rr, req, &openapi.Route{Method: "GET", Swagger: "/reports/"}) {
+		t.Fatalf("expected a redirect")
+	}
+	if rr.Code != 301 || rr.Header().Get("Location") != "/reports/" {
+		t.Fatalf("unexpected response %d %q", rr.Code, rr.Header().Get("Location"))
+	}
+}
@@ -0,0 +1,88 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// unmatchedResponse answers requests that match no spec operation and no
+// _default sample, in place of 404 ROUTE_NOT_FOUND.
+type unmatchedResponse struct {
+	status      int
+	body        []byte
+	contentType string
+	upstream    *httputil.ReverseProxy
+}
+
+// newUnmatchedResponse builds the response from UNMATCHED_STATUS,
+// UNMATCHED_BODY_FILE and UNMATCHED_UPSTREAM. The body file is read once
+// here so a missing file stops the emulator at startup.
+func newUnmatchedResponse(status int, bodyFile, upstream string) (*unmatchedResponse, error) {
+	u := &unmatchedResponse{status: status}
+	if u.status == 0 {
+		u.status = http.StatusNotFound
+	}
+	if u.status < 400 || u.status > 599 {
+		return nil, fmt.Errorf("UNMATCHED_STATUS: %d is not a 4xx or 5xx status", status)
+	}
+
+	if bodyFile = strings.TrimSpace(bodyFile); bodyFile != "" {
+		b, err := os.ReadFile(bodyFile)
+		if err != nil {
+			return nil, fmt.Errorf("UNMATCHED_BODY_FILE: %w", err)
+		}
+		u.body = b
+		u.contentType = mime.TypeByExtension(filepath.Ext(bodyFile))
+		if u.contentType == "" {
+			u.contentType = "application/octet-stream"
+		}
+	}
+
+	if upstream = strings.TrimSpace(upstream); upstream != "" {
+		target, err := url.Parse(upstream)
+		if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+			return nil, fmt.Errorf("UNMATCHED_UPSTREAM: %q is not an http(s) URL", upstream)
+		}
+		proxy := httputil.NewSingleHostReverseProxy(target)
+		proxy.ErrorHandler = func(w http.ResponseWriter, r *http.Request, err error) {
+			writeError(w, 502, CodeUpstreamUnavailable, "Upstream unavailable", map[string]any{
+				"method":   r.Method,
+				"path":     r.URL.Path,
+				"upstream": target.String(),
+				"details":  err.Error(),
+			})
+		}
+		u.upstream = proxy
+	}
+	return u, nil
+}
+
+// write answers an unmatched request: forwarded to the upstream when one is
+// configured, otherwise with the configured status and body file, or with
+// the ROUTE_NOT_FOUND error body.
+func (u *unmatchedResponse) write(w http.ResponseWriter, r *http.Request) {
+	if u.upstream != nil {
+		u.upstream.ServeHTTP(w, r)
+		return
+	}
+	if u.body != nil {
+		w.Header().Set("Content-Type", u.contentType)
+		w.WriteHeader(u.status)
+		_, _ = w.Write(u.body)
+		return
+	}
+	writeError(w, u.status, CodeRouteNotFound, "No route", map[string]any{
+		"method": r.Method,
+		"path":   r.URL.Path,
+	})
+}
@@ -0,0 +1,129 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ozgen/openapi-emulator/config"
+)
+
+func TestHandle_Unmatched_CustomStatusAndBody(t *testing.T) {
+	s := newTestServer(t, config.ValidationNone, config.FallbackNone)
+	dir := t.TempDir()
+	u, err := newUnmatchedResponse(501, writeFile(t, dir, "unmatched.json", `{"error":"not implemented"}`), "")
+	if err != nil {
+		t.Fatalf("newUnmatchedResponse: %v", err)
+	}
+	s.unmatched = u
+
+	rr := httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com/nope", nil))
+	if rr.Code != 501 {
+		t.Fatalf("expected 501, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if ct := rr.Header().Get("Content-Type"); ct != "application/json" {
+		t.Fatalf("expected application/json, got %q", ct)
+	}
+	if rr.Body.String() != `{"error":"not implemented"}` {
+		t.Fatalf("unexpected body: %s", rr.Body.String())
+	}
+
+	// Known paths with another method still get 405.
+	rr = httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodDelete, "http://example.com/items/1", nil))
+	if rr.Code != 405 {
+		t.Fatalf("expected 405, got %d", rr.Code)
+	}
+}
+
+func TestHandle_Unmatched_StatusOnlyKeepsErrorBody(t *testing.T) {
+	s := newTestServer(t, config.ValidationNone, config.FallbackNone)
+	u, err := newUnmatchedResponse(501, "", "")
+	if err != nil {
+		t.Fatalf("newUnmatchedResponse: %v", err)
+	}
+	s.unmatched = u
+
+	rr := httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com/nope", nil))
+	if rr.Code != 501 || !strings.Contains(rr.Body.String(), string(CodeRouteNotFound)) {
+		t.Fatalf("expected 501 %s, got %d: %s", CodeRouteNotFound, rr.Code, rr.Body.String())
+	}
+}
+
+func TestHandle_Unmatched_ForwardsToUpstream(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Upstream", "1")
+		w.WriteHeader(http.StatusTeapot)
+		_, _ = w.Write([]byte(r.Method + " " + r.URL.Path + "?" + r.URL.RawQuery))
+	}))
+	defer upstream.Close()
+
+	s := newTestServer(t, config.ValidationNone, config.FallbackNone)
+	u, err := newUnmatchedResponse(0, "", upstream.URL)
+	if err != nil {
+		t.Fatalf("newUnmatchedResponse: %v", err)
+	}
+	s.unmatched = u
+
+	rr := httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodPost, "http://example.com/legacy/orders?x=1", nil))
+	if rr.Code != http.StatusTeapot || rr.Header().Get("X-Upstream") != "1" {
+		t.Fatalf("expected the upstream answer, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if rr.Body.String() != "POST /legacy/orders?x=1" {
+		t.Fatalf("unexpected forwarded request: %s", rr.Body.String())
+	}
+
+	// Spec routes are still served from samples.
+	rr = httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com/items/123", nil))
+	if rr.Code != 200 || rr.Header().Get("X-Upstream") != "" {
+		t.Fatalf("expected the sample, got %d: %s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestHandle_Unmatched_UpstreamDown_Returns502(t *testing.T) {
+	upstream := httptest.NewServer(http.NotFoundHandler())
+	url := upstream.URL
+	upstream.Close()
+
+	s := newTestServer(t, config.ValidationNone, config.FallbackNone)
+	u, err := newUnmatchedResponse(0, "", url)
+	if err != nil {
+		t.Fatalf("newUnmatchedResponse: %v", err)
+	}
+	s.unmatched = u
+
+	rr := httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com/nope", nil))
+	if rr.Code != 502 || !strings.Contains(rr.Body.String(), string(CodeUpstreamUnavailable)) {
+		t.Fatalf("expected 502 %s, got %d: %s", CodeUpstreamUnavailable, rr.Code, rr.Body.String())
+	}
+}
+
+func TestNewUnmatchedResponse_RejectsInvalidConfig(t *testing.T) {
+	cases := map[string]struct {
+		status   int
+		bodyFile string
+		upstream string
+	}{
+		"success status":  {status: 200},
+		"missing file":    {bodyFile: "/does/not/exist.json"},
+		"relative url":    {upstream: "upstream:8080"},
+		"unsupported url": {upstream: "ftp://upstream"},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := newUnmatchedResponse(tc.status, tc.bodyFile, tc.upstream); err == nil {
+				t.Fatalf("expected an error")
+			}
+		})
+	}
+}
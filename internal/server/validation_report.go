@@ -0,0 +1,70 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/ozgen/openapi-emulator/config"
+	"github.com/ozgen/openapi-emulator/internal/openapi"
+)
+
+const validationHeader = "X-Emulator-Validation"
+
+// validationReport collects the request checks run in VALIDATION_MODE=warn
+// and the failures that were let through.
+type validationReport struct {
+	Checked  []string             `json:"checked"`
+	Failures []openapi.FieldError `json:"failures"`
+}
+
+// newValidationReport returns nil unless VALIDATION_MODE=warn, so callers can
+// use the report's methods unconditionally.
+func (s *Server) newValidationReport() *validationReport {
+	if s.cfg.ValidationMode != config.ValidationWarn {
+		return nil
+	}
+	return &validationReport{Checked: []string{}, Failures: []openapi.FieldError{}}
+}
+
+func (rep *validationReport) check(name string) {
+	if rep != nil {
+		rep.Checked = append(rep.Checked, name)
+	}
+}
+
+// soften records fieldErrs as soft failures. It returns false when there is
+// no report, i.e. the caller must reject the request.
+func (rep *validationReport) soften(fieldErrs ...openapi.FieldError) bool {
+	if rep == nil {
+		return false
+	}
+	rep.Failures = append(rep.Failures, fieldErrs...)
+	return true
+}
+
+// inBody returns body field errors marked with their location, which the
+// REQUEST_BODY_INVALID response leaves implicit.
+func inBody(fieldErrs []openapi.FieldError) []openapi.FieldError {
+	out := make([]openapi.FieldError, len(fieldErrs))
+	for i, e := range fieldErrs {
+		e.In = "body"
+		out[i] = e
+	}
+	return out
+}
+
+// write sets the X-Emulator-Validation header to the JSON encoded report.
+func (rep *validationReport) write(w http.ResponseWriter) {
+	if rep == nil {
+		return
+	}
+	b, err := json.Marshal(rep)
+	if err != nil {
+		return
+	}
+	w.Header().Set(validationHeader, string(b))
+}
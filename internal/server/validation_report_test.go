@@ -0,0 +1,60 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ozgen/openapi-emulator/config"
+)
+
+func TestHandle_ValidationWarn_ReportsSoftFailures(t *testing.T) {
+	s := newTestServer(t, config.ValidationWarn, config.FallbackOpenAPIExample)
+
+	req := httptest.NewRequest(http.MethodPost, "http://example.com/items", strings.NewReader(""))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+	s.handle(rr, req)
+	if rr.Code != 201 {
+		t.Fatalf("warn mode must accept the request, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	var report validationReport
+	if err := json.Unmarshal([]byte(rr.Header().Get(validationHeader)), &report); err != nil {
+		t.Fatalf("bad %s header %q: %v", validationHeader, rr.Header().Get(validationHeader), err)
+	}
+	if want := []string{"path", "query", "bodyRequired", "body"}; !reflect.DeepEqual(report.Checked, want) {
+		t.Fatalf("checked: got %v want %v", report.Checked, want)
+	}
+	if len(report.Failures) == 0 || report.Failures[0].In != "body" {
+		t.Fatalf("expected a body failure, got %+v", report.Failures)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "http://example.com/items", strings.NewReader(`{"a":1}`))
+	req.Header.Set("Content-Type", "application/json")
+	rr = httptest.NewRecorder()
+	s.handle(rr, req)
+	if got := rr.Header().Get(validationHeader); !strings.Contains(got, `"failures":[]`) {
+		t.Fatalf("expected no failures, got %q", got)
+	}
+}
+
+func TestHandle_ValidationSchema_NoReportHeader(t *testing.T) {
+	s := newTestServer(t, config.ValidationSchema, config.FallbackOpenAPIExample)
+
+	rr := httptest.NewRecorder()
+	s.handle(rr, httptest.NewRequest(http.MethodGet, "http://example.com/items/1", nil))
+	if rr.Code != 200 {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if got := rr.Header().Get(validationHeader); got != "" {
+		t.Fatalf("unexpected %s header %q", validationHeader, got)
+	}
+}
@@ -0,0 +1,96 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// VirtualHosts serves several emulators on one port and picks the Server
+// by the request's Host header. A "*.example.local" entry covers every
+// subdomain of example.local; an exact entry beats a wildcard and a longer
+// wildcard beats a shorter one. Requests for unlisted hosts go to the
+// default Server.
+type VirtualHosts struct {
+	def   *Server
+	hosts map[string]*Server
+}
+
+// NewVirtualHosts routes the lowercase host names of hosts to their Server
+// and everything else to def, whose port is listened on.
+func NewVirtualHosts(def *Server, hosts map[string]*Server) *VirtualHosts {
+	return &VirtualHosts{def: def, hosts: hosts}
+}
+
+// Servers returns the default Server followed by the host servers, sorted
+// by host name.
+func (v *VirtualHosts) Servers() []*Server {
+	out := []*Server{v.def}
+	for _, host := range v.Hosts() {
+		out = append(out, v.hosts[host])
+	}
+	return out
+}
+
+// Hosts returns the host entries, sorted, in the order Servers lists their
+// servers after the default one.
+func (v *VirtualHosts) Hosts() []string {
+	names := make([]string, 0, len(v.hosts))
+	for host := range v.hosts {
+		names = append(names, host)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (v *VirtualHosts) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	v.serverFor(r.Host).handle(w, r)
+}
+
+func (v *VirtualHosts) serverFor(host string) *Server {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	if s, ok := lookupHost(v.hosts, host); ok {
+		return s
+	}
+	return v.def
+}
+
+// lookupHost finds host in a map keyed by lowercase host names and
+// "*.domain" wildcards, preferring the exact name, then the longest wildcard.
+func lookupHost[T any](m map[string]T, host string) (T, bool) {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if v, ok := m[host]; ok {
+		return v, true
+	}
+	// Try *.b.c, then *.c, for a.b.c.
+	for rest := host; ; {
+		_, parent, ok := strings.Cut(rest, ".")
+		if !ok || parent == "" {
+			var zero T
+			return zero, false
+		}
+		if v, ok := m["*."+parent]; ok {
+			return v, true
+		}
+		rest = parent
+	}
+}
+
+func (v *VirtualHosts) ListenAndServe() error {
+	sni := map[string]*tls.Certificate{}
+	for host, s := range v.hosts {
+		v.def.log.Printf("virtual host %s: spec=%s samples=%s", host, s.cfg.SpecPath, s.cfg.SamplesDir)
+		if s.cert != nil {
+			sni[host] = s.cert
+		}
+	}
+	return v.def.listen(v, sni)
+}
@@ -0,0 +1,93 @@
+// SPDX-FileCopyrightText: 2026 Greenbone AG
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ozgen/openapi-emulator/config"
+)
+
+func TestVirtualHosts_RoutesByHost(t *testing.T) {
+	def := newTestServer(t, config.ValidationNone, config.FallbackNone)
+
+	dir := t.TempDir()
+	specPath := writeFile(t, dir, "spec.json", `{
+	  "openapi":"3.0.3",
+	  "info":{"title":"payments","version":"1"},
+	  "paths":{"/charges":{"get":{"responses":{"200":{"description":"ok"}}}}}
+	}`)
+	writeFileWithDirs(t, dir, "charges/GET.json", `{"charges":[]}`)
+	payments, err := New(Config{
+		Port:           "0",
+		SpecPath:       specPath,
+		SamplesDir:     dir,
+		FallbackMode:   config.FallbackNone,
+		ValidationMode: config.ValidationNone,
+		Layout:         config.LayoutFolders,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	vh := NewVirtualHosts(def, map[string]*Server{"payments.foo.local": payments})
+
+	cases := []struct {
+		host, path string
+		want       int
+	}{
+		{"Payments.foo.local:8086", "/charges", 200},
+		{"payments.foo.local", "/items/1", 404},
+		{"api.foo.local", "/items/1", 200},
+		{"api.foo.local", "/charges", 404},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(http.MethodGet, "http://"+tc.host+tc.path, nil)
+		rr := httptest.NewRecorder()
+		vh.ServeHTTP(rr, req)
+		if rr.Code != tc.want {
+			t.Fatalf("%s%s: expected %d, got %d: %s", tc.host, tc.path, tc.want, rr.Code, rr.Body.String())
+		}
+	}
+}
+
+func TestVirtualHosts_Servers(t *testing.T) {
+	def := newTestServer(t, config.ValidationNone, config.FallbackNone)
+	a := newTestServer(t, config.ValidationNone, config.FallbackNone)
+	b := newTestServer(t, config.ValidationNone, config.FallbackNone)
+
+	got := NewVirtualHosts(def, map[string]*Server{"b.local": b, "a.local": a}).Servers()
+	if len(got) != 3 || got[0] != def || got[1] != a || got[2] != b {
+		t.Fatalf("expected the default server, then a.local and b.local")
+	}
+}
+
+func TestLookupHost_Wildcards(t *testing.T) {
+	hosts := map[string]string{
+		"api.foo.local":   "exact",
+		"*.foo.local":     "foo",
+		"*.eu.foo.local":  "eu",
+		"*.payments.test": "payments",
+	}
+	cases := map[string]string{
+		"api.foo.local":          "exact",
+		"API.FOO.LOCAL.":         "exact",
+		"web.foo.local":          "foo",
+		"a.b.foo.local":          "foo",
+		"api.eu.foo.local":       "eu",
+		"tenant-1.payments.test": "payments",
+		"foo.local":              "",
+		"payments.test":          "",
+		"localhost":              "",
+	}
+	for host, want := range cases {
+		got, ok := lookupHost(hosts, host)
+		if ok != (want != "") || got != want {
+			t.Fatalf("%s: expected %q, got %q (%v)", host, want, got, ok)
+		}
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 )
 
 func GetEnv(key, defaultValue string) string {
@@ -47,7 +48,43 @@ func GetEnvAsInt(key string, fallback int) int {
 	return fallback
 }
 
+// GetEnvAsDuration parses a Go duration such as "5m"; invalid values use the fallback.
+func GetEnvAsDuration(key string, fallback time.Duration) time.Duration {
+	if value, ok := os.LookupEnv(key); ok {
+		d, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil {
+			return fallback
+		}
+		return d
+	}
+	return fallback
+}
+
+// GetEnvAsList splits a comma-separated variable into trimmed, non-empty items.
+func GetEnvAsList(key string) []string {
+	var out []string
+	for _, item := range strings.Split(os.Getenv(key), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
 func FileExists(path string) bool {
 	st, err := os.Stat(path)
 	return err == nil && !st.IsDir()
 }
+
+// DirWritable reports whether files can be created in dir. It probes with a
+// temporary file, so read-only mounts are detected regardless of the mode bits.
+func DirWritable(dir string) bool {
+	f, err := os.CreateTemp(dir, ".write-probe-*")
+	if err != nil {
+		return false
+	}
+	name := f.Name()
+	_ = f.Close()
+	_ = os.Remove(name)
+	return true
+}
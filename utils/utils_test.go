@@ -10,6 +10,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestGetEnv_ReturnsValueWhenSet(t *testing.T) {
@@ -133,6 +134,37 @@ func TestGetEnvAsInt_FallbackWhenInvalid(t *testing.T) {
 	}
 }
 
+func TestGetEnvAsDuration(t *testing.T) {
+	_ = os.Unsetenv("X_DUR_MISSING")
+	if got := GetEnvAsDuration("X_DUR_MISSING", time.Minute); got != time.Minute {
+		t.Fatalf("got %v want %v", got, time.Minute)
+	}
+
+	t.Setenv("X_DUR", "90s")
+	if got := GetEnvAsDuration("X_DUR", time.Minute); got != 90*time.Second {
+		t.Fatalf("got %v want %v", got, 90*time.Second)
+	}
+
+	t.Setenv("X_DUR_BAD", "soon")
+	if got := GetEnvAsDuration("X_DUR_BAD", time.Minute); got != time.Minute {
+		t.Fatalf("got %v want %v", got, time.Minute)
+	}
+}
+
+func TestGetEnvAsList_SplitsAndTrims(t *testing.T) {
+	t.Setenv("X_LIST", " a, b ,,c ")
+
+	got := GetEnvAsList("X_LIST")
+	if len(got) != 3 || got[0] != "a" || got[1] != "b" || got[2] != "c" {
+		t.Fatalf("unexpected list: %q", got)
+	}
+
+	_ = os.Unsetenv("X_LIST_MISSING")
+	if got := GetEnvAsList("X_LIST_MISSING"); got != nil {
+		t.Fatalf("expected nil when missing, got %q", got)
+	}
+}
+
 func TestFileExists(t *testing.T) {
 	dir := t.TempDir()
 
@@ -158,3 +190,17 @@ func TestFileExists(t *testing.T) {
 		}
 	})
 }
+
+func TestDirWritable(t *testing.T) {
+	dir := t.TempDir()
+	if !DirWritable(dir) {
+		t.Fatalf("expected temp dir to be writable")
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 0 {
+		t.Fatalf("probe file left behind: %v", entries)
+	}
+	if DirWritable(filepath.Join(dir, "missing")) {
+		t.Fatalf("expected missing dir to be reported as not writable")
+	}
+}